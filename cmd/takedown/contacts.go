@@ -0,0 +1,314 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cti-team/takedown/internal/contacts"
+)
+
+// defaultOverridesPath é o arquivo de overrides do operador usado se existir
+const defaultOverridesPath = "configs/contacts/overrides.yaml"
+
+// runContacts implementa "takedown contacts <list|show|find|set|remove>"
+func runContacts(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("usage: takedown contacts <list|show|find|set|remove> [flags]")
+	}
+
+	command, args := args[0], args[1:]
+	flags := flag.NewFlagSet("contacts "+command, flag.ContinueOnError)
+	base := flags.String("directory", "", "base directory file (default: embedded)")
+	overrides := flags.String("overrides", "", "operator overrides file (default: "+defaultOverridesPath+" if present)")
+	asJSON := flags.Bool("json", false, "print JSON output")
+
+	switch command {
+	case "list":
+		kind := flags.String("kind", "", "filter by kind (registrar, hosting, cdn, cert, mailbox, platform)")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		directory, err := loadContactDirectory(*base, *overrides)
+		if err != nil {
+			return err
+		}
+		return printProviders(stdout, directory.List(*kind), *asJSON)
+
+	case "show":
+		id, err := parseWithID(flags, args)
+		if err != nil {
+			return fmt.Errorf("usage: takedown contacts show <id>: %w", err)
+		}
+		directory, err := loadContactDirectory(*base, *overrides)
+		if err != nil {
+			return err
+		}
+		provider, found := directory.Get(id)
+		if !found {
+			return fmt.Errorf("provider %q not found", id)
+		}
+		return printProvider(stdout, provider, *asJSON)
+
+	case "find":
+		kind := flags.String("kind", "", "filter by kind (registrar, hosting, cdn, cert, mailbox, platform)")
+		asn := flags.Int("asn", 0, "autonomous system number")
+		iana := flags.Int("iana", 0, "registrar IANA ID")
+		name := flags.String("name", "", "provider or registrar name")
+		cname := flags.String("cname", "", "CNAME to match against CDNs")
+		country := flags.String("country", "", "country (ISO alpha-2) to match against CERTs")
+		mailDomain := flags.String("mail-domain", "", "email domain to match against mail providers")
+		mx := flags.String("mx", "", "comma separated MX hosts to match against mail providers")
+		host := flags.String("host", "", "site host to match against platforms (ex: user.github.io)")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		directory, err := loadContactDirectory(*base, *overrides)
+		if err != nil {
+			return err
+		}
+
+		var (
+			provider *contacts.Provider
+			found    bool
+		)
+		switch {
+		case *cname != "":
+			provider, found = directory.LookupCNAME(*cname)
+		case *mailDomain != "" || *mx != "":
+			provider, found = directory.LookupMailbox(*mailDomain, splitList(*mx))
+		case *host != "":
+			provider, _, found = directory.LookupPlatform(*host)
+		default:
+			provider, found = directory.Lookup(contacts.Query{Kind: *kind, ASN: *asn, IANAID: *iana, Name: *name, Country: *country})
+		}
+		if !found {
+			return errors.New("no matching provider")
+		}
+		return printProvider(stdout, provider, *asJSON)
+
+	case "set":
+		return runContactsSet(flags, args, overrides, stdout)
+
+	case "remove":
+		file := flags.String("file", "", "file to edit (default: overrides file)")
+		id, err := parseWithID(flags, args)
+		if err != nil {
+			return fmt.Errorf("usage: takedown contacts remove <id>: %w", err)
+		}
+		path := editablePath(*file, *overrides)
+		directory, err := loadEditableDirectory(path)
+		if err != nil {
+			return err
+		}
+
+		removed := directory.Remove(id)
+		// No arquivo de overrides a entrada vira "disabled" para esconder também a da base
+		if *file == "" {
+			if err := directory.Upsert(contacts.Provider{ID: id, Disabled: true}); err != nil {
+				return err
+			}
+		} else if !removed {
+			return fmt.Errorf("provider %q not found in %s", id, path)
+		}
+		if err := directory.Save(path); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(stdout, "removed %s (%s, version %d)\n", id, path, directory.Version)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown contacts command %q", command)
+	}
+}
+
+// runContactsSet cria ou atualiza uma entrada no arquivo editável
+func runContactsSet(flags *flag.FlagSet, args []string, overrides *string, stdout io.Writer) error {
+	file := flags.String("file", "", "file to edit (default: overrides file)")
+	name := flags.String("name", "", "provider name")
+	kinds := flags.String("kind", "", "comma separated kinds (registrar, hosting, cdn, cert, mailbox, platform)")
+	email := flags.String("email", "", "abuse email")
+	phone := flags.String("phone", "", "abuse phone")
+	webform := flags.String("webform", "", "abuse webform URL")
+	api := flags.String("api", "", "abuse API endpoint")
+	channel := flags.String("channel", "", "preferred channel (email, webform, api)")
+	language := flags.String("language", "", "preferred language")
+	notes := flags.String("notes", "", "free-form notes")
+	asns := flags.String("asn", "", "comma separated ASNs")
+	iana := flags.String("iana", "", "comma separated IANA IDs")
+	pattern := flags.String("pattern", "", "comma separated name regexes")
+	cnames := flags.String("cname", "", "comma separated CNAME substrings")
+	countries := flags.String("country", "", "comma separated countries (ISO alpha-2)")
+	mailDomains := flags.String("mail-domain", "", "comma separated email domains (webmail)")
+	mx := flags.String("mx", "", "comma separated MX suffixes")
+	hosts := flags.String("host", "", "comma separated platform host suffixes")
+	id, err := parseWithID(flags, args)
+	if err != nil {
+		return fmt.Errorf("usage: takedown contacts set <id> [flags]: %w", err)
+	}
+
+	asnList, err := parseIntList(*asns)
+	if err != nil {
+		return fmt.Errorf("invalid -asn: %w", err)
+	}
+	ianaList, err := parseIntList(*iana)
+	if err != nil {
+		return fmt.Errorf("invalid -iana: %w", err)
+	}
+
+	provider := contacts.Provider{
+		ID:               id,
+		Name:             *name,
+		Kinds:            splitList(*kinds),
+		Match:            contacts.ProviderMatch{ASNs: asnList, IANAIDs: ianaList, Names: splitList(*pattern), CNAMEs: splitList(*cnames), Countries: splitList(*countries), Domains: splitList(*mailDomains), MX: splitList(*mx), Hosts: splitList(*hosts)},
+		Abuse:            contacts.ProviderAbuse{Email: *email, Phone: *phone, Webform: *webform, API: *api},
+		PreferredChannel: *channel,
+		Language:         *language,
+		Notes:            *notes,
+		LastVerified:     time.Now().UTC().Format("2006-01-02"),
+	}
+
+	path := editablePath(*file, *overrides)
+	directory, err := loadEditableDirectory(path)
+	if err != nil {
+		return err
+	}
+	if err := directory.Upsert(provider); err != nil {
+		return err
+	}
+	if err := directory.Save(path); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(stdout, "saved %s (%s, version %d)\n", provider.ID, path, directory.Version)
+	return nil
+}
+
+// parseWithID aceita o ID antes ou depois das flags ("set godaddy -email ...")
+func parseWithID(flags *flag.FlagSet, args []string) (string, error) {
+	var id string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		id, args = args[0], args[1:]
+	}
+	if err := flags.Parse(args); err != nil {
+		return "", err
+	}
+	if id == "" && flags.NArg() == 1 {
+		id = flags.Arg(0)
+	} else if flags.NArg() > 0 {
+		return "", fmt.Errorf("unexpected arguments %v", flags.Args())
+	}
+	if id == "" {
+		return "", errors.New("missing provider id")
+	}
+	return id, nil
+}
+
+// loadContactDirectory carrega a base e aplica o arquivo de overrides, se houver
+func loadContactDirectory(basePath, overridesPath string) (*contacts.Directory, error) {
+	overridesPath = optionalPath(overridesPath, defaultOverridesPath)
+	if overridesPath == "" {
+		return contacts.LoadWithOverrides(basePath)
+	}
+	return contacts.LoadWithOverrides(basePath, overridesPath)
+}
+
+// loadEditableDirectory carrega o arquivo a ser editado (vazio se ainda não existir)
+func loadEditableDirectory(path string) (*contacts.Directory, error) {
+	directory, err := contacts.LoadDirectory(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return contacts.ParseDirectory([]byte("version: 0\nproviders: []\n"))
+	}
+	return directory, err
+}
+
+// editablePath decide qual arquivo será alterado por set/remove
+func editablePath(file, overrides string) string {
+	if file != "" {
+		return file
+	}
+	if overrides != "" {
+		return overrides
+	}
+	return defaultOverridesPath
+}
+
+// printProviders imprime uma tabela (ou JSON) com os provedores
+func printProviders(stdout io.Writer, providers []contacts.Provider, asJSON bool) error {
+	if asJSON {
+		return json.NewEncoder(stdout).Encode(providers)
+	}
+
+	writer := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "ID\tNAME\tKINDS\tEMAIL\tCHANNEL\tVERIFIED")
+	for _, provider := range providers {
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			provider.ID, provider.Name, strings.Join(provider.Kinds, ","),
+			provider.Abuse.Email, provider.PreferredChannel, provider.LastVerified)
+	}
+	return writer.Flush()
+}
+
+// printProvider imprime os detalhes de um provedor
+func printProvider(stdout io.Writer, provider *contacts.Provider, asJSON bool) error {
+	if asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(provider)
+	}
+
+	writer := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	rows := [][2]string{
+		{"id", provider.ID},
+		{"name", provider.Name},
+		{"kinds", strings.Join(provider.Kinds, ", ")},
+		{"email", provider.Abuse.Email},
+		{"phone", provider.Abuse.Phone},
+		{"webform", provider.Abuse.Webform},
+		{"api", provider.Abuse.API},
+		{"channel", provider.PreferredChannel},
+		{"language", provider.Language},
+		{"notes", provider.Notes},
+		{"last_verified", provider.LastVerified},
+	}
+	for _, row := range rows {
+		if row[1] != "" {
+			_, _ = fmt.Fprintf(writer, "%s:\t%s\n", row[0], row[1])
+		}
+	}
+	for _, contact := range provider.Escalation {
+		_, _ = fmt.Fprintf(writer, "escalation:\t%s %s%s\n", contact.Name, contact.Email, contact.Webform)
+	}
+	return writer.Flush()
+}
+
+// splitList separa uma lista por vírgulas, ignorando itens vazios
+func splitList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// parseIntList converte uma lista separada por vírgulas em inteiros
+func parseIntList(value string) ([]int, error) {
+	var result []int
+	for _, item := range splitList(value) {
+		n, err := strconv.Atoi(item)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, n)
+	}
+	return result, nil
+}
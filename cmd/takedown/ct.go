@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/cti-team/takedown/internal/brand"
+	"github.com/cti-team/takedown/internal/ctwatch"
+	"github.com/cti-team/takedown/pkg/defang"
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// defaultCTPath é a configuração das fontes de Certificate Transparency
+const defaultCTPath = "configs/ct/watcher.yaml"
+
+// runCT implementa "takedown ct": acompanha logs de CT e abre casos para domínios parecidos com as marcas
+func runCT(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("ct", flag.ContinueOnError)
+	configPath := flags.String("config", defaultCTPath, "CT watcher config")
+	once := flags.Bool("once", false, "poll the sources once and print matches instead of opening cases")
+	asJSON := flags.Bool("json", false, "print JSON output (with -once)")
+	options := addMachineFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	brandConfig, err := options.loadBrands()
+	if err != nil {
+		return err
+	}
+	if len(brandConfig.Brands) == 0 {
+		return errors.New("no protected brands configured (see " + defaultBrandsPath + ")")
+	}
+	config, err := ctwatch.LoadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	if len(config.Logs) == 0 {
+		return errors.New("no CT logs configured (see " + defaultCTPath + ")")
+	}
+
+	detector := brand.NewDetector(brandConfig)
+	watcher, err := ctwatch.NewWatcherFromConfig(config, detector)
+	if err != nil {
+		return err
+	}
+
+	if *once {
+		iocs, err := watcher.Poll(context.Background())
+		if printErr := printCTMatches(stdout, iocs, *asJSON); printErr != nil {
+			return printErr
+		}
+		return err
+	}
+
+	machine, err := buildMachine(options)
+	if err != nil {
+		return err
+	}
+	machine.Start()
+	defer machine.Stop()
+
+	watcher.SetSink(machine.ProcessIOC)
+	watcher.Start()
+	defer watcher.Stop()
+
+	_, _ = fmt.Fprintln(stdout, "CT watcher running, press Ctrl+C to stop")
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	<-signals
+	return nil
+}
+
+// printCTMatches imprime os domínios encontrados (defanged) com a marca e o score
+func printCTMatches(stdout io.Writer, iocs []*models.IOC, asJSON bool) error {
+	if asJSON {
+		return json.NewEncoder(stdout).Encode(iocs)
+	}
+
+	writer := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "DOMAIN\tBRAND\tSCORE\tISSUED")
+	for _, ioc := range iocs {
+		score := 0.0
+		if ioc.BrandSimilarity != nil {
+			score = ioc.BrandSimilarity.Score
+		}
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%.2f\t%s\n",
+			defang.Domain(ioc.Value), ioc.GetBrand(), score, ioc.FirstSeen.Format("2006-01-02 15:04"))
+	}
+	return writer.Flush()
+}
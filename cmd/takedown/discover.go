@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/cti-team/takedown/internal/discovery"
+	"github.com/cti-team/takedown/pkg/defang"
+)
+
+const (
+	// defaultBrandsPath lista as marcas protegidas e os domínios oficiais
+	defaultBrandsPath = "configs/brands/protected.yaml"
+	// defaultDiscoveryPath é a configuração do job de descoberta, usada se existir
+	defaultDiscoveryPath = "configs/discovery/discovery.yaml"
+)
+
+// runDiscover implementa "takedown discover": gera permutações dos domínios protegidos e,
+// conforme as flags, resolve, coleta evidências ou roda como job periódico abrindo casos
+func runDiscover(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("discover", flag.ContinueOnError)
+	configPath := flags.String("config", "", "discovery config (default: "+defaultDiscoveryPath+" if present)")
+	brandName := flags.String("brand", "", "only generate candidates for this brand")
+	techniques := flags.String("techniques", "", "comma separated techniques (default: all from config)")
+	resolve := flags.Bool("resolve", false, "resolve candidates and print only live domains")
+	collect := flags.Bool("collect", false, "resolve, collect evidence and score live domains")
+	daemon := flags.Bool("daemon", false, "run periodically and open triage cases for suspicious hits")
+	asJSON := flags.Bool("json", false, "print JSON output")
+	options := addMachineFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	brandConfig, err := options.loadBrands()
+	if err != nil {
+		return err
+	}
+	config, err := loadDiscoveryConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	if list := splitList(*techniques); len(list) > 0 {
+		config.Techniques = list
+	}
+
+	brands := brandConfig.Brands
+	if *brandName != "" {
+		brands = nil
+		for _, protected := range brandConfig.Brands {
+			if strings.EqualFold(protected.Name, *brandName) {
+				brands = append(brands, protected)
+			}
+		}
+	}
+	if len(brands) == 0 {
+		return errors.New("no protected brands configured (see " + defaultBrandsPath + ")")
+	}
+
+	job := discovery.NewJob(config, brands)
+	scorer, err := options.loadScorer()
+	if err != nil {
+		return err
+	}
+	job.SetScorer(scorer)
+	switch {
+	case *daemon:
+		return runDiscoveryDaemon(job, options, stdout)
+	case *collect:
+		collector, err := newCollector(options)
+		if err != nil {
+			return err
+		}
+		job.SetCollector(collector)
+		report, err := job.RunOnce(context.Background())
+		if err != nil {
+			return err
+		}
+		return printHits(stdout, report.Hits, *asJSON)
+	case *resolve:
+		return printHits(stdout, job.Resolve(context.Background(), job.Candidates()), *asJSON)
+	default:
+		return printCandidates(stdout, job.Candidates(), *asJSON)
+	}
+}
+
+// runDiscoveryDaemon roda o job junto com a state machine, que recebe os casos, até SIGINT/SIGTERM
+func runDiscoveryDaemon(job *discovery.Job, options *machineOptions, stdout io.Writer) error {
+	collector, err := newCollector(options)
+	if err != nil {
+		return err
+	}
+	machine, err := buildMachine(options)
+	if err != nil {
+		return err
+	}
+	machine.Start()
+	defer machine.Stop()
+
+	job.SetCollector(collector)
+	job.SetSink(machine.ProcessIOC)
+	job.Start()
+	defer job.Stop()
+
+	_, _ = fmt.Fprintln(stdout, "discovery job running, press Ctrl+C to stop")
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	<-signals
+	return nil
+}
+
+// loadDiscoveryConfig carrega a configuração indicada, a padrão se existir, ou os valores padrão
+func loadDiscoveryConfig(path string) (discovery.Config, error) {
+	if path == "" {
+		if _, err := os.Stat(defaultDiscoveryPath); err != nil {
+			return discovery.DefaultConfig(), nil
+		}
+		path = defaultDiscoveryPath
+	}
+	return discovery.LoadConfig(path)
+}
+
+// printCandidates imprime os domínios gerados (defanged)
+func printCandidates(stdout io.Writer, candidates []discovery.Candidate, asJSON bool) error {
+	if asJSON {
+		return json.NewEncoder(stdout).Encode(candidates)
+	}
+
+	writer := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "DOMAIN\tUNICODE\tTECHNIQUE\tBRAND\tORIGINAL")
+	for _, candidate := range candidates {
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n",
+			defang.Domain(candidate.Domain), defang.Domain(candidate.UnicodeHost),
+			candidate.Technique, candidate.Brand, defang.Domain(candidate.Original))
+	}
+	return writer.Flush()
+}
+
+// printHits imprime os candidatos vivos com endereços e, se coletados, o score
+func printHits(stdout io.Writer, hits []discovery.Hit, asJSON bool) error {
+	if asJSON {
+		return json.NewEncoder(stdout).Encode(hits)
+	}
+
+	writer := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "DOMAIN\tTECHNIQUE\tBRAND\tADDRESSES\tSCORE\tSUSPICIOUS")
+	for _, hit := range hits {
+		score := "-"
+		if hit.Risk != nil {
+			score = fmt.Sprintf("%d", hit.Risk.Score)
+		}
+		if hit.Error != "" {
+			score = "error: " + hit.Error
+		}
+		addresses := make([]string, 0, len(hit.Addresses))
+		for _, address := range hit.Addresses {
+			addresses = append(addresses, defang.IP(address))
+		}
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%v\n",
+			defang.Domain(hit.Domain), hit.Technique, hit.Brand, strings.Join(addresses, ","), score, hit.Suspicious)
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/cti-team/takedown/internal/evidence"
+	"github.com/cti-team/takedown/internal/redact"
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// defaultRedactionPath é a configuração de redação usada se existir (senão, as regras padrão)
+const defaultRedactionPath = "configs/redaction/redaction.yaml"
+
+// runEvidence implementa "takedown evidence <keygen|export|verify>"
+func runEvidence(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("usage: takedown evidence <keygen|export|verify> [flags]")
+	}
+
+	command, args := args[0], args[1:]
+	flags := flag.NewFlagSet("evidence "+command, flag.ContinueOnError)
+
+	switch command {
+	case "keygen":
+		out := flags.String("out", "evidence-signing", "key file prefix (<prefix>.key and <prefix>.pub)")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		return generateEvidenceKey(stdout, *out)
+
+	case "export":
+		keyPath := flags.String("key", "", "Ed25519 private key (PEM)")
+		packPath := flags.String("pack", "", "evidence pack JSON")
+		requestPath := flags.String("request", "", "takedown request JSON (optional)")
+		redaction := flags.String("redaction", "", "redaction rules (default: "+defaultRedactionPath+" if present)")
+		out := flags.String("out", "", "bundle file (default: evidence-<id>.zip)")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if *keyPath == "" || *packPath == "" {
+			return errors.New("usage: takedown evidence export -key <private.key> -pack <pack.json> [-request <request.json>] [-out <bundle.zip>]")
+		}
+		redactor, err := loadRedactor(*redaction)
+		if err != nil {
+			return err
+		}
+		return exportEvidence(stdout, *keyPath, *packPath, *requestPath, *out, redactor)
+
+	case "verify":
+		keyPath := flags.String("key", "", "Ed25519 public key (PEM)")
+		asJSON := flags.Bool("json", false, "print the verified manifest as JSON")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if *keyPath == "" || flags.NArg() != 1 {
+			return errors.New("usage: takedown evidence verify -key <public.pub> <bundle.zip>")
+		}
+		return verifyEvidence(stdout, *keyPath, flags.Arg(0), *asJSON)
+
+	default:
+		return fmt.Errorf("unknown evidence command %q", command)
+	}
+}
+
+// generateEvidenceKey grava um par de chaves novo; a chave privada fica legível só pelo dono
+func generateEvidenceKey(stdout io.Writer, prefix string) error {
+	public, private, err := evidence.GenerateSigningKey()
+	if err != nil {
+		return err
+	}
+	privatePEM, err := evidence.MarshalPrivateKey(private)
+	if err != nil {
+		return err
+	}
+	publicPEM, err := evidence.MarshalPublicKey(public)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(prefix+".key", privatePEM, 0o600); err != nil {
+		return fmt.Errorf("failed to write private key: %w", err)
+	}
+	if err := os.WriteFile(prefix+".pub", publicPEM, 0o644); err != nil { // #nosec G306 -- chave pública
+		return fmt.Errorf("failed to write public key: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(stdout, "key %s written to %s.key and %s.pub\n", evidence.KeyID(public), prefix, prefix)
+	return nil
+}
+
+// exportEvidence gera o bundle assinado de um pack salvo em JSON
+func exportEvidence(stdout io.Writer, keyPath, packPath, requestPath, out string, redactor *redact.Redactor) error {
+	key, err := evidence.LoadSigningKey(keyPath)
+	if err != nil {
+		return err
+	}
+
+	var pack models.EvidencePack
+	if err := readJSONFile(packPath, &pack); err != nil {
+		return err
+	}
+	var request *models.TakedownRequest
+	if requestPath != "" {
+		request = &models.TakedownRequest{}
+		if err := readJSONFile(requestPath, request); err != nil {
+			return err
+		}
+	}
+
+	exporter := evidence.NewBundleExporter(key)
+	exporter.SetRedactor(redactor)
+	data, manifest, err := exporter.Bundle(&pack, request)
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		out = evidence.BundleName(&pack)
+	}
+	if err := os.WriteFile(out, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(stdout, "%s: %d files signed with key %s\n", filepath.Base(out), len(manifest.Files), manifest.KeyID)
+	return nil
+}
+
+// verifyEvidence confere assinatura e hashes de um bundle
+func verifyEvidence(stdout io.Writer, keyPath, bundlePath string, asJSON bool) error {
+	key, err := evidence.LoadVerifyKey(keyPath)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(bundlePath) // #nosec G304 -- caminho vem da configuração do operador
+	if err != nil {
+		return fmt.Errorf("failed to read bundle: %w", err)
+	}
+
+	manifest, err := evidence.VerifyBundle(data, key)
+	if err != nil {
+		return fmt.Errorf("%s: verification FAILED: %w", bundlePath, err)
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(manifest)
+	}
+	_, _ = fmt.Fprintf(stdout, "%s: OK (%s, key %s, %d files, created %s)\n",
+		bundlePath, manifest.Defanged, manifest.KeyID, len(manifest.Files),
+		manifest.CreatedAt.Format("2006-01-02 15:04:05 UTC"))
+	return nil
+}
+
+// loadRedactor carrega as regras de redação do arquivo dado ou do padrão do repositório
+func loadRedactor(path string) (*redact.Redactor, error) {
+	path = optionalPath(path, defaultRedactionPath)
+	if path == "" {
+		return redact.Default(), nil
+	}
+	config, err := redact.LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return redact.NewRedactor(config)
+}
+
+// readJSONFile decodifica um arquivo JSON
+func readJSONFile(path string, value interface{}) error {
+	data, err := os.ReadFile(path) // #nosec G304 -- caminho vem da configuração do operador
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, value); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/cti-team/takedown/internal/brand"
+	"github.com/cti-team/takedown/internal/feeds"
+	"github.com/cti-team/takedown/pkg/defang"
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// defaultFeedsPath é a configuração dos feeds de phishing
+const defaultFeedsPath = "configs/feeds/feeds.yaml"
+
+// runFeeds implementa "takedown feeds": lê os feeds de phishing e abre casos para as URLs novas
+func runFeeds(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("feeds", flag.ContinueOnError)
+	configPath := flags.String("config", defaultFeedsPath, "phishing feeds config")
+	once := flags.Bool("once", false, "read the feeds once and print the URLs without opening cases or saving state")
+	asJSON := flags.Bool("json", false, "print JSON output (with -once)")
+	options := addMachineFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	config, err := feeds.LoadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	if len(config.Feeds) == 0 {
+		return errors.New("no feeds configured (see " + defaultFeedsPath + ")")
+	}
+	brandConfig, err := options.loadBrands()
+	if err != nil {
+		return err
+	}
+
+	statePath := config.StatePath
+	if *once {
+		statePath = ""
+	}
+	store, err := feeds.LoadState(statePath)
+	if err != nil {
+		return err
+	}
+	poller, err := feeds.NewPoller(config, store)
+	if err != nil {
+		return err
+	}
+	if config.BrandsOnly {
+		if len(brandConfig.Brands) == 0 {
+			return errors.New("brands_only is set but no protected brands are configured (see " + defaultBrandsPath + ")")
+		}
+		poller.SetFilter(feeds.NewBrandFilter(brand.NewDetector(brandConfig), brandConfig.Brands))
+	}
+
+	if *once {
+		iocs, err := poller.Poll(context.Background())
+		if printErr := printFeedIndicators(stdout, iocs, *asJSON); printErr != nil {
+			return printErr
+		}
+		return err
+	}
+
+	machine, err := buildMachine(options)
+	if err != nil {
+		return err
+	}
+	machine.Start()
+	defer machine.Stop()
+
+	poller.SetSink(machine.ProcessIOC)
+	poller.Start()
+	defer poller.Stop()
+
+	_, _ = fmt.Fprintf(stdout, "Feed poller running for %d feeds, press Ctrl+C to stop\n", len(config.Feeds))
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	<-signals
+	return nil
+}
+
+// printFeedIndicators imprime as URLs lidas (defanged) com a origem, a confiança e as tags
+func printFeedIndicators(stdout io.Writer, iocs []*models.IOC, asJSON bool) error {
+	if asJSON {
+		return json.NewEncoder(stdout).Encode(iocs)
+	}
+
+	writer := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "SOURCE\tCONFIDENCE\tVALUE\tTAGS\tFIRST SEEN")
+	for _, ioc := range iocs {
+		_, _ = fmt.Fprintf(writer, "%s\t%d\t%s\t%s\t%s\n", ioc.Source, ioc.Confidence, defang.Defang(ioc.Value),
+			strings.Join(ioc.Tags, ","), ioc.FirstSeen.Format("2006-01-02 15:04"))
+	}
+	return writer.Flush()
+}
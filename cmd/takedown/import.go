@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cti-team/takedown/internal/bulk"
+	"github.com/cti-team/takedown/internal/state"
+	"github.com/cti-team/takedown/pkg/defang"
+)
+
+// bulkImportPath é a rota da API que recebe arquivos CSV/JSONL
+const bulkImportPath = "/api/v1/iocs/import"
+
+// runImport implementa "takedown import"
+func runImport(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := flags.String("format", "", "file format: csv or jsonl (default: from the file extension)")
+	api := flags.String("api", "", "takedown API base URL (e.g. http://localhost:8080) that opens the cases")
+	dryRun := flags.Bool("dry-run", false, "validate and preview the routing of each row without opening cases")
+	asJSON := flags.Bool("json", false, "print JSON output")
+	options := addMachineFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: takedown import [-format csv|jsonl] [-dry-run] [-api <url>] [-json] <file>")
+	}
+	if *api == "" && !*dryRun {
+		return errors.New("use -api <url> to open cases through the daemon or -dry-run to preview locally")
+	}
+
+	var err error
+	path := flags.Arg(0)
+	if *format == "" {
+		if *format, err = bulk.DetectFormat(path); err != nil {
+			return err
+		}
+	}
+	data, err := os.ReadFile(path) // #nosec G304 -- caminho vem da configuração do operador
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var report *bulk.Report
+	if *api != "" {
+		report, err = postImport(*api, data, *format, *dryRun)
+	} else {
+		report, err = previewImport(data, *format, options)
+	}
+	if err != nil {
+		return err
+	}
+	return printImportReport(stdout, report, *asJSON)
+}
+
+// newBulkImporter liga a deduplicação e a prévia de roteamento à pipeline
+func newBulkImporter(machine *state.Machine) *bulk.Importer {
+	importer := bulk.NewImporter()
+	importer.SetCaseLookup(machine.OpenCase)
+	importer.SetPlanner(machine.PreviewRouting)
+	return importer
+}
+
+// previewImport valida o arquivo e calcula o roteamento localmente, sem abrir casos
+func previewImport(data []byte, format string, options *machineOptions) (*bulk.Report, error) {
+	rows, err := bulk.ReadRows(bytes.NewReader(data), format)
+	if err != nil {
+		return nil, err
+	}
+	machine, err := buildMachine(options)
+	if err != nil {
+		return nil, err
+	}
+	return newBulkImporter(machine).Import(context.Background(), rows, true), nil
+}
+
+// postImport envia o arquivo para o endpoint de importação em lote
+func postImport(api string, data []byte, format string, dryRun bool) (*bulk.Report, error) {
+	url := strings.TrimSuffix(api, "/") + bulkImportPath + "?format=" + format
+	if dryRun {
+		url += "&dry_run=true"
+	}
+	// a prévia de roteamento faz enrichment de cada linha; arquivos grandes demoram
+	client := &http.Client{Timeout: 10 * time.Minute}
+	resp, err := postAPI(client, url, "application/octet-stream", bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to post file: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&failure)
+		return nil, fmt.Errorf("API returned %d: %s", resp.StatusCode, failure.Error)
+	}
+	var report bulk.Report
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return nil, fmt.Errorf("failed to decode import report: %w", err)
+	}
+	return &report, nil
+}
+
+// printImportReport imprime o resultado de cada linha (defanged) e o resumo
+func printImportReport(stdout io.Writer, report *bulk.Report, asJSON bool) error {
+	if asJSON {
+		return json.NewEncoder(stdout).Encode(report)
+	}
+
+	writer := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "LINE\tSTATUS\tTYPE\tVALUE\tROUTING / REASON")
+	for _, row := range report.Rows {
+		value := row.Value
+		if value == "" {
+			value = row.Input
+		}
+		detail := row.Reason
+		if len(row.Routing) > 0 {
+			targets := make([]string, 0, len(row.Routing))
+			for _, target := range row.Routing {
+				targets = append(targets, fmt.Sprintf("%s %s (%s)", target.Action, target.Entity, target.Type))
+			}
+			detail = strings.Join(targets, "; ")
+		}
+		_, _ = fmt.Fprintf(writer, "%d\t%s\t%s\t%s\t%s\n", row.Line, row.Status, row.Type, defang.Defang(value), detail)
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+
+	mode := "import"
+	if report.DryRun {
+		mode = "dry run"
+	}
+	_, _ = fmt.Fprintf(stdout, "%s: %d rows, %d accepted, %d submitted, %d duplicate, %d rejected, %d failed\n", mode, len(report.Rows),
+		report.Summary[bulk.StatusAccepted], report.Summary[bulk.StatusSubmitted], report.Summary[bulk.StatusDuplicate],
+		report.Summary[bulk.StatusRejected], report.Summary[bulk.StatusFailed])
+	return nil
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/cti-team/takedown/internal/brand"
+	"github.com/cti-team/takedown/internal/connectors/hosting"
+	"github.com/cti-team/takedown/internal/connectors/mailbox"
+	"github.com/cti-team/takedown/internal/connectors/platform"
+	"github.com/cti-team/takedown/internal/connectors/registrar"
+	"github.com/cti-team/takedown/internal/contacts"
+	"github.com/cti-team/takedown/internal/enrichment"
+	"github.com/cti-team/takedown/internal/evidence"
+	"github.com/cti-team/takedown/internal/redact"
+	"github.com/cti-team/takedown/internal/routing"
+	"github.com/cti-team/takedown/internal/scoring"
+	"github.com/cti-team/takedown/internal/state"
+	"github.com/cti-team/takedown/pkg/ipdb"
+)
+
+// defaultProfilesPath são os perfis de coleta de evidências usados se existirem (senão, os embutidos)
+const defaultProfilesPath = "configs/evidence/profiles.yaml"
+
+// defaultRoutingPath são as regras de roteamento cujas condições de país são aplicadas se existirem
+const defaultRoutingPath = "configs/routing/rules.yaml"
+
+// defaultEvidenceDir é a raiz do evidence store (HAR, screenshots e amostras de cada caso)
+const defaultEvidenceDir = "data/evidence"
+
+// defaultSMTPPath é o servidor SMTP dos connectors de email, usado se existir
+const defaultSMTPPath = "configs/smtp.yaml"
+
+// defaultScoringPath são os pesos do score de risco usados se existirem (senão, os padrão)
+const defaultScoringPath = "configs/scoring/weights.yaml"
+
+// machineOptions são as flags da pipeline comuns a todos os comandos que abrem casos
+type machineOptions struct {
+	brands        string
+	contacts      string
+	evidenceDir   string
+	ipdb          string
+	overrides     string
+	profiles      string
+	redaction     string
+	routing       string
+	scoring       string
+	signingKey    string
+	smtp          string
+	minConfidence int
+}
+
+// addMachineFlags registra as flags da pipeline no flag set do comando
+func addMachineFlags(flags *flag.FlagSet) *machineOptions {
+	options := &machineOptions{}
+	flags.StringVar(&options.brands, "brands", "", "protected brands file (default: "+defaultBrandsPath+" if present)")
+	flags.StringVar(&options.contacts, "contacts", "", "base contact directory file (default: embedded)")
+	flags.StringVar(&options.overrides, "contacts-overrides", "", "operator contact overrides (default: "+defaultOverridesPath+" if present)")
+	flags.StringVar(&options.evidenceDir, "evidence-dir", defaultEvidenceDir, "evidence store directory (HAR and artifacts of each case)")
+	flags.StringVar(&options.ipdb, "ipdb", "", "comma separated offline IP-to-ASN/GeoIP datasets (iptoasn TSV or .mmdb)")
+	flags.StringVar(&options.routing, "routing", "", "routing rules with country conditions (default: "+defaultRoutingPath+" if present)")
+	flags.StringVar(&options.profiles, "profiles", "", "evidence collection profiles (default: "+defaultProfilesPath+" if present)")
+	flags.StringVar(&options.redaction, "redaction", "", "redaction rules (default: "+defaultRedactionPath+" if present)")
+	flags.StringVar(&options.scoring, "scoring", "", "risk scoring weights (default: "+defaultScoringPath+" if present)")
+	flags.StringVar(&options.signingKey, "signing-key", "", "Ed25519 private key (PEM) to attach signed evidence bundles to submissions")
+	flags.StringVar(&options.smtp, "smtp", "", "SMTP server of the email connectors (default: "+defaultSMTPPath+" if present; without it nothing is submitted)")
+	flags.IntVar(&options.minConfidence, "min-confidence", 0, "close cases at triage when the IOC confidence is below this value (0-100)")
+	return options
+}
+
+// optionalPath retorna o caminho dado ou, se vazio, o padrão do repositório quando ele existe
+func optionalPath(path, defaultPath string) string {
+	if path != "" {
+		return path
+	}
+	if _, err := os.Stat(defaultPath); err != nil {
+		return ""
+	}
+	return defaultPath
+}
+
+// loadBrands carrega as marcas protegidas (sem arquivo, nenhuma marca)
+func (o *machineOptions) loadBrands() (brand.Config, error) {
+	path := optionalPath(o.brands, defaultBrandsPath)
+	if path == "" {
+		return brand.DefaultConfig(), nil
+	}
+	return brand.LoadConfig(path)
+}
+
+// loadScorer carrega os pesos do score de risco; um arquivo inválido impede a inicialização
+func (o *machineOptions) loadScorer() (*scoring.Engine, error) {
+	path := optionalPath(o.scoring, defaultScoringPath)
+	if path == "" {
+		return scoring.NewEngine(scoring.DefaultConfig()), nil
+	}
+	config, err := scoring.LoadConfig(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return scoring.NewEngine(config), nil
+}
+
+// newCollector cria o coletor de evidências com a configuração do operador
+func newCollector(options *machineOptions) (*evidence.Collector, error) {
+	store, err := evidence.NewStore(options.evidenceDir)
+	if err != nil {
+		return nil, err
+	}
+
+	collector := evidence.NewCollector()
+	collector.SetStore(store)
+	if path := optionalPath(options.profiles, defaultProfilesPath); path != "" {
+		profiles, err := evidence.LoadProfiles(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		collector.SetProfiles(profiles)
+	}
+	return collector, nil
+}
+
+// bundleConnector é um connector de email que anexa o evidence bundle assinado
+type bundleConnector interface {
+	state.Connector
+	SetBundleExporter(exporter *evidence.BundleExporter)
+}
+
+// registerConnectors registra os connectors de email; sem SMTP configurado, os casos param em submit
+func registerConnectors(machine *state.Machine, options *machineOptions, redactor *redact.Redactor) error {
+	path := optionalPath(options.smtp, defaultSMTPPath)
+	if path == "" {
+		return nil
+	}
+	smtpConfig, err := registrar.LoadSMTPConfig(path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	var exporter *evidence.BundleExporter
+	if options.signingKey != "" {
+		key, err := evidence.LoadSigningKey(options.signingKey)
+		if err != nil {
+			return fmt.Errorf("%s: %w", options.signingKey, err)
+		}
+		exporter = evidence.NewBundleExporter(key)
+		exporter.SetRedactor(redactor)
+	}
+
+	connectors := []bundleConnector{
+		registrar.NewGoDaddyConnector(smtpConfig),
+		hosting.NewGenericHostingConnector(smtpConfig),
+		mailbox.NewProviderConnector(smtpConfig),
+		platform.NewSiteConnector(smtpConfig),
+	}
+	for _, connector := range connectors {
+		connector.SetBundleExporter(exporter)
+		machine.RegisterConnector(connector)
+	}
+	return nil
+}
+
+// buildMachine monta a state machine usada por todos os comandos que abrem casos
+func buildMachine(options *machineOptions) (*state.Machine, error) {
+	if options.minConfidence < 0 || options.minConfidence > 100 {
+		return nil, fmt.Errorf("-min-confidence %d out of range 0-100", options.minConfidence)
+	}
+	brandConfig, err := options.loadBrands()
+	if err != nil {
+		return nil, err
+	}
+	redactor, err := loadRedactor(options.redaction)
+	if err != nil {
+		return nil, err
+	}
+	scorer, err := options.loadScorer()
+	if err != nil {
+		return nil, err
+	}
+	collector, err := newCollector(options)
+	if err != nil {
+		return nil, err
+	}
+	// Os overrides valem para o enrichment e para os connectors, que consultam contacts.Default()
+	directory, err := loadContactDirectory(options.contacts, options.overrides)
+	if err != nil {
+		return nil, err
+	}
+	contacts.SetDefault(directory)
+	enricher := enrichment.NewService()
+	enricher.SetDirectory(directory)
+	if paths := splitList(options.ipdb); len(paths) > 0 {
+		datasets, err := ipdb.OpenAll(paths)
+		if err != nil {
+			return nil, err
+		}
+		enricher.SetIPDatabase(datasets)
+	}
+	router := routing.NewEngine()
+	if path := optionalPath(options.routing, defaultRoutingPath); path != "" {
+		if err := router.LoadConditions(path); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	machine := state.NewMachine(collector, enricher, router)
+	machine.SetBrandDetector(brand.NewDetector(brandConfig))
+	machine.SetMinConfidence(options.minConfidence)
+	machine.SetRedactor(redactor)
+	machine.SetScorer(scorer)
+	if err := registerConnectors(machine, options, redactor); err != nil {
+		return nil, err
+	}
+	return machine, nil
+}
@@ -3,10 +3,38 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"os"
+
+	"github.com/cti-team/takedown/pkg/defang"
 )
 
+// commands mapeia os subcomandos para suas implementações
+var commands = map[string]func(args []string, stdout io.Writer) error{
+	"contacts": runContacts,
+	"ct":       runCT,
+	"discover": runDiscover,
+	"evidence": runEvidence,
+	"feeds":    runFeeds,
+	"import":   runImport,
+	"misp":     runMISP,
+	"resume":   runResume,
+	"serve":    runServe,
+	"stix":     runSTIX,
+	"taxii":    runTAXII,
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		if run, ok := commands[os.Args[1]]; ok {
+			if err := run(os.Args[2:], os.Stdout); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	action := flag.String("action", "", "action to perform")
 	ioc := flag.String("ioc", "", "indicator of compromise")
 	tags := flag.String("tags", "", "comma separated list of tags")
@@ -15,5 +43,5 @@ func main() {
 	flag.Parse()
 
 	_, _ = fmt.Fprintf(os.Stdout, "takedown CLI placeholder\n")
-	_, _ = fmt.Fprintf(os.Stdout, "action=%s ioc=%s tags=%s case=%s daemon=%v\n", *action, *ioc, *tags, *caseID, *daemon)
+	_, _ = fmt.Fprintf(os.Stdout, "action=%s ioc=%s tags=%s case=%s daemon=%v\n", *action, defang.Defang(*ioc), *tags, *caseID, *daemon)
 }
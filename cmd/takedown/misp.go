@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/cti-team/takedown/internal/misp"
+	"github.com/cti-team/takedown/internal/state"
+	"github.com/cti-team/takedown/pkg/defang"
+)
+
+// defaultMISPPath é a configuração da integração com o MISP
+const defaultMISPPath = "configs/misp/misp.yaml"
+
+// runMISP implementa "takedown misp <import|watch>"
+func runMISP(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("usage: takedown misp <import|watch> [flags]")
+	}
+
+	command, args := args[0], args[1:]
+	flags := flag.NewFlagSet("misp "+command, flag.ContinueOnError)
+	configPath := flags.String("config", defaultMISPPath, "MISP integration config")
+
+	switch command {
+	case "import":
+		eventID := flags.String("event", "", "fetch the event from the MISP API instead of reading files")
+		asJSON := flags.Bool("json", false, "print JSON output")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if (*eventID == "") == (flags.NArg() == 0) {
+			return errors.New("usage: takedown misp import [-json] (-event <id> | <event.json>...)")
+		}
+		config, err := misp.LoadConfig(*configPath)
+		if err != nil {
+			return err
+		}
+		return importMISP(stdout, config, *eventID, flags.Args(), *asJSON)
+
+	case "watch":
+		options := addMachineFlags(flags)
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		config, err := misp.LoadConfig(*configPath)
+		if err != nil {
+			return err
+		}
+		return watchMISP(stdout, config, options)
+
+	default:
+		return fmt.Errorf("unknown misp command %q", command)
+	}
+}
+
+// importMISP mapeia eventos de arquivos (exports JSON) ou da API e mostra os IOCs, sem abrir casos
+func importMISP(stdout io.Writer, config misp.Config, eventID string, paths []string, asJSON bool) error {
+	var events []misp.Event
+	if eventID != "" {
+		client, err := misp.NewClientFromConfig(config)
+		if err != nil {
+			return err
+		}
+		event, err := client.Event(context.Background(), eventID)
+		if err != nil {
+			return err
+		}
+		events = append(events, *event)
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path) // #nosec G304 -- caminho vem da configuração do operador
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		parsed, err := misp.ParseEvents(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		events = append(events, parsed...)
+	}
+
+	result := misp.Map(events, config.OnlyToIDS)
+	if asJSON {
+		return json.NewEncoder(stdout).Encode(result)
+	}
+
+	writer := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "TYPE\tVALUE\tTAGS\tFIRST SEEN\tATTRIBUTE")
+	for _, ioc := range result.IOCs {
+		attribute := ""
+		if uuids := misp.AttributeUUIDs(ioc.IntelRefs); len(uuids) > 0 {
+			attribute = uuids[0]
+		}
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", ioc.Type, defang.Defang(ioc.Value),
+			strings.Join(ioc.Tags, ","), ioc.FirstSeen.Format("2006-01-02 15:04"), attribute)
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(stdout, "%d events, %d IOCs, %d skipped\n", result.Events, len(result.IOCs), len(result.Skipped))
+	for _, skipped := range result.Skipped {
+		_, _ = fmt.Fprintf(stdout, "  skipped %s (event %s): %s\n", skipped.Attribute, skipped.EventID, skipped.Reason)
+	}
+	return nil
+}
+
+// watchMISP abre casos para os eventos marcados no MISP e devolve o andamento como tags e sightings
+func watchMISP(stdout io.Writer, config misp.Config, options *machineOptions) error {
+	client, err := misp.NewClientFromConfig(config)
+	if err != nil {
+		return err
+	}
+
+	machine, err := buildMachine(options)
+	if err != nil {
+		return err
+	}
+
+	feedback := misp.NewFeedback(client, config.Feedback)
+	machine.AddStatusListener(func(change state.StatusChange) {
+		if err := feedback.StatusChanged(context.Background(), change.To, change.IntelRefs); err != nil {
+			log.Printf("MISP feedback for %s: %v", change.CaseID, err)
+		}
+	})
+	machine.Start()
+	defer machine.Stop()
+
+	poller := misp.NewPoller(client, config)
+	poller.SetSink(machine.ProcessIOC)
+	poller.Start()
+	defer poller.Stop()
+
+	_, _ = fmt.Fprintf(stdout, "MISP poller running for tags %s, press Ctrl+C to stop\n", strings.Join(config.Poll.Tags, ", "))
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	<-signals
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cti-team/takedown/internal/state"
+)
+
+// resumePath é a rota da API que devolve à pipeline casos parados em needs_manual
+const resumePath = "/api/v1/cases/resume"
+
+// runResume implementa "takedown resume": os casos vivem no daemon, então a retomada passa pela API
+func runResume(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("resume", flag.ContinueOnError)
+	api := flags.String("api", "http://localhost:8080", "takedown API base URL")
+	caseID := flags.String("case", "", "case waiting in needs_manual")
+	email := flags.String("email", "", "abuse contact set by the operator (source=manual)")
+	webform := flags.String("webform", "", "abuse webform the operator already submitted the report to; without -email the case moves to submitted")
+	reason := flags.String("reason", "", "why the operator chose this contact")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *caseID == "" || flags.NArg() != 0 {
+		return errors.New("usage: takedown resume [-api <url>] -case <id> [-email <address>] [-webform <url>] [-reason <text>]")
+	}
+	if *reason != "" && *email == "" && *webform == "" {
+		return errors.New("-reason needs -email or -webform; without a contact the case is routed again")
+	}
+
+	response, err := postResume(*api, state.ResumeRequest{CaseID: *caseID, Email: *email, Webform: *webform, Reason: *reason})
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(stdout, "case %s resumed: %s\n", response.CaseID, response.Status)
+	return nil
+}
+
+// postResume envia o pedido de retomada para o daemon
+func postResume(api string, resume state.ResumeRequest) (*state.ResumeResponse, error) {
+	body, err := json.Marshal(resume)
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := postAPI(client, strings.TrimSuffix(api, "/")+resumePath, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resume case: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&failure)
+		return nil, fmt.Errorf("API returned %d: %s", resp.StatusCode, failure.Error)
+	}
+	var response state.ResumeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode resume response: %w", err)
+	}
+	return &response, nil
+}
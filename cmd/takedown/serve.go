@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/cti-team/takedown/internal/bulk"
+	"github.com/cti-team/takedown/internal/state"
+	"github.com/cti-team/takedown/internal/stix"
+)
+
+// defaultListen só aceita conexões locais; expor a API na rede exige -listen explícito
+const defaultListen = "127.0.0.1:8080"
+
+// apiTokenEnv é a variável com o bearer token exigido pela API e enviado pelos comandos que a usam
+const apiTokenEnv = "TAKEDOWN_API_TOKEN"
+
+// shutdownTimeout é quanto esperamos as importações em andamento ao parar o daemon
+const shutdownTimeout = 2 * time.Minute
+
+// runServe implementa "takedown serve"
+func runServe(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	listen := flags.String("listen", defaultListen, "API listen address")
+	options := addMachineFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	return serveAPI(stdout, *listen, options)
+}
+
+// serveAPI roda a pipeline com os endpoints de importação (bundles STIX e CSV/JSONL em lote)
+// e de retomada dos casos em needs_manual. Todos abrem casos que geram emails em nosso nome,
+// então exigem o bearer token de TAKEDOWN_API_TOKEN
+func serveAPI(stdout io.Writer, listen string, options *machineOptions) error {
+	token := os.Getenv(apiTokenEnv)
+	if token == "" {
+		return fmt.Errorf("set %s with the bearer token required by the API", apiTokenEnv)
+	}
+
+	machine, err := buildMachine(options)
+	if err != nil {
+		return err
+	}
+	machine.Start()
+	defer machine.Stop()
+
+	stixImporter := stix.NewImporter()
+	stixImporter.SetSink(machine.ProcessIOC)
+	bulkImporter := newBulkImporter(machine)
+	bulkImporter.SetSink(machine.ProcessIOC)
+
+	mux := http.NewServeMux()
+	mux.Handle(stixBundlesPath, stix.NewHandler(stixImporter))
+	mux.Handle(bulkImportPath, bulk.NewHandler(bulkImporter))
+	mux.Handle(resumePath, state.NewResumeHandler(machine))
+	server := &http.Server{Addr: listen, Handler: requireToken(token, mux), ReadHeaderTimeout: 10 * time.Second}
+
+	errs := make(chan error, 1)
+	go func() { errs <- server.ListenAndServe() }()
+	_, _ = fmt.Fprintf(stdout, "takedown API listening on %s (%s, %s, %s), press Ctrl+C to stop\n", listen, stixBundlesPath, bulkImportPath, resumePath)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	select {
+	case err := <-errs:
+		return fmt.Errorf("API server failed: %w", err)
+	case <-signals:
+	}
+
+	// Importações em andamento terminam de abrir os casos; um segundo sinal interrompe a espera
+	_, _ = fmt.Fprintln(stdout, "shutting down, waiting for in-flight requests (Ctrl+C again to force)")
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	go func() {
+		select {
+		case <-signals:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	if err := server.Shutdown(ctx); err != nil {
+		_ = server.Close()
+		return fmt.Errorf("API shutdown: %w", err)
+	}
+	return nil
+}
+
+// requireToken recusa requisições sem o bearer token da API
+func requireToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		presented, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="takedown"`)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = io.WriteString(w, `{"error":"missing or invalid bearer token"}`+"\n")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// postAPI envia o corpo para uma rota da API com o bearer token de TAKEDOWN_API_TOKEN
+func postAPI(client *http.Client, url, contentType string, body io.Reader) (*http.Response, error) {
+	token := os.Getenv(apiTokenEnv)
+	if token == "" {
+		return nil, fmt.Errorf("set %s with the bearer token of the takedown API", apiTokenEnv)
+	}
+	req, err := http.NewRequest(http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", "Bearer "+token)
+	return client.Do(req)
+}
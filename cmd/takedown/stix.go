@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cti-team/takedown/internal/stix"
+	"github.com/cti-team/takedown/pkg/defang"
+)
+
+// stixBundlesPath é a rota da API que recebe bundles STIX
+const stixBundlesPath = "/api/v1/stix/bundles"
+
+// runSTIX implementa "takedown stix <import|serve>"
+func runSTIX(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("usage: takedown stix <import|serve> [flags]")
+	}
+
+	command, args := args[0], args[1:]
+	flags := flag.NewFlagSet("stix "+command, flag.ContinueOnError)
+
+	switch command {
+	case "import":
+		api := flags.String("api", "", "takedown API base URL (e.g. http://localhost:8080); without it the bundles are only mapped")
+		dryRun := flags.Bool("dry-run", false, "ask the API to map the bundles without opening cases")
+		asJSON := flags.Bool("json", false, "print JSON output")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if flags.NArg() == 0 {
+			return errors.New("usage: takedown stix import [-api <url>] [-dry-run] [-json] <bundle.json>...")
+		}
+		return importSTIX(stdout, flags.Args(), *api, *dryRun, *asJSON)
+
+	case "serve":
+		listen := flags.String("listen", defaultListen, "API listen address")
+		options := addMachineFlags(flags)
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		return serveAPI(stdout, *listen, options)
+
+	default:
+		return fmt.Errorf("unknown stix command %q", command)
+	}
+}
+
+// importSTIX mapeia cada bundle localmente ou o envia para a API do daemon
+func importSTIX(stdout io.Writer, paths []string, api string, dryRun, asJSON bool) error {
+	importer := stix.NewImporter()
+	reports := make([]*stix.Report, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path) // #nosec G304 -- caminho vem da configuração do operador
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		var report *stix.Report
+		if api == "" {
+			report, err = importer.Import(data, true)
+		} else {
+			report, err = postBundle(api, data, dryRun)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		reports = append(reports, report)
+	}
+	return printSTIXReports(stdout, paths, reports, asJSON)
+}
+
+// postBundle envia o bundle para o endpoint de importação
+func postBundle(api string, data []byte, dryRun bool) (*stix.Report, error) {
+	url := strings.TrimSuffix(api, "/") + stixBundlesPath
+	if dryRun {
+		url += "?dry_run=true"
+	}
+	client := &http.Client{Timeout: 60 * time.Second}
+	resp, err := postAPI(client, url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to post bundle: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&failure)
+		return nil, fmt.Errorf("API returned %d: %s", resp.StatusCode, failure.Error)
+	}
+	var report stix.Report
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return nil, fmt.Errorf("failed to decode import report: %w", err)
+	}
+	return &report, nil
+}
+
+// printSTIXReports imprime os IOCs mapeados (defanged) e os indicadores ignorados
+func printSTIXReports(stdout io.Writer, paths []string, reports []*stix.Report, asJSON bool) error {
+	if asJSON {
+		return json.NewEncoder(stdout).Encode(reports)
+	}
+
+	writer := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "TYPE\tVALUE\tTAGS\tFIRST SEEN\tSTIX")
+	for _, report := range reports {
+		for _, ioc := range report.IOCs {
+			stixID := ""
+			if len(ioc.IntelRefs) > 0 {
+				stixID = strings.TrimPrefix(ioc.IntelRefs[0], stix.RefPrefix)
+			}
+			_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", ioc.Type, defang.Defang(ioc.Value),
+				strings.Join(ioc.Tags, ","), ioc.FirstSeen.Format("2006-01-02 15:04"), stixID)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+
+	for i, report := range reports {
+		_, _ = fmt.Fprintf(stdout, "%s: %d indicators, %d IOCs, %d skipped, %d cases opened\n",
+			paths[i], report.Indicators, len(report.IOCs), len(report.Skipped), report.Submitted)
+		for _, skipped := range report.Skipped {
+			_, _ = fmt.Fprintf(stdout, "  skipped %s: %s\n", skipped.ObjectID, skipped.Reason)
+		}
+		for _, failure := range report.Errors {
+			_, _ = fmt.Fprintf(stdout, "  error: %s\n", failure)
+		}
+	}
+	return nil
+}
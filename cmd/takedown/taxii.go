@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/cti-team/takedown/internal/taxii"
+	"github.com/cti-team/takedown/pkg/defang"
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// defaultTAXIIPath é a configuração das coleções TAXII
+const defaultTAXIIPath = "configs/taxii/taxii.yaml"
+
+// runTAXII implementa "takedown taxii": lê as coleções TAXII 2.1 e abre casos para os indicadores
+func runTAXII(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("taxii", flag.ContinueOnError)
+	configPath := flags.String("config", defaultTAXIIPath, "TAXII collections config")
+	once := flags.Bool("once", false, "poll the collections once and print the indicators without opening cases or moving the cursor")
+	asJSON := flags.Bool("json", false, "print JSON output (with -once)")
+	options := addMachineFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	config, err := taxii.LoadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	if len(config.Collections) == 0 {
+		return errors.New("no TAXII collections configured (see " + defaultTAXIIPath + ")")
+	}
+
+	if *once {
+		cursors, _ := taxii.LoadCursors("")
+		poller, err := taxii.NewPoller(config, cursors)
+		if err != nil {
+			return err
+		}
+		iocs, err := poller.Poll(context.Background())
+		if printErr := printTAXIIIndicators(stdout, iocs, *asJSON); printErr != nil {
+			return printErr
+		}
+		return err
+	}
+
+	cursors, err := taxii.LoadCursors(config.CursorPath)
+	if err != nil {
+		return err
+	}
+	poller, err := taxii.NewPoller(config, cursors)
+	if err != nil {
+		return err
+	}
+
+	machine, err := buildMachine(options)
+	if err != nil {
+		return err
+	}
+	machine.Start()
+	defer machine.Stop()
+
+	poller.SetSink(machine.ProcessIOC)
+	poller.Start()
+	defer poller.Stop()
+
+	_, _ = fmt.Fprintf(stdout, "TAXII poller running for %d collections, press Ctrl+C to stop\n", len(config.Collections))
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	<-signals
+	return nil
+}
+
+// printTAXIIIndicators imprime os IOCs mapeados (defanged) com as tags e as referências STIX
+func printTAXIIIndicators(stdout io.Writer, iocs []*models.IOC, asJSON bool) error {
+	if asJSON {
+		return json.NewEncoder(stdout).Encode(iocs)
+	}
+
+	writer := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "TYPE\tVALUE\tTAGS\tFIRST SEEN\tREFS")
+	for _, ioc := range iocs {
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", ioc.Type, defang.Defang(ioc.Value),
+			strings.Join(ioc.Tags, ","), ioc.FirstSeen.Format("2006-01-02 15:04"), strings.Join(ioc.IntelRefs, ","))
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,385 @@
+package analysis
+
+import (
+	"bytes"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/cti-team/takedown/pkg/indicator"
+	"github.com/cti-team/takedown/pkg/models"
+	"golang.org/x/net/html"
+)
+
+// maxMarkerEvidence limita o trecho guardado como evidência de um marcador
+const maxMarkerEvidence = 120
+
+// telegramBotPattern detecta chamadas à API de bots do Telegram (bot<id>:<token>)
+var telegramBotPattern = regexp.MustCompile(`(?i)api\.telegram\.org/bot(\d+):([A-Za-z0-9_-]+)`)
+
+// mailRelayPattern detecta relays de formulário que entregam os dados por email (formsubmit.co/<conta>)
+var mailRelayPattern = regexp.MustCompile(`(?i)formsubmit\.co/(?:ajax/)?([^"'\s/?#<>]+(?:@|%40)[^"'\s/?#<>]+)`)
+
+// recipientFieldPattern reconhece campos ocultos com o destinatário de scripts de envio (formmail, mailer.php)
+var recipientFieldPattern = regexp.MustCompile(`(?i)^_?(?:recipient|to|mail_?to|email_?to|send_?to|receiver)$`)
+
+// scriptPostPattern detecta envios de dados via JavaScript (fetch, $.post, $.ajax, XHR)
+var scriptPostPattern = regexp.MustCompile(`(?i)(?:fetch|\.post|\.ajax|\.open)\s*\(\s*(?:["']POST["']\s*,\s*)?(?:\{\s*url\s*:\s*)?["'](https?://[^"'\s]+)["']`)
+
+// identifierFieldPattern reconhece campos de usuário/conta em formulários de login
+var identifierFieldPattern = regexp.MustCompile(`(?i)^(?:e-?mail|user(?:name|id)?|login|account|conta|usuario|cpf|agencia|phone|signin)`)
+
+// loginHintPattern reconhece formulários de login pelo action/id/class
+var loginHintPattern = regexp.MustCompile(`(?i)log-?in|sign-?in|auth|session|entrar|acesso`)
+
+// KitSignature descreve um marcador conhecido de phishing kit
+type KitSignature struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// DefaultKitSignatures retorna os marcadores de kits mais comuns
+func DefaultKitSignatures() []KitSignature {
+	return []KitSignature{
+		{"16shop", regexp.MustCompile(`(?i)16\s?shop`)},
+		{"kr3pto", regexp.MustCompile(`(?i)kr3pto`)},
+		{"logokit", regexp.MustCompile(`(?i)logo\.clearbit\.com/`)},
+		{"antibot", regexp.MustCompile(`(?i)(?:anti-?bots?|blocker|killbot|bots?)\.php|antibot\.pw|killbot\.org`)},
+		{"obfuscated_js", regexp.MustCompile(`(?i)eval\s*\(\s*(?:atob|unescape|decodeURIComponent\s*\(\s*escape)\s*\(`)},
+		{"scampage_signature", regexp.MustCompile(`(?i)scam\s?page|\bscama\b|rezult|result\s?box`)},
+		{"exfil_endpoint", regexp.MustCompile(`(?i)action\s*=\s*["']?[^"'\s>]*(?:next|send|mail(?:er)?|post|login_?process|verify)\d*\.php`)},
+		{"telegram_exfil", telegramBotPattern},
+		{"geoip_check", regexp.MustCompile(`(?i)ip-api\.com|ipapi\.co|geoplugin\.net|ipinfo\.io|extreme-ip-lookup\.com`)},
+	}
+}
+
+// Config define as marcas monitoradas e os marcadores de kits
+type Config struct {
+	Brands        []string
+	KitSignatures []KitSignature
+}
+
+// DefaultConfig retorna a configuração padrão (sem marcas monitoradas)
+func DefaultConfig() Config {
+	return Config{KitSignatures: DefaultKitSignatures()}
+}
+
+// Analyzer analisa o HTML completo da página coletada
+type Analyzer struct {
+	config Config
+}
+
+// NewAnalyzer cria um analisador com a configuração informada
+func NewAnalyzer(config Config) *Analyzer {
+	return &Analyzer{config: config}
+}
+
+// page acumula o que foi extraído durante a leitura do HTML
+type page struct {
+	base     *url.URL
+	title    string
+	text     strings.Builder
+	names    []string // og:site_name, application-name, alt de logos
+	scripts  strings.Builder
+	forms    []models.FormFinding
+	drops    []string // contas que recebem os dados do formulário
+	orphans  int      // campos de senha fora de <form>
+	logos    []string
+	favicons []string
+}
+
+// Analyze extrai formulários, marcas, logos, favicons e marcadores de kit do HTML
+func (a *Analyzer) Analyze(pageURL string, body []byte, brands ...string) *models.ContentAnalysis {
+	result := &models.ContentAnalysis{PageURL: pageURL}
+
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return result
+	}
+
+	p := &page{}
+	p.base, _ = url.Parse(pageURL)
+	p.walk(doc, nil)
+
+	result.Forms = p.forms
+	for _, form := range p.forms {
+		if form.Login {
+			result.HasLoginForm = true
+		}
+		if form.PasswordFields > 0 {
+			result.HasPasswordForm = true
+		}
+		if (form.CrossDomain || form.Telegram) && form.Action != "" {
+			result.ExternalPosts = appendUnique(result.ExternalPosts, form.Action)
+		}
+	}
+	if p.orphans > 0 {
+		result.HasPasswordForm = true
+		result.HasLoginForm = true
+	}
+
+	for _, match := range scriptPostPattern.FindAllStringSubmatch(p.scripts.String(), -1) {
+		if p.crossDomain(match[1]) {
+			result.ExternalPosts = appendUnique(result.ExternalPosts, maskTelegramURL(match[1]))
+		}
+	}
+	for _, match := range telegramBotPattern.FindAllStringSubmatch(string(body), -1) {
+		result.TelegramBots = appendUnique(result.TelegramBots, maskBotToken(match[1], match[2]))
+	}
+	for _, match := range mailRelayPattern.FindAllStringSubmatch(p.scripts.String(), -1) {
+		p.addDrop(match[1])
+	}
+	result.DropMailboxes = p.drops
+
+	result.Logos = p.logos
+	result.Favicons = p.favicons
+	result.Brands = a.brands(p, append(append([]string(nil), a.config.Brands...), brands...))
+	result.KitMarkers = a.kitMarkers(string(body))
+
+	return result
+}
+
+// walk percorre a árvore HTML; form é o formulário corrente (nil fora de <form>)
+func (p *page) walk(node *html.Node, form *models.FormFinding) {
+	switch node.Type {
+	case html.TextNode:
+		p.text.WriteString(node.Data)
+		p.text.WriteByte(' ')
+	case html.ElementNode:
+		switch node.Data {
+		case "title":
+			if p.title == "" && node.FirstChild != nil {
+				p.title = strings.Join(strings.Fields(node.FirstChild.Data), " ")
+			}
+		case "meta":
+			switch strings.ToLower(attr(node, "property") + attr(node, "name")) {
+			case "og:site_name", "application-name", "apple-mobile-web-app-title":
+				p.names = appendUnique(p.names, strings.TrimSpace(attr(node, "content")))
+			}
+		case "link":
+			if rel := strings.ToLower(attr(node, "rel")); strings.Contains(rel, "icon") {
+				p.favicons = appendUnique(p.favicons, p.resolve(attr(node, "href")))
+			}
+		case "img":
+			src, alt := attr(node, "src"), attr(node, "alt")
+			hints := strings.ToLower(src + " " + alt + " " + attr(node, "class") + " " + attr(node, "id"))
+			if strings.Contains(hints, "logo") {
+				p.logos = appendUnique(p.logos, p.resolve(src))
+				p.names = appendUnique(p.names, strings.TrimSpace(alt))
+			}
+		case "script":
+			if src := attr(node, "src"); src != "" {
+				p.scripts.WriteString(p.resolve(src))
+				p.scripts.WriteByte('\n')
+			}
+			for child := node.FirstChild; child != nil; child = child.NextSibling {
+				p.scripts.WriteString(child.Data)
+				p.scripts.WriteByte('\n')
+			}
+			return
+		case "style":
+			return
+		case "form":
+			finding := p.newForm(node)
+			for child := node.FirstChild; child != nil; child = child.NextSibling {
+				p.walk(child, &finding)
+			}
+			finding.Login = finding.PasswordFields > 0 ||
+				(hasIdentifierField(finding.Fields) && loginHintPattern.MatchString(attr(node, "action")+" "+attr(node, "id")+" "+attr(node, "class")+" "+attr(node, "name")))
+			p.forms = append(p.forms, finding)
+			return
+		case "input", "select", "textarea":
+			p.addField(node, form)
+		}
+	}
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		p.walk(child, form)
+	}
+}
+
+// newForm cria o achado de um <form> resolvendo o destino do envio
+func (p *page) newForm(node *html.Node) models.FormFinding {
+	method := strings.ToUpper(strings.TrimSpace(attr(node, "method")))
+	if method == "" {
+		method = "GET"
+	}
+
+	finding := models.FormFinding{Method: method}
+	if action := strings.TrimSpace(attr(node, "action")); action != "" {
+		resolved := p.resolve(action)
+		finding.CrossDomain = p.crossDomain(resolved)
+		finding.Telegram = telegramBotPattern.MatchString(resolved)
+		finding.Action = maskTelegramURL(resolved)
+		if address, ok := strings.CutPrefix(strings.ToLower(action), "mailto:"); ok {
+			p.addDrop(strings.SplitN(address, "?", 2)[0])
+		}
+		if match := mailRelayPattern.FindStringSubmatch(resolved); match != nil {
+			p.addDrop(match[1])
+		}
+	}
+	return finding
+}
+
+// addDrop registra a conta que recebe os dados, se for um endereço válido
+func (p *page) addDrop(address string) {
+	if unescaped, err := url.PathUnescape(address); err == nil {
+		address = unescaped
+	}
+	if validated, err := indicator.Validate(strings.TrimSpace(address), models.IOCTypeEmail); err == nil {
+		p.drops = appendUnique(p.drops, validated.Value)
+	}
+}
+
+// addField registra um campo no formulário corrente (ou como campo órfão)
+func (p *page) addField(node *html.Node, form *models.FormFinding) {
+	fieldType := strings.ToLower(attr(node, "type"))
+	switch fieldType {
+	case "hidden":
+		if form != nil && recipientFieldPattern.MatchString(attr(node, "name")) {
+			p.addDrop(attr(node, "value"))
+		}
+		return
+	case "submit", "button", "image", "reset":
+		return
+	}
+
+	if form == nil {
+		if fieldType == "password" {
+			p.orphans++
+		}
+		return
+	}
+
+	name := attr(node, "name")
+	if name == "" {
+		name = attr(node, "id")
+	}
+	if name == "" {
+		name = fieldType
+	}
+	form.Fields = append(form.Fields, name)
+	if fieldType == "password" {
+		form.PasswordFields++
+	}
+}
+
+// resolve converte referências relativas em URLs absolutas
+func (p *page) resolve(ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || p.base == nil {
+		return ref
+	}
+	parsed, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return p.base.ResolveReference(parsed).String()
+}
+
+// crossDomain indica se a URL aponta para outro domínio que não o da página
+func (p *page) crossDomain(target string) bool {
+	parsed, err := url.Parse(target)
+	if err != nil || parsed.Hostname() == "" || p.base == nil || p.base.Hostname() == "" {
+		return false
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return false
+	}
+	return !sameSite(parsed.Hostname(), p.base.Hostname())
+}
+
+// brands retorna as marcas citadas na página: nomes declarados e marcas monitoradas encontradas
+func (a *Analyzer) brands(p *page, monitored []string) []string {
+	var found []string
+	for _, name := range p.names {
+		if name != "" && !strings.Contains(strings.ToLower(name), "logo") {
+			found = appendUnique(found, name)
+		}
+	}
+
+	haystack := strings.ToLower(strings.Join([]string{
+		p.title, p.text.String(), strings.Join(p.names, " "),
+		strings.Join(p.logos, " "), strings.Join(p.favicons, " "),
+	}, " "))
+	for _, brand := range monitored {
+		keyword := strings.ToLower(strings.TrimSpace(brand))
+		if keyword != "" && strings.Contains(haystack, keyword) {
+			found = appendUnique(found, strings.TrimSpace(brand))
+		}
+	}
+	return found
+}
+
+// kitMarkers aplica as assinaturas de kits sobre o HTML bruto
+func (a *Analyzer) kitMarkers(body string) []models.KitMarker {
+	var markers []models.KitMarker
+	for _, signature := range a.config.KitSignatures {
+		match := signature.Pattern.FindString(body)
+		if match == "" {
+			continue
+		}
+		match = maskTelegramURL(match)
+		if len(match) > maxMarkerEvidence {
+			match = match[:maxMarkerEvidence]
+		}
+		markers = append(markers, models.KitMarker{Name: signature.Name, Evidence: match})
+	}
+	return markers
+}
+
+// sameSite compara hosts ignorando "www." e relações de subdomínio
+func sameSite(a, b string) bool {
+	a = strings.TrimPrefix(strings.TrimSuffix(strings.ToLower(a), "."), "www.")
+	b = strings.TrimPrefix(strings.TrimSuffix(strings.ToLower(b), "."), "www.")
+	return a == b || strings.HasSuffix(a, "."+b) || strings.HasSuffix(b, "."+a)
+}
+
+// hasIdentifierField verifica se há campo de usuário/email entre os campos
+func hasIdentifierField(fields []string) bool {
+	for _, field := range fields {
+		if identifierFieldPattern.MatchString(field) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskBotToken mantém o ID do bot e mascara o token (o token é uma credencial)
+func maskBotToken(id, token string) string {
+	if len(token) > 4 {
+		token = token[:4]
+	}
+	return "bot" + id + ":" + token + "***"
+}
+
+// maskTelegramURL mascara tokens de bots do Telegram contidos no texto
+func maskTelegramURL(value string) string {
+	return telegramBotPattern.ReplaceAllStringFunc(value, func(match string) string {
+		submatch := telegramBotPattern.FindStringSubmatch(match)
+		return "api.telegram.org/" + maskBotToken(submatch[1], submatch[2])
+	})
+}
+
+// attr retorna o valor de um atributo do elemento
+func attr(node *html.Node, name string) string {
+	for _, attribute := range node.Attr {
+		if strings.EqualFold(attribute.Key, name) {
+			return attribute.Val
+		}
+	}
+	return ""
+}
+
+// appendUnique adiciona o valor se ainda não estiver na lista
+func appendUnique(list []string, value string) []string {
+	if value == "" {
+		return list
+	}
+	for _, existing := range list {
+		if existing == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
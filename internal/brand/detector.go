@@ -0,0 +1,210 @@
+package brand
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/cti-team/takedown/pkg/indicator"
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+const (
+	// TagTyposquatting é adicionada junto com "brand:X" quando o indicador imita um domínio protegido
+	TagTyposquatting = "typosquatting"
+	// TagSimilarityPrefix carrega o score de similaridade no IOC (ex: brand_similarity:0.94)
+	TagSimilarityPrefix = "brand_similarity:"
+
+	// comboPenalty reduz o score quando a marca aparece num subdomínio ou token, não no rótulo registrado
+	comboPenalty = 0.9
+)
+
+// protectedDomain é um domínio oficial pré-processado para comparação
+type protectedDomain struct {
+	brand    string
+	domain   string
+	label    string // rótulo à esquerda do sufixo público, em Unicode
+	skeleton string
+}
+
+// candidate é um trecho do host do indicador comparado com os rótulos protegidos
+type candidate struct {
+	text  string
+	combo bool // subdomínio ou token separado por hífen
+}
+
+// Detector compara indicadores com os domínios protegidos das marcas
+type Detector struct {
+	config   Config
+	domains  []protectedDomain
+	official map[string]bool
+}
+
+// NewDetector cria um detector para as marcas da configuração
+func NewDetector(config Config) *Detector {
+	detector := &Detector{config: config, official: make(map[string]bool)}
+
+	for _, brand := range config.Brands {
+		for _, domain := range brand.Domains {
+			normalized, err := indicator.Normalize(domain)
+			if err != nil || normalized.RegistrableDomain == "" {
+				continue
+			}
+			labels := hostLabels(normalized)
+			if len(labels) == 0 {
+				continue
+			}
+			label := labels[len(labels)-1]
+			detector.official[normalized.RegistrableDomain] = true
+			detector.domains = append(detector.domains, protectedDomain{
+				brand:    brand.Name,
+				domain:   normalized.RegistrableDomain,
+				label:    label,
+				skeleton: Skeleton(label),
+			})
+		}
+	}
+
+	return detector
+}
+
+// Check compara o indicador com os domínios protegidos e retorna a melhor correspondência,
+// ou nil se ficar abaixo do limite ou se o indicador for um domínio oficial
+func (d *Detector) Check(value string) *models.BrandSimilarity {
+	if len(d.domains) == 0 {
+		return nil
+	}
+	normalized, err := indicator.Normalize(value)
+	if err != nil || normalized.IP || normalized.RegistrableDomain == "" {
+		return nil
+	}
+	if d.official[normalized.RegistrableDomain] || d.official[normalized.Site] {
+		return nil
+	}
+
+	var best *models.BrandSimilarity
+	for _, candidate := range candidates(hostLabels(normalized)) {
+		for _, protected := range d.domains {
+			match := d.compare(candidate, protected)
+			if match != nil && (best == nil || match.Score > best.Score) {
+				best = match
+			}
+		}
+	}
+
+	if best == nil || best.Score < d.config.Threshold {
+		return nil
+	}
+	if normalized.UnicodeHost != "" {
+		best.Reasons = append(best.Reasons, fmt.Sprintf("punycode host decodes to %s", normalized.UnicodeHost))
+	}
+	return best
+}
+
+// Tag verifica o IOC e, havendo correspondência, adiciona brand:X, typosquatting e o score como tags
+func (d *Detector) Tag(ioc *models.IOC) *models.BrandSimilarity {
+	match := d.Check(ioc.Value)
+	if match == nil {
+		return nil
+	}
+
+	tags := make([]string, 0, len(ioc.Tags)+3)
+	for _, tag := range ioc.Tags {
+		if !strings.HasPrefix(tag, TagSimilarityPrefix) {
+			tags = append(tags, tag)
+		}
+	}
+	ioc.Tags = tags
+	for _, tag := range []string{"brand:" + match.Brand, TagTyposquatting} {
+		if !ioc.HasTag(tag) {
+			ioc.Tags = append(ioc.Tags, tag)
+		}
+	}
+	ioc.Tags = append(ioc.Tags, fmt.Sprintf("%s%.2f", TagSimilarityPrefix, match.Score))
+	ioc.BrandSimilarity = match
+	return match
+}
+
+// compare calcula a similaridade de um trecho do host com um domínio protegido
+func (d *Detector) compare(candidate candidate, protected protectedDomain) *models.BrandSimilarity {
+	skeleton := Skeleton(candidate.text)
+	match := &models.BrandSimilarity{
+		Brand:    protected.brand,
+		Domain:   protected.domain,
+		Skeleton: skeleton,
+	}
+
+	if skeleton == protected.skeleton {
+		match.Score = 1
+		switch {
+		case candidate.text == protected.label && candidate.combo:
+			match.Reasons = append(match.Reasons, fmt.Sprintf("contains brand label %q", protected.label))
+		case candidate.text == protected.label:
+			match.Reasons = append(match.Reasons, fmt.Sprintf("same label as %s under another suffix", protected.domain))
+		default:
+			match.Reasons = append(match.Reasons, fmt.Sprintf("%q is confusable with %q (skeleton %q)", candidate.text, protected.label, skeleton))
+		}
+	} else {
+		if len([]rune(protected.label)) < d.config.MinLabelLength {
+			return nil
+		}
+		// Esqueletos pegam confundíveis, mas "m → rn" esconde a vizinhança de teclas; vale o melhor dos dois
+		a, b := strings.ToLower(candidate.text), protected.label
+		keyboard := KeyboardDistance(a, b)
+		match.Score = similarity(keyboard, a, b)
+		if skeletonKeyboard := KeyboardDistance(skeleton, protected.skeleton); similarity(skeletonKeyboard, skeleton, protected.skeleton) > match.Score {
+			a, b, keyboard = skeleton, protected.skeleton, skeletonKeyboard
+			match.Score = similarity(keyboard, a, b)
+		}
+		match.Reasons = append(match.Reasons, fmt.Sprintf("%q vs %q: edit distance %d, keyboard distance %.1f",
+			candidate.text, protected.label, EditDistance(a, b), keyboard))
+	}
+
+	if MixedScript(candidate.text) {
+		match.Reasons = append(match.Reasons, fmt.Sprintf("mixed scripts in %q: %s", candidate.text, strings.Join(Scripts(candidate.text), "+")))
+	}
+	if candidate.combo {
+		match.Score *= comboPenalty
+	}
+	match.Score = math.Round(match.Score*100) / 100
+	return match
+}
+
+// hostLabels retorna os rótulos à esquerda do sufixo público, em Unicode (punycode decodificado)
+func hostLabels(normalized *indicator.Normalized) []string {
+	host := normalized.Host
+	if normalized.UnicodeHost != "" {
+		host = normalized.UnicodeHost
+	}
+
+	labels := strings.Split(host, ".")
+	suffixLabels := strings.Count(normalized.PublicSuffix, ".") + 1
+	if len(labels) <= suffixLabels {
+		return nil
+	}
+	return labels[:len(labels)-suffixLabels]
+}
+
+// candidates lista o rótulo registrado, os subdomínios e os tokens separados por hífen
+func candidates(labels []string) []candidate {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	list := []candidate{{text: labels[len(labels)-1]}}
+	seen := map[string]bool{labels[len(labels)-1]: true}
+	add := func(text string) {
+		if text != "" && !seen[text] {
+			seen[text] = true
+			list = append(list, candidate{text: text, combo: true})
+		}
+	}
+
+	for _, label := range labels {
+		add(label)
+		for _, token := range strings.Split(label, "-") {
+			add(token)
+		}
+	}
+	return list
+}
@@ -0,0 +1,131 @@
+package brand
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+func testConfig() Config {
+	config := DefaultConfig()
+	config.Brands = []Protected{
+		{Name: "AcmeBank", Domains: []string{"acmebank.com.br", "acmebank.com"}},
+		{Name: "Microsoft", Domains: []string{"microsoft.com"}},
+		{Name: "BB", Domains: []string{"bb.com.br"}},
+	}
+	return config
+}
+
+func TestDetector_Check(t *testing.T) {
+	detector := NewDetector(testConfig())
+
+	tests := []struct {
+		name   string
+		value  string
+		brand  string
+		score  float64
+		reason string
+	}{
+		{"cyrillic homograph", "https://xn--cmebank-1fg.com.br/login", "AcmeBank", 1, "confusable"},
+		{"unicode homograph", "асmebank.com", "AcmeBank", 1, "mixed scripts"},
+		{"ascii confusable", "rnicrosoft.com", "Microsoft", 1, "confusable"},
+		{"digit confusable", "micr0soft.com", "Microsoft", 1, "confusable"},
+		{"keyboard typo", "acmebsnk.com.br", "AcmeBank", 0.94, "keyboard distance 0.5"},
+		{"transposition", "hxxps://acmebnak[.]com", "AcmeBank", 0.89, "edit distance 1"},
+		{"other suffix", "acmebank.net", "AcmeBank", 1, "another suffix"},
+		{"platform subdomain", "acmebank.github.io", "AcmeBank", 1, "another suffix"},
+		{"combosquatting", "acmebank-seguranca.com", "AcmeBank", 0.9, "contains brand label"},
+		{"typo in subdomain", "acmebamk.login-verify.xyz", "AcmeBank", 0.84, "keyboard distance"},
+		{"short brand exact skeleton", "bb-atualizacao.com", "BB", 0.9, "contains brand label"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := detector.Check(tt.value)
+			if match == nil {
+				t.Fatalf("Expected match for %q", tt.value)
+			}
+			if match.Brand != tt.brand || match.Score != tt.score {
+				t.Errorf("Check(%q) = %s %.2f, want %s %.2f (%v)", tt.value, match.Brand, match.Score, tt.brand, tt.score, match.Reasons)
+			}
+			if !strings.Contains(strings.Join(match.Reasons, "; "), tt.reason) {
+				t.Errorf("Expected reason containing %q, got %v", tt.reason, match.Reasons)
+			}
+		})
+	}
+}
+
+func TestDetector_CheckNoMatch(t *testing.T) {
+	detector := NewDetector(testConfig())
+
+	for _, value := range []string{
+		"https://acmebank.com.br/login", // domínio oficial
+		"https://www.acmebank.com/",     // subdomínio oficial
+		"example.com",                   // sem semelhança
+		"bc.com.br",                     // rótulo curto só casa por esqueleto
+		"203.0.113.10",                  // IP
+		"microscope.com",                // abaixo do limite
+		"not a domain",                  // inválido
+	} {
+		if match := detector.Check(value); match != nil {
+			t.Errorf("Unexpected match for %q: %+v", value, match)
+		}
+	}
+
+	if NewDetector(DefaultConfig()).Check("acmebsnk.com.br") != nil {
+		t.Error("Expected no match without protected brands")
+	}
+}
+
+func TestDetector_Tag(t *testing.T) {
+	detector := NewDetector(testConfig())
+	ioc := &models.IOC{Value: "acmebsnk.com.br", Tags: []string{"phishing", "brand_similarity:0.10"}}
+
+	match := detector.Tag(ioc)
+	if match == nil || ioc.BrandSimilarity != match {
+		t.Fatalf("Expected similarity to be recorded, got %+v", ioc.BrandSimilarity)
+	}
+	expected := []string{"phishing", "brand:AcmeBank", "typosquatting", "brand_similarity:0.94"}
+	if strings.Join(ioc.Tags, ",") != strings.Join(expected, ",") {
+		t.Errorf("Tags = %v, want %v", ioc.Tags, expected)
+	}
+	if ioc.GetBrand() != "AcmeBank" {
+		t.Errorf("Expected GetBrand to return AcmeBank, got %q", ioc.GetBrand())
+	}
+
+	// Aplicar de novo não duplica tags
+	detector.Tag(ioc)
+	if len(ioc.Tags) != len(expected) {
+		t.Errorf("Expected tags to stay unique, got %v", ioc.Tags)
+	}
+
+	untouched := &models.IOC{Value: "example.com", Tags: []string{"phishing"}}
+	if detector.Tag(untouched) != nil || len(untouched.Tags) != 1 || untouched.BrandSimilarity != nil {
+		t.Errorf("Expected IOC without match to be untouched, got %+v", untouched)
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig([]byte("threshold: 0.85\nbrands:\n  - name: AcmeBank\n    domains: [acmebank.com.br]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Threshold != 0.85 || config.MinLabelLength != 5 || len(config.Brands) != 1 {
+		t.Errorf("Unexpected config: %+v", config)
+	}
+
+	invalid := []string{
+		"threshold: 1.5\n",
+		"brands:\n  - domains: [acmebank.com.br]\n",
+		"brands:\n  - name: Acme Bank\n    domains: [acmebank.com.br]\n",
+		"brands:\n  - name: AcmeBank\n",
+		"brands:\n  - name: AcmeBank\n    domains: [com.br]\n",
+		"brands:\n  - name: AcmeBank\n    domains: [acmebank.com]\n  - name: AcmeBank\n    domains: [acme.com]\n",
+	}
+	for _, data := range invalid {
+		if _, err := ParseConfig([]byte(data)); err == nil {
+			t.Errorf("Expected error for %q", data)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package brand
+
+// adjacentSubstitutionCost é o custo de trocar uma tecla por uma vizinha no teclado
+const adjacentSubstitutionCost = 0.5
+
+// qwertyRows são as linhas do teclado QWERTY usadas para calcular vizinhança entre teclas
+var qwertyRows = []string{
+	"1234567890-",
+	"qwertyuiop",
+	"asdfghjkl",
+	"zxcvbnm",
+}
+
+// keyboardNeighbors mapeia cada tecla para as teclas adjacentes (mesma linha e linhas vizinhas)
+var keyboardNeighbors = buildKeyboardNeighbors()
+
+// EditDistance calcula a distância de Damerau-Levenshtein (com transposição de vizinhos) entre duas strings
+func EditDistance(a, b string) int {
+	return int(weightedDistance([]rune(a), []rune(b), func(x, y rune) float64 {
+		if x == y {
+			return 0
+		}
+		return 1
+	}))
+}
+
+// KeyboardDistance é a distância de edição em que trocar uma tecla por uma vizinha custa metade,
+// refletindo erros de digitação reais (acmebsnk, acmevank)
+func KeyboardDistance(a, b string) float64 {
+	return weightedDistance([]rune(a), []rune(b), func(x, y rune) float64 {
+		switch {
+		case x == y:
+			return 0
+		case keyboardNeighbors[x][y]:
+			return adjacentSubstitutionCost
+		default:
+			return 1
+		}
+	})
+}
+
+// similarity converte uma distância em similaridade entre 0 e 1 pelo tamanho da maior string
+func similarity(distance float64, a, b string) float64 {
+	longest := len([]rune(a))
+	if n := len([]rune(b)); n > longest {
+		longest = n
+	}
+	if longest == 0 {
+		return 1
+	}
+	score := 1 - distance/float64(longest)
+	if score < 0 {
+		return 0
+	}
+	return score
+}
+
+// weightedDistance implementa a distância de "optimal string alignment" com custo de substituição variável
+func weightedDistance(a, b []rune, substitution func(x, y rune) float64) float64 {
+	rows := make([][]float64, len(a)+1)
+	for i := range rows {
+		rows[i] = make([]float64, len(b)+1)
+		rows[i][0] = float64(i)
+	}
+	for j := range rows[0] {
+		rows[0][j] = float64(j)
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			best := rows[i-1][j] + 1 // remoção
+			if insert := rows[i][j-1] + 1; insert < best {
+				best = insert
+			}
+			if replace := rows[i-1][j-1] + substitution(a[i-1], b[j-1]); replace < best {
+				best = replace
+			}
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				if transpose := rows[i-2][j-2] + 1; transpose < best {
+					best = transpose
+				}
+			}
+			rows[i][j] = best
+		}
+	}
+	return rows[len(a)][len(b)]
+}
+
+// buildKeyboardNeighbors monta a tabela de vizinhança a partir de qwertyRows
+func buildKeyboardNeighbors() map[rune]map[rune]bool {
+	neighbors := make(map[rune]map[rune]bool)
+	link := func(x, y rune) {
+		if neighbors[x] == nil {
+			neighbors[x] = make(map[rune]bool)
+		}
+		neighbors[x][y] = true
+	}
+
+	for row, keys := range qwertyRows {
+		line := []rune(keys)
+		for col, key := range line {
+			if col+1 < len(line) {
+				link(key, line[col+1])
+				link(line[col+1], key)
+			}
+			if row+1 < len(qwertyRows) {
+				below := []rune(qwertyRows[row+1])
+				// Cada linha é deslocada meia tecla: a tecla de baixo na mesma coluna e a anterior são vizinhas
+				for _, k := range []int{col - 1, col} {
+					if k >= 0 && k < len(below) {
+						link(key, below[k])
+						link(below[k], key)
+					}
+				}
+			}
+		}
+	}
+	return neighbors
+}
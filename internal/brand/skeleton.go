@@ -0,0 +1,225 @@
+package brand
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+//go:embed confusables.txt
+var confusablesData string
+
+// confusables mapeia cada caractere confundível para o seu protótipo (já resolvido transitivamente)
+var confusables = mustParseConfusables(confusablesData)
+
+// homoglyphs é o inverso de confusables: protótipo → caracteres que se parecem com ele
+var homoglyphs = invertConfusables(confusables)
+
+// scripts são os sistemas de escrita considerados na detecção de mistura; o resto conta como "Other"
+var scripts = []struct {
+	name  string
+	table *unicode.RangeTable
+}{
+	{"Latin", unicode.Latin},
+	{"Cyrillic", unicode.Cyrillic},
+	{"Greek", unicode.Greek},
+	{"Armenian", unicode.Armenian},
+	{"Georgian", unicode.Georgian},
+	{"Cherokee", unicode.Cherokee},
+	{"Arabic", unicode.Arabic},
+	{"Hebrew", unicode.Hebrew},
+	{"Thai", unicode.Thai},
+	{"Han", unicode.Han},
+	{"Hiragana", unicode.Hiragana},
+	{"Katakana", unicode.Katakana},
+	{"Hangul", unicode.Hangul},
+	{"Bopomofo", unicode.Bopomofo},
+}
+
+// allowedMixes são as combinações legítimas do perfil "highly restrictive" da UTS #39
+var allowedMixes = [][]string{
+	{"Latin", "Han", "Hiragana", "Katakana"},
+	{"Latin", "Han", "Bopomofo"},
+	{"Latin", "Han", "Hangul"},
+}
+
+// Skeleton retorna o esqueleto do texto no sentido da UTS #39: NFD, troca de cada caractere
+// confundível pelo protótipo e NFD de novo. Diferente da especificação, as marcas
+// combinantes são descartadas para que "pаypál" e "paypal" tenham o mesmo esqueleto.
+func Skeleton(value string) string {
+	var builder strings.Builder
+	for _, r := range norm.NFD.String(strings.ToLower(value)) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if prototype, ok := confusables[r]; ok {
+			builder.WriteString(prototype)
+			continue
+		}
+		builder.WriteRune(r)
+	}
+	return norm.NFD.String(builder.String())
+}
+
+// Homoglyphs retorna os caracteres confundíveis com o protótipo (ex: "a" → "а", "ɑ", "α"), em ordem
+func Homoglyphs(prototype string) []string {
+	return homoglyphs[prototype]
+}
+
+// Scripts retorna os sistemas de escrita das letras do texto, em ordem alfabética
+func Scripts(value string) []string {
+	seen := make(map[string]bool)
+	for _, r := range value {
+		// Letras "Common" (ex: a marca de prolongamento ー) valem para qualquer escrita
+		if !unicode.IsLetter(r) || unicode.In(r, unicode.Common, unicode.Inherited) {
+			continue
+		}
+		seen[scriptOf(r)] = true
+	}
+
+	found := make([]string, 0, len(seen))
+	for name := range seen {
+		found = append(found, name)
+	}
+	sort.Strings(found)
+	return found
+}
+
+// MixedScript indica se o texto mistura sistemas de escrita fora das combinações permitidas
+func MixedScript(value string) bool {
+	found := Scripts(value)
+	if len(found) < 2 {
+		return false
+	}
+
+	for _, allowed := range allowedMixes {
+		if containsAll(allowed, found) {
+			return false
+		}
+	}
+	return true
+}
+
+// scriptOf retorna o sistema de escrita de uma letra
+func scriptOf(r rune) string {
+	for _, script := range scripts {
+		if unicode.Is(script.table, r) {
+			return script.name
+		}
+	}
+	return "Other"
+}
+
+// containsAll indica se todos os itens estão no conjunto
+func containsAll(set, items []string) bool {
+	for _, item := range items {
+		found := false
+		for _, candidate := range set {
+			if candidate == item {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// mustParseConfusables interpreta a tabela embutida; erro aqui é bug de build
+func mustParseConfusables(data string) map[rune]string {
+	table, err := parseConfusables(data)
+	if err != nil {
+		panic(err)
+	}
+	return table
+}
+
+// parseConfusables lê linhas "origem ; protótipo ; tipo # comentário" com códigos hexadecimais
+func parseConfusables(data string) (map[rune]string, error) {
+	table := make(map[rune]string)
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if idx := strings.Index(text, "#"); idx >= 0 {
+			text = text[:idx]
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		fields := strings.Split(text, ";")
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("confusables line %d: expected source and prototype", line)
+		}
+		source, err := parseCodePoints(fields[0])
+		if err != nil || len([]rune(source)) != 1 {
+			return nil, fmt.Errorf("confusables line %d: invalid source %q", line, strings.TrimSpace(fields[0]))
+		}
+		prototype, err := parseCodePoints(fields[1])
+		if err != nil || prototype == "" {
+			return nil, fmt.Errorf("confusables line %d: invalid prototype %q", line, strings.TrimSpace(fields[1]))
+		}
+		table[[]rune(source)[0]] = prototype
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// Protótipos que também são confundíveis (ԝ → w → vv) são resolvidos até o fim
+	for source, prototype := range table {
+		table[source] = resolvePrototype(table, prototype)
+	}
+	return table, nil
+}
+
+// invertConfusables agrupa as origens por protótipo
+func invertConfusables(table map[rune]string) map[string][]string {
+	inverted := make(map[string][]string)
+	for source, prototype := range table {
+		inverted[prototype] = append(inverted[prototype], string(source))
+	}
+	for _, sources := range inverted {
+		sort.Strings(sources)
+	}
+	return inverted
+}
+
+// resolvePrototype aplica a tabela ao protótipo até ele não mudar mais
+func resolvePrototype(table map[rune]string, prototype string) string {
+	for i := 0; i < 4; i++ {
+		var builder strings.Builder
+		for _, r := range prototype {
+			if next, ok := table[r]; ok {
+				builder.WriteString(next)
+			} else {
+				builder.WriteRune(r)
+			}
+		}
+		if builder.String() == prototype {
+			break
+		}
+		prototype = builder.String()
+	}
+	return prototype
+}
+
+// parseCodePoints converte "0072 006E" em "rn"
+func parseCodePoints(value string) (string, error) {
+	var builder strings.Builder
+	for _, field := range strings.Fields(value) {
+		code, err := strconv.ParseUint(field, 16, 32)
+		if err != nil {
+			return "", err
+		}
+		builder.WriteRune(rune(code))
+	}
+	return builder.String(), nil
+}
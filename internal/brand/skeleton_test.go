@@ -0,0 +1,114 @@
+package brand
+
+import "testing"
+
+func TestSkeleton(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"paypal", "paypal"},
+		{"pаypаl", "paypal"}, // "а" cirílico
+		{"PayPal", "paypal"},
+		{"paypál", "paypal"},
+		{"rnicrosoft", "rnicrosoft"},
+		{"microsoft", "rnicrosoft"},
+		{"g00gle", "google"},
+		{"app1e", "apple"},
+		{"аррӏе", "apple"},
+		{"ԝells", "vvells"},
+		{"wells", "vvells"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := Skeleton(tt.input); got != tt.expected {
+				t.Errorf("Skeleton(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMixedScript(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"paypal", false},
+		{"pаypal", true},
+		{"аррӏе", false},
+		{"bücher", false},
+		{"例えtest", false},
+		{"ソニーbank", false},
+		{"pαypal", true},
+		{"acme-01", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := MixedScript(tt.input); got != tt.expected {
+				t.Errorf("MixedScript(%q) = %v, want %v (scripts %v)", tt.input, got, tt.expected, Scripts(tt.input))
+			}
+		})
+	}
+}
+
+func TestDistances(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		edit     int
+		keyboard float64
+	}{
+		{"acmebank", "acmebank", 0, 0},
+		{"acmebank", "acmebsnk", 1, 0.5}, // s é vizinha de a
+		{"acmebank", "acmebpnk", 1, 1},   // p não é vizinha de a
+		{"acmebank", "acmebnak", 1, 1},   // transposição
+		{"acmebank", "acmebankk", 1, 1},  // inserção
+		{"acmebank", "acmeank", 1, 1},    // remoção
+		{"acmebank", "acnebamk", 2, 1},   // m/n são vizinhas
+		{"", "abc", 3, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := EditDistance(tt.a, tt.b); got != tt.edit {
+				t.Errorf("EditDistance = %d, want %d", got, tt.edit)
+			}
+			if got := KeyboardDistance(tt.a, tt.b); got != tt.keyboard {
+				t.Errorf("KeyboardDistance = %.1f, want %.1f", got, tt.keyboard)
+			}
+		})
+	}
+}
+
+func TestParseConfusables(t *testing.T) {
+	table, err := parseConfusables("0430 ;\t0061 ;\tMA\t# а → a\n051D ; 0077 ; MA\n0077 ; 0076 0076 ; MA\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table['а'] != "a" || table['ԝ'] != "vv" {
+		t.Errorf("Unexpected table: %q", table)
+	}
+
+	if _, err := parseConfusables("0430\n"); err == nil {
+		t.Error("Expected error for line without prototype")
+	}
+	if _, err := parseConfusables("ZZZZ ; 0061 ; MA\n"); err == nil {
+		t.Error("Expected error for invalid code point")
+	}
+}
+
+func TestHomoglyphs(t *testing.T) {
+	found := Homoglyphs("o")
+	for _, expected := range []string{"0", "о", "ο", "օ"} {
+		if !containsAll(found, []string{expected}) {
+			t.Errorf("Expected %q among homoglyphs of o, got %q", expected, found)
+		}
+	}
+	if len(Homoglyphs("rn")) != 1 || Homoglyphs("rn")[0] != "m" {
+		t.Errorf("Expected m as homoglyph of rn, got %q", Homoglyphs("rn"))
+	}
+	if Homoglyphs("%") != nil {
+		t.Error("Expected no homoglyphs for %")
+	}
+}
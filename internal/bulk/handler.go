@@ -0,0 +1,78 @@
+package bulk
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+// maxUploadBytes limita o tamanho dos arquivos recebidos pela API
+const maxUploadBytes = 16 << 20
+
+// Handler expõe a importação por HTTP: POST com o CSV ou JSONL no corpo
+// (?format=csv|jsonl ou pelo Content-Type); ?dry_run=true só valida e mostra o roteamento
+type Handler struct {
+	importer *Importer
+}
+
+// NewHandler cria o endpoint de importação em lote
+func NewHandler(importer *Importer) *Handler {
+	return &Handler{importer: importer}
+}
+
+// ServeHTTP implementa http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = formatFromContentType(r.Header.Get("Content-Type"))
+	}
+	if format == "" {
+		writeJSON(w, http.StatusUnsupportedMediaType, map[string]string{"error": "use ?format=csv|jsonl or a text/csv or application/x-ndjson body"})
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxUploadBytes+1))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "failed to read body"})
+		return
+	}
+	if len(data) > maxUploadBytes {
+		writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": "file too large"})
+		return
+	}
+	rows, err := ReadRows(bytes.NewReader(data), format)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, h.importer.Import(r.Context(), rows, dryRun))
+}
+
+// formatFromContentType mapeia o Content-Type para o formato
+func formatFromContentType(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/csv":
+		return FormatCSV
+	case "application/x-ndjson", "application/jsonl", "application/x-jsonlines":
+		return FormatJSONL
+	default:
+		return ""
+	}
+}
+
+// writeJSON grava a resposta em JSON
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,198 @@
+package bulk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cti-team/takedown/internal/routing"
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// Status de cada linha no relatório
+const (
+	StatusAccepted  = "accepted"  // válida; em dry-run, nada foi aberto
+	StatusSubmitted = "submitted" // caso aberto
+	StatusRejected  = "rejected"  // falhou na validação
+	StatusDuplicate = "duplicate" // repetida no arquivo ou com caso aberto
+	StatusFailed    = "failed"    // válida, mas a pipeline recusou
+)
+
+// defaultConcurrency limita as prévias de roteamento (enrichment) em paralelo
+const defaultConcurrency = 8
+
+// Sink recebe os IOCs válidos (normalmente Machine.ProcessIOC)
+type Sink func(ioc *models.IOC) error
+
+// CaseLookup encontra um caso aberto para o IOC (normalmente Machine.OpenCase)
+type CaseLookup func(iocType models.IOCType, value string) (string, bool)
+
+// Planner calcula o roteamento de um IOC sem abrir caso (normalmente Machine.PreviewRouting)
+type Planner func(ctx context.Context, ioc *models.IOC) ([]routing.ActionDefinition, error)
+
+// Target resume uma ação de roteamento prevista
+type Target struct {
+	Type    string `json:"type"`
+	Entity  string `json:"entity"`
+	Action  string `json:"action"`
+	Contact string `json:"contact,omitempty"`
+}
+
+// RowResult é o resultado de uma linha
+type RowResult struct {
+	Line    int            `json:"line"`
+	Input   string         `json:"input"`
+	Type    models.IOCType `json:"type,omitempty"`
+	Value   string         `json:"value,omitempty"` // valor normalizado
+	Tags    []string       `json:"tags,omitempty"`
+	Status  string         `json:"status"`
+	Reason  string         `json:"reason,omitempty"`
+	CaseID  string         `json:"case_id,omitempty"` // caso aberto que já cobre o IOC
+	Routing []Target       `json:"routing,omitempty"`
+
+	ioc *models.IOC
+}
+
+// Report é o relatório da importação
+type Report struct {
+	DryRun  bool           `json:"dry_run"`
+	Rows    []RowResult    `json:"rows"`
+	Summary map[string]int `json:"summary"`
+}
+
+// Importer valida as linhas, descarta duplicatas e entrega os IOCs à pipeline
+type Importer struct {
+	sink        Sink
+	cases       CaseLookup
+	planner     Planner
+	concurrency int
+	now         func() time.Time
+}
+
+// NewImporter cria um importador sem sink, busca de casos ou prévia de roteamento
+func NewImporter() *Importer {
+	return &Importer{
+		concurrency: defaultConcurrency,
+		now:         func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// SetSink configura quem recebe os IOCs
+func (i *Importer) SetSink(sink Sink) {
+	i.sink = sink
+}
+
+// SetCaseLookup configura a deduplicação contra casos abertos
+func (i *Importer) SetCaseLookup(cases CaseLookup) {
+	i.cases = cases
+}
+
+// SetPlanner configura a prévia de roteamento usada em dry-run
+func (i *Importer) SetPlanner(planner Planner) {
+	i.planner = planner
+}
+
+// Import valida cada linha e descarta as repetidas (no arquivo ou com caso aberto). Em dry-run
+// mostra o roteamento de cada linha válida; senão entrega as válidas ao sink.
+func (i *Importer) Import(ctx context.Context, rows []Row, dryRun bool) *Report {
+	report := &Report{DryRun: dryRun, Rows: make([]RowResult, 0, len(rows)), Summary: make(map[string]int)}
+	firstLine := make(map[string]int)
+	now := i.now()
+
+	for _, row := range rows {
+		result := RowResult{Line: row.Line, Input: row.Value}
+		ioc, err := Validate(row, now)
+		switch {
+		case err != nil:
+			result.Status, result.Reason = StatusRejected, err.Error()
+		default:
+			result.Type, result.Value, result.Tags = ioc.Type, ioc.Value, ioc.Tags
+			key := string(ioc.Type) + "|" + ioc.Value
+			if line, ok := firstLine[key]; ok {
+				result.Status, result.Reason = StatusDuplicate, fmt.Sprintf("same %s as line %d", ioc.Type, line)
+				break
+			}
+			firstLine[key] = row.Line
+			if i.cases != nil {
+				if caseID, ok := i.cases(ioc.Type, ioc.Value); ok {
+					result.Status, result.Reason, result.CaseID = StatusDuplicate, "open case "+caseID, caseID
+					break
+				}
+			}
+			result.Status, result.ioc = StatusAccepted, ioc
+		}
+		report.Rows = append(report.Rows, result)
+	}
+
+	if dryRun {
+		i.plan(ctx, report.Rows)
+	} else {
+		i.submit(report.Rows)
+	}
+	for _, row := range report.Rows {
+		report.Summary[row.Status]++
+	}
+	return report
+}
+
+// submit entrega as linhas aceitas ao sink, em ordem
+func (i *Importer) submit(rows []RowResult) {
+	if i.sink == nil {
+		return
+	}
+	for index := range rows {
+		row := &rows[index]
+		if row.Status != StatusAccepted {
+			continue
+		}
+		if err := i.sink(row.ioc); err != nil {
+			row.Status, row.Reason = StatusFailed, err.Error()
+			continue
+		}
+		row.Status = StatusSubmitted
+	}
+}
+
+// plan calcula a prévia de roteamento das linhas aceitas em paralelo
+func (i *Importer) plan(ctx context.Context, rows []RowResult) {
+	if i.planner == nil {
+		return
+	}
+	sem := make(chan struct{}, i.concurrency)
+	var wg sync.WaitGroup
+	for index := range rows {
+		row := &rows[index]
+		if row.Status != StatusAccepted {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			actions, err := i.planner(ctx, row.ioc)
+			if err != nil {
+				row.Reason = "routing preview failed: " + err.Error()
+				return
+			}
+			if len(actions) == 0 {
+				row.Reason = "no routing target (case would be closed)"
+			}
+			for _, action := range actions {
+				contact := action.Target.Email
+				if contact == "" {
+					contact = action.Target.Webform
+				}
+				row.Routing = append(row.Routing, Target{
+					Type:    action.Target.Type,
+					Entity:  action.Target.Entity,
+					Action:  string(action.Action),
+					Contact: contact,
+				})
+			}
+		}()
+	}
+	wg.Wait()
+}
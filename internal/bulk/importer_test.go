@@ -0,0 +1,166 @@
+package bulk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cti-team/takedown/internal/routing"
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// testCSV tem uma linha válida, uma repetida, uma com caso aberto, uma inválida e uma que o sink recusa
+const testCSV = `value,type,tags
+https://acme-login.com/auth,url,phishing
+hxxps://acme-login[.]com/auth,,phishing
+open-case.com,domain,phishing
+not a host,,
+queue-full.com,,malware
+`
+
+func newTestImporter(received *[]string) *Importer {
+	importer := NewImporter()
+	importer.SetSink(func(ioc *models.IOC) error {
+		if ioc.Value == "queue-full.com" {
+			return errors.New("work queue is full")
+		}
+		*received = append(*received, ioc.Value)
+		return nil
+	})
+	importer.SetCaseLookup(func(iocType models.IOCType, value string) (string, bool) {
+		return "tdk-open", iocType == models.IOCTypeDomain && value == "open-case.com"
+	})
+	importer.SetPlanner(func(_ context.Context, ioc *models.IOC) ([]routing.ActionDefinition, error) {
+		if ioc.HasTag("malware") {
+			return nil, nil
+		}
+		return []routing.ActionDefinition{{
+			Target: models.TakedownTarget{Type: "hosting", Entity: "Example Hosting", Email: "abuse@hosting.com"},
+			Action: models.ActionRemoveContent,
+		}}, nil
+	})
+	return importer
+}
+
+func TestImport(t *testing.T) {
+	rows, err := ReadRows(strings.NewReader(testCSV), FormatCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		dryRun       bool
+		wantStatus   []string
+		wantReceived []string
+		wantSummary  map[string]int
+	}{
+		{
+			name:         "submit",
+			wantStatus:   []string{StatusSubmitted, StatusDuplicate, StatusDuplicate, StatusRejected, StatusFailed},
+			wantReceived: []string{"https://acme-login.com/auth"},
+			wantSummary:  map[string]int{StatusSubmitted: 1, StatusDuplicate: 2, StatusRejected: 1, StatusFailed: 1},
+		},
+		{
+			name:        "dry run",
+			dryRun:      true,
+			wantStatus:  []string{StatusAccepted, StatusDuplicate, StatusDuplicate, StatusRejected, StatusAccepted},
+			wantSummary: map[string]int{StatusAccepted: 2, StatusDuplicate: 2, StatusRejected: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received []string
+			report := newTestImporter(&received).Import(context.Background(), rows, tt.dryRun)
+
+			var statuses []string
+			for _, row := range report.Rows {
+				statuses = append(statuses, row.Status)
+			}
+			if !reflect.DeepEqual(statuses, tt.wantStatus) {
+				t.Errorf("statuses = %v, want %v", statuses, tt.wantStatus)
+			}
+			if !reflect.DeepEqual(received, tt.wantReceived) {
+				t.Errorf("sink received %v, want %v", received, tt.wantReceived)
+			}
+			if !reflect.DeepEqual(report.Summary, tt.wantSummary) {
+				t.Errorf("summary = %v, want %v", report.Summary, tt.wantSummary)
+			}
+
+			if reason := report.Rows[1].Reason; reason != "same url as line 2" {
+				t.Errorf("unexpected duplicate reason %q", reason)
+			}
+			if report.Rows[2].CaseID != "tdk-open" {
+				t.Errorf("expected open case reference, got %+v", report.Rows[2])
+			}
+			if tt.dryRun {
+				want := []Target{{Type: "hosting", Entity: "Example Hosting", Action: string(models.ActionRemoveContent), Contact: "abuse@hosting.com"}}
+				if !reflect.DeepEqual(report.Rows[0].Routing, want) {
+					t.Errorf("routing preview = %+v, want %+v", report.Rows[0].Routing, want)
+				}
+				if !strings.Contains(report.Rows[4].Reason, "no routing target") {
+					t.Errorf("expected no-target reason, got %q", report.Rows[4].Reason)
+				}
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(NewHandler(newTestImporter(&received)))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		method      string
+		query       string
+		contentType string
+		body        string
+		wantStatus  int
+		wantRows    int
+	}{
+		{name: "csv by content type", method: http.MethodPost, contentType: "text/csv; charset=utf-8", body: testCSV, wantStatus: http.StatusOK, wantRows: 5},
+		{name: "jsonl dry run", method: http.MethodPost, query: "?format=jsonl&dry_run=true", body: `{"value": "a.com"}`, wantStatus: http.StatusOK, wantRows: 1},
+		{name: "unknown format", method: http.MethodPost, contentType: "application/octet-stream", body: testCSV, wantStatus: http.StatusUnsupportedMediaType},
+		{name: "bad csv header", method: http.MethodPost, query: "?format=csv", body: "url\na.com\n", wantStatus: http.StatusBadRequest},
+		{name: "wrong method", method: http.MethodGet, wantStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, server.URL+tt.query, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var report Report
+			if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+				t.Fatal(err)
+			}
+			if len(report.Rows) != tt.wantRows {
+				t.Errorf("got %d rows, want %d", len(report.Rows), tt.wantRows)
+			}
+		})
+	}
+}
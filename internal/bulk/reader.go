@@ -0,0 +1,236 @@
+// Package bulk importa IOCs em lote (CSV ou JSONL), validando cada linha,
+// descartando duplicatas de casos abertos e, em dry-run, mostrando o roteamento.
+package bulk
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// Formatos de arquivo suportados
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+// maxLineBytes limita o tamanho de uma linha JSONL
+const maxLineBytes = 1 << 20
+
+// Row é uma linha do arquivo antes da validação
+type Row struct {
+	Line      int      `json:"line"`
+	Value     string   `json:"value"`
+	Type      string   `json:"type,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	Source    string   `json:"source,omitempty"`
+	FirstSeen string   `json:"first_seen,omitempty"`
+	URLs      []string `json:"urls,omitempty"`   // URLs de distribuição de um hash
+	Emails    []string `json:"emails,omitempty"` // contas drop e remetentes de uma URL ou domínio
+	Error     string   `json:"error,omitempty"`  // erro de leitura da linha (JSON inválido, colunas faltando)
+}
+
+// DetectFormat deduz o formato pela extensão do arquivo
+func DetectFormat(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".jsonl", ".ndjson":
+		return FormatJSONL, nil
+	default:
+		return "", fmt.Errorf("cannot detect format of %s (use .csv, .jsonl or -format)", path)
+	}
+}
+
+// ReadRows lê as linhas no formato dado; linhas malformadas voltam com Error preenchido
+func ReadRows(r io.Reader, format string) ([]Row, error) {
+	switch format {
+	case FormatCSV:
+		return readCSV(r)
+	case FormatJSONL:
+		return readJSONL(r)
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// readCSV lê um CSV com cabeçalho; value é obrigatório e type, tags, source, first_seen, urls e emails são opcionais
+func readCSV(r io.Reader) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("empty CSV file")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["value"]; !ok {
+		return nil, errors.New("CSV header must have a value column")
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				rows = append(rows, Row{Line: parseErr.StartLine, Error: parseErr.Err.Error()})
+				continue
+			}
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		rows = append(rows, Row{
+			Line:      line,
+			Value:     field(record, "value"),
+			Type:      field(record, "type"),
+			Tags:      splitTags(field(record, "tags")),
+			Source:    field(record, "source"),
+			FirstSeen: field(record, "first_seen"),
+			URLs:      splitURLs(field(record, "urls")),
+			Emails:    splitEmails(field(record, "emails")),
+		})
+	}
+	return rows, nil
+}
+
+// jsonRow aceita tags e emails como lista ou como texto separado por vírgulas, e urls como lista ou texto
+type jsonRow struct {
+	Value     string          `json:"value"`
+	Type      string          `json:"type"`
+	Tags      json.RawMessage `json:"tags"`
+	Source    string          `json:"source"`
+	FirstSeen string          `json:"first_seen"`
+	URLs      json.RawMessage `json:"urls"`
+	Emails    json.RawMessage `json:"emails"`
+}
+
+// readJSONL lê um objeto JSON por linha; linhas vazias são ignoradas
+func readJSONL(r io.Reader) ([]Row, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineBytes)
+
+	var rows []Row
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		var parsed jsonRow
+		if err := json.Unmarshal(data, &parsed); err != nil {
+			rows = append(rows, Row{Line: line, Error: "invalid JSON: " + err.Error()})
+			continue
+		}
+		tags, err := parseJSONTags(parsed.Tags)
+		if err != nil {
+			rows = append(rows, Row{Line: line, Value: parsed.Value, Error: err.Error()})
+			continue
+		}
+		urls, err := parseJSONURLs(parsed.URLs)
+		if err != nil {
+			rows = append(rows, Row{Line: line, Value: parsed.Value, Error: err.Error()})
+			continue
+		}
+		emails, err := parseJSONTags(parsed.Emails)
+		if err != nil {
+			rows = append(rows, Row{Line: line, Value: parsed.Value, Error: "emails must be a list or a comma separated string"})
+			continue
+		}
+		rows = append(rows, Row{
+			Line:      line,
+			Value:     strings.TrimSpace(parsed.Value),
+			Type:      strings.TrimSpace(parsed.Type),
+			Tags:      tags,
+			Source:    strings.TrimSpace(parsed.Source),
+			FirstSeen: strings.TrimSpace(parsed.FirstSeen),
+			URLs:      urls,
+			Emails:    emails,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read JSONL: %w", err)
+	}
+	return rows, nil
+}
+
+// parseJSONTags aceita ["a", "b"] ou "a,b"
+func parseJSONTags(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var list []string
+	if err := json.Unmarshal(raw, &list); err == nil {
+		return splitTags(strings.Join(list, ",")), nil
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err != nil {
+		return nil, errors.New("tags must be a list or a comma separated string")
+	}
+	return splitTags(text), nil
+}
+
+// splitTags separa tags por vírgula, ponto e vírgula ou barra vertical
+func splitTags(value string) []string {
+	fields := strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' || r == '|' })
+	var tags []string
+	for _, field := range fields {
+		if tag := strings.TrimSpace(field); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// parseJSONURLs aceita ["u1", "u2"] ou "u1 u2"
+func parseJSONURLs(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var list []string
+	if err := json.Unmarshal(raw, &list); err == nil {
+		return splitURLs(strings.Join(list, " ")), nil
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err != nil {
+		return nil, errors.New("urls must be a list or a space separated string")
+	}
+	return splitURLs(text), nil
+}
+
+// splitURLs separa URLs por espaço ou barra vertical (vírgula e ponto e vírgula aparecem em query strings)
+func splitURLs(value string) []string {
+	urls := strings.FieldsFunc(value, func(r rune) bool { return r == '|' || unicode.IsSpace(r) })
+	if len(urls) == 0 {
+		return nil
+	}
+	return urls
+}
+
+// splitEmails separa endereços por vírgula, ponto e vírgula, barra vertical ou espaço
+func splitEmails(value string) []string {
+	return splitTags(strings.Join(strings.Fields(value), ","))
+}
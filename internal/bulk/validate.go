@@ -0,0 +1,110 @@
+package bulk
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cti-team/takedown/pkg/indicator"
+	"github.com/cti-team/takedown/pkg/models"
+	"github.com/google/uuid"
+)
+
+// DefaultSource é a origem dos IOCs sem coluna source
+const DefaultSource = "bulk"
+
+// firstSeenLayouts são os formatos aceitos em first_seen
+var firstSeenLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"}
+
+// Validate normaliza a linha em um IOC: valida e canoniza o valor com indicator.Validate
+// (detectando o tipo quando ausente), interpreta first_seen e valida as URLs de distribuição de hashes
+// e as contas de email ligadas a URLs
+func Validate(row Row, now time.Time) (*models.IOC, error) {
+	if row.Error != "" {
+		return nil, errors.New(row.Error)
+	}
+	if row.Value == "" {
+		return nil, errors.New("empty value")
+	}
+
+	validated, err := indicator.Validate(row.Value, models.IOCType(strings.ToLower(row.Type)))
+	if err != nil {
+		return nil, err
+	}
+
+	ioc := &models.IOC{
+		IndicatorID: "bulk-" + uuid.New().String(),
+		Type:        validated.Type,
+		Value:       validated.Value,
+		Source:      DefaultSource,
+		Tags:        normalizeTags(row.Tags),
+		FirstSeen:   now,
+	}
+	if row.Source != "" {
+		ioc.Source = row.Source
+	}
+	if len(row.URLs) > 0 && ioc.Type != models.IOCTypeHash {
+		return nil, fmt.Errorf("urls only apply to hash indicators, not %s", ioc.Type)
+	}
+	for _, raw := range row.URLs {
+		url, err := indicator.Validate(raw, models.IOCTypeURL)
+		if err != nil {
+			return nil, fmt.Errorf("distribution url %s: %w", raw, err)
+		}
+		ioc.DistributionURLs = append(ioc.DistributionURLs, url.Value)
+	}
+	if len(row.Emails) > 0 && ioc.Type != models.IOCTypeURL && ioc.Type != models.IOCTypeDomain && ioc.Type != models.IOCTypeIP {
+		return nil, fmt.Errorf("emails only apply to url, domain and ip indicators, not %s", ioc.Type)
+	}
+	for _, raw := range row.Emails {
+		email, err := indicator.Validate(raw, models.IOCTypeEmail)
+		if err != nil {
+			return nil, fmt.Errorf("linked email %s: %w", raw, err)
+		}
+		ioc.Emails = append(ioc.Emails, email.Value)
+	}
+	if row.FirstSeen != "" {
+		firstSeen, err := parseFirstSeen(row.FirstSeen)
+		if err != nil {
+			return nil, err
+		}
+		if firstSeen.After(now.Add(time.Hour)) {
+			return nil, fmt.Errorf("first_seen %s is in the future", row.FirstSeen)
+		}
+		ioc.FirstSeen = firstSeen
+	}
+	return ioc, nil
+}
+
+// parseFirstSeen aceita RFC 3339, data e hora sem fuso (UTC) ou só a data
+func parseFirstSeen(value string) (time.Time, error) {
+	for _, layout := range firstSeenLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid first_seen %q (use RFC 3339 or YYYY-MM-DD)", value)
+}
+
+// normalizeTags coloca as tags em minúsculas (menos o nome em brand:X), sem repetição e em ordem
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := []string{}
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if len(tag) > 6 && strings.EqualFold(tag[:6], "brand:") {
+			tag = "brand:" + tag[6:]
+		} else {
+			tag = strings.ToLower(tag)
+		}
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
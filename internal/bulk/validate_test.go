@@ -0,0 +1,190 @@
+package bulk
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+func TestValidate(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		row       Row
+		wantType  models.IOCType
+		wantValue string
+		wantTags  []string
+		wantSeen  time.Time
+		wantURLs  []string
+		wantMail  []string
+		wantErr   string
+	}{
+		{
+			name:      "defanged url with detected type",
+			row:       Row{Value: "hxxps://Acme-Login[.]com:443/auth#x", Tags: []string{"Phishing", "phishing", "brand:AcmeBank"}},
+			wantType:  models.IOCTypeURL,
+			wantValue: "https://acme-login.com/auth",
+			wantTags:  []string{"brand:AcmeBank", "phishing"},
+			wantSeen:  now,
+		},
+		{
+			name:      "domain with date",
+			row:       Row{Value: "Acme-Verify.com.", Type: "domain", FirstSeen: "2025-02-20"},
+			wantType:  models.IOCTypeDomain,
+			wantValue: "acme-verify.com",
+			wantTags:  []string{},
+			wantSeen:  time.Date(2025, 2, 20, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "ip",
+			row:       Row{Value: "45.67.89.7", FirstSeen: "2025-02-20T10:00:00-03:00"},
+			wantType:  models.IOCTypeIP,
+			wantValue: "45.67.89.7",
+			wantTags:  []string{},
+			wantSeen:  time.Date(2025, 2, 20, 13, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "domain declared as url",
+			row:       Row{Value: "acme-login.com", Type: "URL"},
+			wantType:  models.IOCTypeURL,
+			wantValue: "http://acme-login.com/",
+			wantTags:  []string{},
+			wantSeen:  now,
+		},
+		{
+			name:      "hash with distribution urls",
+			row:       Row{Value: strings.Repeat("AB", 32), Tags: []string{"malware"}, URLs: []string{"hxxp://files[.]com/a.exe", "https://b.com/x.zip"}},
+			wantType:  models.IOCTypeHash,
+			wantValue: strings.Repeat("ab", 32),
+			wantTags:  []string{"malware"},
+			wantSeen:  now,
+			wantURLs:  []string{"http://files.com/a.exe", "https://b.com/x.zip"},
+		},
+		{
+			name:      "url with linked emails",
+			row:       Row{Value: "https://acme-login.com/auth", Emails: []string{"Drop[@]gmail.com"}},
+			wantType:  models.IOCTypeURL,
+			wantValue: "https://acme-login.com/auth",
+			wantTags:  []string{},
+			wantSeen:  now,
+			wantMail:  []string{"drop@gmail.com"},
+		},
+		{name: "empty value", row: Row{Value: ""}, wantErr: "empty value"},
+		{name: "invalid linked email", row: Row{Value: "a.com", Emails: []string{"drop@"}}, wantErr: "linked email"},
+		{name: "emails on an email", row: Row{Value: "a@a.com", Emails: []string{"b@a.com"}}, wantErr: "emails only apply"},
+		{name: "invalid distribution url", row: Row{Value: strings.Repeat("ab", 32), URLs: []string{"http://10.0.0.8/a.exe"}}, wantErr: "distribution url"},
+		{name: "urls on a domain", row: Row{Value: "a.com", URLs: []string{"https://a.com/x"}}, wantErr: "only apply to hash"},
+		{name: "read error", row: Row{Error: "invalid JSON: unexpected end"}, wantErr: "invalid JSON"},
+		{name: "url declared as domain", row: Row{Value: "https://a.com/login", Type: "domain"}, wantErr: "looks like url"},
+		{name: "domain declared as ip", row: Row{Value: "a.com", Type: "ip"}, wantErr: "invalid IP address"},
+		{name: "private ip", row: Row{Value: "10.0.0.8"}, wantErr: "reserved range"},
+		{name: "unsupported type", row: Row{Value: "a.com", Type: "asn"}, wantErr: "unsupported type"},
+		{name: "invalid host", row: Row{Value: "http://exa mple.com/"}, wantErr: "invalid"},
+		{name: "invalid first_seen", row: Row{Value: "a.com", FirstSeen: "20/02/2025"}, wantErr: "invalid first_seen"},
+		{name: "future first_seen", row: Row{Value: "a.com", FirstSeen: "2025-03-05"}, wantErr: "in the future"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ioc, err := Validate(tt.row, now)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Validate error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ioc.Type != tt.wantType || ioc.Value != tt.wantValue || ioc.Source != DefaultSource {
+				t.Errorf("got %s %s (%s), want %s %s", ioc.Type, ioc.Value, ioc.Source, tt.wantType, tt.wantValue)
+			}
+			if !reflect.DeepEqual(ioc.Tags, tt.wantTags) {
+				t.Errorf("tags = %v, want %v", ioc.Tags, tt.wantTags)
+			}
+			if !reflect.DeepEqual(ioc.DistributionURLs, tt.wantURLs) {
+				t.Errorf("distribution urls = %v, want %v", ioc.DistributionURLs, tt.wantURLs)
+			}
+			if !reflect.DeepEqual(ioc.Emails, tt.wantMail) {
+				t.Errorf("emails = %v, want %v", ioc.Emails, tt.wantMail)
+			}
+			if !ioc.FirstSeen.Equal(tt.wantSeen) {
+				t.Errorf("first seen = %s, want %s", ioc.FirstSeen, tt.wantSeen)
+			}
+		})
+	}
+}
+
+func TestReadRows(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		input   string
+		want    []Row
+		wantErr bool
+	}{
+		{
+			name:   "csv with optional columns",
+			format: FormatCSV,
+			input: "Value,Type,Tags,Source,first_seen\n" +
+				"https://a.com/login,url,\"phishing,brand:AcmeBank\",analyst,2025-02-01\n" +
+				"# comentário\n" +
+				"b.com,,phishing;c2,,\n",
+			want: []Row{
+				{Line: 2, Value: "https://a.com/login", Type: "url", Tags: []string{"phishing", "brand:AcmeBank"}, Source: "analyst", FirstSeen: "2025-02-01"},
+				{Line: 4, Value: "b.com", Tags: []string{"phishing", "c2"}},
+			},
+		},
+		{
+			name:   "csv hash with urls",
+			format: FormatCSV,
+			input:  "value,type,urls\n" + strings.Repeat("a", 32) + ",hash,https://a.com/x.exe | http://b.com/y?a=1;b=2\n",
+			want:   []Row{{Line: 2, Value: strings.Repeat("a", 32), Type: "hash", URLs: []string{"https://a.com/x.exe", "http://b.com/y?a=1;b=2"}}},
+		},
+		{
+			name:   "csv url with emails",
+			format: FormatCSV,
+			input:  "value,emails\nhttps://a.com/,\"drop@gmail.com; logs@yahoo.com\"\n",
+			want:   []Row{{Line: 2, Value: "https://a.com/", Emails: []string{"drop@gmail.com", "logs@yahoo.com"}}},
+		},
+		{
+			name:   "csv with only values",
+			format: FormatCSV,
+			input:  "value\na.com\n",
+			want:   []Row{{Line: 2, Value: "a.com"}},
+		},
+		{name: "csv without value column", format: FormatCSV, input: "url,tags\na.com,x\n", wantErr: true},
+		{
+			name:   "jsonl with list and string tags",
+			format: FormatJSONL,
+			input: `{"value": "https://a.com/", "tags": ["phishing", "tlp:amber"]}` + "\n\n" +
+				`{"value": "b.com", "type": "domain", "tags": "malware, c2", "first_seen": "2025-02-01"}` + "\n" +
+				`{"value": broken}` + "\n" +
+				`{"value": "c.com", "tags": 3}` + "\n" +
+				`{"value": "d41d8cd98f00b204e9800998ecf8427e", "urls": ["https://a.com/x.exe"]}` + "\n",
+			want: []Row{
+				{Line: 1, Value: "https://a.com/", Tags: []string{"phishing", "tlp:amber"}},
+				{Line: 3, Value: "b.com", Type: "domain", Tags: []string{"malware", "c2"}, FirstSeen: "2025-02-01"},
+				{Line: 4, Error: "invalid JSON: invalid character 'b' looking for beginning of value"},
+				{Line: 5, Value: "c.com", Error: "tags must be a list or a comma separated string"},
+				{Line: 6, Value: "d41d8cd98f00b204e9800998ecf8427e", URLs: []string{"https://a.com/x.exe"}},
+			},
+		},
+		{name: "unknown format", format: "xml", input: "<iocs/>", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := ReadRows(strings.NewReader(tt.input), tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadRows error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(rows, tt.want) {
+				t.Errorf("ReadRows =\n%+v\nwant\n%+v", rows, tt.want)
+			}
+		})
+	}
+}
@@ -3,11 +3,15 @@ package hosting
 import (
 	"context"
 	"fmt"
+	"net"
 	"strings"
 	"time"
 
 	"github.com/cti-team/takedown/internal/connectors/registrar"
+	"github.com/cti-team/takedown/internal/contacts"
+	"github.com/cti-team/takedown/internal/evidence"
 	"github.com/cti-team/takedown/internal/state"
+	"github.com/cti-team/takedown/pkg/defang"
 	"github.com/cti-team/takedown/pkg/models"
 )
 
@@ -15,6 +19,7 @@ import (
 type GenericHostingConnector struct {
 	smtpConfig registrar.SMTPConfig
 	templates  map[string]string
+	bundles    *evidence.BundleExporter
 }
 
 // NewGenericHostingConnector cria um novo connector genérico para hosting.
@@ -28,27 +33,43 @@ func NewGenericHostingConnector(smtpConfig registrar.SMTPConfig) *GenericHosting
 	return connector
 }
 
+// SetBundleExporter anexa o evidence bundle assinado aos emails de remoção.
+func (g *GenericHostingConnector) SetBundleExporter(exporter *evidence.BundleExporter) {
+	g.bundles = exporter
+}
+
 // GetType retorna o tipo do connector.
 func (g *GenericHostingConnector) GetType() string {
 	return "hosting"
 }
 
 // Submit submete um takedown request para o provedor de hosting.
-func (g *GenericHostingConnector) Submit(_ context.Context, request *models.TakedownRequest, evidence *models.EvidencePack) error {
+func (g *GenericHostingConnector) Submit(ctx context.Context, request *models.TakedownRequest, evidence *models.EvidencePack) error {
 	// Preparar email baseado no template
 	subject, body, err := g.prepareEmail(request, evidence)
 	if err != nil {
 		return fmt.Errorf("failed to prepare email: %w", err)
 	}
 
-	// Determinar email de destino
+	// Determinar email de destino; sem contato resolvido, apenas o diretório é consultado
 	abuseEmail := request.Target.Email
 	if abuseEmail == "" {
-		abuseEmail = g.guessAbuseEmail(request.Target.Entity)
+		query := contacts.Query{Kind: contacts.KindHosting, Name: request.Target.Entity}
+		if provider, ok := contacts.Default().Lookup(query); ok {
+			abuseEmail = provider.Abuse.Email
+		}
+	}
+	if abuseEmail == "" {
+		return fmt.Errorf("no abuse email known for %s", request.Target.Entity)
+	}
+
+	attachments, err := registrar.BundleAttachment(ctx, g.bundles, request, evidence)
+	if err != nil {
+		return err
 	}
 
 	// Enviar email
-	err = g.sendEmail(abuseEmail, subject, body)
+	err = g.sendEmail(abuseEmail, subject, body, attachments...)
 	if err != nil {
 		return fmt.Errorf("failed to send email: %w", err)
 	}
@@ -100,11 +121,20 @@ func (g *GenericHostingConnector) prepareEmail(request *models.TakedownRequest,
 	}
 
 	// Extrair informações do evidence
-	domain := g.extractDomain(evidence.Defanged)
+	// O hosting remove o conteúdo do host completo (não apenas do domínio registrável)
+	host := request.Target.Domain
+	if host == "" {
+		host = defang.Host(evidence.Defanged)
+	}
+	domain := defang.Domain(host)
 	ip := "unknown"
 	if len(evidence.DNS.A) > 0 {
 		ip = evidence.DNS.A[0]
 	}
+	if net.ParseIP(host) != nil {
+		// IOC de IP: não há domínio, o alvo é o próprio endereço
+		ip, domain = host, "none (bare IP)"
+	}
 
 	// Preparar subject
 	title := strings.ToUpper(category[:1]) + category[1:]
@@ -122,80 +152,18 @@ func (g *GenericHostingConnector) prepareEmail(request *models.TakedownRequest,
 	body = strings.ReplaceAll(body, "{first_seen}", evidence.CollectedAt.Format("2006-01-02 15:04:05 UTC"))
 	body = strings.ReplaceAll(body, "{risk_score}", fmt.Sprintf("%d", evidence.Risk.Score))
 	body = strings.ReplaceAll(body, "{rationale}", evidence.Risk.Rationale)
+	body = strings.ReplaceAll(body, "{content_findings}", evidence.Content.Summary())
+	body = strings.ReplaceAll(body, "{cloaking}", evidence.Cloaking.Summary())
+	body = strings.ReplaceAll(body, "{services}", evidence.ServicesSummary())
+	body = strings.ReplaceAll(body, "{sample}", evidence.File.Summary())
 	body = strings.ReplaceAll(body, "{defanged_url}", evidence.Defanged)
 
 	return subject, body, nil
 }
 
 // sendEmail envia email usando a mesma função do registrar connector
-func (g *GenericHostingConnector) sendEmail(to, subject, body string) error {
-	// Reutilizar função de envio de email
-	// Em uma implementação real, isso seria um serviço compartilhado
-	return fmt.Errorf("SMTP not implemented in this demo - would send to %s", to)
-}
-
-// guessAbuseEmail tenta adivinhar o email de abuse do provedor
-func (g *GenericHostingConnector) guessAbuseEmail(providerName string) string {
-	// Mapeamento de provedores conhecidos
-	knownProviders := map[string]string{
-		"digitalocean": "abuse@digitalocean.com",
-		"amazon":       "abuse@amazonaws.com",
-		"google":       "network-abuse@google.com",
-		"microsoft":    "abuse@microsoft.com",
-		"cloudflare":   "abuse@cloudflare.com",
-		"ovh":          "abuse@ovh.net",
-		"hetzner":      "abuse@hetzner.de",
-		"vultr":        "abuse@vultr.com",
-		"linode":       "abuse@linode.com",
-	}
-
-	providerLower := strings.ToLower(providerName)
-	for provider, email := range knownProviders {
-		if strings.Contains(providerLower, provider) {
-			return email
-		}
-	}
-
-	// Fallback: abuse@domain
-	domain := g.extractDomainFromProvider(providerName)
-	return fmt.Sprintf("abuse@%s", domain)
-}
-
-// extractDomainFromProvider extrai domínio do nome do provedor
-func (g *GenericHostingConnector) extractDomainFromProvider(provider string) string {
-	provider = strings.ToLower(provider)
-	provider = strings.ReplaceAll(provider, " ", "")
-	provider = strings.ReplaceAll(provider, ",", "")
-	provider = strings.ReplaceAll(provider, "llc", "")
-	provider = strings.ReplaceAll(provider, "inc", "")
-	provider = strings.ReplaceAll(provider, "ltd", "")
-
-	if provider == "" {
-		return "example.com"
-	}
-
-	return provider + ".com"
-}
-
-// extractDomain extrai domínio de URL defanged
-func (g *GenericHostingConnector) extractDomain(defanged string) string {
-	clean := strings.ReplaceAll(defanged, "[.]", ".")
-	clean = strings.ReplaceAll(clean, "hxxp://", "http://")
-	clean = strings.ReplaceAll(clean, "hxxps://", "https://")
-
-	// Extrair apenas o hostname
-	if strings.Contains(clean, "://") {
-		parts := strings.Split(clean, "://")
-		if len(parts) > 1 {
-			hostPath := parts[1]
-			if strings.Contains(hostPath, "/") {
-				return strings.Split(hostPath, "/")[0]
-			}
-			return hostPath
-		}
-	}
-
-	return clean
+func (g *GenericHostingConnector) sendEmail(to, subject, body string, attachments ...registrar.Attachment) error {
+	return registrar.SendMail(g.smtpConfig, to, subject, body, attachments...)
 }
 
 // loadTemplates carrega templates de email para hosting
@@ -218,6 +186,8 @@ CASE DETAILS:
 EVIDENCE:
 - URLs (defanged): {defanged_url}
 - Analysis: {rationale}
+- Page findings: {content_findings}
+- Cloaking: {cloaking}
 
 REQUESTED ACTION:
 Immediate removal of phishing content and customer notification.
@@ -250,6 +220,7 @@ CASE DETAILS:
 EVIDENCE:
 - URLs (defanged): {defanged_url}
 - Analysis: {rationale}
+- Sample: {sample}
 
 REQUESTED ACTION:
 Immediate removal of malware payload and customer notification.
@@ -277,6 +248,7 @@ CASE DETAILS:
 EVIDENCE:
 - URLs (defanged): {defanged_url}
 - Analysis: {rationale}
+- Open services: {services}
 
 REQUESTED ACTION:
 Immediate takedown of C2 infrastructure.
@@ -306,6 +278,8 @@ CASE DETAILS:
 EVIDENCE:
 - URLs (defanged): {defanged_url}
 - Analysis: {rationale}
+- Open services: {services}
+- Sample: {sample}
 
 Please investigate and take appropriate action per your AUP.
 
@@ -0,0 +1,139 @@
+package mailbox
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cti-team/takedown/internal/connectors/registrar"
+	"github.com/cti-team/takedown/internal/state"
+	"github.com/cti-team/takedown/pkg/defang"
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// ProviderConnector pede aos provedores de email a desativação de contas drop e remetentes.
+type ProviderConnector struct {
+	*registrar.EmailSender
+	templates map[string]string
+}
+
+// NewProviderConnector cria um novo connector para provedores de email.
+func NewProviderConnector(smtpConfig registrar.SMTPConfig) *ProviderConnector {
+	connector := &ProviderConnector{
+		EmailSender: registrar.NewEmailSender(smtpConfig),
+		templates:   make(map[string]string),
+	}
+	connector.loadTemplates()
+
+	return connector
+}
+
+// GetType retorna o tipo do connector.
+func (p *ProviderConnector) GetType() string {
+	return "mailbox"
+}
+
+// Submit pede a desativação da conta por email; provedores só com webform param em needs_manual antes.
+func (p *ProviderConnector) Submit(ctx context.Context, request *models.TakedownRequest, evidence *models.EvidencePack) error {
+	subject, body := p.prepareEmail(request, evidence)
+	return p.Send(ctx, request, evidence, subject, body, "account disable request")
+}
+
+// CheckStatus verifica o status junto ao provedor de email.
+func (p *ProviderConnector) CheckStatus(_ context.Context, _ *models.TakedownRequest) (*state.StatusUpdate, error) {
+	// Provedores de email não expõem o status da denúncia; o follow-up confere se a conta recebe email
+	nextFollowUp := time.Now().Add(24 * time.Hour)
+
+	return &state.StatusUpdate{
+		Status:       models.StatusFollowUp,
+		Notes:        "Awaiting account suspension by the mail provider (24h SLA)",
+		NextFollowUp: &nextFollowUp,
+	}, nil
+}
+
+// prepareEmail monta o pedido: conta drop (recebe os dados do kit) ou remetente da campanha.
+func (p *ProviderConnector) prepareEmail(request *models.TakedownRequest, evidence *models.EvidencePack) (string, string) {
+	category := "drop"
+	for _, tag := range request.Tags {
+		if tag == "sender" || tag == "spam" {
+			category = "sender"
+			break
+		}
+	}
+
+	account := request.Target.Domain
+	if account == "" {
+		account = defang.Refang(evidence.Defanged)
+	}
+	pages := "none recorded"
+	if len(evidence.PhishingURLs) > 0 {
+		pages = strings.Join(evidence.PhishingURLs, "\n  ")
+	}
+
+	subject := fmt.Sprintf("[Abuse] Please disable account %s — phishing", defang.Defang(account))
+	if category == "drop" {
+		subject = fmt.Sprintf("[Abuse] Please disable account %s — receives stolen credentials", defang.Defang(account))
+	}
+
+	body := registrar.FillTemplate(p.templates[category], request, evidence, map[string]string{
+		"account":       defang.Defang(account),
+		"provider":      request.Target.Entity,
+		"phishing_urls": pages,
+	})
+
+	return subject, body
+}
+
+// loadTemplates carrega templates de email para provedores de email
+func (p *ProviderConnector) loadTemplates() {
+	p.templates["drop"] = `Hello Abuse Team,
+
+The account below, hosted on your service, receives credentials stolen
+by an active phishing kit. Please DISABLE the account.
+
+CASE DETAILS:
+- Case ID: {case_id}
+- Evidence ID: {evidence_id}
+- Account: {account}
+- Provider: {provider}
+- First seen: {first_seen}
+- Risk Score: {risk_score}/100
+
+PHISHING PAGES SENDING DATA TO THIS ACCOUNT (defanged):
+  {phishing_urls}
+
+REQUESTED ACTION:
+Disable the account and stop delivery to it. Disabling the mailbox stops
+the kit from collecting credentials even while the pages stay online.
+
+Please confirm receipt and provide a ticket ID.
+
+Regards,
+CTI Security Team`
+
+	p.templates["sender"] = `Hello Abuse Team,
+
+The account below, hosted on your service, is sending phishing emails
+that impersonate our customers' brands. Please DISABLE the account.
+
+CASE DETAILS:
+- Case ID: {case_id}
+- Evidence ID: {evidence_id}
+- Account: {account}
+- Provider: {provider}
+- First seen: {first_seen}
+- Risk Score: {risk_score}/100
+
+LINKED PHISHING PAGES (defanged):
+  {phishing_urls}
+
+REQUESTED ACTION:
+Disable the sending account and review other accounts created by the
+same actor.
+
+Please confirm receipt and provide a ticket ID.
+
+Regards,
+CTI Security Team`
+}
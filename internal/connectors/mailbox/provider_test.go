@@ -0,0 +1,125 @@
+package mailbox
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cti-team/takedown/internal/connectors/registrar"
+	"github.com/cti-team/takedown/internal/evidence"
+	"github.com/cti-team/takedown/internal/redact"
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+func TestProviderConnector_PrepareEmail(t *testing.T) {
+	connector := NewProviderConnector(registrar.SMTPConfig{})
+	evidence := &models.EvidencePack{
+		EvidenceID:   "ev-1",
+		Defanged:     "drop[@]gmail[.]com",
+		PhishingURLs: []string{"hxxps://acme-login[.]example[.]com/auth"},
+	}
+
+	tests := []struct {
+		name        string
+		tags        []string
+		wantSubject string
+		wantBody    string
+	}{
+		{name: "drop mailbox", tags: []string{"phishing"}, wantSubject: "receives stolen credentials", wantBody: "receives credentials stolen"},
+		{name: "sender account", tags: []string{"phishing", "sender"}, wantSubject: "— phishing", wantBody: "is sending phishing emails"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &models.TakedownRequest{
+				CaseID: "tdk-1",
+				Tags:   tt.tags,
+				Target: models.TakedownTarget{Type: "mailbox", Entity: "Google (Gmail / Workspace)", Domain: "drop@gmail.com"},
+			}
+			subject, body := connector.prepareEmail(request, evidence)
+			if !strings.Contains(subject, "drop[@]gmail[.]com") || !strings.Contains(subject, tt.wantSubject) {
+				t.Errorf("Unexpected subject %q", subject)
+			}
+			for _, want := range []string{tt.wantBody, "Account: drop[@]gmail[.]com", "hxxps://acme-login[.]example[.]com/auth", "tdk-1"} {
+				if !strings.Contains(body, want) {
+					t.Errorf("Expected %q in body:\n%s", want, body)
+				}
+			}
+			if strings.Contains(body, "{") {
+				t.Errorf("Unreplaced placeholder in body:\n%s", body)
+			}
+		})
+	}
+}
+
+func TestProviderConnector_RedactedEmailIOC(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	connector := NewProviderConnector(registrar.SMTPConfig{})
+	exporter := evidence.NewBundleExporter(key)
+
+	pack := &models.EvidencePack{
+		EvidenceID: "ev-1",
+		Defanged:   "drop[@]gmail[.]com",
+		PhishingURLs: []string{
+			"hxxps://acme-login[.]example[.]com/auth?to=drop@gmail.com",
+			"hxxps://acme-login[.]example[.]com/auth?owner=victim@bank.com",
+		},
+	}
+	// Sem conta no target, o connector usa o próprio IOC
+	request := &models.TakedownRequest{
+		CaseID: "tdk-1",
+		Tags:   []string{"phishing"},
+		Target: models.TakedownTarget{Type: "mailbox", Entity: "Google (Gmail / Workspace)"},
+	}
+
+	// Como na submissão: o connector recebe a cópia redigida pela state machine e a auditoria
+	redacted, audit := redact.Default().Pack(pack)
+	ctx := redact.WithAudit(context.Background(), audit)
+	subject, body := connector.prepareEmail(request, redacted)
+
+	if !strings.Contains(subject, "drop[@]gmail[.]com") {
+		t.Errorf("Expected the email IOC in the subject, got %q", subject)
+	}
+	for _, want := range []string{"Account: drop[@]gmail[.]com", "auth?to=drop@gmail.com", "owner=[REDACTED:email]"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected %q in body:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "victim@bank.com") {
+		t.Errorf("Victim email leaked into body:\n%s", body)
+	}
+
+	attachments, err := registrar.BundleAttachment(ctx, exporter, request, redacted)
+	if err != nil || len(attachments) != 1 {
+		t.Fatalf("Unexpected attachments %v (%v)", attachments, err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(attachments[0].Data), int64(len(attachments[0].Data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range archive.File {
+		if file.Name != "evidence.json" {
+			continue
+		}
+		reader, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var bundled models.EvidencePack
+		if err := json.NewDecoder(reader).Decode(&bundled); err != nil {
+			t.Fatal(err)
+		}
+		_ = reader.Close()
+		if bundled.Defanged != "drop[@]gmail[.]com" {
+			t.Errorf("Email IOC redacted in the bundle: %q", bundled.Defanged)
+		}
+	}
+}
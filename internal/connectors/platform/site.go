@@ -0,0 +1,130 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cti-team/takedown/internal/connectors/registrar"
+	"github.com/cti-team/takedown/internal/state"
+	"github.com/cti-team/takedown/pkg/defang"
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// SiteConnector pede às plataformas (github.io, blogspot, Wix...) a remoção do site do cliente.
+type SiteConnector struct {
+	*registrar.EmailSender
+	templates map[string]string
+}
+
+// NewSiteConnector cria um novo connector para plataformas.
+func NewSiteConnector(smtpConfig registrar.SMTPConfig) *SiteConnector {
+	connector := &SiteConnector{
+		EmailSender: registrar.NewEmailSender(smtpConfig),
+		templates:   make(map[string]string),
+	}
+	connector.loadTemplates()
+
+	return connector
+}
+
+// GetType retorna o tipo do connector.
+func (s *SiteConnector) GetType() string {
+	return "platform"
+}
+
+// Submit pede a remoção do site por email; plataformas só com webform ou API param em needs_manual antes.
+func (s *SiteConnector) Submit(ctx context.Context, request *models.TakedownRequest, evidence *models.EvidencePack) error {
+	subject, body := s.prepareEmail(request, evidence)
+	return s.Send(ctx, request, evidence, subject, body, "site removal request")
+}
+
+// CheckStatus verifica o status junto à plataforma.
+func (s *SiteConnector) CheckStatus(_ context.Context, _ *models.TakedownRequest) (*state.StatusUpdate, error) {
+	// Plataformas não expõem o status da denúncia; o follow-up confere se o site saiu do ar
+	nextFollowUp := time.Now().Add(24 * time.Hour)
+
+	return &state.StatusUpdate{
+		Status:       models.StatusFollowUp,
+		Notes:        "Awaiting site removal by the platform (24-48h SLA)",
+		NextFollowUp: &nextFollowUp,
+	}, nil
+}
+
+// prepareEmail monta o pedido de remoção: site de phishing ou arquivo de malware na plataforma.
+func (s *SiteConnector) prepareEmail(request *models.TakedownRequest, evidence *models.EvidencePack) (string, string) {
+	category := "phishing"
+	for _, tag := range request.Tags {
+		if tag == "malware" {
+			category = "malware"
+			break
+		}
+	}
+
+	// A plataforma remove o site do cliente (o host), nunca o domínio da plataforma
+	site := request.Target.Domain
+	if site == "" {
+		site = defang.Host(evidence.Defanged)
+	}
+
+	subject := fmt.Sprintf("[Abuse] Phishing site on %s: %s", request.Target.Entity, defang.Defang(site))
+	if category == "malware" {
+		subject = fmt.Sprintf("[Abuse] Malware hosted on %s: %s", request.Target.Entity, defang.Defang(site))
+	}
+
+	body := registrar.FillTemplate(s.templates[category], request, evidence, map[string]string{
+		"site":         defang.Defang(site),
+		"platform":     request.Target.Entity,
+		"defanged_url": evidence.Defanged,
+		"sample":       evidence.File.Summary(),
+	})
+
+	return subject, body
+}
+
+// loadTemplates carrega templates de email para plataformas
+func (s *SiteConnector) loadTemplates() {
+	s.templates["phishing"] = `Hello Abuse Team,
+
+The site below, published by one of your users on {platform}, is an
+active phishing page. Please REMOVE the site.
+
+CASE DETAILS:
+- Case ID: {case_id}
+- Evidence ID: {evidence_id}
+- Site: {site}
+- URL (defanged): {defanged_url}
+- First seen: {first_seen}
+- Risk Score: {risk_score}/100
+
+REQUESTED ACTION:
+Take down the site and suspend the account that published it. Only this
+site is affected; we are not asking for action on your platform domain.
+
+Please confirm receipt and provide a ticket ID.
+
+Regards,
+CTI Security Team`
+
+	s.templates["malware"] = `Hello Abuse Team,
+
+The file below, published by one of your users on {platform}, is being
+used to distribute malware. Please REMOVE the file.
+
+CASE DETAILS:
+- Case ID: {case_id}
+- Evidence ID: {evidence_id}
+- Site: {site}
+- URL (defanged): {defanged_url}
+- Sample: {sample}
+- First seen: {first_seen}
+- Risk Score: {risk_score}/100
+
+REQUESTED ACTION:
+Remove the file and review other content published by the same account.
+
+Please confirm receipt and provide a ticket ID.
+
+Regards,
+CTI Security Team`
+}
@@ -0,0 +1,49 @@
+package platform
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cti-team/takedown/internal/connectors/registrar"
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+func TestSiteConnector_PrepareEmail(t *testing.T) {
+	connector := NewSiteConnector(registrar.SMTPConfig{})
+	evidence := &models.EvidencePack{
+		EvidenceID: "ev-1",
+		Defanged:   "hxxps://acme-login[.]github[.]io/auth",
+	}
+
+	tests := []struct {
+		name        string
+		tags        []string
+		wantSubject string
+		wantBody    string
+	}{
+		{name: "phishing site", tags: []string{"phishing"}, wantSubject: "Phishing site on GitHub, Inc.", wantBody: "active phishing page"},
+		{name: "malware file", tags: []string{"malware"}, wantSubject: "Malware hosted on GitHub, Inc.", wantBody: "Sample: no file captured"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &models.TakedownRequest{
+				CaseID: "tdk-1",
+				Tags:   tt.tags,
+				Target: models.TakedownTarget{Type: "platform", Entity: "GitHub, Inc.", Domain: "acme-login.github.io"},
+			}
+			subject, body := connector.prepareEmail(request, evidence)
+			if !strings.Contains(subject, "acme-login[.]github[.]io") || !strings.Contains(subject, tt.wantSubject) {
+				t.Errorf("Unexpected subject %q", subject)
+			}
+			for _, want := range []string{tt.wantBody, "Site: acme-login[.]github[.]io", "hxxps://acme-login[.]github[.]io/auth", "tdk-1"} {
+				if !strings.Contains(body, want) {
+					t.Errorf("Expected %q in body:\n%s", want, body)
+				}
+			}
+			if strings.Contains(body, "{") {
+				t.Errorf("Unreplaced placeholder in body:\n%s", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package registrar
+
+import (
+	"github.com/cti-team/takedown/pkg/defang"
+	"github.com/cti-team/takedown/pkg/indicator"
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// targetDomain retorna o domínio registrável alvo: o definido no roteamento ou o extraído da evidência.
+// O registrar suspende example.com.br, nunca login.bank.example.com.br.
+func targetDomain(request *models.TakedownRequest, evidence *models.EvidencePack) string {
+	if request.Target.Domain != "" {
+		return request.Target.Domain
+	}
+	if domain := indicator.RegistrableDomain(evidence.Defanged); domain != "" {
+		return domain
+	}
+	return defang.Host(evidence.Defanged)
+}
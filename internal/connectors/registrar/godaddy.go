@@ -3,11 +3,13 @@ package registrar
 import (
 	"context"
 	"fmt"
-	"net/smtp"
 	"strings"
 	"time"
 
+	"github.com/cti-team/takedown/internal/contacts"
+	"github.com/cti-team/takedown/internal/evidence"
 	"github.com/cti-team/takedown/internal/state"
+	"github.com/cti-team/takedown/pkg/defang"
 	"github.com/cti-team/takedown/pkg/models"
 )
 
@@ -15,6 +17,7 @@ import (
 type GoDaddyConnector struct {
 	smtpConfig SMTPConfig
 	templates  map[string]string
+	bundles    *evidence.BundleExporter
 }
 
 // SMTPConfig configuração para envio de emails
@@ -36,6 +39,11 @@ func NewGoDaddyConnector(smtpConfig SMTPConfig) *GoDaddyConnector {
 	return connector
 }
 
+// SetBundleExporter anexa o evidence bundle assinado aos emails de takedown
+func (g *GoDaddyConnector) SetBundleExporter(exporter *evidence.BundleExporter) {
+	g.bundles = exporter
+}
+
 // GetType retorna o tipo do connector
 func (g *GoDaddyConnector) GetType() string {
 	return "registrar"
@@ -54,13 +62,21 @@ func (g *GoDaddyConnector) Submit(ctx context.Context, request *models.TakedownR
 		return fmt.Errorf("failed to prepare email: %w", err)
 	}
 
-	// Enviar email para abuse@godaddy.com
-	abuseEmail := "abuse@godaddy.com"
-	if request.Target.Email != "" {
-		abuseEmail = request.Target.Email
+	// Enviar email para o contato de abuse da GoDaddy no diretório
+	abuseEmail := request.Target.Email
+	if abuseEmail == "" {
+		abuseEmail = contacts.Default().RegistrarAbuseEmail(request.Target.Entity)
+	}
+	if abuseEmail == "" {
+		return fmt.Errorf("no abuse email known for %s", request.Target.Entity)
+	}
+
+	attachments, err := BundleAttachment(ctx, g.bundles, request, evidence)
+	if err != nil {
+		return err
 	}
 
-	err = g.sendEmail(abuseEmail, subject, body)
+	err = g.sendEmail(abuseEmail, subject, body, attachments...)
 	if err != nil {
 		return fmt.Errorf("failed to send email: %w", err)
 	}
@@ -109,7 +125,7 @@ func (g *GoDaddyConnector) prepareEmail(request *models.TakedownRequest, evidenc
 	}
 
 	// Substituir placeholders
-	domain := evidence.Defanged
+	domain := defang.Domain(targetDomain(request, evidence))
 	if domain == "" {
 		domain = "suspicious-domain[.]com" // fallback
 	}
@@ -124,26 +140,15 @@ func (g *GoDaddyConnector) prepareEmail(request *models.TakedownRequest, evidenc
 	body = strings.ReplaceAll(body, "{first_seen}", evidence.CollectedAt.Format("2006-01-02 15:04:05 UTC"))
 	body = strings.ReplaceAll(body, "{risk_score}", fmt.Sprintf("%d", evidence.Risk.Score))
 	body = strings.ReplaceAll(body, "{rationale}", evidence.Risk.Rationale)
+	body = strings.ReplaceAll(body, "{content_findings}", evidence.Content.Summary())
+	body = strings.ReplaceAll(body, "{cloaking}", evidence.Cloaking.Summary())
 
 	return subject, body, nil
 }
 
 // sendEmail envia um email via SMTP
-func (g *GoDaddyConnector) sendEmail(to, subject, body string) error {
-	// Configurar conexão SMTP
-	auth := smtp.PlainAuth("", g.smtpConfig.Username, g.smtpConfig.Password, g.smtpConfig.Host)
-
-	// Preparar headers e corpo do email
-	msg := fmt.Sprintf("To: %s\r\n"+
-		"From: %s\r\n"+
-		"Subject: %s\r\n"+
-		"Content-Type: text/plain; charset=UTF-8\r\n"+
-		"\r\n"+
-		"%s", to, g.smtpConfig.From, subject, body)
-
-	// Enviar email
-	addr := fmt.Sprintf("%s:%d", g.smtpConfig.Host, g.smtpConfig.Port)
-	return smtp.SendMail(addr, auth, g.smtpConfig.From, []string{to}, []byte(msg))
+func (g *GoDaddyConnector) sendEmail(to, subject, body string, attachments ...Attachment) error {
+	return SendMail(g.smtpConfig, to, subject, body, attachments...)
 }
 
 // loadTemplates carrega templates de email
@@ -161,6 +166,8 @@ DADOS DO CASO:
 - Risk Score: {risk_score}/100
 - Primeiro visto: {first_seen}
 - Análise: {rationale}
+- Achados na página: {content_findings}
+- Cloaking: {cloaking}
 
 AÇÃO SOLICITADA:
 Suspensão imediata do domínio por violação de ToS (phishing/fraud).
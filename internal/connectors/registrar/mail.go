@@ -0,0 +1,202 @@
+package registrar
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strings"
+
+	"github.com/cti-team/takedown/internal/evidence"
+	"github.com/cti-team/takedown/internal/redact"
+	"github.com/cti-team/takedown/pkg/models"
+	"gopkg.in/yaml.v3"
+)
+
+// base64LineLength é o limite de linha da RFC 2045 para partes em base64
+const base64LineLength = 76
+
+// Attachment é um arquivo anexado ao email de takedown
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// BundleAttachment gera o evidence bundle assinado do pack como anexo; sem exportador, não há anexo.
+// Se a submissão já redigiu o pack (redact.WithAudit), o bundle reaproveita essa auditoria.
+func BundleAttachment(ctx context.Context, exporter *evidence.BundleExporter, request *models.TakedownRequest, pack *models.EvidencePack) ([]Attachment, error) {
+	if exporter == nil || pack == nil {
+		return nil, nil
+	}
+	var data []byte
+	var err error
+	if audit, ok := redact.AuditFromContext(ctx); ok {
+		data, _, err = exporter.BundleRedacted(pack, audit, request)
+	} else {
+		data, _, err = exporter.Bundle(pack, request)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to build evidence bundle: %w", err)
+	}
+	return []Attachment{{Name: evidence.BundleName(pack), ContentType: "application/zip", Data: data}}, nil
+}
+
+// BuildMessage monta o email; com anexos, o corpo vira a primeira parte de um multipart/mixed
+func BuildMessage(from, to, subject, body string, attachments ...Attachment) []byte {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", subject))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+
+	if len(attachments) == 0 {
+		msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+		msg.WriteString(body)
+		return msg.Bytes()
+	}
+
+	writer := multipart.NewWriter(&msg)
+	fmt.Fprintf(&msg, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", writer.Boundary())
+
+	// Escritas em bytes.Buffer não falham
+	part, _ := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"text/plain; charset=UTF-8"},
+	})
+	_, _ = part.Write([]byte(body))
+
+	for _, attachment := range attachments {
+		contentType := attachment.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		part, _ := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(contentType, map[string]string{"name": attachment.Name})},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		encoded := base64.StdEncoding.EncodeToString(attachment.Data)
+		for len(encoded) > base64LineLength {
+			_, _ = part.Write([]byte(encoded[:base64LineLength] + "\r\n"))
+			encoded = encoded[base64LineLength:]
+		}
+		_, _ = part.Write([]byte(encoded + "\r\n"))
+	}
+	_ = writer.Close()
+
+	return msg.Bytes()
+}
+
+// SendMail envia o email via SMTP com a configuração dada
+func SendMail(config SMTPConfig, to, subject, body string, attachments ...Attachment) error {
+	auth := smtp.PlainAuth("", config.Username, config.Password, config.Host)
+	msg := BuildMessage(config.From, to, subject, body, attachments...)
+	addr := fmt.Sprintf("%s:%d", config.Host, config.Port)
+	return smtp.SendMail(addr, auth, config.From, []string{to}, msg)
+}
+
+// EmailSender reúne o envio dos connectors que só denunciam por email: anexa o evidence
+// bundle, envia ao contato do target e registra o envio no histórico do caso
+type EmailSender struct {
+	smtpConfig SMTPConfig
+	bundles    *evidence.BundleExporter
+}
+
+// NewEmailSender cria o sender com a configuração SMTP dada
+func NewEmailSender(smtpConfig SMTPConfig) *EmailSender {
+	return &EmailSender{smtpConfig: smtpConfig}
+}
+
+// SetBundleExporter anexa o evidence bundle assinado aos emails enviados
+func (e *EmailSender) SetBundleExporter(exporter *evidence.BundleExporter) {
+	e.bundles = exporter
+}
+
+// Send envia o pedido ao email do target; action descreve o pedido no evento email_sent
+func (e *EmailSender) Send(ctx context.Context, request *models.TakedownRequest, pack *models.EvidencePack, subject, body, action string) error {
+	abuseEmail := request.Target.Email
+	if abuseEmail == "" {
+		return fmt.Errorf("no abuse email known for %s", request.Target.Entity)
+	}
+
+	attachments, err := BundleAttachment(ctx, e.bundles, request, pack)
+	if err != nil {
+		return err
+	}
+	if err := SendMail(e.smtpConfig, abuseEmail, subject, body, attachments...); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	request.AddEvent("email_sent", "email", abuseEmail, fmt.Sprintf("Sent %s to %s", action, abuseEmail))
+	return nil
+}
+
+// FillTemplate preenche os campos comuns do caso ({case_id}, {evidence_id}, {first_seen},
+// {risk_score}) e os campos próprios do connector, sem reprocessar os valores inseridos
+func FillTemplate(template string, request *models.TakedownRequest, pack *models.EvidencePack, fields map[string]string) string {
+	pairs := []string{
+		"{case_id}", request.CaseID,
+		"{evidence_id}", request.EvidenceID,
+		"{first_seen}", pack.CollectedAt.Format("2006-01-02 15:04:05 UTC"),
+		"{risk_score}", fmt.Sprintf("%d", pack.Risk.Score),
+	}
+	for name, value := range fields {
+		pairs = append(pairs, "{"+name+"}", value)
+	}
+	return strings.NewReplacer(pairs...).Replace(template)
+}
+
+// smtpFile é o formato de configs/smtp.yaml; a senha vem de uma variável de ambiente
+type smtpFile struct {
+	SMTP struct {
+		Host        string `yaml:"host"`
+		Port        int    `yaml:"port"`
+		Username    string `yaml:"username"`
+		PasswordEnv string `yaml:"password_env"`
+		From        string `yaml:"from"`
+	} `yaml:"smtp"`
+}
+
+// LoadSMTPConfig carrega o servidor SMTP usado pelos connectors de email
+func LoadSMTPConfig(path string) (SMTPConfig, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- caminho vem da configuração do operador
+	if err != nil {
+		return SMTPConfig{}, fmt.Errorf("failed to read smtp config: %w", err)
+	}
+	return ParseSMTPConfig(data)
+}
+
+// ParseSMTPConfig interpreta e valida a configuração SMTP (porta padrão 587, senha em SMTP_PASS)
+func ParseSMTPConfig(data []byte) (SMTPConfig, error) {
+	var file smtpFile
+	file.SMTP.Port = 587
+	file.SMTP.PasswordEnv = "SMTP_PASS"
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return SMTPConfig{}, fmt.Errorf("failed to parse smtp config: %w", err)
+	}
+
+	config := SMTPConfig{
+		Host:     file.SMTP.Host,
+		Port:     file.SMTP.Port,
+		Username: file.SMTP.Username,
+		From:     file.SMTP.From,
+	}
+	if config.Host == "" {
+		return SMTPConfig{}, fmt.Errorf("smtp.host is required")
+	}
+	if config.Port <= 0 || config.Port > 65535 {
+		return SMTPConfig{}, fmt.Errorf("smtp.port %d out of range", config.Port)
+	}
+	if config.From == "" {
+		return SMTPConfig{}, fmt.Errorf("smtp.from is required")
+	}
+	if file.SMTP.PasswordEnv != "" {
+		config.Password = os.Getenv(file.SMTP.PasswordEnv)
+	}
+	return config, nil
+}
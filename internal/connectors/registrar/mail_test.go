@@ -0,0 +1,230 @@
+package registrar
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cti-team/takedown/internal/evidence"
+	"github.com/cti-team/takedown/internal/redact"
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+func TestBuildMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		attachments []Attachment
+		wantParts   int
+	}{
+		{name: "plain text", wantParts: 0},
+		{
+			name: "with bundle",
+			attachments: []Attachment{
+				{Name: "evidence-ev-1.zip", ContentType: "application/zip", Data: bytes.Repeat([]byte{0x50, 0x4b, 0x03, 0x04}, 100)},
+			},
+			wantParts: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := BuildMessage("cti@example.com", "abuse@example.net", "Solicitação — acme[.]com", "corpo do email", tt.attachments...)
+			message, err := mail.ReadMessage(bytes.NewReader(raw))
+			if err != nil {
+				t.Fatalf("invalid message: %v", err)
+			}
+			subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+			if err != nil || subject != "Solicitação — acme[.]com" {
+				t.Fatalf("unexpected subject %q (%v)", subject, err)
+			}
+
+			mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantParts == 0 {
+				body, _ := io.ReadAll(message.Body)
+				if mediaType != "text/plain" || string(body) != "corpo do email" {
+					t.Fatalf("unexpected plain message %s: %q", mediaType, body)
+				}
+				return
+			}
+
+			if mediaType != "multipart/mixed" {
+				t.Fatalf("unexpected content type %s", mediaType)
+			}
+			reader := multipart.NewReader(message.Body, params["boundary"])
+			var parts []*multipart.Part
+			var contents [][]byte
+			for {
+				part, err := reader.NextPart()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				// NextPart decodifica quoted-printable, não base64; os dados chegam como foram escritos
+				content, _ := io.ReadAll(part)
+				parts = append(parts, part)
+				contents = append(contents, content)
+			}
+			if len(parts) != tt.wantParts {
+				t.Fatalf("got %d parts, want %d", len(parts), tt.wantParts)
+			}
+			if string(contents[0]) != "corpo do email" {
+				t.Fatalf("unexpected body part %q", contents[0])
+			}
+			if parts[1].FileName() != "evidence-ev-1.zip" {
+				t.Fatalf("unexpected attachment name %q", parts[1].FileName())
+			}
+			for _, line := range strings.Split(strings.TrimSpace(string(contents[1])), "\r\n") {
+				if len(line) > base64LineLength {
+					t.Fatalf("base64 line longer than %d: %d", base64LineLength, len(line))
+				}
+			}
+		})
+	}
+}
+
+func TestBundleAttachment_RedactionAudit(t *testing.T) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exporter := evidence.NewBundleExporter(private)
+	pack := &models.EvidencePack{
+		EvidenceID: "ev-1",
+		Defanged:   "hxxps://bank-login[.]example/",
+		HTTP: models.HTTPInfo{
+			Headers: map[string]string{"Set-Cookie": "session=abc123"},
+			Body:    "welcome victim@bank.com",
+		},
+	}
+	redacted, audit := redact.Default().Pack(pack)
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		pack      *models.EvidencePack
+		wantTotal int
+	}{
+		{name: "redacted by the submission", ctx: redact.WithAudit(context.Background(), audit), pack: redacted, wantTotal: 2},
+		{name: "redacted by the exporter", ctx: context.Background(), pack: pack, wantTotal: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attachments, err := BundleAttachment(tt.ctx, exporter, nil, tt.pack)
+			if err != nil || len(attachments) != 1 {
+				t.Fatalf("unexpected attachments %v (%v)", attachments, err)
+			}
+			data := attachments[0].Data
+			archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var bundled redact.Audit
+			for _, file := range archive.File {
+				if file.Name != "redaction.json" {
+					continue
+				}
+				reader, err := file.Open()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := json.NewDecoder(reader).Decode(&bundled); err != nil {
+					t.Fatal(err)
+				}
+				_ = reader.Close()
+			}
+			if bundled.Total() != tt.wantTotal {
+				t.Errorf("redaction.json: %s, want %d values", bundled.Summary(), tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestFillTemplate(t *testing.T) {
+	request := &models.TakedownRequest{CaseID: "tdk-1", EvidenceID: "ev-1"}
+	pack := &models.EvidencePack{
+		CollectedAt: time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC),
+		Risk:        models.RiskAssessment{Score: 87},
+	}
+
+	// Valores inseridos não são reprocessados, mesmo que pareçam um placeholder
+	body := FillTemplate("{case_id} {evidence_id} {first_seen} {risk_score} {site} {unknown}", request, pack,
+		map[string]string{"site": "{case_id}"})
+
+	want := "tdk-1 ev-1 2026-03-01 12:30:00 UTC 87 {case_id} {unknown}"
+	if body != want {
+		t.Errorf("got %q, want %q", body, want)
+	}
+}
+
+func TestEmailSender_SendWithoutEmail(t *testing.T) {
+	sender := NewEmailSender(SMTPConfig{})
+	request := &models.TakedownRequest{Target: models.TakedownTarget{Entity: "Example Pages"}}
+
+	err := sender.Send(context.Background(), request, &models.EvidencePack{}, "subject", "body", "site removal request")
+	if err == nil || !strings.Contains(err.Error(), "no abuse email known for Example Pages") {
+		t.Errorf("expected missing email error, got %v", err)
+	}
+	if len(request.History) != 0 {
+		t.Errorf("expected no history events, got %+v", request.History)
+	}
+}
+
+func TestParseSMTPConfig(t *testing.T) {
+	t.Setenv("SMTP_PASS", "")
+	t.Setenv("TEST_SMTP_PASS", "secret")
+
+	tests := []struct {
+		name    string
+		yaml    string
+		want    SMTPConfig
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			yaml: "smtp:\n  host: smtp.example.com\n  from: cti@example.com\n",
+			want: SMTPConfig{Host: "smtp.example.com", Port: 587, From: "cti@example.com"},
+		},
+		{
+			name: "password from env",
+			yaml: "smtp:\n  host: smtp.example.com\n  port: 465\n  username: cti\n  password_env: TEST_SMTP_PASS\n  from: cti@example.com\n",
+			want: SMTPConfig{Host: "smtp.example.com", Port: 465, Username: "cti", Password: "secret", From: "cti@example.com"},
+		},
+		{name: "no host", yaml: "smtp:\n  from: cti@example.com\n", wantErr: "smtp.host"},
+		{name: "no sender", yaml: "smtp:\n  host: smtp.example.com\n", wantErr: "smtp.from"},
+		{name: "bad port", yaml: "smtp:\n  host: smtp.example.com\n  port: 70000\n  from: cti@example.com\n", wantErr: "smtp.port"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ParseSMTPConfig([]byte(tt.yaml))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if config != tt.want {
+				t.Errorf("got %+v, want %+v", config, tt.want)
+			}
+		})
+	}
+}
@@ -4,11 +4,11 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"net/url"
 	"strings"
 	"time"
 
 	"github.com/cti-team/takedown/internal/state"
+	"github.com/cti-team/takedown/pkg/defang"
 	"github.com/cti-team/takedown/pkg/models"
 )
 
@@ -36,7 +36,7 @@ func (r *RegistroBRConnector) GetType() string {
 // Submit submete um takedown request para Registro.br
 func (r *RegistroBRConnector) Submit(ctx context.Context, request *models.TakedownRequest, evidence *models.EvidencePack) error {
 	// Verificar se é domínio .br
-	domain := r.extractDomain(evidence.Defanged)
+	domain := targetDomain(request, evidence)
 	if !strings.HasSuffix(strings.ToLower(domain), ".br") {
 		return fmt.Errorf("this connector is only for .br domains")
 	}
@@ -120,7 +120,7 @@ func (r *RegistroBRConnector) submitContentAbuse(ctx context.Context, request *m
 
 // prepareSACIData prepara dados para submissão ao SACI-Adm
 func (r *RegistroBRConnector) prepareSACIData(request *models.TakedownRequest, evidence *models.EvidencePack) string {
-	domain := r.extractDomain(evidence.Defanged)
+	domain := defang.Domain(targetDomain(request, evidence))
 
 	data := fmt.Sprintf(`SACI-Adm Submission Data:
 Domain: %s
@@ -159,7 +159,7 @@ func (r *RegistroBRConnector) notifyCERTBR(ctx context.Context, request *models.
 
 // prepareCERTNotification prepara notificação para CERT.br
 func (r *RegistroBRConnector) prepareCERTNotification(request *models.TakedownRequest, evidence *models.EvidencePack) string {
-	domain := r.extractDomain(evidence.Defanged)
+	domain := defang.Domain(targetDomain(request, evidence))
 
 	return fmt.Sprintf(`Assunto: [Coordenação de Incidente] %s — %s
 
@@ -188,19 +188,3 @@ CTI Security Team`,
 		request.CaseID, request.EvidenceID, domain, evidence.Risk.Score,
 		evidence.Risk.Rationale, evidence.CollectedAt.Format("2006-01-02 15:04:05 UTC"))
 }
-
-// extractDomain extrai o domínio de uma URL defanged
-func (r *RegistroBRConnector) extractDomain(defanged string) string {
-	// Remover defang
-	clean := strings.ReplaceAll(defanged, "[.]", ".")
-	clean = strings.ReplaceAll(clean, "hxxp://", "http://")
-	clean = strings.ReplaceAll(clean, "hxxps://", "https://")
-
-	// Parse URL
-	if parsedURL, err := url.Parse(clean); err == nil {
-		return parsedURL.Hostname()
-	}
-
-	// Fallback: assumir que é um domínio direto
-	return clean
-}
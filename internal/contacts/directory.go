@@ -0,0 +1,585 @@
+package contacts
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cti-team/takedown/pkg/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Tipos de provedor suportados pelo diretório
+const (
+	KindRegistrar = "registrar"
+	KindHosting   = "hosting"
+	KindCDN       = "cdn"
+	KindCERT      = "cert"
+	KindMailbox   = "mailbox"
+	KindPlatform  = "platform"
+)
+
+// Canais de contato preferenciais
+const (
+	ChannelEmail   = "email"
+	ChannelWebform = "webform"
+	ChannelAPI     = "api"
+)
+
+// Confiança base de entradas do diretório (entradas não verificadas há mais de um ano valem menos)
+const (
+	directoryConfidence      = 85
+	staleDirectoryConfidence = 70
+	directoryStaleAfter      = 365 * 24 * time.Hour
+)
+
+//go:embed directory.yaml
+var embeddedDirectory []byte
+
+// Directory representa o diretório versionado de contatos de abuse
+type Directory struct {
+	Version   int        `yaml:"version" json:"version"`
+	UpdatedAt string     `yaml:"updated_at,omitempty" json:"updated_at,omitempty"`
+	Providers []Provider `yaml:"providers" json:"providers"`
+
+	patterns map[string][]*regexp.Regexp // regex de nome compiladas por ID
+}
+
+// Provider representa um provedor (registrar, hosting ou CDN) e seus canais de abuse
+type Provider struct {
+	ID               string              `yaml:"id" json:"id"`
+	Name             string              `yaml:"name,omitempty" json:"name,omitempty"`
+	Kinds            []string            `yaml:"kinds,omitempty" json:"kinds,omitempty"`
+	Match            ProviderMatch       `yaml:"match,omitempty" json:"match,omitempty"`
+	Abuse            ProviderAbuse       `yaml:"abuse,omitempty" json:"abuse,omitempty"`
+	PreferredChannel string              `yaml:"preferred_channel,omitempty" json:"preferred_channel,omitempty"`
+	Language         string              `yaml:"language,omitempty" json:"language,omitempty"`
+	Escalation       []EscalationContact `yaml:"escalation,omitempty" json:"escalation,omitempty"`
+	Notes            string              `yaml:"notes,omitempty" json:"notes,omitempty"`
+	LastVerified     string              `yaml:"last_verified,omitempty" json:"last_verified,omitempty"`
+	Disabled         bool                `yaml:"disabled,omitempty" json:"disabled,omitempty"` // usado em overrides para remover entradas
+}
+
+// ProviderMatch define como um provedor é identificado
+type ProviderMatch struct {
+	ASNs      []int    `yaml:"asns,omitempty" json:"asns,omitempty"`
+	IANAIDs   []int    `yaml:"iana_ids,omitempty" json:"iana_ids,omitempty"`
+	Names     []string `yaml:"names,omitempty" json:"names,omitempty"`         // regex, case-insensitive
+	CNAMEs    []string `yaml:"cnames,omitempty" json:"cnames,omitempty"`       // substrings de CNAME (detecção de CDN)
+	Countries []string `yaml:"countries,omitempty" json:"countries,omitempty"` // ISO alpha-2 (CERTs nacionais)
+	Domains   []string `yaml:"domains,omitempty" json:"domains,omitempty"`     // domínios de email (webmail)
+	MX        []string `yaml:"mx,omitempty" json:"mx,omitempty"`               // sufixos de MX (domínios próprios no provedor)
+	Hosts     []string `yaml:"hosts,omitempty" json:"hosts,omitempty"`         // sufixos de host de plataformas (github.io, blogspot.com)
+}
+
+// ProviderAbuse reúne os canais de abuse do provedor
+type ProviderAbuse struct {
+	Email   string `yaml:"email,omitempty" json:"email,omitempty"`
+	Phone   string `yaml:"phone,omitempty" json:"phone,omitempty"`
+	Webform string `yaml:"webform,omitempty" json:"webform,omitempty"`
+	API     string `yaml:"api,omitempty" json:"api,omitempty"`
+}
+
+// EscalationContact representa um contato de escalonamento
+type EscalationContact struct {
+	Name    string `yaml:"name" json:"name"`
+	Email   string `yaml:"email,omitempty" json:"email,omitempty"`
+	Webform string `yaml:"webform,omitempty" json:"webform,omitempty"`
+	Phone   string `yaml:"phone,omitempty" json:"phone,omitempty"`
+	Notes   string `yaml:"notes,omitempty" json:"notes,omitempty"`
+}
+
+// Query descreve os critérios de busca no diretório
+type Query struct {
+	Kind    string
+	ASN     int
+	IANAID  int
+	Name    string
+	Country string
+}
+
+var (
+	defaultDirectory *Directory
+	defaultOnce      sync.Once
+	defaultMutex     sync.RWMutex
+)
+
+// Default retorna o diretório em uso (por padrão, a versão embutida no binário)
+func Default() *Directory {
+	defaultOnce.Do(func() {
+		directory, err := ParseDirectory(embeddedDirectory)
+		if err != nil {
+			panic(fmt.Sprintf("invalid embedded contact directory: %v", err))
+		}
+		defaultMutex.Lock()
+		defaultDirectory = directory
+		defaultMutex.Unlock()
+	})
+
+	defaultMutex.RLock()
+	defer defaultMutex.RUnlock()
+	return defaultDirectory
+}
+
+// SetDefault substitui o diretório em uso (ex: após carregar overrides do operador)
+func SetDefault(directory *Directory) {
+	defaultOnce.Do(func() {})
+	defaultMutex.Lock()
+	defaultDirectory = directory
+	defaultMutex.Unlock()
+}
+
+// Embedded retorna uma cópia do diretório embutido no binário
+func Embedded() (*Directory, error) {
+	return ParseDirectory(embeddedDirectory)
+}
+
+// LoadDirectory carrega um diretório de um arquivo YAML
+func LoadDirectory(path string) (*Directory, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- caminho vem da configuração do operador
+	if err != nil {
+		return nil, fmt.Errorf("failed to read contact directory: %w", err)
+	}
+	return ParseDirectory(data)
+}
+
+// LoadWithOverrides carrega o diretório base (embutido se path vazio) e aplica overrides
+func LoadWithOverrides(basePath string, overridePaths ...string) (*Directory, error) {
+	var (
+		directory *Directory
+		err       error
+	)
+
+	if basePath == "" {
+		directory, err = Embedded()
+	} else {
+		directory, err = LoadDirectory(basePath)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range overridePaths {
+		overrides, err := LoadDirectory(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := directory.ApplyOverrides(overrides); err != nil {
+			return nil, fmt.Errorf("failed to apply overrides from %s: %w", path, err)
+		}
+	}
+
+	return directory, nil
+}
+
+// ParseDirectory interpreta e valida um diretório em YAML
+func ParseDirectory(data []byte) (*Directory, error) {
+	var directory Directory
+	if err := yaml.Unmarshal(data, &directory); err != nil {
+		return nil, fmt.Errorf("failed to parse contact directory: %w", err)
+	}
+
+	if err := directory.compile(); err != nil {
+		return nil, err
+	}
+
+	return &directory, nil
+}
+
+// Save grava o diretório, incrementando a versão e a data de atualização
+func (d *Directory) Save(path string) error {
+	d.Version++
+	d.UpdatedAt = time.Now().UTC().Format("2006-01-02")
+
+	data, err := yaml.Marshal(d)
+	if err != nil {
+		return fmt.Errorf("failed to serialize contact directory: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
+
+// compile valida as entradas e pré-compila as regex de nome
+func (d *Directory) compile() error {
+	d.patterns = make(map[string][]*regexp.Regexp, len(d.Providers))
+	seen := make(map[string]bool, len(d.Providers))
+
+	for _, provider := range d.Providers {
+		if provider.ID == "" {
+			return fmt.Errorf("provider %q has no id", provider.Name)
+		}
+		if seen[provider.ID] {
+			return fmt.Errorf("duplicate provider id %q", provider.ID)
+		}
+		seen[provider.ID] = true
+
+		for _, pattern := range provider.Match.Names {
+			re, err := regexp.Compile("(?i)" + pattern)
+			if err != nil {
+				return fmt.Errorf("provider %q: invalid name pattern %q: %w", provider.ID, pattern, err)
+			}
+			d.patterns[provider.ID] = append(d.patterns[provider.ID], re)
+		}
+	}
+
+	return nil
+}
+
+// ApplyOverrides mescla entradas do operador: campos preenchidos substituem os da base
+func (d *Directory) ApplyOverrides(overrides *Directory) error {
+	for _, override := range overrides.Providers {
+		idx := d.indexOf(override.ID)
+
+		switch {
+		case override.Disabled && idx >= 0:
+			d.Providers = append(d.Providers[:idx], d.Providers[idx+1:]...)
+		case override.Disabled:
+			continue
+		case idx >= 0:
+			d.Providers[idx] = mergeProvider(d.Providers[idx], override)
+		default:
+			d.Providers = append(d.Providers, override)
+		}
+	}
+
+	if overrides.Version > 0 {
+		d.UpdatedAt = latestDate(d.UpdatedAt, overrides.UpdatedAt)
+	}
+
+	return d.compile()
+}
+
+// Get retorna um provedor pelo ID
+func (d *Directory) Get(id string) (*Provider, bool) {
+	idx := d.indexOf(id)
+	if idx < 0 {
+		return nil, false
+	}
+	return &d.Providers[idx], true
+}
+
+// Upsert insere ou mescla um provedor
+func (d *Directory) Upsert(provider Provider) error {
+	idx := d.indexOf(provider.ID)
+	if idx >= 0 {
+		d.Providers[idx] = mergeProvider(d.Providers[idx], provider)
+	} else {
+		d.Providers = append(d.Providers, provider)
+	}
+	return d.compile()
+}
+
+// Remove remove um provedor pelo ID
+func (d *Directory) Remove(id string) bool {
+	idx := d.indexOf(id)
+	if idx < 0 {
+		return false
+	}
+	d.Providers = append(d.Providers[:idx], d.Providers[idx+1:]...)
+	delete(d.patterns, id)
+	return true
+}
+
+// List retorna os provedores de um tipo (todos se kind vazio)
+func (d *Directory) List(kind string) []Provider {
+	var result []Provider
+	for _, provider := range d.Providers {
+		if kind == "" || provider.HasKind(kind) {
+			result = append(result, provider)
+		}
+	}
+	return result
+}
+
+// Lookup busca um provedor por ASN, IANA ID, regex de nome e, por último, país
+func (d *Directory) Lookup(query Query) (*Provider, bool) {
+	candidates := d.candidates(query.Kind)
+
+	if query.ASN != 0 {
+		for _, provider := range candidates {
+			if containsInt(provider.Match.ASNs, query.ASN) {
+				return provider, true
+			}
+		}
+	}
+
+	if query.IANAID != 0 {
+		for _, provider := range candidates {
+			if containsInt(provider.Match.IANAIDs, query.IANAID) {
+				return provider, true
+			}
+		}
+	}
+
+	if query.Name != "" {
+		for _, provider := range candidates {
+			for _, re := range d.patterns[provider.ID] {
+				if re.MatchString(query.Name) {
+					return provider, true
+				}
+			}
+		}
+	}
+
+	if query.Country != "" {
+		for _, provider := range candidates {
+			for _, country := range provider.Match.Countries {
+				if strings.EqualFold(country, query.Country) {
+					return provider, true
+				}
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// LookupCNAME detecta CDNs a partir do CNAME do domínio
+func (d *Directory) LookupCNAME(cname string) (*Provider, bool) {
+	cname = strings.ToLower(cname)
+	for _, provider := range d.candidates(KindCDN) {
+		for _, pattern := range provider.Match.CNAMEs {
+			if strings.Contains(cname, strings.ToLower(pattern)) {
+				return provider, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// LookupMailbox identifica o provedor de email pelo domínio do endereço e, para domínios
+// próprios, pelos registros MX
+func (d *Directory) LookupMailbox(domain string, mx []string) (*Provider, bool) {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	candidates := d.candidates(KindMailbox)
+	for _, provider := range candidates {
+		for _, known := range provider.Match.Domains {
+			if strings.EqualFold(known, domain) {
+				return provider, true
+			}
+		}
+	}
+	for _, host := range mx {
+		host = strings.ToLower(strings.TrimSuffix(host, "."))
+		for _, provider := range candidates {
+			for _, suffix := range provider.Match.MX {
+				suffix = strings.ToLower(suffix)
+				if host == suffix || strings.HasSuffix(host, "."+suffix) {
+					return provider, true
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+// LookupPlatform identifica a plataforma que hospeda o host (github.io, web.app, sites.google.com);
+// vale o sufixo mais longo, para que storage.googleapis.com não caia em googleapis.com
+func (d *Directory) LookupPlatform(host string) (*Provider, string, bool) {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	var (
+		best   *Provider
+		suffix string
+	)
+	for _, provider := range d.candidates(KindPlatform) {
+		for _, pattern := range provider.Match.Hosts {
+			pattern = strings.ToLower(pattern)
+			if (host == pattern || strings.HasSuffix(host, "."+pattern)) && len(pattern) > len(suffix) {
+				best, suffix = provider, pattern
+			}
+		}
+	}
+	return best, suffix, best != nil
+}
+
+// RegistrarAbuseEmail retorna o email de abuse de um registrar conhecido pelo nome
+func (d *Directory) RegistrarAbuseEmail(registrarName string) string {
+	if provider, ok := d.Lookup(Query{Kind: KindRegistrar, Name: registrarName}); ok {
+		return provider.Abuse.Email
+	}
+	return ""
+}
+
+// candidates retorna ponteiros para os provedores de um tipo
+func (d *Directory) candidates(kind string) []*Provider {
+	var result []*Provider
+	for i := range d.Providers {
+		if kind == "" || d.Providers[i].HasKind(kind) {
+			result = append(result, &d.Providers[i])
+		}
+	}
+	return result
+}
+
+// indexOf retorna a posição de um provedor pelo ID
+func (d *Directory) indexOf(id string) int {
+	for i, provider := range d.Providers {
+		if provider.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// HasKind verifica se o provedor atua em um tipo de target
+func (p *Provider) HasKind(kind string) bool {
+	for _, k := range p.Kinds {
+		if strings.EqualFold(k, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// ContactInfo converte os canais de abuse para o modelo de contato
+func (p *Provider) ContactInfo() models.ContactInfo {
+	reason := fmt.Sprintf("contact directory entry %q", p.ID)
+	if p.LastVerified != "" {
+		reason += fmt.Sprintf(" (verified %s)", p.LastVerified)
+	}
+
+	return models.ContactInfo{
+		Email:      p.Abuse.Email,
+		Phone:      p.Abuse.Phone,
+		Webform:    p.Abuse.Webform,
+		Source:     models.ContactSourceDirectory,
+		Confidence: p.confidence(time.Now()),
+		Reason:     reason,
+	}
+}
+
+// confidence calcula a confiança base da entrada conforme a data da última verificação
+func (p *Provider) confidence(now time.Time) int {
+	verified, err := time.Parse("2006-01-02", p.LastVerified)
+	if err != nil {
+		return staleDirectoryConfidence
+	}
+	if now.Sub(verified) > directoryStaleAfter {
+		return staleDirectoryConfidence
+	}
+	return directoryConfidence
+}
+
+// CDNInfo converte o provedor para o modelo de CDN
+func (p *Provider) CDNInfo() *models.CDNInfo {
+	return &models.CDNInfo{
+		Name:    p.Name,
+		Abuse:   p.ContactInfo(),
+		Webform: p.Abuse.Webform,
+	}
+}
+
+// CERTInfo converte o provedor para o modelo de CERT nacional
+func (p *Provider) CERTInfo(country string) *models.CERTInfo {
+	return &models.CERTInfo{
+		Name:    p.Name,
+		Country: strings.ToUpper(country),
+		Abuse:   p.ContactInfo(),
+	}
+}
+
+// MailboxInfo converte o provedor para o modelo de provedor de email da conta
+func (p *Provider) MailboxInfo(account string, mx []string) *models.MailboxInfo {
+	return &models.MailboxInfo{
+		Name:    p.Name,
+		Account: account,
+		MX:      mx,
+		Abuse:   p.ContactInfo(),
+	}
+}
+
+// PlatformInfo converte o provedor para o modelo de plataforma do site
+func (p *Provider) PlatformInfo(suffix, site string) *models.PlatformInfo {
+	return &models.PlatformInfo{
+		Name:   p.Name,
+		Suffix: suffix,
+		Site:   site,
+		Abuse:  p.ContactInfo(),
+		API:    p.Abuse.API,
+	}
+}
+
+// mergeProvider aplica os campos não vazios do override sobre a entrada base
+func mergeProvider(base, override Provider) Provider {
+	merged := base
+	if override.Name != "" {
+		merged.Name = override.Name
+	}
+	if len(override.Kinds) > 0 {
+		merged.Kinds = override.Kinds
+	}
+	if len(override.Match.ASNs) > 0 {
+		merged.Match.ASNs = override.Match.ASNs
+	}
+	if len(override.Match.IANAIDs) > 0 {
+		merged.Match.IANAIDs = override.Match.IANAIDs
+	}
+	if len(override.Match.Names) > 0 {
+		merged.Match.Names = override.Match.Names
+	}
+	if len(override.Match.CNAMEs) > 0 {
+		merged.Match.CNAMEs = override.Match.CNAMEs
+	}
+	if len(override.Match.Countries) > 0 {
+		merged.Match.Countries = override.Match.Countries
+	}
+	if len(override.Match.Domains) > 0 {
+		merged.Match.Domains = override.Match.Domains
+	}
+	if len(override.Match.MX) > 0 {
+		merged.Match.MX = override.Match.MX
+	}
+	if len(override.Match.Hosts) > 0 {
+		merged.Match.Hosts = override.Match.Hosts
+	}
+	if override.Abuse.Email != "" {
+		merged.Abuse.Email = override.Abuse.Email
+	}
+	if override.Abuse.Phone != "" {
+		merged.Abuse.Phone = override.Abuse.Phone
+	}
+	if override.Abuse.Webform != "" {
+		merged.Abuse.Webform = override.Abuse.Webform
+	}
+	if override.Abuse.API != "" {
+		merged.Abuse.API = override.Abuse.API
+	}
+	if override.PreferredChannel != "" {
+		merged.PreferredChannel = override.PreferredChannel
+	}
+	if override.Language != "" {
+		merged.Language = override.Language
+	}
+	if len(override.Escalation) > 0 {
+		merged.Escalation = override.Escalation
+	}
+	if override.Notes != "" {
+		merged.Notes = override.Notes
+	}
+	if override.LastVerified != "" {
+		merged.LastVerified = override.LastVerified
+	}
+	return merged
+}
+
+// containsInt verifica se um inteiro está na lista
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// latestDate retorna a data ISO mais recente
+func latestDate(a, b string) string {
+	if b > a {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,298 @@
+package contacts
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+func TestEmbeddedDirectory_Parses(t *testing.T) {
+	directory, err := Embedded()
+	if err != nil {
+		t.Fatalf("Embedded directory should parse: %v", err)
+	}
+	if directory.Version < 1 {
+		t.Errorf("Expected version >= 1, got %d", directory.Version)
+	}
+	if len(directory.List(KindRegistrar)) == 0 || len(directory.List(KindHosting)) == 0 || len(directory.List(KindCDN)) == 0 {
+		t.Error("Expected registrars, hosting and CDN providers in embedded directory")
+	}
+}
+
+func TestDirectory_RegistrarAbuseEmail(t *testing.T) {
+	directory := Default()
+
+	tests := []struct {
+		registrarName string
+		expected      string
+	}{
+		{"GoDaddy.com, LLC", "abuse@godaddy.com"},
+		{"NameCheap, Inc.", "abuse@namecheap.com"},
+		{"Registro.br", "abuse@registro.br"},
+		{"Amazon Registrar, Inc.", "legal@amazon.com"},
+		{"Google LLC", "domain-abuse@google.com"},
+		{"Cloudflare, Inc.", "abuse@cloudflare.com"},
+		{"Unknown Registrar", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.registrarName, func(t *testing.T) {
+			result := directory.RegistrarAbuseEmail(tt.registrarName)
+			if result != tt.expected {
+				t.Errorf("RegistrarAbuseEmail(%s) = %v, want %v", tt.registrarName, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDirectory_Lookup(t *testing.T) {
+	directory := Default()
+
+	tests := []struct {
+		name       string
+		query      Query
+		expectedID string
+		found      bool
+	}{
+		{"ASN", Query{Kind: KindHosting, ASN: 16509}, "amazon-aws", true},
+		{"ASN wins over name", Query{Kind: KindHosting, ASN: 14061, Name: "OVH SAS"}, "digitalocean", true},
+		{"IANA ID", Query{Kind: KindRegistrar, IANAID: 1068}, "namecheap", true},
+		{"Name regex", Query{Kind: KindHosting, Name: "HETZNER-AS"}, "hetzner", true},
+		{"Country", Query{Kind: KindCERT, Country: "br"}, "cert-br", true},
+		{"Country is CERT only", Query{Kind: KindHosting, Country: "BR"}, "", false},
+		{"Kind filter", Query{Kind: KindRegistrar, ASN: 16509}, "", false},
+		{"Unknown", Query{Kind: KindHosting, ASN: 64500, Name: "Example Hosting"}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, found := directory.Lookup(tt.query)
+			if found != tt.found {
+				t.Fatalf("Expected found=%v, got %v", tt.found, found)
+			}
+			if found && provider.ID != tt.expectedID {
+				t.Errorf("Expected provider %s, got %s", tt.expectedID, provider.ID)
+			}
+		})
+	}
+}
+
+func TestDirectory_LookupCNAME(t *testing.T) {
+	directory := Default()
+
+	provider, found := directory.LookupCNAME("d111111abcdef8.CloudFront.net")
+	if !found || provider.ID != "amazon-cloudfront" {
+		t.Fatalf("Expected amazon-cloudfront, got %+v", provider)
+	}
+	if info := provider.CDNInfo(); info.Abuse.Email != "abuse@amazonaws.com" || info.Webform == "" {
+		t.Errorf("Unexpected CDN info: %+v", info)
+	}
+
+	if _, found := directory.LookupCNAME("www.example.com"); found {
+		t.Error("Expected no CDN for plain CNAME")
+	}
+}
+
+func TestDirectory_LookupMailbox(t *testing.T) {
+	directory := Default()
+
+	tests := []struct {
+		name   string
+		domain string
+		mx     []string
+		wantID string
+	}{
+		{name: "webmail domain", domain: "Gmail.com.", wantID: "gmail"},
+		{name: "country webmail", domain: "hotmail.com.br", wantID: "outlook"},
+		{name: "workspace by mx", domain: "acme-suporte.example", mx: []string{"aspmx.l.google.com"}, wantID: "gmail"},
+		{name: "exchange online by mx", domain: "acme-suporte.example", mx: []string{"acme-suporte-example.mail.protection.outlook.com."}, wantID: "outlook"},
+		{name: "mx suffix must match a label", domain: "acme-suporte.example", mx: []string{"mx.notgoogle.com"}},
+		{name: "self-hosted", domain: "acme-suporte.example", mx: []string{"mail.acme-suporte.example"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, found := directory.LookupMailbox(tt.domain, tt.mx)
+			if tt.wantID == "" {
+				if found {
+					t.Errorf("Expected no provider, got %s", provider.ID)
+				}
+				return
+			}
+			if !found || provider.ID != tt.wantID {
+				t.Fatalf("Expected %s, got %+v", tt.wantID, provider)
+			}
+			info := provider.MailboxInfo("drop@"+tt.domain, tt.mx)
+			if info.Account != "drop@"+tt.domain || info.Abuse.Source != models.ContactSourceDirectory {
+				t.Errorf("Unexpected mailbox info: %+v", info)
+			}
+		})
+	}
+}
+
+func TestDirectory_ApplyOverrides(t *testing.T) {
+	directory, err := Embedded()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	overrides, err := ParseDirectory([]byte(`
+version: 3
+updated_at: "2099-01-01"
+providers:
+  - id: godaddy
+    abuse:
+      email: "new-abuse@godaddy.com"
+  - id: enom
+    disabled: true
+  - id: example-host
+    name: "Example Hosting"
+    kinds: ["hosting"]
+    match:
+      asns: [64500]
+    abuse:
+      email: "abuse@example-host.net"
+`))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing overrides: %v", err)
+	}
+
+	if err := directory.ApplyOverrides(overrides); err != nil {
+		t.Fatalf("Unexpected error applying overrides: %v", err)
+	}
+
+	godaddy, _ := directory.Get("godaddy")
+	if godaddy.Abuse.Email != "new-abuse@godaddy.com" {
+		t.Errorf("Expected overridden email, got %s", godaddy.Abuse.Email)
+	}
+	if godaddy.Abuse.Webform == "" || godaddy.Name != "GoDaddy.com, LLC" {
+		t.Error("Expected base fields to be kept when not overridden")
+	}
+
+	if _, found := directory.Get("enom"); found {
+		t.Error("Expected disabled provider to be removed")
+	}
+
+	provider, found := directory.Lookup(Query{Kind: KindHosting, ASN: 64500})
+	if !found || provider.ID != "example-host" {
+		t.Errorf("Expected new provider to be matched, got %+v", provider)
+	}
+
+	if directory.UpdatedAt != "2099-01-01" {
+		t.Errorf("Expected updated_at from overrides, got %s", directory.UpdatedAt)
+	}
+}
+
+func TestLoadWithOverrides_SaveBumpsVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.yaml")
+
+	overrides := &Directory{Version: 1}
+	if err := overrides.Upsert(Provider{
+		ID:    "example-registrar",
+		Kinds: []string{KindRegistrar},
+		Match: ProviderMatch{Names: []string{"example registrar"}},
+		Abuse: ProviderAbuse{Email: "abuse@example-registrar.test"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := overrides.Save(path); err != nil {
+		t.Fatalf("Unexpected error saving: %v", err)
+	}
+
+	saved, err := LoadDirectory(path)
+	if err != nil {
+		t.Fatalf("Unexpected error loading saved file: %v", err)
+	}
+	if saved.Version != 2 {
+		t.Errorf("Expected version 2 after save, got %d", saved.Version)
+	}
+
+	directory, err := LoadWithOverrides("", path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if email := directory.RegistrarAbuseEmail("Example Registrar LLC"); email != "abuse@example-registrar.test" {
+		t.Errorf("Expected override email, got %q", email)
+	}
+}
+
+func TestParseDirectory_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"missing id", "providers:\n  - name: x\n", "no id"},
+		{"duplicate id", "providers:\n  - id: a\n  - id: a\n", "duplicate"},
+		{"invalid regex", "providers:\n  - id: a\n    match:\n      names: [\"(\"]\n", "invalid name pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDirectory([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestGetASNAbuseEmail(t *testing.T) {
+	if email := GetASNAbuseEmail(24940, "HETZNER-AS"); email != "abuse@hetzner.de" {
+		t.Errorf("Expected directory email, got %s", email)
+	}
+	if email := GetASNAbuseEmail(64500, "Example Hosting Ltd"); email != "abuse@examplehosting.com" {
+		t.Errorf("Expected derived email, got %s", email)
+	}
+}
+
+func TestLoadDirectory_MissingFile(t *testing.T) {
+	_, err := LoadDirectory(filepath.Join(t.TempDir(), "missing.yaml"))
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected not-exist error, got %v", err)
+	}
+}
+
+func TestDirectory_LookupPlatform(t *testing.T) {
+	directory := Default()
+
+	tests := []struct {
+		name       string
+		host       string
+		wantID     string
+		wantSuffix string
+	}{
+		{name: "github pages", host: "acme-login.github.io", wantID: "github", wantSuffix: "github.io"},
+		{name: "firebase", host: "acme-login.web.app", wantID: "firebase", wantSuffix: "web.app"},
+		{name: "nested blogspot", host: "www.acme-login.blogspot.com.", wantID: "blogger", wantSuffix: "blogspot.com"},
+		{name: "country blogspot", host: "acme-login.blogspot.com.br", wantID: "blogger", wantSuffix: "blogspot.com.br"},
+		{name: "google sites by host", host: "Sites.Google.com", wantID: "google-sites", wantSuffix: "sites.google.com"},
+		{name: "wix", host: "acmelogin.wixsite.com", wantID: "wix", wantSuffix: "wixsite.com"},
+		{name: "suffix must match a label", host: "acme-login-github.io.example"},
+		{name: "lookalike platform", host: "acme-web.app.example"},
+		{name: "regular domain", host: "login.acme-bank.example"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, suffix, found := directory.LookupPlatform(tt.host)
+			if tt.wantID == "" {
+				if found {
+					t.Errorf("Expected no platform, got %s", provider.ID)
+				}
+				return
+			}
+			if !found || provider.ID != tt.wantID || suffix != tt.wantSuffix {
+				t.Fatalf("Expected %s (%s), got %+v (%s)", tt.wantID, tt.wantSuffix, provider, suffix)
+			}
+			info := provider.PlatformInfo(suffix, tt.host)
+			if !info.HasContact() || info.Abuse.Source != models.ContactSourceDirectory {
+				t.Errorf("Unexpected platform info: %+v", info)
+			}
+		})
+	}
+}
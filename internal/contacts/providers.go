@@ -1,83 +1,39 @@
 package contacts
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/cti-team/takedown/pkg/models"
 )
 
-// Constantes para emails de abuse centralizados
-const (
-	CloudflareAbuseEmail = "abuse@cloudflare.com"
-	AmazonAbuseEmail     = "abuse@amazonaws.com"
-	FastlyAbuseEmail     = "abuse@fastly.com"
-	AkamaiAbuseEmail     = "abuse@akamai.com"
-	GoogleAbuseEmail     = "network-abuse@google.com"
-	MicrosoftAbuseEmail  = "abuse@microsoft.com"
-)
-
-// GetCDNProviders retorna mapa de provedores CDN conhecidos
-func GetCDNProviders() map[string]*models.CDNInfo {
-	return map[string]*models.CDNInfo{
-		"cloudflare": {
-			Name:    "Cloudflare",
-			Webform: "https://www.cloudflare.com/abuse/form",
-			Abuse: models.ContactInfo{
-				Email: CloudflareAbuseEmail,
-			},
-		},
-		"fastly": {
-			Name: "Fastly",
-			Abuse: models.ContactInfo{
-				Email: FastlyAbuseEmail,
-			},
-		},
-		"akamai": {
-			Name: "Akamai",
-			Abuse: models.ContactInfo{
-				Email: AkamaiAbuseEmail,
-			},
-		},
-		"amazon": {
-			Name: "Amazon CloudFront",
-			Abuse: models.ContactInfo{
-				Email: AmazonAbuseEmail,
-			},
-		},
+// GetASNAbuseEmail retorna email de abuse para um ASN, consultando o diretório de contatos
+func GetASNAbuseEmail(asn int, asnName string) string {
+	provider, ok := Default().Lookup(Query{Kind: KindHosting, ASN: asn, Name: asnName})
+	if ok && provider.Abuse.Email != "" {
+		return provider.Abuse.Email
 	}
-}
 
-// GetASNAbuseEmailMap retorna mapa de emails de abuse para ASNs conhecidos
-func GetASNAbuseEmailMap() map[string]string {
-	return map[string]string{
-		"google llc":            GoogleAbuseEmail,
-		"cloudflare, inc.":      CloudflareAbuseEmail,
-		"amazon.com, inc.":      AmazonAbuseEmail,
-		"microsoft corporation": MicrosoftAbuseEmail,
-		"digitalocean":          "abuse@digitalocean.com",
-		"ovh":                   "abuse@ovh.net",
-		"hetzner":               "abuse@hetzner.de",
-		"vultr":                 "abuse@vultr.com",
-		"linode":                "abuse@linode.com",
-	}
+	// Fallback genérico
+	return GuessContact(asnName).Email
 }
 
-// GetASNAbuseEmail retorna email de abuse para um ASN específico
-func GetASNAbuseEmail(asnName string) string {
-	asnName = strings.ToLower(asnName)
-	abuseEmails := GetASNAbuseEmailMap()
-
-	for provider, email := range abuseEmails {
-		if strings.Contains(asnName, provider) {
-			return email
-		}
+// GuessContact deduz um contato abuse@<dominio> a partir do nome do AS/provedor.
+// O resultado é marcado como palpite e não deve ser usado sem validação.
+func GuessContact(asnName string) models.ContactInfo {
+	domain := ExtractDomainFromName(asnName)
+	if domain == "" {
+		return models.ContactInfo{}
 	}
 
-	// Fallback genérico
-	return "abuse@" + ExtractDomainFromName(asnName)
+	return models.ContactInfo{
+		Email:  "abuse@" + domain,
+		Source: models.ContactSourceGuess,
+		Reason: fmt.Sprintf("guessed from provider name %q", asnName),
+	}
 }
 
-// ExtractDomainFromName extrai um possível domínio do nome do ASN
+// ExtractDomainFromName extrai um possível domínio do nome do ASN (vazio se não houver)
 func ExtractDomainFromName(name string) string {
 	name = strings.ToLower(name)
 	name = strings.ReplaceAll(name, " ", "")
@@ -86,10 +42,10 @@ func ExtractDomainFromName(name string) string {
 	name = strings.ReplaceAll(name, "inc", "")
 	name = strings.ReplaceAll(name, "ltd", "")
 	name = strings.ReplaceAll(name, "corporation", "")
-	name = strings.TrimSpace(name)
+	name = strings.Trim(name, ". -")
 
 	if name == "" {
-		return "example.com"
+		return ""
 	}
 
 	return name + ".com"
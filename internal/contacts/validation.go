@@ -0,0 +1,245 @@
+package contacts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// defaultValidationTimeout limita as consultas DNS de validação de contatos
+const defaultValidationTimeout = 5 * time.Second
+
+// sourceConfidence é a confiança base por origem do contato (0-100)
+var sourceConfidence = map[models.ContactSource]int{
+	models.ContactSourceManual:    100,
+	models.ContactSourceRDAP:      90,
+	models.ContactSourceDirectory: directoryConfidence,
+	models.ContactSourceWHOIS:     75,
+	models.ContactSourceGuess:     25,
+}
+
+// unknownSourceConfidence é usada para contatos sem origem registrada
+const unknownSourceConfidence = 40
+
+// Resolver abstrai as consultas DNS usadas na validação (satisfeito por *net.Resolver)
+type Resolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// EmailCheck é o resultado da validação de um endereço de email
+type EmailCheck struct {
+	Email       string `json:"email"`
+	Domain      string `json:"domain,omitempty"`
+	SyntaxValid bool   `json:"syntax_valid"`
+	HasMX       bool   `json:"has_mx"`                // MX explícito publicado
+	ImplicitMX  bool   `json:"implicit_mx,omitempty"` // sem MX, mas com A/AAAA (RFC 5321 5.1)
+	NullMX      bool   `json:"null_mx,omitempty"`     // MX "." (RFC 7505): domínio não recebe email
+	Temporary   bool   `json:"temporary,omitempty"`   // falha temporária de DNS
+	Error       string `json:"error,omitempty"`
+}
+
+// Deliverable indica se o domínio aceita email
+func (c EmailCheck) Deliverable() bool {
+	return c.SyntaxValid && !c.NullMX && (c.HasMX || c.ImplicitMX)
+}
+
+// Validator valida sintaxe e MX de contatos e calcula a confiança
+type Validator struct {
+	resolver Resolver
+	timeout  time.Duration
+	cache    map[string]EmailCheck // resultados de DNS por domínio
+	mutex    sync.RWMutex
+}
+
+// NewValidator cria um validador usando o resolver DNS do sistema
+func NewValidator() *Validator {
+	return NewValidatorWithResolver(net.DefaultResolver)
+}
+
+// NewValidatorWithResolver cria um validador com um resolver específico
+func NewValidatorWithResolver(resolver Resolver) *Validator {
+	return &Validator{
+		resolver: resolver,
+		timeout:  defaultValidationTimeout,
+		cache:    make(map[string]EmailCheck),
+	}
+}
+
+// CheckEmail valida a sintaxe do endereço e se o domínio recebe email
+func (v *Validator) CheckEmail(ctx context.Context, email string) EmailCheck {
+	check := EmailCheck{Email: email}
+
+	domain, err := emailDomain(email)
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+	check.Domain = domain
+	check.SyntaxValid = true
+
+	v.mutex.RLock()
+	cached, found := v.cache[domain]
+	v.mutex.RUnlock()
+	if found {
+		cached.Email = email
+		return cached
+	}
+
+	check = v.checkDomain(ctx, check)
+	if !check.Temporary {
+		v.mutex.Lock()
+		v.cache[domain] = check
+		v.mutex.Unlock()
+	}
+
+	return check
+}
+
+// Score valida o contato e preenche Confidence e Reason. Um contato já pontuado (copiado de
+// outro alvo, como o hosting que atende o mailbox) não recebe as penalidades de novo
+func (v *Validator) Score(ctx context.Context, contact *models.ContactInfo) EmailCheck {
+	if contact.Validated {
+		if contact.Email == "" {
+			return EmailCheck{}
+		}
+		return v.CheckEmail(ctx, contact.Email)
+	}
+	contact.Validated = true
+
+	confidence := contact.Confidence
+	if confidence == 0 {
+		confidence = baseConfidence(contact.Source)
+	}
+
+	source := string(contact.Source)
+	if source == "" {
+		source = "unknown"
+	}
+	notes := []string{"source=" + source}
+	if contact.Reason != "" {
+		notes = []string{contact.Reason, "source=" + source}
+	}
+
+	var check EmailCheck
+	if contact.Email == "" {
+		if contact.Webform == "" {
+			confidence = 0
+			notes = append(notes, "no contact channel")
+		}
+		contact.Confidence = confidence
+		contact.Reason = strings.Join(notes, "; ")
+		return check
+	}
+
+	check = v.CheckEmail(ctx, contact.Email)
+	switch {
+	case !check.SyntaxValid:
+		confidence = 0
+		notes = append(notes, "invalid address: "+check.Error)
+	case check.NullMX:
+		confidence = 0
+		notes = append(notes, "domain publishes null MX")
+	case check.HasMX:
+		notes = append(notes, "mx ok")
+	case check.ImplicitMX:
+		confidence -= 10
+		notes = append(notes, "no MX, using A/AAAA")
+	case check.Temporary:
+		confidence -= 15
+		notes = append(notes, "DNS check failed: "+check.Error)
+	default:
+		confidence = 5
+		notes = append(notes, "domain does not receive email")
+	}
+
+	contact.Confidence = clampConfidence(confidence)
+	contact.Reason = strings.Join(notes, "; ")
+	return check
+}
+
+// checkDomain consulta MX (e A/AAAA como MX implícito) do domínio
+func (v *Validator) checkDomain(ctx context.Context, check EmailCheck) EmailCheck {
+	ctx, cancel := context.WithTimeout(ctx, v.timeout)
+	defer cancel()
+
+	records, err := v.resolver.LookupMX(ctx, check.Domain)
+	if err == nil && len(records) > 0 {
+		if len(records) == 1 && (records[0].Host == "." || records[0].Host == "") {
+			check.NullMX = true
+			return check
+		}
+		check.HasMX = true
+		return check
+	}
+	if err != nil && !isNotFound(err) {
+		check.Temporary = true
+		check.Error = err.Error()
+		return check
+	}
+
+	hosts, err := v.resolver.LookupHost(ctx, check.Domain)
+	switch {
+	case err == nil && len(hosts) > 0:
+		check.ImplicitMX = true
+	case err != nil && !isNotFound(err):
+		check.Temporary = true
+		check.Error = err.Error()
+	default:
+		check.Error = "domain has no MX or address records"
+	}
+
+	return check
+}
+
+// emailDomain valida a sintaxe do endereço e retorna o domínio em minúsculas
+func emailDomain(email string) (string, error) {
+	address, err := mail.ParseAddress(email)
+	if err != nil {
+		return "", fmt.Errorf("invalid email syntax: %w", err)
+	}
+	if address.Name != "" || address.Address != strings.TrimSpace(email) {
+		return "", errors.New("invalid email syntax: display names are not allowed")
+	}
+
+	at := strings.LastIndex(address.Address, "@")
+	domain := strings.ToLower(strings.TrimSuffix(address.Address[at+1:], "."))
+	if !strings.Contains(domain, ".") || strings.HasPrefix(domain, "[") {
+		return "", fmt.Errorf("invalid email domain %q", domain)
+	}
+
+	return domain, nil
+}
+
+// isNotFound verifica se o erro DNS é NXDOMAIN/sem registros (definitivo)
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
+// baseConfidence retorna a confiança base de uma origem
+func baseConfidence(source models.ContactSource) int {
+	if confidence, ok := sourceConfidence[source]; ok {
+		return confidence
+	}
+	return unknownSourceConfidence
+}
+
+// clampConfidence limita a confiança ao intervalo 0-100
+func clampConfidence(confidence int) int {
+	switch {
+	case confidence < 0:
+		return 0
+	case confidence > 100:
+		return 100
+	default:
+		return confidence
+	}
+}
@@ -0,0 +1,162 @@
+package contacts
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// fakeResolver simula respostas DNS por domínio
+type fakeResolver struct {
+	mx      map[string][]*net.MX
+	hosts   map[string][]string
+	failing map[string]bool
+	queries int
+}
+
+func (r *fakeResolver) LookupMX(_ context.Context, name string) ([]*net.MX, error) {
+	r.queries++
+	if r.failing[name] {
+		return nil, &net.DNSError{Err: "server misbehaving", Name: name, IsTemporary: true}
+	}
+	if records, ok := r.mx[name]; ok {
+		return records, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (r *fakeResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if addrs, ok := r.hosts[host]; ok {
+		return addrs, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func newFakeResolver() *fakeResolver {
+	return &fakeResolver{
+		mx: map[string][]*net.MX{
+			"registrar.test": {{Host: "mx1.registrar.test.", Pref: 10}},
+			"nomail.test":    {{Host: ".", Pref: 0}},
+		},
+		hosts: map[string][]string{
+			"implicit.test": {"203.0.113.10"},
+		},
+		failing: map[string]bool{"flaky.test": true},
+	}
+}
+
+func TestValidator_Score(t *testing.T) {
+	validator := NewValidatorWithResolver(newFakeResolver())
+
+	tests := []struct {
+		name       string
+		contact    models.ContactInfo
+		confidence int
+		reason     string
+	}{
+		{"RDAP with MX", models.ContactInfo{Email: "abuse@registrar.test", Source: models.ContactSourceRDAP}, 90, "mx ok"},
+		{"Implicit MX", models.ContactInfo{Email: "abuse@implicit.test", Source: models.ContactSourceWHOIS}, 65, "no MX"},
+		{"Null MX", models.ContactInfo{Email: "abuse@nomail.test", Source: models.ContactSourceRDAP}, 0, "null MX"},
+		{"Nonexistent domain", models.ContactInfo{Email: "abuse@genericprovider.com", Source: models.ContactSourceGuess}, 5, "does not receive email"},
+		{"Temporary failure", models.ContactInfo{Email: "abuse@flaky.test", Source: models.ContactSourceRDAP}, 75, "DNS check failed"},
+		{"Invalid syntax", models.ContactInfo{Email: "abuse at registrar", Source: models.ContactSourceRDAP}, 0, "invalid address"},
+		{"Display name", models.ContactInfo{Email: "Abuse <abuse@registrar.test>", Source: models.ContactSourceRDAP}, 0, "invalid address"},
+		{"Guess keeps low confidence", models.ContactInfo{Email: "abuse@registrar.test", Source: models.ContactSourceGuess}, 25, "source=guess"},
+		{"Preset confidence", models.ContactInfo{Email: "abuse@registrar.test", Source: models.ContactSourceDirectory, Confidence: 70}, 70, "source=directory"},
+		{"Webform only", models.ContactInfo{Webform: "https://registrar.test/abuse", Source: models.ContactSourceDirectory}, 85, "source=directory"},
+		{"No channel", models.ContactInfo{}, 0, "no contact channel"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contact := tt.contact
+			validator.Score(context.Background(), &contact)
+
+			if contact.Confidence != tt.confidence {
+				t.Errorf("Expected confidence %d, got %d (%s)", tt.confidence, contact.Confidence, contact.Reason)
+			}
+			if !strings.Contains(contact.Reason, tt.reason) {
+				t.Errorf("Expected reason to contain %q, got %q", tt.reason, contact.Reason)
+			}
+		})
+	}
+}
+
+func TestValidator_ScoreIsIdempotent(t *testing.T) {
+	validator := NewValidatorWithResolver(newFakeResolver())
+
+	tests := []struct {
+		name       string
+		contact    models.ContactInfo
+		confidence int
+	}{
+		{"Implicit MX", models.ContactInfo{Email: "abuse@implicit.test", Source: models.ContactSourceWHOIS}, 65},
+		{"Temporary failure", models.ContactInfo{Email: "abuse@flaky.test", Source: models.ContactSourceRDAP}, 75},
+		{"Preset confidence", models.ContactInfo{Email: "abuse@registrar.test", Source: models.ContactSourceDirectory, Confidence: 70}, 70},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contact := tt.contact
+			validator.Score(context.Background(), &contact)
+			reason := contact.Reason
+
+			// O mesmo contato copiado para outro alvo é pontuado de novo pelo enrichment
+			copied := contact
+			validator.Score(context.Background(), &copied)
+
+			if copied.Confidence != tt.confidence || copied.Reason != reason {
+				t.Errorf("Expected %d (%s) after scoring twice, got %d (%s)", tt.confidence, reason, copied.Confidence, copied.Reason)
+			}
+		})
+	}
+}
+
+func TestValidator_CheckEmailCachesDomain(t *testing.T) {
+	resolver := newFakeResolver()
+	validator := NewValidatorWithResolver(resolver)
+
+	first := validator.CheckEmail(context.Background(), "abuse@registrar.test")
+	second := validator.CheckEmail(context.Background(), "noc@Registrar.test")
+
+	if !first.Deliverable() || !second.Deliverable() {
+		t.Errorf("Expected deliverable results, got %+v / %+v", first, second)
+	}
+	if second.Email != "noc@Registrar.test" {
+		t.Errorf("Expected cached result to keep the queried address, got %s", second.Email)
+	}
+	if resolver.queries != 1 {
+		t.Errorf("Expected 1 MX query, got %d", resolver.queries)
+	}
+
+	// Falhas temporárias não são cacheadas
+	validator.CheckEmail(context.Background(), "abuse@flaky.test")
+	validator.CheckEmail(context.Background(), "abuse@flaky.test")
+	if resolver.queries != 3 {
+		t.Errorf("Expected temporary failures to be retried, got %d queries", resolver.queries)
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	if !isNotFound(&net.DNSError{IsNotFound: true}) {
+		t.Error("Expected NXDOMAIN to be not found")
+	}
+	if isNotFound(errors.New("timeout")) {
+		t.Error("Expected generic error not to be not found")
+	}
+}
+
+func TestGuessContact(t *testing.T) {
+	contact := GuessContact("Example Hosting Ltd")
+	if contact.Email != "abuse@examplehosting.com" || !contact.IsGuessed() {
+		t.Errorf("Unexpected guessed contact: %+v", contact)
+	}
+
+	if contact := GuessContact("Inc."); contact.Email != "" {
+		t.Errorf("Expected no guess for empty name, got %+v", contact)
+	}
+}
@@ -0,0 +1,98 @@
+// Package ctwatch acompanha logs de Certificate Transparency e abre IOCs para
+// certificados emitidos para domínios parecidos com as marcas protegidas.
+package ctwatch
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Tipos de fonte suportados
+const (
+	SourceTypeRFC6962 = "rfc6962"
+	SourceTypeJSON    = "json"
+)
+
+// LogConfig descreve uma fonte de certificados
+type LogConfig struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"` // rfc6962 ou json
+	URL  string `yaml:"url"`
+
+	// rfc6962
+	BatchSize int   `yaml:"batch_size,omitempty"` // entradas por get-entries
+	Backfill  int64 `yaml:"backfill,omitempty"`   // entradas anteriores ao topo lidas no primeiro ciclo
+
+	// json: caminhos com pontos até a lista de entradas e, em cada entrada, até os nomes
+	EntriesField string `yaml:"entries_field,omitempty"` // vazio: a resposta é a própria lista
+	NamesField   string `yaml:"names_field,omitempty"`
+}
+
+// Config define as fontes e o ritmo do watcher
+type Config struct {
+	IntervalSeconds   int         `yaml:"interval_seconds"`
+	MaxBatchesPerPoll int         `yaml:"max_batches_per_poll"` // limita o trabalho por ciclo em logs muito ativos
+	Logs              []LogConfig `yaml:"logs"`
+}
+
+// DefaultConfig retorna a configuração padrão, sem fontes (espelha configs/ct/watcher.yaml)
+func DefaultConfig() Config {
+	return Config{
+		IntervalSeconds:   60,
+		MaxBatchesPerPoll: 20,
+	}
+}
+
+// LoadConfig carrega a configuração de um arquivo YAML sobre os valores padrão
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- caminho vem da configuração do operador
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read ct config: %w", err)
+	}
+	return ParseConfig(data)
+}
+
+// ParseConfig interpreta e valida a configuração; campos ausentes mantêm o padrão
+func ParseConfig(data []byte) (Config, error) {
+	config := DefaultConfig()
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return Config{}, fmt.Errorf("failed to parse ct config: %w", err)
+	}
+
+	if config.IntervalSeconds <= 0 {
+		return Config{}, fmt.Errorf("interval_seconds must be positive, got %d", config.IntervalSeconds)
+	}
+	if config.MaxBatchesPerPoll <= 0 {
+		return Config{}, fmt.Errorf("max_batches_per_poll must be positive, got %d", config.MaxBatchesPerPoll)
+	}
+
+	seen := make(map[string]bool, len(config.Logs))
+	for i, source := range config.Logs {
+		if source.Name == "" {
+			return Config{}, fmt.Errorf("log %d has no name", i+1)
+		}
+		if seen[source.Name] {
+			return Config{}, fmt.Errorf("duplicate log %q", source.Name)
+		}
+		seen[source.Name] = true
+		if source.URL == "" {
+			return Config{}, fmt.Errorf("log %q has no url", source.Name)
+		}
+		switch source.Type {
+		case SourceTypeRFC6962:
+			if source.BatchSize < 0 || source.Backfill < 0 {
+				return Config{}, fmt.Errorf("log %q: batch_size and backfill cannot be negative", source.Name)
+			}
+		case SourceTypeJSON:
+			if source.NamesField == "" {
+				return Config{}, fmt.Errorf("log %q: json sources need names_field", source.Name)
+			}
+		default:
+			return Config{}, fmt.Errorf("log %q: unknown type %q", source.Name, source.Type)
+		}
+	}
+
+	return config, nil
+}
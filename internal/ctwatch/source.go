@@ -0,0 +1,210 @@
+package ctwatch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cti-team/takedown/pkg/ctlog"
+)
+
+const (
+	defaultBatchSize  = 256
+	maxFeedBytes      = 32 << 20
+	feedClientTimeout = 30 * time.Second
+)
+
+// Source entrega os certificados novos desde a última chamada
+type Source interface {
+	Name() string
+	Poll(ctx context.Context) ([]ctlog.Entry, error)
+}
+
+// NewSource cria a fonte descrita na configuração
+func NewSource(config LogConfig, maxBatches int) (Source, error) {
+	switch config.Type {
+	case SourceTypeRFC6962:
+		return NewLogSource(config, maxBatches), nil
+	case SourceTypeJSON:
+		return NewJSONSource(config), nil
+	default:
+		return nil, fmt.Errorf("log %q: unknown type %q", config.Name, config.Type)
+	}
+}
+
+// LogSource lê um log RFC 6962 a partir do topo da árvore, guardando a posição entre ciclos
+type LogSource struct {
+	name       string
+	client     *ctlog.Client
+	batchSize  int64
+	backfill   int64
+	maxBatches int
+	next       int64 // próxima entrada a ler; -1 antes do primeiro ciclo
+}
+
+// NewLogSource cria uma fonte para o log RFC 6962 da configuração
+func NewLogSource(config LogConfig, maxBatches int) *LogSource {
+	batchSize := int64(config.BatchSize)
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	if maxBatches <= 0 {
+		maxBatches = DefaultConfig().MaxBatchesPerPoll
+	}
+	return &LogSource{
+		name:       config.Name,
+		client:     ctlog.NewClient(config.URL),
+		batchSize:  batchSize,
+		backfill:   config.Backfill,
+		maxBatches: maxBatches,
+		next:       -1,
+	}
+}
+
+// Name retorna o nome do log
+func (s *LogSource) Name() string {
+	return s.name
+}
+
+// Poll lê as entradas novas até o topo atual (ou até maxBatches lotes)
+func (s *LogSource) Poll(ctx context.Context) ([]ctlog.Entry, error) {
+	sth, err := s.client.GetSTH()
+	if err != nil {
+		return nil, err
+	}
+	if s.next < 0 {
+		s.next = sth.TreeSize - s.backfill
+		if s.next < 0 {
+			s.next = 0
+		}
+	}
+
+	var entries []ctlog.Entry
+	for batch := 0; batch < s.maxBatches && s.next < sth.TreeSize; batch++ {
+		if err := ctx.Err(); err != nil {
+			return entries, err
+		}
+
+		end := s.next + s.batchSize - 1
+		if end >= sth.TreeSize {
+			end = sth.TreeSize - 1
+		}
+		received, err := s.client.GetEntries(s.next, end)
+		if err != nil {
+			return entries, err
+		}
+		if len(received) == 0 {
+			break
+		}
+		// O log pode devolver menos entradas que o pedido; a posição avança pelo recebido
+		entries = append(entries, received...)
+		s.next += int64(len(received))
+	}
+	return entries, nil
+}
+
+// JSONSource consulta um endpoint JSON com certificados recentes (agregadores, stand-ins locais)
+type JSONSource struct {
+	name         string
+	url          string
+	entriesField string
+	namesField   string
+	httpClient   *http.Client
+}
+
+// NewJSONSource cria uma fonte para o endpoint JSON da configuração
+func NewJSONSource(config LogConfig) *JSONSource {
+	return &JSONSource{
+		name:         config.Name,
+		url:          config.URL,
+		entriesField: config.EntriesField,
+		namesField:   config.NamesField,
+		httpClient:   &http.Client{Timeout: feedClientTimeout},
+	}
+}
+
+// Name retorna o nome da fonte
+func (s *JSONSource) Name() string {
+	return s.name
+}
+
+// Poll baixa o documento e extrai os nomes de cada entrada; a deduplicação fica com o watcher
+func (s *JSONSource) Poll(ctx context.Context) ([]ctlog.Entry, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("feed request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("feed returned HTTP %d", resp.StatusCode)
+	}
+
+	var document interface{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxFeedBytes)).Decode(&document); err != nil {
+		return nil, fmt.Errorf("failed to parse feed: %w", err)
+	}
+
+	list, ok := lookupField(document, s.entriesField).([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("feed has no entry list at %q", s.entriesField)
+	}
+
+	now := time.Now().UTC()
+	entries := make([]ctlog.Entry, 0, len(list))
+	for i, item := range list {
+		names := stringList(lookupField(item, s.namesField))
+		if len(names) == 0 {
+			continue
+		}
+		entries = append(entries, ctlog.Entry{Index: int64(i), Timestamp: now, Names: names})
+	}
+	return entries, nil
+}
+
+// lookupField segue um caminho com pontos (data.leaf_cert.all_domains); vazio retorna o próprio valor
+func lookupField(value interface{}, path string) interface{} {
+	if path == "" {
+		return value
+	}
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+	return value
+}
+
+// stringList aceita uma string ou uma lista de strings e normaliza para minúsculas
+func stringList(value interface{}) []string {
+	var names []string
+	switch typed := value.(type) {
+	case string:
+		names = append(names, typed)
+	case []interface{}:
+		for _, item := range typed {
+			if name, ok := item.(string); ok {
+				names = append(names, name)
+			}
+		}
+	}
+
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			result = append(result, name)
+		}
+	}
+	return result
+}
@@ -0,0 +1,190 @@
+package ctwatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cti-team/takedown/internal/brand"
+	"github.com/cti-team/takedown/pkg/indicator"
+	"github.com/cti-team/takedown/pkg/models"
+	"github.com/google/uuid"
+)
+
+const (
+	// SourceCT é a origem dos IOCs abertos pelo watcher
+	SourceCT = "ct"
+
+	// maxSeenSites limita a memória da deduplicação; ao estourar, o conjunto recomeça
+	maxSeenSites = 100000
+)
+
+// Sink recebe os IOCs novos (normalmente Machine.ProcessIOC)
+type Sink func(ioc *models.IOC) error
+
+// Watcher consulta as fontes de CT e compara os nomes com as marcas protegidas
+type Watcher struct {
+	sources  []Source
+	detector *brand.Detector
+	sink     Sink
+	interval time.Duration
+	seen     map[string]bool // sites (eTLD+1 com sufixos privados) já emitidos
+	mutex    sync.Mutex
+	stopChan chan struct{}
+}
+
+// NewWatcher cria um watcher com as regras de similaridade do detector de marcas
+func NewWatcher(detector *brand.Detector, sources ...Source) *Watcher {
+	return &Watcher{
+		sources:  sources,
+		detector: detector,
+		interval: time.Duration(DefaultConfig().IntervalSeconds) * time.Second,
+		seen:     make(map[string]bool),
+		stopChan: make(chan struct{}),
+	}
+}
+
+// NewWatcherFromConfig cria o watcher com as fontes da configuração
+func NewWatcherFromConfig(config Config, detector *brand.Detector) (*Watcher, error) {
+	watcher := NewWatcher(detector)
+	watcher.SetInterval(time.Duration(config.IntervalSeconds) * time.Second)
+	for _, logConfig := range config.Logs {
+		source, err := NewSource(logConfig, config.MaxBatchesPerPoll)
+		if err != nil {
+			return nil, err
+		}
+		watcher.AddSource(source)
+	}
+	return watcher, nil
+}
+
+// AddSource adiciona uma fonte de certificados
+func (w *Watcher) AddSource(source Source) {
+	w.sources = append(w.sources, source)
+}
+
+// SetSink configura quem recebe os IOCs
+func (w *Watcher) SetSink(sink Sink) {
+	w.sink = sink
+}
+
+// SetInterval configura o intervalo entre ciclos de Start
+func (w *Watcher) SetInterval(interval time.Duration) {
+	w.interval = interval
+}
+
+// Poll consulta todas as fontes uma vez e emite um IOC por site parecido com uma marca.
+// Falha em uma fonte não impede as outras; os erros são retornados juntos.
+func (w *Watcher) Poll(ctx context.Context) ([]*models.IOC, error) {
+	var emitted []*models.IOC
+	var errs []error
+
+	for _, source := range w.sources {
+		entries, err := source.Poll(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", source.Name(), err))
+		}
+
+		for _, entry := range entries {
+			for _, name := range entry.Names {
+				ioc := w.match(name, entry.Timestamp)
+				if ioc == nil {
+					continue
+				}
+				if w.sink != nil {
+					if err := w.sink(ioc); err != nil {
+						errs = append(errs, fmt.Errorf("%s: failed to open case for %s: %w", source.Name(), ioc.Value, err))
+						w.forget(ioc.Value)
+						continue
+					}
+				}
+				emitted = append(emitted, ioc)
+			}
+		}
+	}
+
+	return emitted, errors.Join(errs...)
+}
+
+// Start consulta as fontes a cada intervalo até Stop
+func (w *Watcher) Start() {
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			iocs, err := w.Poll(context.Background())
+			if err != nil {
+				log.Printf("CT watcher: %v", err)
+			}
+			if len(iocs) > 0 {
+				log.Printf("CT watcher: %d lookalike domains found", len(iocs))
+			}
+
+			select {
+			case <-ticker.C:
+			case <-w.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Stop encerra o watcher iniciado por Start
+func (w *Watcher) Stop() {
+	close(w.stopChan)
+}
+
+// match compara o nome do certificado com as marcas e monta o IOC se for novo
+func (w *Watcher) match(name string, issued time.Time) *models.IOC {
+	// Certificados wildcard cobrem o próprio domínio pai
+	name = strings.TrimPrefix(name, "*.")
+	normalized, err := indicator.Normalize(name)
+	if err != nil || normalized.IP || normalized.Site == "" {
+		return nil
+	}
+
+	ioc := &models.IOC{
+		IndicatorID: "ct-" + uuid.New().String(),
+		Type:        models.IOCTypeDomain,
+		Value:       normalized.Host,
+		FirstSeen:   issued,
+		Source:      SourceCT,
+	}
+	if w.detector.Tag(ioc) == nil {
+		return nil
+	}
+	if !w.remember(normalized.Site) {
+		return nil
+	}
+	return ioc
+}
+
+// remember registra o site e indica se ele é novo
+func (w *Watcher) remember(site string) bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.seen[site] {
+		return false
+	}
+	if len(w.seen) >= maxSeenSites {
+		w.seen = make(map[string]bool)
+	}
+	w.seen[site] = true
+	return true
+}
+
+// forget permite emitir o site de novo (falha ao abrir o caso)
+func (w *Watcher) forget(host string) {
+	normalized, err := indicator.Normalize(host)
+	if err != nil {
+		return
+	}
+	w.mutex.Lock()
+	delete(w.seen, normalized.Site)
+	w.mutex.Unlock()
+}
@@ -0,0 +1,172 @@
+package ctwatch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cti-team/takedown/internal/brand"
+	"github.com/cti-team/takedown/pkg/ctlog/ctlogtest"
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+func testDetector() *brand.Detector {
+	config := brand.DefaultConfig()
+	config.Brands = []brand.Protected{{Name: "AcmeBank", Domains: []string{"acmebank.com.br"}}}
+	return brand.NewDetector(config)
+}
+
+func TestWatcher_RFC6962(t *testing.T) {
+	server := ctlogtest.NewServer(2)
+	defer server.Close()
+
+	issued := time.Date(2026, 5, 10, 8, 0, 0, 0, time.UTC)
+	server.AddCertificate(ctlogtest.Certificate("old.acmebnak.com"), issued) // antes do backfill
+	server.AddCertificate(ctlogtest.Certificate("www.example.org"), issued)
+	server.AddPrecertificate(ctlogtest.Certificate("acmebsnk.com.br", "www.acmebsnk.com.br"), issued)
+	server.AddCertificate(ctlogtest.Certificate("acmebank.com.br", "www.acmebank.com.br"), issued)
+
+	source := NewLogSource(LogConfig{Name: "standin", URL: server.URL, BatchSize: 10, Backfill: 3}, 5)
+	watcher := NewWatcher(testDetector(), source)
+
+	var received []*models.IOC
+	watcher.SetSink(func(ioc *models.IOC) error {
+		received = append(received, ioc)
+		return nil
+	})
+
+	iocs, err := watcher.Poll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(iocs) != 1 || len(received) != 1 {
+		t.Fatalf("Expected one lookalike (www. deduplicated, official ignored), got %+v", iocs)
+	}
+
+	ioc := received[0]
+	if ioc.Value != "acmebsnk.com.br" || ioc.Source != SourceCT || ioc.Type != models.IOCTypeDomain ||
+		!ioc.FirstSeen.Equal(issued) || ioc.GetBrand() != "AcmeBank" || !ioc.HasTag("typosquatting") {
+		t.Errorf("Unexpected IOC: %+v", ioc)
+	}
+	if ioc.BrandSimilarity == nil || ioc.BrandSimilarity.Score < 0.9 {
+		t.Errorf("Expected similarity score, got %+v", ioc.BrandSimilarity)
+	}
+
+	// Só entradas novas são lidas no ciclo seguinte
+	server.AddCertificate(ctlogtest.Certificate("*.acmebamk.com"), issued.Add(time.Hour))
+	iocs, err = watcher.Poll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(iocs) != 1 || iocs[0].Value != "acmebamk.com" {
+		t.Fatalf("Expected wildcard lookalike from the new entry, got %+v", iocs)
+	}
+
+	iocs, err = watcher.Poll(context.Background())
+	if err != nil || len(iocs) != 0 {
+		t.Errorf("Expected nothing new, got %+v (%v)", iocs, err)
+	}
+}
+
+func TestWatcher_JSONFeed(t *testing.T) {
+	feed := `{"data": [
+		{"cert": {"all_domains": ["acmebnak.com", "www.acmebnak.com"]}},
+		{"cert": {"all_domains": "shop.example.net"}},
+		{"cert": {"all_domains": ["xn--cmebank-1fg.com.br"]}},
+		{"other": true}
+	]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(feed))
+	}))
+	defer server.Close()
+
+	config, err := ParseConfig([]byte(`
+logs:
+  - name: aggregator
+    type: json
+    url: ` + server.URL + `
+    entries_field: data
+    names_field: cert.all_domains
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	watcher, err := NewWatcherFromConfig(config, testDetector())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	iocs, err := watcher.Poll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := make([]string, 0, len(iocs))
+	for _, ioc := range iocs {
+		values = append(values, ioc.Value)
+	}
+	if strings.Join(values, ",") != "acmebnak.com,xn--cmebank-1fg.com.br" {
+		t.Errorf("Unexpected IOCs: %v", values)
+	}
+
+	// O feed repete os mesmos certificados; nada é emitido de novo
+	iocs, err = watcher.Poll(context.Background())
+	if err != nil || len(iocs) != 0 {
+		t.Errorf("Expected duplicates to be suppressed, got %v (%v)", iocs, err)
+	}
+}
+
+func TestWatcher_SourceAndSinkErrors(t *testing.T) {
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer broken.Close()
+
+	server := ctlogtest.NewServer(0)
+	defer server.Close()
+	server.AddCertificate(ctlogtest.Certificate("acmebnak.com"), time.Now())
+
+	watcher := NewWatcher(testDetector(),
+		NewJSONSource(LogConfig{Name: "broken", URL: broken.URL, NamesField: "domains"}),
+		NewLogSource(LogConfig{Name: "standin", URL: server.URL, Backfill: 10}, 1),
+	)
+	failures := 0
+	watcher.SetSink(func(*models.IOC) error {
+		failures++
+		return errors.New("queue full")
+	})
+
+	iocs, err := watcher.Poll(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "broken") || !strings.Contains(err.Error(), "queue full") {
+		t.Errorf("Expected joined source and sink errors, got %v", err)
+	}
+	if len(iocs) != 0 || failures != 1 {
+		t.Errorf("Expected one failed emission, got %v (failures %d)", iocs, failures)
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig([]byte("logs:\n  - name: argon\n    type: rfc6962\n    url: https://ct.example/log/\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.IntervalSeconds != 60 || len(config.Logs) != 1 {
+		t.Errorf("Unexpected config: %+v", config)
+	}
+
+	for _, data := range []string{
+		"interval_seconds: 0\n",
+		"logs:\n  - type: rfc6962\n    url: https://ct.example/\n",
+		"logs:\n  - name: a\n    type: rfc6962\n",
+		"logs:\n  - name: a\n    type: certstream\n    url: wss://example\n",
+		"logs:\n  - name: a\n    type: json\n    url: https://feed.example/\n",
+		"logs:\n  - name: a\n    type: rfc6962\n    url: https://a/\n  - name: a\n    type: rfc6962\n    url: https://b/\n",
+	} {
+		if _, err := ParseConfig([]byte(data)); err == nil {
+			t.Errorf("Expected error for %q", data)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package discovery
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config define as técnicas, os sufixos e os limites da descoberta
+type Config struct {
+	Techniques    []string `yaml:"techniques"`
+	TLDs          []string `yaml:"tlds"`
+	IntervalHours int      `yaml:"interval_hours"` // intervalo do job no modo daemon
+	MinScore      int      `yaml:"min_score"`      // score de risco mínimo para abrir caso
+	Concurrency   int      `yaml:"concurrency"`    // consultas DNS e coletas em paralelo
+}
+
+// DefaultConfig retorna a configuração padrão (espelha configs/discovery/discovery.yaml)
+func DefaultConfig() Config {
+	return Config{
+		Techniques: append([]string(nil), Techniques...),
+		TLDs: []string{
+			"com", "net", "org", "info", "biz", "co", "io", "app", "online", "site",
+			"xyz", "top", "shop", "live", "com.br", "net.br",
+		},
+		IntervalHours: 6,
+		MinScore:      30,
+		Concurrency:   10,
+	}
+}
+
+// LoadConfig carrega a configuração de um arquivo YAML sobre os valores padrão
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- caminho vem da configuração do operador
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read discovery config: %w", err)
+	}
+	return ParseConfig(data)
+}
+
+// ParseConfig interpreta e valida a configuração; campos ausentes mantêm o padrão
+func ParseConfig(data []byte) (Config, error) {
+	config := DefaultConfig()
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return Config{}, fmt.Errorf("failed to parse discovery config: %w", err)
+	}
+
+	for _, technique := range config.Techniques {
+		if !knownTechnique(technique) {
+			return Config{}, fmt.Errorf("unknown technique %q", technique)
+		}
+	}
+	if config.IntervalHours <= 0 {
+		return Config{}, fmt.Errorf("interval_hours must be positive, got %d", config.IntervalHours)
+	}
+	if config.MinScore < 0 || config.MinScore > 100 {
+		return Config{}, fmt.Errorf("min_score %d out of range 0-100", config.MinScore)
+	}
+	if config.Concurrency <= 0 {
+		return Config{}, fmt.Errorf("concurrency must be positive, got %d", config.Concurrency)
+	}
+
+	return config, nil
+}
+
+// knownTechnique indica se a técnica é suportada
+func knownTechnique(technique string) bool {
+	for _, known := range Techniques {
+		if technique == known {
+			return true
+		}
+	}
+	return false
+}
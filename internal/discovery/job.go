@@ -0,0 +1,299 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cti-team/takedown/internal/brand"
+	"github.com/cti-team/takedown/internal/scoring"
+	"github.com/cti-team/takedown/pkg/indicator"
+	"github.com/cti-team/takedown/pkg/models"
+	"github.com/google/uuid"
+)
+
+const (
+	// SourceDiscovery é a origem dos IOCs abertos pelo job
+	SourceDiscovery = "discovery"
+
+	defaultLookupTimeout = 5 * time.Second
+)
+
+// Resolver abstrai a consulta DNS dos candidatos (satisfeito por *net.Resolver)
+type Resolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// Collector coleta as evidências de um IOC (satisfeito por *evidence.Collector)
+type Collector interface {
+	CollectEvidence(ioc *models.IOC) (*models.EvidencePack, error)
+}
+
+// Sink recebe os IOCs suspeitos para abrir caso (normalmente Machine.ProcessIOC)
+type Sink func(ioc *models.IOC) error
+
+// Hit é um candidato que resolve no DNS
+type Hit struct {
+	Candidate
+	Addresses  []string               `json:"addresses"`
+	IOC        *models.IOC            `json:"ioc,omitempty"`
+	Evidence   *models.EvidencePack   `json:"-"`
+	Risk       *models.RiskAssessment `json:"risk,omitempty"`
+	Suspicious bool                   `json:"suspicious"`
+	Opened     bool                   `json:"opened,omitempty"` // caso aberto nesta execução
+	Error      string                 `json:"error,omitempty"`
+}
+
+// Report resume uma execução do job
+type Report struct {
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Generated  int       `json:"generated"`
+	Hits       []Hit     `json:"hits"`
+}
+
+// Job gera permutações das marcas protegidas, resolve, coleta evidências dos vivos
+// e abre casos de triagem para os suspeitos
+type Job struct {
+	config    Config
+	brands    []brand.Protected
+	resolver  Resolver
+	collector Collector
+	scorer    *scoring.Engine
+	sink      Sink
+	timeout   time.Duration
+	opened    map[string]bool // domínios que já viraram caso
+	mutex     sync.Mutex
+	stopChan  chan struct{}
+}
+
+// NewJob cria o job de descoberta para as marcas protegidas, usando o resolver DNS do sistema
+func NewJob(config Config, brands []brand.Protected) *Job {
+	return &Job{
+		config:   config,
+		brands:   brands,
+		resolver: net.DefaultResolver,
+		scorer:   scoring.NewEngine(scoring.DefaultConfig()),
+		timeout:  defaultLookupTimeout,
+		opened:   make(map[string]bool),
+		stopChan: make(chan struct{}),
+	}
+}
+
+// SetResolver substitui o resolver DNS (testes, resolvers internos ou passivos)
+func (j *Job) SetResolver(resolver Resolver) {
+	j.resolver = resolver
+}
+
+// SetCollector configura a coleta de evidências dos candidatos vivos; sem collector nada é aberto
+func (j *Job) SetCollector(collector Collector) {
+	j.collector = collector
+}
+
+// SetScorer configura a engine de score usada para decidir se o candidato é suspeito
+func (j *Job) SetScorer(scorer *scoring.Engine) {
+	j.scorer = scorer
+}
+
+// SetSink configura quem recebe os IOCs suspeitos
+func (j *Job) SetSink(sink Sink) {
+	j.sink = sink
+}
+
+// Candidates gera as permutações de todos os domínios protegidos, sem os domínios oficiais
+func (j *Job) Candidates() []Candidate {
+	official := make(map[string]bool)
+	for _, protected := range j.brands {
+		for _, domain := range protected.Domains {
+			official[indicator.RegistrableDomain(domain)] = true
+		}
+	}
+
+	seen := make(map[string]bool)
+	var candidates []Candidate
+	for _, protected := range j.brands {
+		for _, domain := range protected.Domains {
+			for _, candidate := range Permutations(protected.Name, domain, j.config.TLDs, j.config.Techniques) {
+				if official[candidate.Domain] || seen[candidate.Domain] {
+					continue
+				}
+				seen[candidate.Domain] = true
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+	return candidates
+}
+
+// Resolve consulta os candidatos em paralelo e retorna os que resolvem, na ordem de geração
+func (j *Job) Resolve(ctx context.Context, candidates []Candidate) []Hit {
+	hits := make([]*Hit, len(candidates))
+	j.parallel(len(candidates), func(i int) {
+		lookupCtx, cancel := context.WithTimeout(ctx, j.timeout)
+		defer cancel()
+
+		addresses, err := j.resolver.LookupHost(lookupCtx, candidates[i].Domain)
+		if err != nil || len(addresses) == 0 {
+			return
+		}
+		sort.Strings(addresses)
+		hits[i] = &Hit{Candidate: candidates[i], Addresses: addresses}
+	})
+
+	var live []Hit
+	for _, hit := range hits {
+		if hit != nil {
+			live = append(live, *hit)
+		}
+	}
+	return live
+}
+
+// RunOnce executa um ciclo completo: gera, resolve, coleta, pontua e abre casos para os suspeitos
+func (j *Job) RunOnce(ctx context.Context) (*Report, error) {
+	report := &Report{StartedAt: time.Now().UTC()}
+
+	candidates := j.Candidates()
+	report.Generated = len(candidates)
+	report.Hits = j.Resolve(ctx, candidates)
+	if err := ctx.Err(); err != nil {
+		return report, fmt.Errorf("discovery interrupted: %w", err)
+	}
+
+	if j.collector != nil {
+		j.parallel(len(report.Hits), func(i int) {
+			j.assess(&report.Hits[i])
+		})
+	}
+
+	for i := range report.Hits {
+		hit := &report.Hits[i]
+		if !hit.Suspicious || j.sink == nil || j.alreadyOpened(hit.Domain) {
+			continue
+		}
+		if err := j.sink(hit.IOC); err != nil {
+			hit.Error = fmt.Sprintf("failed to open case: %v", err)
+			j.forget(hit.Domain)
+			continue
+		}
+		hit.Opened = true
+	}
+
+	report.FinishedAt = time.Now().UTC()
+	return report, nil
+}
+
+// Start executa o job imediatamente e depois a cada IntervalHours, até Stop
+func (j *Job) Start() {
+	go func() {
+		ticker := time.NewTicker(time.Duration(j.config.IntervalHours) * time.Hour)
+		defer ticker.Stop()
+
+		for {
+			j.runLogged()
+			select {
+			case <-ticker.C:
+			case <-j.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Stop encerra o job iniciado por Start
+func (j *Job) Stop() {
+	close(j.stopChan)
+}
+
+// runLogged executa um ciclo e registra o resumo no log
+func (j *Job) runLogged() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-j.stopChan:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	report, err := j.RunOnce(ctx)
+	if err != nil {
+		log.Printf("Discovery run failed: %v", err)
+		return
+	}
+
+	opened := 0
+	for _, hit := range report.Hits {
+		if hit.Opened {
+			opened++
+		}
+	}
+	log.Printf("Discovery: %d candidates, %d live, %d cases opened", report.Generated, len(report.Hits), opened)
+}
+
+// assess coleta a evidência do candidato vivo e decide se é suspeito pelo score de risco
+func (j *Job) assess(hit *Hit) {
+	hit.IOC = &models.IOC{
+		IndicatorID: "disc-" + uuid.New().String(),
+		Type:        models.IOCTypeDomain,
+		Value:       hit.Domain,
+		FirstSeen:   time.Now().UTC(),
+		Source:      SourceDiscovery,
+		Tags:        []string{"brand:" + hit.Brand, brand.TagTyposquatting, "permutation:" + hit.Technique},
+	}
+
+	pack, err := j.collector.CollectEvidence(hit.IOC)
+	if err != nil {
+		hit.Error = fmt.Sprintf("evidence collection failed: %v", err)
+		return
+	}
+	risk := j.scorer.Assess(scoring.Input{IOC: hit.IOC, Evidence: pack, Body: string(pack.Body)})
+	pack.Risk = risk
+	hit.Evidence = pack
+	hit.Risk = &risk
+	hit.Suspicious = risk.Score >= j.config.MinScore
+}
+
+// alreadyOpened marca o domínio como aberto e indica se ele já tinha virado caso antes
+func (j *Job) alreadyOpened(domain string) bool {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	if j.opened[domain] {
+		return true
+	}
+	j.opened[domain] = true
+	return false
+}
+
+// forget permite reabrir o domínio no próximo ciclo (falha ao abrir o caso)
+func (j *Job) forget(domain string) {
+	j.mutex.Lock()
+	delete(j.opened, domain)
+	j.mutex.Unlock()
+}
+
+// parallel executa fn para cada índice com no máximo Concurrency goroutines
+func (j *Job) parallel(count int, fn func(i int)) {
+	limit := j.config.Concurrency
+	if limit <= 0 {
+		limit = 1
+	}
+
+	semaphore := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
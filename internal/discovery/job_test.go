@@ -0,0 +1,178 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cti-team/takedown/internal/brand"
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// fakeResolver responde apenas para os domínios cadastrados
+type fakeResolver map[string][]string
+
+func (r fakeResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if addresses, ok := r[host]; ok {
+		return addresses, nil
+	}
+	return nil, errors.New("no such host")
+}
+
+// fakeCollector devolve uma página de login para os domínios marcados como phishing
+type fakeCollector struct {
+	phishing map[string]bool
+}
+
+func (c fakeCollector) CollectEvidence(ioc *models.IOC) (*models.EvidencePack, error) {
+	pack := &models.EvidencePack{HTTP: models.HTTPInfo{Status: 200}}
+	if c.phishing[ioc.Value] {
+		pack.HTTP.Title = "AcmeBank - Internet Banking"
+		pack.Content = &models.ContentAnalysis{HasLoginForm: true, HasPasswordForm: true, Brands: []string{"AcmeBank"}}
+	}
+	return pack, nil
+}
+
+func testJob() *Job {
+	config := DefaultConfig()
+	config.TLDs = []string{"com", "net"}
+	return NewJob(config, []brand.Protected{
+		{Name: "AcmeBank", Domains: []string{"acmebank.com.br", "acmebank.com"}},
+	})
+}
+
+func TestJob_Candidates(t *testing.T) {
+	candidates := testJob().Candidates()
+	for _, candidate := range candidates {
+		if candidate.Domain == "acmebank.com" || candidate.Domain == "acmebank.com.br" {
+			t.Errorf("Official domain %s generated as candidate", candidate.Domain)
+		}
+	}
+	if len(candidates) == 0 {
+		t.Fatal("Expected candidates")
+	}
+}
+
+func TestJob_RunOnce(t *testing.T) {
+	job := testJob()
+	job.SetResolver(fakeResolver{
+		"acmebnak.com.br": {"203.0.113.10"},
+		"acme-bank.com":   {"203.0.113.20"},
+		"acmebank.net":    {"203.0.113.30", "2001:db8::1"},
+	})
+	job.SetCollector(fakeCollector{phishing: map[string]bool{"acmebnak.com.br": true}})
+
+	var opened []*models.IOC
+	job.SetSink(func(ioc *models.IOC) error {
+		opened = append(opened, ioc)
+		return nil
+	})
+
+	report, err := job.RunOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Generated == 0 || len(report.Hits) != 3 {
+		t.Fatalf("Expected 3 live hits, got %+v", report.Hits)
+	}
+	if len(opened) != 1 || opened[0].Value != "acmebnak.com.br" {
+		t.Fatalf("Expected one case for acmebnak.com.br, got %+v", opened)
+	}
+
+	ioc := opened[0]
+	if ioc.Source != SourceDiscovery || ioc.Type != models.IOCTypeDomain || ioc.GetBrand() != "AcmeBank" ||
+		!ioc.HasTag("typosquatting") || !ioc.HasTag("permutation:transposition") {
+		t.Errorf("Unexpected IOC: %+v", ioc)
+	}
+
+	for _, hit := range report.Hits {
+		if hit.Risk == nil {
+			t.Errorf("Expected risk for %s", hit.Domain)
+			continue
+		}
+		if hit.Suspicious != (hit.Domain == "acmebnak.com.br") {
+			t.Errorf("Unexpected suspicious=%v for %s (score %d)", hit.Suspicious, hit.Domain, hit.Risk.Score)
+		}
+		if hit.Domain == "acmebank.net" && strings.Join(hit.Addresses, ",") != "2001:db8::1,203.0.113.30" {
+			t.Errorf("Expected sorted addresses, got %v", hit.Addresses)
+		}
+	}
+
+	// Segunda execução não reabre o mesmo domínio
+	if _, err := job.RunOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(opened) != 1 {
+		t.Errorf("Expected no duplicate cases, got %d", len(opened))
+	}
+}
+
+func TestJob_SinkFailureRetries(t *testing.T) {
+	job := testJob()
+	job.SetResolver(fakeResolver{"acmebnak.com.br": {"203.0.113.10"}})
+	job.SetCollector(fakeCollector{phishing: map[string]bool{"acmebnak.com.br": true}})
+
+	calls := 0
+	job.SetSink(func(*models.IOC) error {
+		calls++
+		if calls == 1 {
+			return errors.New("queue full")
+		}
+		return nil
+	})
+
+	report, err := job.RunOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Hits[0].Opened || !strings.Contains(report.Hits[0].Error, "queue full") {
+		t.Errorf("Expected failed hit, got %+v", report.Hits[0])
+	}
+
+	report, err = job.RunOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Hits[0].Opened || calls != 2 {
+		t.Errorf("Expected case to be opened on retry, got %+v (calls %d)", report.Hits[0], calls)
+	}
+}
+
+func TestJob_WithoutCollector(t *testing.T) {
+	job := testJob()
+	job.SetResolver(fakeResolver{"acmebnak.com.br": {"203.0.113.10"}})
+	job.SetSink(func(*models.IOC) error {
+		t.Error("Sink must not be called without evidence")
+		return nil
+	})
+
+	report, err := job.RunOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Hits) != 1 || report.Hits[0].Suspicious {
+		t.Errorf("Expected one live, non-assessed hit, got %+v", report.Hits)
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig([]byte("techniques: [omission, tld_swap]\nmin_score: 50\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Techniques) != 2 || config.MinScore != 50 || config.IntervalHours != 6 || len(config.TLDs) == 0 {
+		t.Errorf("Unexpected config: %+v", config)
+	}
+
+	for _, data := range []string{
+		"techniques: [typo]\n",
+		"interval_hours: 0\n",
+		"min_score: 101\n",
+		"concurrency: -1\n",
+	} {
+		if _, err := ParseConfig([]byte(data)); err == nil {
+			t.Errorf("Expected error for %q", data)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+// Package discovery gera variações dos domínios protegidos das marcas e procura
+// as que estão no ar, abrindo casos para as suspeitas antes de alguém reportá-las.
+package discovery
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/cti-team/takedown/internal/brand"
+	"github.com/cti-team/takedown/pkg/indicator"
+	"golang.org/x/net/idna"
+)
+
+// Técnicas de permutação suportadas
+const (
+	TechniqueOmission      = "omission"
+	TechniqueTransposition = "transposition"
+	TechniqueHomoglyph     = "homoglyph"
+	TechniqueBitsquatting  = "bitsquatting"
+	TechniqueTLDSwap       = "tld_swap"
+	TechniqueHyphenation   = "hyphenation"
+)
+
+// Techniques lista todas as técnicas na ordem em que são geradas
+var Techniques = []string{
+	TechniqueOmission,
+	TechniqueTransposition,
+	TechniqueHomoglyph,
+	TechniqueBitsquatting,
+	TechniqueTLDSwap,
+	TechniqueHyphenation,
+}
+
+// sequencePrototypes são os protótipos de dois caracteres da tabela de confundíveis
+var sequencePrototypes = []string{"rn", "vv", "cl"}
+
+// Candidate é um domínio gerado a partir de um domínio protegido
+type Candidate struct {
+	Domain      string `json:"domain"` // ASCII (punycode para IDN)
+	UnicodeHost string `json:"unicode_host,omitempty"`
+	Technique   string `json:"technique"`
+	Brand       string `json:"brand"`
+	Original    string `json:"original"`
+}
+
+// Permutations gera os candidatos de um domínio protegido com as técnicas pedidas
+// (todas se vazio). tlds são os sufixos usados na troca de TLD.
+func Permutations(brandName, domain string, tlds, techniques []string) []Candidate {
+	normalized, err := indicator.Normalize(domain)
+	if err != nil || normalized.RegistrableDomain == "" {
+		return nil
+	}
+	suffix := normalized.PublicSuffix
+	label := strings.TrimSuffix(normalized.RegistrableDomain, "."+suffix)
+	if unicodeLabel, err := idna.Punycode.ToUnicode(label); err == nil {
+		label = unicodeLabel
+	}
+	if len(techniques) == 0 {
+		techniques = Techniques
+	}
+
+	seen := map[string]bool{normalized.RegistrableDomain: true}
+	var candidates []Candidate
+	add := func(technique, host string) {
+		generated, err := indicator.Normalize(host)
+		if err != nil || generated.RegistrableDomain == "" || seen[generated.RegistrableDomain] {
+			return
+		}
+		seen[generated.RegistrableDomain] = true
+		candidates = append(candidates, Candidate{
+			Domain:      generated.RegistrableDomain,
+			UnicodeHost: generated.UnicodeHost,
+			Technique:   technique,
+			Brand:       brandName,
+			Original:    normalized.RegistrableDomain,
+		})
+	}
+
+	for _, technique := range techniques {
+		var labels []string
+		switch technique {
+		case TechniqueOmission:
+			labels = omissions(label)
+		case TechniqueTransposition:
+			labels = transpositions(label)
+		case TechniqueHomoglyph:
+			labels = homoglyphs(label)
+		case TechniqueBitsquatting:
+			labels = bitsquats(label)
+		case TechniqueHyphenation:
+			labels = hyphenations(label)
+		case TechniqueTLDSwap:
+			for _, tld := range tlds {
+				tld = strings.Trim(strings.ToLower(tld), ".")
+				if tld != "" && tld != suffix {
+					add(technique, label+"."+tld)
+				}
+			}
+		}
+		for _, generated := range labels {
+			add(technique, generated+"."+suffix)
+		}
+	}
+
+	return candidates
+}
+
+// omissions remove um caractere por vez (acmebnk)
+func omissions(label string) []string {
+	runes := []rune(label)
+	var result []string
+	for i := range runes {
+		result = append(result, string(runes[:i])+string(runes[i+1:]))
+	}
+	return result
+}
+
+// transpositions troca caracteres vizinhos diferentes (acmebnak)
+func transpositions(label string) []string {
+	runes := []rune(label)
+	var result []string
+	for i := 0; i+1 < len(runes); i++ {
+		if runes[i] == runes[i+1] {
+			continue
+		}
+		swapped := append([]rune(nil), runes...)
+		swapped[i], swapped[i+1] = swapped[i+1], swapped[i]
+		result = append(result, string(swapped))
+	}
+	return result
+}
+
+// homoglyphs troca um caractere ou sequência por um confundível da tabela da UTS #39
+// (acmebank → аcmebank, acrnebank, acmebаnk)
+func homoglyphs(label string) []string {
+	var result []string
+	for i, r := range label {
+		rest := label[i:]
+		prefix := label[:i]
+
+		// Sequências de dois caracteres (rn → m) e o caractere isolado (a → а)
+		for _, prototype := range append(sequencePrototypes, string(r)) {
+			if !strings.HasPrefix(rest, prototype) {
+				continue
+			}
+			for _, glyph := range brand.Homoglyphs(prototype) {
+				result = append(result, prefix+glyph+rest[len(prototype):])
+			}
+		}
+
+		// Caminho inverso: m → rn, d → cl
+		if skeleton := brand.Skeleton(string(r)); skeleton != string(r) {
+			result = append(result, prefix+skeleton+rest[len(string(r)):])
+		}
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+// bitsquats inverte um bit de cada caractere, mantendo só resultados válidos em DNS
+func bitsquats(label string) []string {
+	var result []string
+	for i := 0; i < len(label); i++ {
+		if label[i] >= 0x80 {
+			continue
+		}
+		for bit := 0; bit < 8; bit++ {
+			flipped := label[i] ^ (1 << bit)
+			valid := (flipped >= 'a' && flipped <= 'z') || (flipped >= '0' && flipped <= '9') ||
+				(flipped == '-' && i > 0 && i < len(label)-1)
+			if valid {
+				result = append(result, label[:i]+string(flipped)+label[i+1:])
+			}
+		}
+	}
+	return result
+}
+
+// hyphenations insere um hífen entre dois caracteres (acme-bank)
+func hyphenations(label string) []string {
+	runes := []rune(label)
+	var result []string
+	for i := 1; i < len(runes); i++ {
+		if runes[i-1] == '-' || runes[i] == '-' {
+			continue
+		}
+		result = append(result, string(runes[:i])+"-"+string(runes[i:]))
+	}
+	return result
+}
@@ -0,0 +1,64 @@
+package discovery
+
+import "testing"
+
+func TestPermutations(t *testing.T) {
+	tests := []struct {
+		technique string
+		expected  []string
+		absent    []string
+	}{
+		{TechniqueOmission, []string{"acmebnk.com.br", "cmebank.com.br", "acmeban.com.br"}, nil},
+		{TechniqueTransposition, []string{"acmebnak.com.br", "camebank.com.br"}, nil},
+		{TechniqueHomoglyph, []string{"acrnebank.com.br", "xn--cmebank-1fg.com.br"}, nil},
+		{TechniqueBitsquatting, []string{"ccmebank.com.br", "acmebcnk.com.br"}, []string{"-cmebank.com.br"}},
+		{TechniqueTLDSwap, []string{"acmebank.com", "acmebank.xyz"}, []string{"acmebank.com.br"}},
+		{TechniqueHyphenation, []string{"acme-bank.com.br", "a-cmebank.com.br"}, []string{"-acmebank.com.br", "acmebank-.com.br"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.technique, func(t *testing.T) {
+			candidates := Permutations("AcmeBank", "https://www.acmebank.com.br/", []string{"com", "xyz", "com.br"}, []string{tt.technique})
+			found := make(map[string]Candidate)
+			for _, candidate := range candidates {
+				if candidate.Technique != tt.technique || candidate.Brand != "AcmeBank" || candidate.Original != "acmebank.com.br" {
+					t.Errorf("Unexpected candidate metadata: %+v", candidate)
+				}
+				found[candidate.Domain] = candidate
+			}
+			for _, domain := range tt.expected {
+				if _, ok := found[domain]; !ok {
+					t.Errorf("Expected %s among %d candidates", domain, len(candidates))
+				}
+			}
+			for _, domain := range tt.absent {
+				if _, ok := found[domain]; ok {
+					t.Errorf("Did not expect %s", domain)
+				}
+			}
+		})
+	}
+}
+
+func TestPermutations_Details(t *testing.T) {
+	candidates := Permutations("AcmeBank", "acmebank.com.br", []string{"com"}, nil)
+
+	seen := make(map[string]bool)
+	for _, candidate := range candidates {
+		if seen[candidate.Domain] {
+			t.Errorf("Duplicate candidate %s", candidate.Domain)
+		}
+		if candidate.Domain == "acmebank.com.br" {
+			t.Error("Original domain must not be a candidate")
+		}
+		seen[candidate.Domain] = true
+
+		if candidate.Domain == "xn--cmebank-1fg.com.br" && candidate.UnicodeHost != "аcmebank.com.br" {
+			t.Errorf("Expected unicode form of homoglyph, got %q", candidate.UnicodeHost)
+		}
+	}
+
+	if Permutations("AcmeBank", "com.br", nil, nil) != nil {
+		t.Error("Expected no candidates for a bare public suffix")
+	}
+}
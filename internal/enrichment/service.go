@@ -2,60 +2,311 @@ package enrichment
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"os"
-	"strconv"
+	"sort"
 	"strings"
 
 	"github.com/cti-team/takedown/internal/contacts"
+	"github.com/cti-team/takedown/pkg/indicator"
+	"github.com/cti-team/takedown/pkg/ipdb"
 	"github.com/cti-team/takedown/pkg/models"
 	"github.com/cti-team/takedown/pkg/rdap"
+	"github.com/cti-team/takedown/pkg/whois"
 )
 
+// errNoNetworkData indica que nenhuma base offline cobre o IP: o hosting recorre ao RDAP do RIR
+var errNoNetworkData = errors.New("no network data")
+
 // Service enriquece IOCs com informações adicionais
 type Service struct {
-	rdapClient *rdap.Client
+	rdapClient  *rdap.Client
+	whoisClient *whois.Client
+	ipDatabase  ipdb.Reader
+	directory   *contacts.Directory
+	validator   *contacts.Validator
+	resolver    contacts.Resolver // MX dos domínios de email e IPs do hosting
 }
 
 // NewService cria um novo serviço de enrichment
 func NewService() *Service {
 	return &Service{
-		rdapClient: rdap.NewClient(),
+		rdapClient:  rdap.NewClient(),
+		whoisClient: whois.NewClient(),
+		validator:   contacts.NewValidator(),
+		resolver:    net.DefaultResolver,
+	}
+}
+
+// SetIPDatabase configura bases offline de IP-ASN/GeoIP usadas para identificar o hosting
+func (s *Service) SetIPDatabase(reader ipdb.Reader) {
+	s.ipDatabase = reader
+}
+
+// SetRDAPClient substitui o cliente RDAP (domínios e redes de IP)
+func (s *Service) SetRDAPClient(client *rdap.Client) {
+	s.rdapClient = client
+}
+
+// SetDirectory configura o diretório de contatos (por padrão, contacts.Default())
+func (s *Service) SetDirectory(directory *contacts.Directory) {
+	s.directory = directory
+}
+
+// SetResolver substitui o resolver DNS usado para o MX de IOCs de email e os IPs do hosting
+func (s *Service) SetResolver(resolver contacts.Resolver) {
+	s.resolver = resolver
+}
+
+// SetValidator configura o validador de contatos (sintaxe/MX e confiança)
+func (s *Service) SetValidator(validator *contacts.Validator) {
+	s.validator = validator
+}
+
+// contactDirectory retorna o diretório de contatos em uso
+func (s *Service) contactDirectory() *contacts.Directory {
+	if s.directory != nil {
+		return s.directory
 	}
+	return contacts.Default()
 }
 
 // EnrichIOC enriquece um IOC com informações de RDAP e ASN
 func (s *Service) EnrichIOC(ctx context.Context, evidenceID string) (*models.AbuseContact, error) {
 	// TODO: Carregar evidence pack pelo ID
 	// Por enquanto, vamos simular com um domínio de exemplo
-	domain := "suspicious-domain.com"
+	return s.EnrichIndicator(ctx, "suspicious-domain.com")
+}
 
-	// Buscar informações RDAP
-	contact, err := s.rdapClient.LookupDomain(domain)
+// EnrichIndicator enriquece uma URL, domínio, IP ou email: o registro é consultado pelo
+// domínio registrável e hosting/CDN pelo host completo do IOC; IPs usam o RDAP do RIR
+func (s *Service) EnrichIndicator(ctx context.Context, value string) (*models.AbuseContact, error) {
+	if account, ok := emailAccount(value); ok {
+		return s.enrichEmail(ctx, account)
+	}
+
+	normalized, err := indicator.Normalize(value)
 	if err != nil {
-		return nil, fmt.Errorf("RDAP lookup failed: %w", err)
+		return nil, err
 	}
 
-	// Enriquecer com informações de hosting
-	if err := s.enrichHosting(ctx, domain, contact); err != nil {
-		// Log error but continue
-		_, _ = fmt.Fprintf(os.Stderr, "Hosting enrichment failed: %v\n", err)
+	contact := &models.AbuseContact{}
+	if !normalized.IP {
+		domain := normalized.RegistrableDomain
+		if domain == "" {
+			domain = normalized.Host
+		}
+		// Sites em plataformas (github.io, blogspot, Wix...): o domínio registrável é da plataforma,
+		// então não há registro a consultar nem registrar a acionar
+		if platform := s.lookupPlatform(normalized); platform != nil {
+			contact.Platform = platform
+		} else {
+			// Buscar informações RDAP, com fallback para WHOIS em TLDs sem RDAP utilizável
+			contact, err = s.lookupRegistration(ctx, domain)
+			if err != nil {
+				return nil, err
+			}
+		}
+		contact.Domain = domain
 	}
+	contact.Host = normalized.Host
+	s.enrichInfrastructure(ctx, normalized, contact)
 
 	// Detectar CDN
-	if err := s.detectCDN(ctx, domain, contact); err != nil {
+	if !normalized.IP {
+		if err := s.detectCDN(ctx, normalized.Host, contact); err != nil {
+			// Log error but continue
+			_, _ = fmt.Fprintf(os.Stderr, "CDN detection failed: %v\n", err)
+		}
+	}
+
+	// Validar contatos e calcular confiança
+	s.scoreContacts(ctx, contact)
+
+	return contact, nil
+}
+
+// enrichInfrastructure preenche hosting e CERT nacional do host. IOCs de IP não têm registrar: o
+// contato vem da rede no RIR, com upstream para escalar. O CERT vale para qualquer IOC com país
+// de hosting conhecido, pois regras como c2_infrastructure o acionam também para domínios
+func (s *Service) enrichInfrastructure(ctx context.Context, normalized *indicator.Normalized, contact *models.AbuseContact) {
+	if err := s.enrichHosting(ctx, normalized.Host, contact); err != nil {
 		// Log error but continue
-		_, _ = fmt.Fprintf(os.Stderr, "CDN detection failed: %v\n", err)
+		_, _ = fmt.Fprintf(os.Stderr, "Hosting enrichment failed: %v\n", err)
 	}
 
+	// O hosting já consulta o RIR quando as bases offline não identificam o provedor
+	if normalized.IP && contact.Network == nil {
+		if err := s.enrichNetwork(normalized.Host, contact); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Network enrichment failed: %v\n", err)
+		}
+	}
+	s.lookupCERT(contact)
+}
+
+// lookupPlatform identifica a plataforma do host pelo catálogo do diretório; hosts em sufixos
+// privados da PSL fora do catálogo viram plataforma sem contato (só suprime o registrar)
+func (s *Service) lookupPlatform(normalized *indicator.Normalized) *models.PlatformInfo {
+	site := normalized.Host
+	if normalized.PrivateSuffix && normalized.Site != "" {
+		site = normalized.Site
+	}
+
+	if provider, suffix, ok := s.contactDirectory().LookupPlatform(normalized.Host); ok {
+		return provider.PlatformInfo(suffix, site)
+	}
+	if normalized.PrivateSuffix {
+		return &models.PlatformInfo{Name: normalized.PublicSuffix, Suffix: normalized.PublicSuffix, Site: site}
+	}
+	return nil
+}
+
+// scoreContacts completa o contato do registrar pelo diretório e valida todos os contatos
+func (s *Service) scoreContacts(ctx context.Context, contact *models.AbuseContact) {
+	if contact.Abuse.Email == "" && contact.Registrar != nil {
+		query := contacts.Query{Kind: contacts.KindRegistrar, IANAID: contact.Registrar.IANAID, Name: contact.Registrar.Name}
+		if provider, ok := s.contactDirectory().Lookup(query); ok {
+			phone := contact.Abuse.Phone
+			contact.Abuse = provider.ContactInfo()
+			if contact.Abuse.Phone == "" {
+				contact.Abuse.Phone = phone
+			}
+		}
+	}
+
+	if s.validator == nil {
+		return
+	}
+
+	s.validator.Score(ctx, &contact.Abuse)
+	if contact.Hosting != nil {
+		s.validator.Score(ctx, &contact.Hosting.Abuse)
+	}
+	if contact.CDN != nil {
+		s.validator.Score(ctx, &contact.CDN.Abuse)
+	}
+	if contact.Network != nil {
+		s.validator.Score(ctx, &contact.Network.Abuse)
+	}
+	if contact.Upstream != nil {
+		s.validator.Score(ctx, &contact.Upstream.Abuse)
+	}
+	if contact.CERT != nil {
+		s.validator.Score(ctx, &contact.CERT.Abuse)
+	}
+	if contact.Mailbox != nil {
+		s.validator.Score(ctx, &contact.Mailbox.Abuse)
+	}
+	if contact.Platform.HasContact() {
+		s.validator.Score(ctx, &contact.Platform.Abuse)
+	}
+}
+
+// emailAccount reconhece um endereço de email (refangado e em minúsculas)
+func emailAccount(value string) (string, bool) {
+	validated, err := indicator.Validate(value, models.IOCTypeEmail)
+	if err != nil || strings.Contains(value, "://") {
+		return "", false
+	}
+	return validated.Value, true
+}
+
+// enrichEmail identifica o provedor da conta: webmail pelo domínio do endereço; domínios
+// próprios pelo MX, com o registro e o hosting do domínio para suspender o domínio da campanha
+func (s *Service) enrichEmail(ctx context.Context, account string) (*models.AbuseContact, error) {
+	domain := account[strings.LastIndex(account, "@")+1:]
+	mx := s.lookupMX(ctx, domain)
+
+	if provider, ok := s.contactDirectory().LookupMailbox(domain, nil); ok {
+		contact := &models.AbuseContact{Host: domain, Mailbox: provider.MailboxInfo(account, mx)}
+		s.scoreContacts(ctx, contact)
+		return contact, nil
+	}
+
+	contact, err := s.EnrichIndicator(ctx, domain)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Email domain enrichment failed: %v\n", err)
+		contact = &models.AbuseContact{Host: domain}
+	}
+	if provider, ok := s.contactDirectory().LookupMailbox(domain, mx); ok {
+		contact.Mailbox = provider.MailboxInfo(account, mx)
+	} else {
+		contact.Mailbox = selfHostedMailbox(account, domain, mx, contact)
+	}
+	if s.validator != nil {
+		s.validator.Score(ctx, &contact.Mailbox.Abuse)
+	}
+	return contact, nil
+}
+
+// selfHostedMailbox atribui a conta ao operador do MX; quando o MX é do próprio domínio,
+// quem responde é o hosting do domínio
+func selfHostedMailbox(account, domain string, mx []string, contact *models.AbuseContact) *models.MailboxInfo {
+	mailbox := &models.MailboxInfo{Name: domain, Account: account, MX: mx}
+	owner := indicator.RegistrableDomain(domain)
+	operator := owner
+	if len(mx) > 0 {
+		if registrable := indicator.RegistrableDomain(mx[0]); registrable != "" {
+			operator = registrable
+		}
+	}
+	if operator != owner {
+		mailbox.Name = operator
+		mailbox.Abuse = models.ContactInfo{
+			Email:  "abuse@" + operator,
+			Source: models.ContactSourceGuess,
+			Reason: fmt.Sprintf("guessed from MX operator %s", operator),
+		}
+		return mailbox
+	}
+	if contact.Hosting != nil {
+		mailbox.Name = contact.Hosting.Name
+		mailbox.Abuse = contact.Hosting.Abuse
+	}
+	return mailbox
+}
+
+// lookupMX retorna os hosts MX do domínio em ordem de preferência (vazio em falhas)
+func (s *Service) lookupMX(ctx context.Context, domain string) []string {
+	if s.resolver == nil {
+		return nil
+	}
+	records, err := s.resolver.LookupMX(ctx, domain)
+	if err != nil {
+		return nil
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Pref < records[j].Pref })
+	var hosts []string
+	for _, record := range records {
+		if host := strings.ToLower(strings.TrimSuffix(record.Host, ".")); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// lookupRegistration consulta RDAP e recorre ao WHOIS (porta 43) quando o RDAP falha
+func (s *Service) lookupRegistration(ctx context.Context, domain string) (*models.AbuseContact, error) {
+	contact, rdapErr := s.rdapClient.LookupDomain(domain)
+	if rdapErr == nil {
+		return contact, nil
+	}
+
+	contact, whoisErr := s.whoisClient.LookupDomain(ctx, domain)
+	if whoisErr != nil {
+		return nil, fmt.Errorf("RDAP lookup failed: %v; WHOIS fallback failed: %w", rdapErr, whoisErr)
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "RDAP lookup failed for %s, using WHOIS: %v\n", domain, rdapErr)
 	return contact, nil
 }
 
 // enrichHosting enriquece com informações do provedor de hosting
 func (s *Service) enrichHosting(ctx context.Context, domain string, contact *models.AbuseContact) error {
 	// Resolver IP do domínio
-	ips, err := net.LookupHost(domain)
+	ips, err := s.resolver.LookupHost(ctx, domain)
 	if err != nil {
 		return fmt.Errorf("IP lookup failed: %w", err)
 	}
@@ -67,85 +318,100 @@ func (s *Service) enrichHosting(ctx context.Context, domain string, contact *mod
 	// Usar o primeiro IP para lookup de ASN
 	ip := ips[0]
 
-	// Lookup ASN (implementação simplificada)
-	asn, asnName, err := s.lookupASN(ip)
-	if err != nil {
-		return fmt.Errorf("ASN lookup failed: %w", err)
+	record, err := s.lookupIPInfo(ip)
+	if err == nil {
+		contact.Hosting = &models.HostingInfo{
+			ASN:     record.ASN,
+			Name:    record.ASName,
+			Country: record.Country,
+		}
+	}
+
+	// Sem base offline, ou base só de país: sem AS não há provedor a consultar no diretório nem
+	// nome para adivinhar, então a rede e o contato de abuse vêm do RDAP do RIR
+	if record.ASN == 0 && record.ASName == "" {
+		if netErr := s.enrichNetwork(ip, contact); netErr != nil {
+			if err != nil {
+				return fmt.Errorf("ASN lookup failed: %w; %v", err, netErr)
+			}
+			return netErr
+		}
+		return nil
 	}
 
-	contact.Hosting = &models.HostingInfo{
-		ASN:  asn,
-		Name: asnName,
-		Abuse: models.ContactInfo{
-			Email: contacts.GetASNAbuseEmail(asnName),
-		},
+	query := contacts.Query{Kind: contacts.KindHosting, ASN: record.ASN, Name: record.ASName}
+	if provider, ok := s.contactDirectory().Lookup(query); ok {
+		contact.Hosting.Abuse = provider.ContactInfo()
+	} else {
+		contact.Hosting.Abuse = contacts.GuessContact(record.ASName)
 	}
 
 	return nil
 }
 
-// detectCDN detecta se o domínio usa CDN
-func (s *Service) detectCDN(ctx context.Context, domain string, contact *models.AbuseContact) error {
-	// Verificar CNAME para detectar CDNs conhecidos
-	cname, err := net.LookupCNAME(domain)
+// enrichNetwork consulta a rede do IP no RIR: o contato publicado pelo titular substitui
+// palpites pelo nome do AS, e a alocação pai vira upstream quando tem outro contato
+func (s *Service) enrichNetwork(ip string, contact *models.AbuseContact) error {
+	network, upstream, err := s.rdapClient.LookupIP(ip)
 	if err != nil {
-		// Não é erro crítico
-		return nil
+		return fmt.Errorf("RIR RDAP lookup failed: %w", err)
 	}
+	contact.Network = network
 
-	cname = strings.ToLower(cname)
-
-	// Detectar CDNs baseado em CNAME patterns
-	cdnProviders := contacts.GetCDNProviders()
-
-	for pattern, cdnInfo := range cdnProviders {
-		if strings.Contains(cname, pattern) {
-			contact.CDN = cdnInfo
-			break
-		}
+	if upstream != nil && upstream.Abuse.Email != "" && !strings.EqualFold(upstream.Abuse.Email, network.Abuse.Email) {
+		contact.Upstream = upstream
 	}
 
+	if contact.Hosting == nil {
+		contact.Hosting = &models.HostingInfo{}
+	}
+	if contact.Hosting.Name == "" {
+		contact.Hosting.Name = network.DisplayName()
+	}
+	if contact.Hosting.Country == "" {
+		contact.Hosting.Country = network.Country
+	}
+	if network.Abuse.Email != "" && (contact.Hosting.Abuse.Email == "" || contact.Hosting.Abuse.IsGuessed()) {
+		contact.Hosting.Abuse = network.Abuse
+	}
 	return nil
 }
 
-// lookupASN realiza lookup de ASN para um IP (implementação simplificada)
-func (s *Service) lookupASN(ip string) (int, string, error) {
-	// Esta é uma implementação muito simplificada
-	// Em produção, usaria serviços como Team Cymru ou MaxMind
-
-	// Mapeamento estático para ASNs conhecidos (apenas para demo)
-	asnMap := map[string]struct {
-		ASN  int
-		Name string
-	}{
-		"8.8.8.8":        {15169, "Google LLC"},
-		"1.1.1.1":        {13335, "Cloudflare, Inc."},
-		"208.67.222.222": {36692, "Cisco OpenDNS"},
+// lookupCERT associa o CERT nacional do país do hosting, quando está no diretório
+func (s *Service) lookupCERT(contact *models.AbuseContact) {
+	if contact.Hosting == nil || contact.Hosting.Country == "" {
+		return
 	}
-
-	if info, exists := asnMap[ip]; exists {
-		return info.ASN, info.Name, nil
+	query := contacts.Query{Kind: contacts.KindCERT, Country: contact.Hosting.Country}
+	if provider, ok := s.contactDirectory().Lookup(query); ok {
+		contact.CERT = provider.CERTInfo(contact.Hosting.Country)
 	}
+}
 
-	// Fallback genérico baseado na classe do IP
-	if strings.HasPrefix(ip, "192.168.") || strings.HasPrefix(ip, "10.") {
-		return 0, "Private Network", nil
+// lookupIPInfo resolve ASN, nome do AS e país pelas bases offline. Se a base cobre o IP só
+// com o país (ex: MMDB de GeoIP), o ASN fica vazio; sem cobertura, retorna errNoNetworkData.
+func (s *Service) lookupIPInfo(ip string) (ipdb.Record, error) {
+	if s.ipDatabase != nil {
+		if record, ok := ipdb.LookupString(s.ipDatabase, ip); ok {
+			return record, nil
+		}
 	}
+	return ipdb.Record{}, fmt.Errorf("%w for %s", errNoNetworkData, ip)
+}
 
-	// Parse IP para determinar região aproximada
-	parts := strings.Split(ip, ".")
-	if len(parts) != 4 {
-		return 0, "Unknown", fmt.Errorf("invalid IP format")
+// detectCDN detecta se o domínio usa CDN
+func (s *Service) detectCDN(ctx context.Context, domain string, contact *models.AbuseContact) error {
+	// Verificar CNAME para detectar CDNs conhecidos
+	cname, err := net.LookupCNAME(domain)
+	if err != nil {
+		// Não é erro crítico
+		return nil
 	}
 
-	firstOctet, _ := strconv.Atoi(parts[0])
-
-	switch {
-	case firstOctet >= 1 && firstOctet <= 126:
-		return 999999, "Generic Hosting Provider", nil
-	case firstOctet >= 128 && firstOctet <= 191:
-		return 888888, "International ISP", nil
-	default:
-		return 777777, "Unknown Provider", nil
+	// Detectar CDNs baseado nos padrões de CNAME do diretório
+	if provider, ok := s.contactDirectory().LookupCNAME(cname); ok {
+		contact.CDN = provider.CDNInfo()
 	}
+
+	return nil
 }
@@ -0,0 +1,445 @@
+package enrichment
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cti-team/takedown/internal/contacts"
+	"github.com/cti-team/takedown/internal/routing"
+	"github.com/cti-team/takedown/pkg/indicator"
+	"github.com/cti-team/takedown/pkg/ipdb"
+	"github.com/cti-team/takedown/pkg/models"
+	"github.com/cti-team/takedown/pkg/rdap"
+)
+
+func TestService_LookupIPInfo_OfflineDatabase(t *testing.T) {
+	asnTrie := ipdb.NewTrie()
+	asnTrie.Insert(netip.MustParsePrefix("203.0.113.0/24"), ipdb.Record{ASN: 64500, ASName: "Example Hosting", Country: "BR"})
+
+	service := NewService()
+	service.SetIPDatabase(asnTrie)
+
+	record, err := service.lookupIPInfo("203.0.113.10")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if record.ASN != 64500 || record.ASName != "Example Hosting" || record.Country != "BR" {
+		t.Errorf("Unexpected record: %+v", record)
+	}
+}
+
+// rirClient cria um cliente RDAP cujo RIR responde só os caminhos de /ip informados
+func rirClient(t *testing.T, networks map[string]string) *rdap.Client {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := networks[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rdap+json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	client := rdap.NewClient()
+	client.SetIPTransport(server.Client().Transport)
+	client.SetIPBaseURL(server.URL)
+	return client
+}
+
+func TestService_LookupIPInfo_CountryOnlyDatabase(t *testing.T) {
+	countryTrie := ipdb.NewTrie()
+	countryTrie.Insert(netip.MustParsePrefix("8.8.8.0/24"), ipdb.Record{Country: "US"})
+
+	service := NewService()
+	service.SetIPDatabase(countryTrie)
+	service.SetRDAPClient(rirClient(t, nil))
+
+	record, err := service.lookupIPInfo("8.8.8.8")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if record.ASN != 0 || record.ASName != "" || record.Country != "US" {
+		t.Errorf("Expected offline country without a guessed ASN, got %+v", record)
+	}
+
+	// Sem resposta do RIR, o hosting fica só com o país, sem ASN nem contato adivinhado
+	contact := &models.AbuseContact{Domain: "8.8.8.8"}
+	if err := service.enrichHosting(context.Background(), "8.8.8.8", contact); err == nil {
+		t.Fatal("Expected the RIR fallback error")
+	}
+	if contact.Hosting.ASN != 0 || contact.Hosting.Country != "US" || contact.Hosting.Abuse.Email != "" {
+		t.Errorf("Expected country-only hosting, got %+v", contact.Hosting)
+	}
+
+	// Sem provedor identificado, nenhum alvo de hosting é roteado
+	for _, action := range routing.NewEngine().DetermineActions([]string{"malware"}, contact) {
+		if action.Target.Type == "hosting" {
+			t.Errorf("Expected no hosting target for a country-only record, got %+v", action.Target)
+		}
+	}
+}
+
+func TestService_LookupIPInfo_NoDatabase(t *testing.T) {
+	service := NewService()
+	service.SetRDAPClient(rirClient(t, nil))
+
+	if _, err := service.lookupIPInfo("1.1.1.1"); !errors.Is(err, errNoNetworkData) {
+		t.Fatalf("Expected no network data error, got %v", err)
+	}
+
+	contact := &models.AbuseContact{Domain: "1.1.1.1"}
+	if err := service.enrichHosting(context.Background(), "1.1.1.1", contact); !errors.Is(err, errNoNetworkData) {
+		t.Fatalf("Expected hosting enrichment to fail without network data, got %v", err)
+	}
+	if contact.Hosting != nil {
+		t.Errorf("Expected no placeholder hosting, got %+v", contact.Hosting)
+	}
+}
+
+func TestService_EnrichHosting_RIRFallback(t *testing.T) {
+	service := NewService()
+	service.SetResolver(hostResolver{"login.acme-verify.com": {"45.67.89.10"}})
+	service.SetRDAPClient(rirClient(t, map[string]string{
+		"/ip/45.67.89.10": `{"handle": "NET-45-67-89-0-1", "name": "EXAMPLE-HOSTING", "country": "DE",
+			"entities": [{"handle": "ABUSE-1", "roles": ["abuse"],
+				"vcardArray": ["vcard", [["email", {}, "text", "abuse@examplehosting.net"]]]}]}`,
+	}))
+
+	// Sem base offline, o hosting de um domínio vem do RDAP do RIR e continua sendo roteado
+	contact := &models.AbuseContact{Domain: "acme-verify.com"}
+	if err := service.enrichHosting(context.Background(), "login.acme-verify.com", contact); err != nil {
+		t.Fatal(err)
+	}
+	if contact.Hosting == nil || contact.Hosting.Name != "EXAMPLE-HOSTING" || contact.Hosting.Country != "DE" ||
+		contact.Hosting.Abuse.Email != "abuse@examplehosting.net" || contact.Hosting.Abuse.Source != models.ContactSourceRDAP {
+		t.Fatalf("Expected hosting from the RIR, got %+v", contact.Hosting)
+	}
+
+	var hosting *routing.ActionDefinition
+	for _, action := range routing.NewEngine().DetermineActions([]string{"phishing"}, contact) {
+		if action.Target.Type == "hosting" {
+			hosting = &action
+		}
+	}
+	if hosting == nil || hosting.Target.Email != "abuse@examplehosting.net" {
+		t.Errorf("Expected the RIR hosting contact to be routed, got %+v", hosting)
+	}
+}
+
+// staticResolver responde MX apenas para os domínios configurados
+type staticResolver map[string][]*net.MX
+
+func (r staticResolver) LookupMX(_ context.Context, name string) ([]*net.MX, error) {
+	if records, ok := r[name]; ok {
+		return records, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (r staticResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func TestService_ScoreContacts(t *testing.T) {
+	service := NewService()
+	service.SetValidator(contacts.NewValidatorWithResolver(staticResolver{
+		"godaddy.com": {{Host: "mx.godaddy.com.", Pref: 10}},
+	}))
+
+	contact := &models.AbuseContact{
+		Registrar: &models.RegistrarInfo{Name: "GoDaddy.com, LLC", IANAID: 146},
+		Hosting: &models.HostingInfo{
+			ASN:   64500,
+			Name:  "Example Hosting",
+			Abuse: contacts.GuessContact("Example Hosting"),
+		},
+	}
+
+	service.scoreContacts(context.Background(), contact)
+
+	if contact.Abuse.Email != "abuse@godaddy.com" || contact.Abuse.Source != models.ContactSourceDirectory {
+		t.Errorf("Expected registrar contact from directory, got %+v", contact.Abuse)
+	}
+	if contact.Abuse.Confidence == 0 || !strings.Contains(contact.Abuse.Reason, "mx ok") {
+		t.Errorf("Expected validated registrar contact, got %+v", contact.Abuse)
+	}
+
+	hosting := contact.Hosting.Abuse
+	if hosting.Source != models.ContactSourceGuess || hosting.Confidence != 5 {
+		t.Errorf("Expected guessed hosting contact without MX to score 5, got %+v", hosting)
+	}
+}
+
+func TestService_ScoreContacts_RegistrarFromServiceDirectory(t *testing.T) {
+	overrides := filepath.Join(t.TempDir(), "overrides.yaml")
+	data := []byte(`version: 1
+providers:
+  - id: registrar-local
+    name: "Registrar Local Ltda"
+    kinds: ["registrar"]
+    match:
+      names: ["^Registrar Local"]
+    abuse:
+      email: "abuse@registrarlocal.com.br"
+`)
+	if err := os.WriteFile(overrides, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	directory, err := contacts.LoadWithOverrides("", overrides)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service := NewService()
+	service.SetDirectory(directory)
+	service.SetValidator(nil)
+
+	// O registrar só existe no diretório do serviço: o diretório padrão do processo não o conhece
+	contact := &models.AbuseContact{Registrar: &models.RegistrarInfo{Name: "Registrar Local Ltda"}}
+	service.scoreContacts(context.Background(), contact)
+	if contact.Abuse.Email != "abuse@registrarlocal.com.br" || contact.Abuse.Source != models.ContactSourceDirectory {
+		t.Errorf("Expected registrar contact from the service directory, got %+v", contact.Abuse)
+	}
+	if email := contacts.Default().RegistrarAbuseEmail("Registrar Local Ltda"); email != "" {
+		t.Errorf("Expected the default directory untouched, got %s", email)
+	}
+}
+
+func TestService_DirectoryOverridesRouting(t *testing.T) {
+	overrides := filepath.Join(t.TempDir(), "overrides.yaml")
+	data := []byte(`version: 1
+providers:
+  - id: provedor-local
+    name: "Provedor Local Ltda"
+    kinds: ["hosting"]
+    match:
+      asns: [64500]
+    abuse:
+      email: "abuse@provedorlocal.example"
+`)
+	if err := os.WriteFile(overrides, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	directory, err := contacts.LoadWithOverrides("", overrides)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	asnTrie := ipdb.NewTrie()
+	asnTrie.Insert(netip.MustParsePrefix("203.0.113.0/24"), ipdb.Record{ASN: 64500, ASName: "Example Hosting"})
+
+	service := NewService()
+	service.SetIPDatabase(asnTrie)
+	service.SetDirectory(directory)
+
+	// Um IP literal dispensa DNS no lookup do hosting
+	contact := &models.AbuseContact{Domain: "203.0.113.10"}
+	if err := service.enrichHosting(context.Background(), "203.0.113.10", contact); err != nil {
+		t.Fatal(err)
+	}
+
+	var hosting *routing.ActionDefinition
+	for _, action := range routing.NewEngine().DetermineActions([]string{"malware"}, contact) {
+		if action.Target.Type == "hosting" {
+			hosting = &action
+		}
+	}
+	if hosting == nil || hosting.Target.Email != "abuse@provedorlocal.example" {
+		t.Errorf("Expected the overridden hosting contact to be routed, got %+v", hosting)
+	}
+}
+
+func TestService_EnrichIndicator_IP(t *testing.T) {
+	var serverURL string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rdap+json")
+		switch r.URL.Path {
+		case "/ip/203.0.113.10":
+			_, _ = w.Write([]byte(`{"handle": "NET-203-0-113-0-2", "name": "C2-CUSTOMER", "country": "BR",
+				"links": [{"rel": "up", "href": "` + serverURL + `/ip/203.0.0.0/16"}],
+				"entities": [{"handle": "ABUSE-1", "roles": ["abuse"],
+					"vcardArray": ["vcard", [["email", {}, "text", "security@customer.example"]]]}]}`))
+		case "/ip/203.0.0.0/16":
+			_, _ = w.Write([]byte(`{"handle": "NET-203-0-0-0-1", "country": "BR",
+				"entities": [{"handle": "TRANSIT", "roles": ["registrant", "abuse"],
+					"vcardArray": ["vcard", [["fn", {}, "text", "Transit Telecom S.A."], ["email", {}, "text", "abuse@transit.example"]]]}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	asnTrie := ipdb.NewTrie()
+	asnTrie.Insert(netip.MustParsePrefix("203.0.113.0/24"), ipdb.Record{ASN: 64500, ASName: "Example Hosting"})
+	client := rdap.NewClient()
+	client.SetIPTransport(server.Client().Transport)
+	client.SetIPBaseURL(server.URL)
+
+	service := NewService()
+	service.SetIPDatabase(asnTrie)
+	service.SetRDAPClient(client)
+	service.SetValidator(nil)
+
+	contact, err := service.EnrichIndicator(context.Background(), "http://203.0.113.10:8080/gate.php")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contact.Registrar != nil || contact.Domain != "" || !contact.IsIP() {
+		t.Errorf("Expected IP path without registrar, got %+v", contact)
+	}
+	if contact.Hosting == nil || contact.Hosting.ASN != 64500 || contact.Hosting.Country != "BR" {
+		t.Fatalf("Expected hosting from ipdb with RIR country, got %+v", contact.Hosting)
+	}
+	if contact.Hosting.Abuse.Email != "security@customer.example" || contact.Hosting.Abuse.Source != models.ContactSourceRDAP {
+		t.Errorf("Expected RIR abuse contact instead of guess, got %+v", contact.Hosting.Abuse)
+	}
+	if contact.Upstream == nil || contact.Upstream.DisplayName() != "Transit Telecom S.A." {
+		t.Errorf("Expected parent allocation as upstream, got %+v", contact.Upstream)
+	}
+	if contact.CERT == nil || contact.CERT.Name != "CERT.br" || contact.CERT.Abuse.Email != "cert@cert.br" {
+		t.Errorf("Expected CERT.br for BR network, got %+v", contact.CERT)
+	}
+}
+
+// hostResolver resolve apenas os hosts configurados, sem MX
+type hostResolver map[string][]string
+
+func (r hostResolver) LookupMX(_ context.Context, name string) ([]*net.MX, error) {
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (r hostResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if ips, ok := r[host]; ok {
+		return ips, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func TestService_EnrichInfrastructure_DomainC2(t *testing.T) {
+	asnTrie := ipdb.NewTrie()
+	asnTrie.Insert(netip.MustParsePrefix("45.67.89.0/24"), ipdb.Record{ASN: 64500, ASName: "Example Hosting", Country: "BR"})
+
+	service := NewService()
+	service.SetIPDatabase(asnTrie)
+	service.SetResolver(hostResolver{"panel.c2-gate.com": {"45.67.89.10"}})
+	service.SetValidator(nil)
+
+	normalized, err := indicator.Normalize("https://panel.c2-gate.com/gate.php")
+	if err != nil {
+		t.Fatal(err)
+	}
+	contact := &models.AbuseContact{Domain: "c2-gate.com", Host: normalized.Host}
+	service.enrichInfrastructure(context.Background(), normalized, contact)
+
+	if contact.CERT == nil || contact.CERT.Name != "CERT.br" {
+		t.Fatalf("Expected CERT.br for a domain hosted in BR, got %+v", contact.CERT)
+	}
+
+	var cert *routing.ActionDefinition
+	for _, action := range routing.NewEngine().DetermineActions([]string{"c2"}, contact) {
+		if action.Target.Type == "cert" {
+			cert = &action
+		}
+	}
+	if cert == nil || cert.Target.Email != "cert@cert.br" {
+		t.Errorf("Expected the c2 domain to be routed to CERT.br, got %+v", cert)
+	}
+}
+
+func TestService_EnrichIndicator_Email(t *testing.T) {
+	service := NewService()
+	service.SetResolver(staticResolver{
+		"gmail.com": {{Host: "alt1.gmail-smtp-in.l.google.com.", Pref: 20}, {Host: "gmail-smtp-in.l.google.com.", Pref: 5}},
+	})
+	service.SetValidator(nil)
+
+	contact, err := service.EnrichIndicator(context.Background(), "Acme.Suporte[@]gmail[.]com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !contact.IsEmail() || contact.Registrar != nil || contact.Hosting != nil {
+		t.Fatalf("Expected webmail account without registration lookups, got %+v", contact)
+	}
+	mailbox := contact.Mailbox
+	if mailbox.Account != "acme.suporte@gmail.com" || !strings.HasPrefix(mailbox.Name, "Google") || mailbox.Abuse.Webform == "" {
+		t.Errorf("Unexpected mailbox: %+v", mailbox)
+	}
+	if len(mailbox.MX) != 2 || mailbox.MX[0] != "gmail-smtp-in.l.google.com" {
+		t.Errorf("Expected MX hosts by preference, got %v", mailbox.MX)
+	}
+}
+
+func TestSelfHostedMailbox(t *testing.T) {
+	hosting := &models.HostingInfo{Name: "Example Hosting", Abuse: models.ContactInfo{Email: "abuse@hosting.example", Source: models.ContactSourceDirectory}}
+
+	tests := []struct {
+		name      string
+		mx        []string
+		wantName  string
+		wantEmail string
+	}{
+		{name: "third party mx", mx: []string{"mx1.mailhost.example.org"}, wantName: "example.org", wantEmail: "abuse@example.org"},
+		{name: "mx on the same domain", mx: []string{"mail.acme-suporte.net"}, wantName: "Example Hosting", wantEmail: "abuse@hosting.example"},
+		{name: "no mx", wantName: "Example Hosting", wantEmail: "abuse@hosting.example"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contact := &models.AbuseContact{Domain: "acme-suporte.net", Hosting: hosting}
+			mailbox := selfHostedMailbox("drop@acme-suporte.net", "acme-suporte.net", tt.mx, contact)
+			if mailbox.Name != tt.wantName || mailbox.Abuse.Email != tt.wantEmail || mailbox.Account != "drop@acme-suporte.net" {
+				t.Errorf("Unexpected mailbox: %+v", mailbox)
+			}
+		})
+	}
+}
+
+func TestService_LookupPlatform(t *testing.T) {
+	service := NewService()
+
+	tests := []struct {
+		name        string
+		value       string
+		wantName    string
+		wantSite    string
+		wantContact bool
+	}{
+		{name: "github pages", value: "hxxps://acme-login[.]github[.]io/auth", wantName: "GitHub, Inc.", wantSite: "acme-login.github.io", wantContact: true},
+		{name: "google sites path", value: "https://sites.google.com/view/acme-login", wantName: "Google Sites / Docs / Forms", wantSite: "sites.google.com", wantContact: true},
+		{name: "uncatalogued private suffix", value: "acme-login.glitch.me", wantName: "glitch.me", wantSite: "acme-login.glitch.me"},
+		{name: "regular domain", value: "login.acme-bank.example"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			normalized, err := indicator.Normalize(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			platform := service.lookupPlatform(normalized)
+			if tt.wantName == "" {
+				if platform != nil {
+					t.Errorf("Expected no platform, got %+v", platform)
+				}
+				return
+			}
+			if platform == nil || platform.Name != tt.wantName || platform.Site != tt.wantSite {
+				t.Fatalf("Expected %s (%s), got %+v", tt.wantName, tt.wantSite, platform)
+			}
+			if platform.HasContact() != tt.wantContact {
+				t.Errorf("Expected HasContact %v, got %+v", tt.wantContact, platform)
+			}
+		})
+	}
+}
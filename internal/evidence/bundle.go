@@ -0,0 +1,365 @@
+package evidence
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cti-team/takedown/internal/redact"
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+const (
+	// ManifestName e SignatureName são os arquivos de controle do bundle
+	ManifestName  = "manifest.json"
+	SignatureName = "manifest.sig"
+
+	manifestVersion = 1
+	// maxBundleFileBytes limita cada arquivo lido na verificação
+	maxBundleFileBytes = 256 << 20
+)
+
+// Tipos de artefato do manifest
+const (
+	KindEvidence   = "evidence"
+	KindDNS        = "dns"
+	KindHTTP       = "http"
+	KindTLS        = "tls"
+	KindContent    = "content"
+	KindHAR        = "har"
+	KindScreenshot = "screenshot"
+	KindReport     = "report"
+	KindRedaction  = "redaction"
+)
+
+// Manifest descreve o bundle; a assinatura Ed25519 destacada (manifest.sig) cobre seus bytes exatos
+type Manifest struct {
+	Version     int            `json:"version"`
+	EvidenceID  string         `json:"evidence_id"`
+	CaseID      string         `json:"case_id,omitempty"`
+	IOC         string         `json:"ioc"`
+	Defanged    string         `json:"defanged"`
+	CollectedAt time.Time      `json:"collected_at"`
+	CreatedAt   time.Time      `json:"created_at"`
+	Algorithm   string         `json:"algorithm"`
+	KeyID       string         `json:"key_id"`
+	Files       []ManifestFile `json:"files"`
+}
+
+// ManifestFile é um artefato do bundle com seu hash
+type ManifestFile struct {
+	Name   string `json:"name"`
+	Kind   string `json:"kind"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// bundleFile é um artefato ainda não gravado no ZIP
+type bundleFile struct {
+	name string
+	kind string
+	data []byte
+}
+
+// BundleExporter empacota EvidencePacks em ZIPs assinados para submissões e uso jurídico
+type BundleExporter struct {
+	key      ed25519.PrivateKey
+	redactor *redact.Redactor
+	now      func() time.Time
+}
+
+// NewBundleExporter cria um exportador que assina os manifests com a chave Ed25519.
+// As evidências são redigidas com as regras padrão antes de entrar no bundle.
+func NewBundleExporter(key ed25519.PrivateKey) *BundleExporter {
+	return &BundleExporter{
+		key:      key,
+		redactor: redact.Default(),
+		now:      func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// SetRedactor configura as regras de redação aplicadas ao pack e ao HAR
+func (e *BundleExporter) SetRedactor(redactor *redact.Redactor) {
+	e.redactor = redactor
+}
+
+// BundleName retorna o nome do arquivo do bundle de um pack
+func BundleName(pack *models.EvidencePack) string {
+	return "evidence-" + pack.EvidenceID + ".zip"
+}
+
+// Bundle gera o ZIP em memória (anexos de email)
+func (e *BundleExporter) Bundle(pack *models.EvidencePack, request *models.TakedownRequest) ([]byte, *Manifest, error) {
+	var buffer bytes.Buffer
+	manifest, err := e.Export(&buffer, pack, request)
+	if err != nil {
+		return nil, nil, err
+	}
+	return buffer.Bytes(), manifest, nil
+}
+
+// BundleRedacted gera em memória o ZIP de um pack já redigido (ver ExportRedacted)
+func (e *BundleExporter) BundleRedacted(pack *models.EvidencePack, audit *redact.Audit, request *models.TakedownRequest) ([]byte, *Manifest, error) {
+	var buffer bytes.Buffer
+	manifest, err := e.ExportRedacted(&buffer, pack, audit, request)
+	if err != nil {
+		return nil, nil, err
+	}
+	return buffer.Bytes(), manifest, nil
+}
+
+// Export grava o ZIP com os artefatos redigidos (DNS, HTTP, TLS, conteúdo, HAR, screenshots),
+// a auditoria da redação, o report.html, o manifest.json com os hashes e a assinatura destacada
+func (e *BundleExporter) Export(w io.Writer, pack *models.EvidencePack, request *models.TakedownRequest) (*Manifest, error) {
+	if pack == nil {
+		return nil, errors.New("no evidence pack to export")
+	}
+	pack, audit := e.redactor.Pack(pack)
+	return e.export(w, pack, audit, request)
+}
+
+// ExportRedacted grava o bundle de um pack que já foi redigido por quem gerou a auditoria
+// (a state machine, antes da submissão). Só o HAR é redigido aqui, somando-se à auditoria.
+func (e *BundleExporter) ExportRedacted(w io.Writer, pack *models.EvidencePack, audit *redact.Audit, request *models.TakedownRequest) (*Manifest, error) {
+	if pack == nil {
+		return nil, errors.New("no evidence pack to export")
+	}
+	merged := &redact.Audit{Findings: []redact.Finding{}}
+	if audit != nil {
+		merged.Findings = append(merged.Findings, audit.Findings...)
+	}
+	return e.export(w, pack, merged, request)
+}
+
+// export grava o ZIP de um pack redigido com a auditoria correspondente
+func (e *BundleExporter) export(w io.Writer, pack *models.EvidencePack, audit *redact.Audit, request *models.TakedownRequest) (*Manifest, error) {
+	files, err := e.artifactFiles(pack, audit)
+	if err != nil {
+		return nil, err
+	}
+
+	auditData, err := json.MarshalIndent(audit, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode redaction audit: %w", err)
+	}
+	files = append(files, bundleFile{name: "redaction.json", kind: KindRedaction, data: auditData})
+
+	manifest := &Manifest{
+		Version:     manifestVersion,
+		EvidenceID:  pack.EvidenceID,
+		IOC:         pack.IOC,
+		Defanged:    pack.Defanged,
+		CollectedAt: pack.CollectedAt,
+		CreatedAt:   e.now(),
+		Algorithm:   "ed25519",
+		KeyID:       KeyID(e.key.Public().(ed25519.PublicKey)),
+	}
+	if request != nil {
+		manifest.CaseID = request.CaseID
+	}
+	for _, file := range files {
+		manifest.Files = append(manifest.Files, describe(file))
+	}
+
+	// O report lista os hashes dos demais artefatos e entra no manifest como mais um arquivo
+	report, err := renderReport(pack, request, manifest, audit)
+	if err != nil {
+		return nil, err
+	}
+	reportFile := bundleFile{name: "report.html", kind: KindReport, data: report}
+	files = append(files, reportFile)
+	manifest.Files = append(manifest.Files, describe(reportFile))
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(e.key, manifestData))
+
+	archive := zip.NewWriter(w)
+	files = append(files,
+		bundleFile{name: ManifestName, data: manifestData},
+		bundleFile{name: SignatureName, data: []byte(signature + "\n")},
+	)
+	for _, file := range files {
+		header := &zip.FileHeader{Name: file.name, Method: zip.Deflate, Modified: manifest.CreatedAt}
+		writer, err := archive.CreateHeader(header)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add %s: %w", file.name, err)
+		}
+		if _, err := writer.Write(file.data); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish bundle: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// VerifyBundle confere a assinatura do manifest e o hash de cada arquivo do ZIP.
+// Arquivos ausentes, alterados ou fora do manifest invalidam o bundle.
+func VerifyBundle(data []byte, key ed25519.PublicKey) (*Manifest, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid bundle: %w", err)
+	}
+
+	contents := make(map[string][]byte, len(archive.File))
+	for _, file := range archive.File {
+		if _, duplicate := contents[file.Name]; duplicate {
+			return nil, fmt.Errorf("duplicate file %s in bundle", file.Name)
+		}
+		content, err := readZipFile(file)
+		if err != nil {
+			return nil, err
+		}
+		contents[file.Name] = content
+	}
+
+	manifestData, ok := contents[ManifestName]
+	if !ok {
+		return nil, errors.New("bundle has no " + ManifestName)
+	}
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(contents[SignatureName])))
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return nil, errors.New("bundle has no valid " + SignatureName)
+	}
+	if !ed25519.Verify(key, manifestData, signature) {
+		return nil, fmt.Errorf("manifest signature does not match key %s", KeyID(key))
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	listed := map[string]bool{ManifestName: true, SignatureName: true}
+	for _, file := range manifest.Files {
+		content, ok := contents[file.Name]
+		if !ok {
+			return &manifest, fmt.Errorf("%s is listed in the manifest but missing", file.Name)
+		}
+		sum := sha256.Sum256(content)
+		if hex.EncodeToString(sum[:]) != file.SHA256 || int64(len(content)) != file.Size {
+			return &manifest, fmt.Errorf("%s does not match its manifest hash", file.Name)
+		}
+		listed[file.Name] = true
+	}
+	for name := range contents {
+		if !listed[name] {
+			return &manifest, fmt.Errorf("%s is not listed in the manifest", name)
+		}
+	}
+
+	return &manifest, nil
+}
+
+// artifactFiles serializa as seções do pack já redigido e lê HAR e screenshots do disco.
+// O HAR é redigido depois de conferido o hash da coleta; screenshots entram como estão.
+func (e *BundleExporter) artifactFiles(pack *models.EvidencePack, audit *redact.Audit) ([]bundleFile, error) {
+	sections := []struct {
+		name  string
+		kind  string
+		value interface{}
+		skip  bool
+	}{
+		{"evidence.json", KindEvidence, pack, false},
+		{"dns.json", KindDNS, pack.DNS, false},
+		{"http.json", KindHTTP, pack.HTTP, false},
+		{"tls.json", KindTLS, pack.TLS, pack.TLS == nil},
+		{"content.json", KindContent, pack.Content, pack.Content == nil},
+	}
+
+	var files []bundleFile
+	for _, section := range sections {
+		if section.skip {
+			continue
+		}
+		data, err := json.MarshalIndent(section.value, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", section.name, err)
+		}
+		files = append(files, bundleFile{name: section.name, kind: section.kind, data: data})
+	}
+
+	if pack.HAR != "" {
+		file, err := storedArtifact(pack, pack.HAR, harFileName, KindHAR)
+		if err != nil {
+			return nil, err
+		}
+		if file.data, err = e.redactor.HAR(file.data, audit); err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	screenshots := append([]string(nil), pack.Screenshots...)
+	sort.Strings(screenshots)
+	for _, path := range screenshots {
+		file, err := storedArtifact(pack, path, "screenshots/"+filepath.Base(path), KindScreenshot)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+// storedArtifact lê um arquivo do evidence store, conferindo o hash registrado na coleta
+func storedArtifact(pack *models.EvidencePack, path, name, kind string) (bundleFile, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- caminho gravado pelo próprio evidence store
+	if err != nil {
+		return bundleFile{}, fmt.Errorf("failed to read %s: %w", kind, err)
+	}
+	if expected, ok := pack.Hashes[path]; ok {
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != expected {
+			return bundleFile{}, fmt.Errorf("%s changed since collection (hash mismatch)", path)
+		}
+	}
+	return bundleFile{name: name, kind: kind, data: data}, nil
+}
+
+// describe calcula a entrada do manifest de um artefato
+func describe(file bundleFile) ManifestFile {
+	sum := sha256.Sum256(file.data)
+	return ManifestFile{
+		Name:   file.name,
+		Kind:   file.kind,
+		SHA256: hex.EncodeToString(sum[:]),
+		Size:   int64(len(file.data)),
+	}
+}
+
+// readZipFile lê um arquivo do ZIP com limite de tamanho
+func readZipFile(file *zip.File) ([]byte, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", file.Name, err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(reader, maxBundleFileBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file.Name, err)
+	}
+	if len(data) > maxBundleFileBytes {
+		return nil, fmt.Errorf("%s exceeds %d bytes", file.Name, maxBundleFileBytes)
+	}
+	return data, nil
+}
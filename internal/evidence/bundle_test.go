@@ -0,0 +1,303 @@
+package evidence
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// testPack grava HAR e screenshot num store temporário e monta o pack com seus hashes
+func testPack(t *testing.T) *models.EvidencePack {
+	t.Helper()
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pack := &models.EvidencePack{
+		EvidenceID:  "ev-123",
+		IOC:         "ioc-1",
+		CollectedAt: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		DNS:         models.DNSRecord{A: []string{"203.0.113.10"}},
+		HTTP:        models.HTTPInfo{Status: 200, Title: "Acme <Login>", Chain: []string{"https://acme-login.example/start"}},
+		TLS:         &models.TLSInfo{CN: "acme-login.example", Issuer: "CN=Test CA"},
+		Risk:        models.RiskAssessment{Score: 90, Category: "phishing"},
+		Defanged:    "hxxps://acme-login[.]example/start",
+	}
+	for _, artifact := range []struct{ name, content string }{
+		{harFileName, `{"log":{"version":"1.2","entries":[{"request":{"method":"GET","url":"https://acme-login.example/start",` +
+			`"headers":[{"name":"Cookie","value":"session=abc123"}]},"response":{"status":200,"content":{"text":"hello victim@acme.example"}}}]}}`},
+		{"screenshot.png", "\x89PNG fake"},
+	} {
+		stored, err := store.Save(pack.EvidenceID, artifact.name, []byte(artifact.content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		pack.AddHash(stored.Path, stored.SHA256)
+		if artifact.name == harFileName {
+			pack.HAR = stored.Path
+		} else {
+			pack.Screenshots = append(pack.Screenshots, stored.Path)
+		}
+	}
+	return pack
+}
+
+// rewriteZip copia o bundle aplicando edit a cada arquivo; edit retorna false para remover
+func rewriteZip(t *testing.T, data []byte, edit func(name string, content []byte) ([]byte, bool), extra map[string]string) []byte {
+	t.Helper()
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	writer := zip.NewWriter(&out)
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(rc)
+		_ = rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, keep := edit(file.Name, content)
+		if !keep {
+			continue
+		}
+		w, _ := writer.Create(file.Name)
+		_, _ = w.Write(content)
+	}
+	for name, content := range extra {
+		w, _ := writer.Create(name)
+		_, _ = w.Write([]byte(content))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return out.Bytes()
+}
+
+func TestBundleExportAndVerify(t *testing.T) {
+	public, private, err := GenerateSigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pack := testPack(t)
+	request := &models.TakedownRequest{CaseID: "case-9", RequestedAction: models.ActionSuspendDomain}
+
+	data, manifest, err := NewBundleExporter(private).Bundle(pack, request)
+	if err != nil {
+		t.Fatalf("Bundle returned error: %v", err)
+	}
+	if manifest.CaseID != "case-9" || manifest.KeyID != KeyID(public) || manifest.Algorithm != "ed25519" {
+		t.Fatalf("unexpected manifest header: %+v", manifest)
+	}
+
+	kinds := make(map[string]string)
+	for _, file := range manifest.Files {
+		kinds[file.Name] = file.Kind
+	}
+	for name, kind := range map[string]string{
+		"evidence.json":              KindEvidence,
+		"dns.json":                   KindDNS,
+		"http.json":                  KindHTTP,
+		"tls.json":                   KindTLS,
+		harFileName:                  KindHAR,
+		"screenshots/screenshot.png": KindScreenshot,
+		"report.html":                KindReport,
+		"redaction.json":             KindRedaction,
+	} {
+		if kinds[name] != kind {
+			t.Errorf("manifest entry %s: got kind %q, want %q", name, kinds[name], kind)
+		}
+	}
+	if _, ok := kinds["content.json"]; ok {
+		t.Errorf("content.json should be omitted for a pack without content analysis")
+	}
+
+	verified, err := VerifyBundle(data, public)
+	if err != nil {
+		t.Fatalf("VerifyBundle returned error: %v", err)
+	}
+	if verified.EvidenceID != "ev-123" || len(verified.Files) != len(manifest.Files) {
+		t.Fatalf("unexpected verified manifest: %+v", verified)
+	}
+
+	// O report é legível, escapa o HTML da página e não expõe a URL ativa
+	var report, capture, audit []byte
+	rewriteZip(t, data, func(name string, content []byte) ([]byte, bool) {
+		switch name {
+		case "report.html":
+			report = content
+		case harFileName:
+			capture = content
+		case "redaction.json":
+			audit = content
+		}
+		return content, true
+	}, nil)
+	for _, secret := range []string{"abc123", "victim@acme.example"} {
+		if bytes.Contains(capture, []byte(secret)) {
+			t.Errorf("bundled HAR contains %q", secret)
+		}
+	}
+	if !bytes.Contains(audit, []byte(`"rule": "cookie"`)) || !bytes.Contains(audit, []byte(`"rule": "email"`)) {
+		t.Errorf("redaction.json does not record the HAR findings: %s", audit)
+	}
+	for _, want := range []string{"hxxps://acme-login[.]example/start", "Acme &lt;Login&gt;", "case-9", KeyID(public), "2 values redacted"} {
+		if !bytes.Contains(report, []byte(want)) {
+			t.Errorf("report.html does not contain %q", want)
+		}
+	}
+	if bytes.Contains(report, []byte("https://acme-login.example")) {
+		t.Errorf("report.html contains a live URL")
+	}
+}
+
+func TestVerifyBundleRejectsTampering(t *testing.T) {
+	public, private, err := GenerateSigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPublic, _, err := GenerateSigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _, err := NewBundleExporter(private).Bundle(testPack(t), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keep := func(_ string, content []byte) ([]byte, bool) { return content, true }
+	tests := []struct {
+		name    string
+		bundle  []byte
+		key     []byte
+		wantErr string
+	}{
+		{
+			name:    "wrong key",
+			bundle:  data,
+			key:     otherPublic,
+			wantErr: "signature",
+		},
+		{
+			name: "modified artifact",
+			bundle: rewriteZip(t, data, func(name string, content []byte) ([]byte, bool) {
+				if name == "dns.json" {
+					return bytes.Replace(content, []byte("203.0.113.10"), []byte("203.0.113.99"), 1), true
+				}
+				return content, true
+			}, nil),
+			key:     public,
+			wantErr: "dns.json does not match",
+		},
+		{
+			name: "modified manifest",
+			bundle: rewriteZip(t, data, func(name string, content []byte) ([]byte, bool) {
+				if name == ManifestName {
+					return bytes.Replace(content, []byte("ev-123"), []byte("ev-999"), 1), true
+				}
+				return content, true
+			}, nil),
+			key:     public,
+			wantErr: "signature",
+		},
+		{
+			name: "missing artifact",
+			bundle: rewriteZip(t, data, func(name string, content []byte) ([]byte, bool) {
+				return content, name != harFileName
+			}, nil),
+			key:     public,
+			wantErr: "missing",
+		},
+		{
+			name:    "unlisted file",
+			bundle:  rewriteZip(t, data, keep, map[string]string{"notes.txt": "added later"}),
+			key:     public,
+			wantErr: "not listed",
+		},
+		{
+			name: "missing signature",
+			bundle: rewriteZip(t, data, func(name string, content []byte) ([]byte, bool) {
+				return content, name != SignatureName
+			}, nil),
+			key:     public,
+			wantErr: SignatureName,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := VerifyBundle(tt.bundle, tt.key)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("VerifyBundle error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBundleRejectsChangedArtifact(t *testing.T) {
+	_, private, err := GenerateSigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pack := testPack(t)
+	sum := sha256.Sum256([]byte("something else"))
+	pack.Hashes[pack.HAR] = hex.EncodeToString(sum[:])
+
+	if _, _, err := NewBundleExporter(private).Bundle(pack, nil); err == nil || !strings.Contains(err.Error(), "hash mismatch") {
+		t.Fatalf("expected hash mismatch error, got %v", err)
+	}
+}
+
+func TestSigningKeyPEMRoundTrip(t *testing.T) {
+	public, private, err := GenerateSigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	privatePEM, err := MarshalPrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicPEM, err := MarshalPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateFile, err := store.Save("keys", "signing.key", privatePEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicFile, err := store.Save("keys", "signing.pub", publicPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loadedPrivate, err := LoadSigningKey(privateFile.Path)
+	if err != nil {
+		t.Fatalf("LoadSigningKey returned error: %v", err)
+	}
+	loadedPublic, err := LoadVerifyKey(publicFile.Path)
+	if err != nil {
+		t.Fatalf("LoadVerifyKey returned error: %v", err)
+	}
+	if !loadedPrivate.Equal(private) || !loadedPublic.Equal(public) {
+		t.Fatalf("keys changed after PEM round trip")
+	}
+	if _, err := LoadVerifyKey(privateFile.Path); err == nil {
+		t.Fatalf("LoadVerifyKey accepted a private key file")
+	}
+}
@@ -0,0 +1,153 @@
+package evidence
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// minContentSimilarity é a similaridade mínima (Jaccard de palavras) para considerar duas páginas iguais
+const minContentSimilarity = 0.5
+
+// wordPattern separa o corpo em palavras para a comparação de conteúdo
+var wordPattern = regexp.MustCompile(`[\pL\pN_]{3,}`)
+
+// detectCloaking compara cada variante com a primeira (referência) e escolhe a visão da vítima.
+// Retorna nil se houver menos de duas variantes, junto com o índice da variante a usar no pack.
+func detectCloaking(variants []models.FetchVariant, bodies [][]byte) (*models.CloakingResult, int) {
+	if len(variants) < 2 {
+		return nil, 0
+	}
+
+	baseline := variants[0]
+	baseWords := wordSet(bodies[0])
+	result := &models.CloakingResult{Baseline: baseline.Profile}
+
+	for i, variant := range variants[1:] {
+		diffs := variantDiffs(baseline, variant)
+		if baseline.Error == "" && variant.Error == "" {
+			if similarity := jaccard(baseWords, wordSet(bodies[i+1])); similarity < minContentSimilarity {
+				diffs = append(diffs, fmt.Sprintf("content similarity %.0f%%", similarity*100))
+			}
+		}
+		if len(diffs) > 0 {
+			result.Reasons = append(result.Reasons,
+				fmt.Sprintf("%s vs %s: %s", variant.Profile, baseline.Profile, strings.Join(diffs, ", ")))
+		}
+	}
+
+	if len(result.Reasons) == 0 {
+		return result, 0
+	}
+
+	result.Detected = true
+	victim := 0
+	for i := range variants {
+		if maliciousness(variants[i]) > maliciousness(variants[victim]) {
+			victim = i
+		}
+	}
+	result.VictimProfile = variants[victim].Profile
+	return result, victim
+}
+
+// variantDiffs lista as diferenças objetivas entre duas variantes
+func variantDiffs(baseline, variant models.FetchVariant) []string {
+	var diffs []string
+
+	if (baseline.Error == "") != (variant.Error == "") {
+		failed := baseline.Profile
+		if variant.Error != "" {
+			failed = variant.Profile
+		}
+		return []string{"fetch failed only for " + failed}
+	}
+	if baseline.Error != "" {
+		return nil
+	}
+
+	if baseline.HTTP.Status/100 != variant.HTTP.Status/100 {
+		diffs = append(diffs, fmt.Sprintf("status %d vs %d", variant.HTTP.Status, baseline.HTTP.Status))
+	}
+	if variantHost, baseHost := finalHost(variant), finalHost(baseline); variantHost != baseHost {
+		diffs = append(diffs, fmt.Sprintf("final host %s vs %s", variantHost, baseHost))
+	}
+	if variant.HTTP.Title != baseline.HTTP.Title {
+		diffs = append(diffs, fmt.Sprintf("title %q vs %q", variant.HTTP.Title, baseline.HTTP.Title))
+	}
+	if hasPasswordForm(variant) != hasPasswordForm(baseline) {
+		owner := baseline.Profile
+		if hasPasswordForm(variant) {
+			owner = variant.Profile
+		}
+		diffs = append(diffs, "password form only for "+owner)
+	}
+
+	return diffs
+}
+
+// maliciousness pontua os indícios de phishing de uma variante para escolher a visão da vítima
+func maliciousness(variant models.FetchVariant) int {
+	if variant.Error != "" {
+		return -1
+	}
+
+	score := 0
+	if variant.HTTP.Status/100 == 2 {
+		score++
+	}
+	if content := variant.Content; content != nil {
+		if content.HasPasswordForm {
+			score += 3
+		}
+		if content.HasLoginForm {
+			score += 2
+		}
+		score += 2*len(content.TelegramBots) + len(content.KitMarkers) + len(content.Brands) + len(content.ExternalPosts)
+	}
+	return score
+}
+
+// finalHost retorna o host da última URL da cadeia de redirects
+func finalHost(variant models.FetchVariant) string {
+	if len(variant.HTTP.Chain) == 0 {
+		return ""
+	}
+	parsed, err := url.Parse(variant.HTTP.Chain[len(variant.HTTP.Chain)-1])
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+// hasPasswordForm indica se a análise de conteúdo encontrou campo de senha
+func hasPasswordForm(variant models.FetchVariant) bool {
+	return variant.Content != nil && variant.Content.HasPasswordForm
+}
+
+// wordSet retorna o conjunto de palavras do corpo (em minúsculas)
+func wordSet(body []byte) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range wordPattern.FindAllString(strings.ToLower(string(body)), -1) {
+		words[word] = true
+	}
+	return words
+}
+
+// jaccard calcula a similaridade entre dois conjuntos de palavras (1 se ambos vazios)
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+
+	shared := 0
+	for word := range a {
+		if b[word] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
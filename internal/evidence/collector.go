@@ -1,19 +1,354 @@
 package evidence
 
 import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cti-team/takedown/internal/analysis"
+	"github.com/cti-team/takedown/pkg/defang"
+	"github.com/cti-team/takedown/pkg/har"
+	"github.com/cti-team/takedown/pkg/indicator"
 	"github.com/cti-team/takedown/pkg/models"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultTimeout      = 30 * time.Second
+	defaultMaxRedirects = 10
+	defaultUserAgent    = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36"
+	bodyPreviewBytes    = 1024 // HTTPInfo.Body guarda apenas o primeiro 1KB
+	harFileName         = "capture.har"
 )
 
-// Collector is a stub implementation used for building and testing.
-// TODO: implement evidence collection logic.
-type Collector struct{}
+// titlePattern extrai o <title> da página
+var titlePattern = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// Collector coleta evidências HTTP/TLS de um IOC, gravando a captura completa em HAR
+type Collector struct {
+	store        *Store
+	analyzer     *analysis.Analyzer
+	dialer       *net.Dialer // recusa endereços não públicos: usado pelo transport e pela sondagem de portas
+	transport    http.RoundTripper
+	timeout      time.Duration
+	maxRedirects int
+	bodyLimit    int64
+	profiles     []Profile
+	ports        []int
+	sampleLimit  int64
+
+	probeTimeout  time.Duration
+	bannerTimeout time.Duration
+}
 
 // NewCollector returns a new Collector instance.
 func NewCollector() *Collector {
-	return &Collector{}
+	// O IOC e os redirects são controlados pelo atacante: a conexão só é aberta depois de conferir
+	// que o endereço resolvido é público, senão a resposta de um serviço interno (metadata da nuvem,
+	// rede privada) iria para o HAR e para o bundle enviado a terceiros
+	dialer := &net.Dialer{
+		Timeout:   defaultTimeout,
+		KeepAlive: 30 * time.Second,
+		Control:   indicator.DialControl,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// Com proxy a verificação veria só o endereço do proxy, que busca o destino sem ela
+	transport.Proxy = nil
+	// Sites de phishing frequentemente usam certificados inválidos; a cadeia é registrada no HAR
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} // #nosec G402 -- coleta de evidência, não confiança
+
+	return &Collector{
+		analyzer:     analysis.NewAnalyzer(analysis.DefaultConfig()),
+		dialer:       dialer,
+		transport:    transport,
+		timeout:      defaultTimeout,
+		maxRedirects: defaultMaxRedirects,
+		bodyLimit:    har.DefaultBodyLimit,
+		profiles:     DefaultProfiles(),
+		ports:        DefaultPorts,
+		sampleLimit:  defaultSampleLimit,
+
+		probeTimeout:  defaultProbeTimeout,
+		bannerTimeout: defaultBannerTimeout,
+	}
+}
+
+// SetStore configura o evidence store onde o HAR é gravado
+func (c *Collector) SetStore(store *Store) {
+	c.store = store
+}
+
+// SetAnalyzer substitui o analisador de conteúdo (nil desativa a análise)
+func (c *Collector) SetAnalyzer(analyzer *analysis.Analyzer) {
+	c.analyzer = analyzer
+}
+
+// SetTransport substitui o transport HTTP usado na coleta
+func (c *Collector) SetTransport(transport http.RoundTripper) {
+	c.transport = transport
 }
 
-// CollectEvidence returns an empty EvidencePack placeholder.
+// SetBodyLimit define o tamanho máximo de corpo gravado por entrada do HAR
+func (c *Collector) SetBodyLimit(limit int64) {
+	c.bodyLimit = limit
+}
+
+// SetProfiles define os perfis de coleta; o primeiro é a referência para detectar cloaking
+func (c *Collector) SetProfiles(profiles []Profile) {
+	c.profiles = profiles
+}
+
+// SetSampleLimit define quanto é lido de URLs que servem amostras com hash informado
+func (c *Collector) SetSampleLimit(limit int64) {
+	c.sampleLimit = limit
+}
+
+// SetPorts define as portas sondadas em IOCs de IP (vazio desativa a sondagem)
+func (c *Collector) SetPorts(ports []int) {
+	c.ports = ports
+}
+
+// CollectEvidence coleta as evidências de um IOC e grava o HAR da cadeia de redirects
 func (c *Collector) CollectEvidence(ioc *models.IOC) (*models.EvidencePack, error) {
-	return &models.EvidencePack{EvidenceID: "stub"}, nil
+	pack := &models.EvidencePack{
+		EvidenceID:  "ev-" + uuid.New().String(),
+		IOC:         ioc.IndicatorID,
+		CollectedAt: time.Now().UTC(),
+	}
+	if ioc.Value != "" {
+		pack.Defanged = pack.GetDefangedURL(ioc.Value)
+	}
+
+	// Contas de email não têm página própria: a evidência são as páginas que enviam para elas
+	for _, page := range ioc.PhishingURLs {
+		pack.PhishingURLs = append(pack.PhishingURLs, defang.Defang(page))
+	}
+
+	target := fetchURL(ioc)
+	if ioc.Type == models.IOCTypeIP && len(c.ports) > 0 {
+		// IPs não têm URL: banners e certificados das portas abertas são a evidência, e o
+		// primeiro serviço HTTP(S) é capturado como uma URL
+		host := defang.Refang(ioc.Value)
+		pack.Services = c.probeServices(host)
+		for _, service := range pack.Services {
+			if service.TLS != nil {
+				pack.TLS = service.TLS
+				break
+			}
+		}
+		target = serviceURL(host, pack.Services)
+	}
+	if target == "" {
+		return pack, nil
+	}
+
+	recorder := har.NewRecorder(c.transport, c.bodyLimit)
+	brand := ioc.GetBrand()
+
+	// URLs com amostras informadas são lidas até o limite de amostra para que os hashes confiram
+	readLimit := c.bodyLimit
+	if len(ioc.Hashes) > 0 && c.sampleLimit > readLimit {
+		readLimit = c.sampleLimit
+	}
+
+	// Cada perfil é uma página do HAR; falhas de rede não invalidam a coleta e ficam registradas
+	bodies := make([][]byte, 0, len(c.profiles))
+	for _, profile := range c.profiles {
+		pageRef := recorder.StartPage(profile.Name + " " + target)
+		variant, body := c.fetch(recorder, target, profile, brand, readLimit)
+		variant.PageRef = pageRef
+		variant.HTTP.Chain = redirectChain(recorder.Entries(), pageRef)
+		pack.Variants = append(pack.Variants, variant)
+		bodies = append(bodies, body)
+	}
+
+	if len(pack.Variants) > 0 {
+		// Com cloaking, o pack mostra o que a vítima vê, não o que o scanner recebeu
+		cloaking, victim := detectCloaking(pack.Variants, bodies)
+		pack.Cloaking = cloaking
+		pack.HARPage = pack.Variants[victim].PageRef
+		pack.HTTP = pack.Variants[victim].HTTP
+		if tlsInfo := pack.Variants[victim].TLS; tlsInfo != nil || pack.TLS == nil {
+			pack.TLS = tlsInfo
+		}
+		pack.Content = pack.Variants[victim].Content
+		if body := bodies[victim]; int64(len(body)) > c.bodyLimit {
+			pack.Body = body[:c.bodyLimit]
+		} else {
+			pack.Body = body
+		}
+
+		// Amostras de malware: o provedor localiza e remove o arquivo pelo hash
+		if body := bodies[victim]; len(ioc.Hashes) > 0 || detectFileType(body) != "" {
+			finalURL := target
+			if chain := pack.HTTP.Chain; len(chain) > 0 {
+				finalURL = chain[len(chain)-1]
+			}
+			pack.File = sampleFile(finalURL, body, ioc.Hashes, int64(len(body)) >= readLimit)
+		}
+	}
+
+	if c.store != nil {
+		if err := c.saveHAR(recorder.HAR(), pack); err != nil {
+			return nil, err
+		}
+	}
+
+	return pack, nil
+}
+
+// fetch executa o GET sob um perfil seguindo redirects e retorna a variante e o corpo lido (até readLimit)
+func (c *Collector) fetch(recorder *har.Recorder, target string, profile Profile, brand string, readLimit int64) (models.FetchVariant, []byte) {
+	variant := models.FetchVariant{Profile: profile.Name}
+
+	jar, _ := cookiejar.New(nil)
+	client := &http.Client{
+		Transport: recorder,
+		Timeout:   c.timeout,
+		Jar:       jar,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= c.maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", c.maxRedirects)
+			}
+			if scheme := req.URL.Scheme; scheme != "http" && scheme != "https" {
+				return fmt.Errorf("refusing redirect to %s URL", scheme)
+			}
+			if profile.Referer != "" {
+				req.Header.Set("Referer", profile.Referer)
+			}
+			return nil
+		},
+	}
+
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		variant.Error = err.Error()
+		return variant, nil
+	}
+	req.Header.Set("User-Agent", profile.UserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+	if profile.AcceptLanguage != "" {
+		req.Header.Set("Accept-Language", profile.AcceptLanguage)
+	}
+	if profile.Referer != "" {
+		req.Header.Set("Referer", profile.Referer)
+	}
+	for name, value := range profile.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		variant.Error = err.Error()
+		return variant, nil
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, readLimit))
+	sum := sha256.Sum256(body)
+	variant.BodySHA256 = hex.EncodeToString(sum[:])
+	variant.BodySize = len(body)
+
+	variant.HTTP.Status = resp.StatusCode
+	variant.HTTP.Headers = make(map[string]string, len(resp.Header))
+	for name := range resp.Header {
+		variant.HTTP.Headers[name] = resp.Header.Get(name)
+	}
+	variant.HTTP.Title = extractTitle(body)
+	if c.analyzer != nil {
+		variant.Content = c.analyzer.Analyze(resp.Request.URL.String(), body, brand)
+	}
+	preview := body
+	if len(preview) > bodyPreviewBytes {
+		preview = preview[:bodyPreviewBytes]
+	}
+	variant.HTTP.Body = string(preview)
+
+	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		variant.TLS = certificateInfo(resp.TLS.PeerCertificates[0])
+	}
+
+	return variant, body
+}
+
+// certificateInfo extrai os campos do certificado registrados no evidence pack
+func certificateInfo(cert *x509.Certificate) *models.TLSInfo {
+	return &models.TLSInfo{
+		Issuer:    cert.Issuer.String(),
+		CN:        cert.Subject.CommonName,
+		SAN:       cert.DNSNames,
+		NotBefore: cert.NotBefore.UTC(),
+		NotAfter:  cert.NotAfter.UTC(),
+		Serial:    cert.SerialNumber.String(),
+		Algorithm: cert.SignatureAlgorithm.String(),
+	}
+}
+
+// saveHAR grava o HAR no evidence store e registra caminho e hash no pack
+func (c *Collector) saveHAR(archive *har.HAR, pack *models.EvidencePack) error {
+	data, err := archive.Marshal()
+	if err != nil {
+		return fmt.Errorf("failed to serialize HAR: %w", err)
+	}
+
+	stored, err := c.store.Save(pack.EvidenceID, harFileName, data)
+	if err != nil {
+		return fmt.Errorf("failed to store HAR: %w", err)
+	}
+
+	pack.HAR = stored.Path
+	pack.AddHash(stored.Path, stored.SHA256)
+	return nil
+}
+
+// fetchURL retorna a URL a ser coletada para o IOC (vazio se não aplicável)
+func fetchURL(ioc *models.IOC) string {
+	value := defang.Refang(ioc.Value)
+	if value == "" {
+		return ""
+	}
+
+	switch ioc.Type {
+	case models.IOCTypeURL:
+		if !strings.Contains(value, "://") {
+			return "http://" + value
+		}
+		return value
+	case models.IOCTypeDomain:
+		return "http://" + value + "/"
+	default:
+		return ""
+	}
+}
+
+// redirectChain lista as URLs visitadas em uma página do HAR, na ordem da captura
+func redirectChain(entries []har.Entry, pageRef string) []string {
+	var chain []string
+	for _, entry := range entries {
+		if entry.Pageref == pageRef {
+			chain = append(chain, entry.Request.URL)
+		}
+	}
+	return chain
+}
+
+// extractTitle extrai o título HTML da página
+func extractTitle(body []byte) string {
+	match := titlePattern.FindSubmatch(body)
+	if match == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(string(match[1])), " ")
 }
@@ -1,10 +1,29 @@
 package evidence
 
 import (
-	"github.com/cti-team/takedown/pkg/models"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"syscall"
 	"testing"
+
+	"github.com/cti-team/takedown/pkg/har"
+	"github.com/cti-team/takedown/pkg/indicator"
+	"github.com/cti-team/takedown/pkg/models"
 )
 
+// newTestCollector libera a coleta em loopback, onde rodam os servidores httptest
+func newTestCollector() *Collector {
+	c := NewCollector()
+	c.dialer.Control = nil
+	return c
+}
+
 func TestNewCollector(t *testing.T) {
 	c := NewCollector()
 	if c == nil {
@@ -22,7 +41,331 @@ func TestCollectorCollectEvidence(t *testing.T) {
 	if pack == nil {
 		t.Fatalf("CollectEvidence returned nil")
 	}
-	if pack.EvidenceID != "stub" {
+	if !strings.HasPrefix(pack.EvidenceID, "ev-") {
 		t.Fatalf("unexpected EvidenceID: %s", pack.EvidenceID)
 	}
+	if pack.IOC != "test" {
+		t.Fatalf("unexpected IOC reference: %s", pack.IOC)
+	}
+	if pack.HAR != "" {
+		t.Fatalf("expected no HAR for an IOC without value, got %s", pack.HAR)
+	}
+}
+
+func TestCollectorCollectEvidence_WritesHAR(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/start":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc"})
+			http.Redirect(w, r, "/login", http.StatusFound)
+		case "/login":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write([]byte("<html><head><title> Acme Bank\n Login </title></head><body>" + strings.Repeat("x", 4096) + "</body></html>"))
+		}
+	}))
+	defer server.Close()
+
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := newTestCollector()
+	c.SetStore(store)
+	c.SetBodyLimit(2048)
+	c.SetProfiles(DefaultProfiles()[:1])
+
+	pack, err := c.CollectEvidence(&models.IOC{IndicatorID: "ioc-1", Type: models.IOCTypeURL, Value: server.URL + "/start"})
+	if err != nil {
+		t.Fatalf("CollectEvidence returned error: %v", err)
+	}
+
+	if pack.HTTP.Status != http.StatusOK || pack.HTTP.Title != "Acme Bank Login" {
+		t.Errorf("unexpected HTTP info: status=%d title=%q", pack.HTTP.Status, pack.HTTP.Title)
+	}
+	if len(pack.HTTP.Chain) != 2 || !strings.HasSuffix(pack.HTTP.Chain[1], "/login") {
+		t.Errorf("unexpected redirect chain: %v", pack.HTTP.Chain)
+	}
+	if len(pack.HTTP.Body) != 1024 {
+		t.Errorf("expected 1KB body preview, got %d bytes", len(pack.HTTP.Body))
+	}
+	if pack.TLS == nil || pack.TLS.Serial == "" {
+		t.Errorf("expected TLS info, got %+v", pack.TLS)
+	}
+
+	data, err := os.ReadFile(pack.HAR)
+	if err != nil {
+		t.Fatalf("HAR file not written: %v", err)
+	}
+	sum := sha256.Sum256(data)
+	if pack.Hashes[pack.HAR] != hex.EncodeToString(sum[:]) {
+		t.Errorf("HAR hash mismatch: %s", pack.Hashes[pack.HAR])
+	}
+
+	var archive har.HAR
+	if err := json.Unmarshal(data, &archive); err != nil {
+		t.Fatalf("invalid HAR JSON: %v", err)
+	}
+	if archive.Log.Version != "1.2" || len(archive.Log.Entries) != 2 || len(archive.Log.Pages) != 1 {
+		t.Fatalf("unexpected HAR log: version=%s entries=%d", archive.Log.Version, len(archive.Log.Entries))
+	}
+
+	redirect, final := archive.Log.Entries[0], archive.Log.Entries[1]
+	if redirect.Response.Status != http.StatusFound || redirect.Response.RedirectURL != "/login" {
+		t.Errorf("unexpected redirect entry: %+v", redirect.Response)
+	}
+	if len(final.Request.Cookies) != 1 || final.Request.Cookies[0].Name != "session" {
+		t.Errorf("expected session cookie on second request, got %+v", final.Request.Cookies)
+	}
+	if !final.Response.Content.Truncated || len(final.Response.Content.Text) != 2048 {
+		t.Errorf("expected body truncated at 2048 bytes, got %d (truncated=%v)",
+			len(final.Response.Content.Text), final.Response.Content.Truncated)
+	}
+	if final.TLS == nil || len(final.TLS.Certificates) == 0 || final.ServerIPAddress == "" {
+		t.Errorf("expected TLS and server IP in HAR entry, got %+v", final)
+	}
+}
+
+func TestCollectorCollectEvidence_AnalyzesFullBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte("<html><head><title>Sign in</title></head><body>" + strings.Repeat("<p>filler</p>", 200) +
+			`<img class="logo" src="/acme.png" alt="AcmeBank"><form action="https://drop.example.net/send.php" method="post">` +
+			`<input name="user"><input type="password" name="pass"></form></body></html>`))
+	}))
+	defer server.Close()
+
+	c := newTestCollector()
+	pack, err := c.CollectEvidence(&models.IOC{IndicatorID: "ioc-2", Type: models.IOCTypeURL, Value: server.URL + "/", Tags: []string{"brand:AcmeBank"}})
+	if err != nil {
+		t.Fatalf("CollectEvidence returned error: %v", err)
+	}
+
+	if pack.Content == nil {
+		t.Fatal("expected content analysis in the evidence pack")
+	}
+	if !pack.Content.HasPasswordForm || len(pack.Content.ExternalPosts) != 1 {
+		t.Errorf("expected cross-domain password form beyond the 1KB preview, got %+v", pack.Content)
+	}
+	if len(pack.Content.Brands) != 1 || pack.Content.Brands[0] != "AcmeBank" {
+		t.Errorf("expected AcmeBank brand, got %v", pack.Content.Brands)
+	}
+	if !strings.Contains(string(pack.Body), `type="password"`) {
+		t.Error("expected the full victim body to be kept for scoring")
+	}
+
+	c.SetAnalyzer(nil)
+	pack, err = c.CollectEvidence(&models.IOC{IndicatorID: "ioc-3", Type: models.IOCTypeURL, Value: server.URL + "/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pack.Content != nil {
+		t.Errorf("expected no content analysis when disabled, got %+v", pack.Content)
+	}
+}
+
+func TestCollectorCollectEvidence_DetectsCloaking(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if !strings.Contains(r.UserAgent(), "iPhone") {
+			_, _ = w.Write([]byte("<html><head><title>Under construction</title></head><body><p>This site is under construction, please come back later.</p></body></html>"))
+			return
+		}
+		_, _ = w.Write([]byte(`<html><head><title>AcmeBank - Sign in</title></head><body><h1>Welcome to AcmeBank online banking</h1>` +
+			`<form action="/next.php" method="post"><input name="user"><input type="password" name="pass"></form></body></html>`))
+	}))
+	defer server.Close()
+
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := newTestCollector()
+	c.SetStore(store)
+	pack, err := c.CollectEvidence(&models.IOC{IndicatorID: "ioc-4", Type: models.IOCTypeURL, Value: server.URL + "/"})
+	if err != nil {
+		t.Fatalf("CollectEvidence returned error: %v", err)
+	}
+
+	if len(pack.Variants) != len(DefaultProfiles()) {
+		t.Fatalf("expected one variant per profile, got %d", len(pack.Variants))
+	}
+	if pack.Cloaking == nil || !pack.Cloaking.Detected || pack.Cloaking.Baseline != "desktop" || pack.Cloaking.VictimProfile != "mobile" {
+		t.Fatalf("expected cloaking with mobile victim view, got %+v", pack.Cloaking)
+	}
+	if len(pack.Cloaking.Reasons) != 1 || !strings.Contains(pack.Cloaking.Reasons[0], "password form only for mobile") {
+		t.Errorf("unexpected cloaking reasons: %v", pack.Cloaking.Reasons)
+	}
+
+	// O pack mostra a visão da vítima, não a página "limpa" servida ao desktop
+	if pack.HTTP.Title != "AcmeBank - Sign in" || pack.Content == nil || !pack.Content.HasPasswordForm {
+		t.Errorf("expected pack to carry the victim view, got title=%q content=%+v", pack.HTTP.Title, pack.Content)
+	}
+	if pack.HARPage != pack.Variants[1].PageRef || len(pack.HTTP.Chain) != 1 {
+		t.Errorf("expected HAR page and chain of the mobile variant, got page=%s chain=%v", pack.HARPage, pack.HTTP.Chain)
+	}
+	if pack.Variants[0].BodySHA256 == pack.Variants[1].BodySHA256 {
+		t.Error("expected different body hashes for desktop and mobile variants")
+	}
+}
+
+func TestParseProfiles(t *testing.T) {
+	profiles, err := LoadProfiles("../../configs/evidence/profiles.yaml")
+	if err != nil {
+		t.Fatalf("repository profiles should parse: %v", err)
+	}
+	if len(profiles) != len(DefaultProfiles()) || profiles[0].Name != DefaultProfiles()[0].Name {
+		t.Errorf("profiles.yaml and DefaultProfiles differ: %+v", profiles)
+	}
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"empty", "profiles: []\n", "no profiles"},
+		{"missing name", "profiles:\n  - user_agent: x\n", "no name"},
+		{"duplicate", "profiles:\n  - {name: a, user_agent: x}\n  - {name: a, user_agent: y}\n", "duplicate"},
+		{"missing user agent", "profiles:\n  - name: a\n", "no user_agent"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseProfiles([]byte(tt.data)); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCollectorCollectEvidence_UnreachableHost(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	c := newTestCollector()
+	c.SetStore(store)
+
+	pack, err := c.CollectEvidence(&models.IOC{IndicatorID: "ioc-2", Type: models.IOCTypeURL, Value: url})
+	if err != nil {
+		t.Fatalf("network failures should not fail collection: %v", err)
+	}
+	if pack.HTTP.Status != 0 || pack.HAR == "" {
+		t.Fatalf("expected HAR with failed entry, got status=%d har=%q", pack.HTTP.Status, pack.HAR)
+	}
+
+	data, _ := os.ReadFile(pack.HAR)
+	var archive har.HAR
+	if err := json.Unmarshal(data, &archive); err != nil {
+		t.Fatal(err)
+	}
+	if len(archive.Log.Entries) != len(DefaultProfiles()) {
+		t.Fatalf("expected one failed entry per profile, got %d", len(archive.Log.Entries))
+	}
+	for _, entry := range archive.Log.Entries {
+		if entry.Error == "" {
+			t.Errorf("expected failed entry with error, got %+v", entry)
+		}
+	}
+	if pack.Cloaking == nil || pack.Cloaking.Detected {
+		t.Errorf("expected no cloaking when every profile fails, got %+v", pack.Cloaking)
+	}
+}
+
+func TestCollectorCollectEvidence_RefusesInternalAddresses(t *testing.T) {
+	var internalHits atomic.Int32
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		internalHits.Add(1)
+		_, _ = w.Write([]byte("instance metadata"))
+	}))
+	defer internal.Close()
+
+	// O servidor "público" redireciona para o interno e para um esquema local
+	public := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/internal":
+			http.Redirect(w, r, internal.URL+"/latest/meta-data/", http.StatusFound)
+		case "/file":
+			http.Redirect(w, r, "file:///etc/passwd", http.StatusFound)
+		}
+	}))
+	defer public.Close()
+
+	c := NewCollector()
+	c.SetProfiles(DefaultProfiles()[:1])
+	c.dialer.Control = func(network, address string, raw syscall.RawConn) error {
+		if address == public.Listener.Addr().String() {
+			return nil
+		}
+		return indicator.DialControl(network, address, raw)
+	}
+
+	tests := []struct {
+		name    string
+		url     string
+		wantErr string
+	}{
+		{name: "direct", url: internal.URL + "/", wantErr: "refusing to connect"},
+		{name: "redirect to internal host", url: public.URL + "/internal", wantErr: "refusing to connect"},
+		{name: "redirect to file url", url: public.URL + "/file", wantErr: "refusing redirect to file URL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pack, err := c.CollectEvidence(&models.IOC{IndicatorID: "ioc-ssrf", Type: models.IOCTypeURL, Value: tt.url})
+			if err != nil {
+				t.Fatalf("CollectEvidence returned error: %v", err)
+			}
+			if len(pack.Variants) != 1 || !strings.Contains(pack.Variants[0].Error, tt.wantErr) {
+				t.Fatalf("Expected fetch error %q, got %+v", tt.wantErr, pack.Variants)
+			}
+			if pack.HTTP.Body != "" || pack.Content != nil {
+				t.Errorf("Expected no internal content in the evidence pack, got %+v", pack.HTTP)
+			}
+		})
+	}
+	if hits := internalHits.Load(); hits != 0 {
+		t.Errorf("Internal server was contacted %d time(s)", hits)
+	}
+}
+
+func TestStore_RejectsPathTraversal(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"", "..", "../escape", "a/b"} {
+		if _, err := store.Save("ev-1", name, []byte("x")); err == nil {
+			t.Errorf("expected error for name %q", name)
+		}
+	}
+	if _, err := store.Save("../ev", "capture.har", []byte("x")); err == nil {
+		t.Error("expected error for evidence ID with path separator")
+	}
+}
+
+func TestFetchURL(t *testing.T) {
+	tests := []struct {
+		ioc      models.IOC
+		expected string
+	}{
+		{models.IOC{Type: models.IOCTypeURL, Value: "https://evil.example/login"}, "https://evil.example/login"},
+		{models.IOC{Type: models.IOCTypeURL, Value: "evil.example/login"}, "http://evil.example/login"},
+		{models.IOC{Type: models.IOCTypeDomain, Value: "evil.example"}, "http://evil.example/"},
+		{models.IOC{Type: models.IOCTypeURL, Value: "hxxps://evil[.]example/login"}, "https://evil.example/login"},
+		{models.IOC{Type: models.IOCTypeDomain, Value: "evil(.)example"}, "http://evil.example/"},
+		{models.IOC{Type: models.IOCTypeIP, Value: "203.0.113.10"}, ""},
+		{models.IOC{Type: models.IOCTypeHash, Value: "abc"}, ""},
+	}
+
+	for _, tt := range tests {
+		if result := fetchURL(&tt.ioc); result != tt.expected {
+			t.Errorf("fetchURL(%s) = %q, want %q", tt.ioc.Value, result, tt.expected)
+		}
+	}
 }
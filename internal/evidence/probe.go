@@ -0,0 +1,191 @@
+package evidence
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+const (
+	defaultProbeTimeout  = 5 * time.Second
+	defaultBannerTimeout = 2 * time.Second
+	maxBannerBytes       = 512
+	maxHeadBytes         = 8 << 10
+)
+
+// DefaultPorts são as portas sondadas em IOCs de IP: serviços que se identificam ao conectar
+// (FTP, SSH, SMTP) e HTTP(S), incluindo as portas alternativas comuns em painéis de C2
+var DefaultPorts = []int{21, 22, 25, 80, 443, 8080, 8443}
+
+// probeServices sonda as portas do IP em paralelo e retorna as abertas, em ordem de porta
+func (c *Collector) probeServices(host string) []models.ServiceInfo {
+	results := make([]*models.ServiceInfo, len(c.ports))
+	var wg sync.WaitGroup
+	for i, port := range c.ports {
+		wg.Add(1)
+		go func(i, port int) {
+			defer wg.Done()
+			results[i] = c.probePort(host, port)
+		}(i, port)
+	}
+	wg.Wait()
+
+	var services []models.ServiceInfo
+	for _, service := range results {
+		if service != nil {
+			services = append(services, *service)
+		}
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Port < services[j].Port })
+	return services
+}
+
+// probePort identifica o serviço da porta pelo banner, por TLS ou por um HEAD HTTP;
+// retorna nil se a porta não aceita conexão
+func (c *Collector) probePort(host string, port int) *models.ServiceInfo {
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+	conn, err := c.dialProbe(address)
+	if err != nil {
+		return nil
+	}
+	service := &models.ServiceInfo{Port: port}
+
+	// Serviços que falam primeiro se identificam sem requisição
+	banner := readBanner(conn, c.bannerTimeout)
+	_ = conn.Close()
+	if banner != "" {
+		service.Banner = banner
+		service.Protocol = bannerProtocol(banner, port)
+		return service
+	}
+
+	if info, banner, ok := c.probeTLS(address, host); ok {
+		service.TLS = info
+		service.Protocol = "tls"
+		if banner != "" {
+			service.Protocol = "https"
+			service.Banner = banner
+		}
+		return service
+	}
+
+	if conn, err := c.dialProbe(address); err == nil {
+		if banner := c.headRequest(conn, host); banner != "" {
+			service.Protocol = "http"
+			service.Banner = banner
+		}
+		_ = conn.Close()
+	}
+	return service
+}
+
+// dialProbe conecta na porta sondada com o timeout de sondagem e a mesma restrição de endereços da coleta
+func (c *Collector) dialProbe(address string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: c.probeTimeout, Control: c.dialer.Control}
+	return dialer.Dial("tcp", address)
+}
+
+// probeTLS tenta o handshake TLS e, se aceito, um HEAD HTTP dentro da sessão
+func (c *Collector) probeTLS(address, host string) (*models.TLSInfo, string, bool) {
+	config := &tls.Config{InsecureSkipVerify: true} // #nosec G402 -- coleta de evidência, não confiança
+	conn, err := c.dialProbe(address)
+	if err != nil {
+		return nil, "", false
+	}
+	defer func() { _ = conn.Close() }()
+
+	session := tls.Client(conn, config)
+	_ = session.SetDeadline(time.Now().Add(c.probeTimeout))
+	if err := session.Handshake(); err != nil {
+		return nil, "", false
+	}
+
+	var info *models.TLSInfo
+	if certificates := session.ConnectionState().PeerCertificates; len(certificates) > 0 {
+		info = certificateInfo(certificates[0])
+	}
+	return info, c.headRequest(session, host), true
+}
+
+// headRequest envia um HEAD / e resume a resposta em "HTTP/1.1 200 OK (Server: x)"
+func (c *Collector) headRequest(conn net.Conn, host string) string {
+	_ = conn.SetDeadline(time.Now().Add(c.probeTimeout))
+	request := fmt.Sprintf("HEAD / HTTP/1.0\r\nHost: %s\r\nUser-Agent: %s\r\n\r\n", host, defaultUserAgent)
+	if _, err := io.WriteString(conn, request); err != nil {
+		return ""
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(io.LimitReader(conn, maxHeadBytes)), nil)
+	if err != nil {
+		return ""
+	}
+	_ = resp.Body.Close()
+
+	banner := resp.Proto + " " + resp.Status
+	if server := resp.Header.Get("Server"); server != "" {
+		banner += " (Server: " + server + ")"
+	}
+	return cleanBanner([]byte(banner))
+}
+
+// readBanner lê o que o serviço envia ao conectar, até o tempo limite
+func readBanner(conn net.Conn, timeout time.Duration) string {
+	_ = conn.SetReadDeadline(time.Now().Add(timeout))
+	buf := make([]byte, maxBannerBytes)
+	n, _ := conn.Read(buf)
+	return cleanBanner(buf[:n])
+}
+
+// cleanBanner mantém a primeira linha, apenas com ASCII imprimível
+func cleanBanner(data []byte) string {
+	line, _, _ := strings.Cut(string(data), "\n")
+	return strings.TrimSpace(strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e {
+			return -1
+		}
+		return r
+	}, line))
+}
+
+// bannerProtocol deduz o protocolo pelo banner (e pela porta, quando o banner é ambíguo)
+func bannerProtocol(banner string, port int) string {
+	upper := strings.ToUpper(banner)
+	switch {
+	case strings.HasPrefix(upper, "SSH-"):
+		return "ssh"
+	case strings.HasPrefix(upper, "HTTP/"):
+		return "http"
+	case strings.HasPrefix(upper, "+OK"):
+		return "pop3"
+	case strings.HasPrefix(upper, "* OK"):
+		return "imap"
+	case strings.HasPrefix(upper, "220"):
+		if strings.Contains(upper, "SMTP") || strings.Contains(upper, "MAIL") || port == 25 || port == 587 {
+			return "smtp"
+		}
+		return "ftp"
+	default:
+		return ""
+	}
+}
+
+// serviceURL retorna a URL do primeiro serviço HTTP(S) sondado, para a captura completa
+func serviceURL(host string, services []models.ServiceInfo) string {
+	for _, service := range services {
+		switch service.Protocol {
+		case "http", "https":
+			return service.Protocol + "://" + net.JoinHostPort(host, strconv.Itoa(service.Port)) + "/"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,131 @@
+package evidence
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// listenBanner abre um serviço TCP local que envia o banner ao conectar
+func listenBanner(t *testing.T, banner string) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = conn.Write([]byte(banner))
+			_ = conn.Close()
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+// serverPort retorna a porta de um httptest.Server
+func serverPort(t *testing.T, server *httptest.Server) int {
+	t.Helper()
+	parsed, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, _ := strconv.Atoi(parsed.Port())
+	return port
+}
+
+func TestCollectorCollectEvidence_ProbesIPServices(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Server", "nginx")
+		_, _ = w.Write([]byte("<html><title>Panel</title></html>"))
+	})
+	httpServer := httptest.NewServer(handler)
+	defer httpServer.Close()
+	tlsServer := httptest.NewTLSServer(handler)
+	defer tlsServer.Close()
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedPort := closed.Addr().(*net.TCPAddr).Port
+	_ = closed.Close()
+
+	sshPort := listenBanner(t, "SSH-2.0-OpenSSH_8.9p1 Ubuntu-3\r\n")
+	ftpPort := listenBanner(t, "220 (vsFTPd 3.0.5)\r\n")
+	ports := []int{sshPort, ftpPort, serverPort(t, httpServer), serverPort(t, tlsServer), closedPort}
+
+	c := newTestCollector()
+	c.SetPorts(ports)
+	c.bannerTimeout = 200 * time.Millisecond
+
+	pack, err := c.CollectEvidence(&models.IOC{IndicatorID: "ioc-ip", Type: models.IOCTypeIP, Value: "127.0.0[.]1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[int]struct {
+		protocol string
+		banner   string
+		tls      bool
+	}{
+		sshPort:                   {"ssh", "SSH-2.0-OpenSSH_8.9p1 Ubuntu-3", false},
+		ftpPort:                   {"ftp", "220 (vsFTPd 3.0.5)", false},
+		serverPort(t, httpServer): {"http", "HTTP/1.0 200 OK (Server: nginx)", false},
+		serverPort(t, tlsServer):  {"https", "HTTP/1.0 200 OK (Server: nginx)", true},
+	}
+	if len(pack.Services) != len(want) {
+		t.Fatalf("Expected %d open ports (closed port skipped), got %+v", len(want), pack.Services)
+	}
+	for i, service := range pack.Services {
+		if i > 0 && service.Port < pack.Services[i-1].Port {
+			t.Errorf("Expected services ordered by port, got %+v", pack.Services)
+		}
+		expected, ok := want[service.Port]
+		if !ok {
+			t.Errorf("Unexpected service %+v", service)
+			continue
+		}
+		if service.Protocol != expected.protocol || service.Banner != expected.banner || (service.TLS != nil) != expected.tls {
+			t.Errorf("Port %d: got %+v, want %+v", service.Port, service, expected)
+		}
+	}
+
+	if pack.TLS == nil || len(pack.TLS.SAN) == 0 {
+		t.Errorf("Expected certificate of the TLS service in the pack, got %+v", pack.TLS)
+	}
+	if pack.HTTP.Status != http.StatusOK || pack.HTTP.Title != "Panel" {
+		t.Errorf("Expected HTTP capture of the first web service, got %+v", pack.HTTP)
+	}
+}
+
+func TestBannerProtocol(t *testing.T) {
+	tests := []struct {
+		banner string
+		port   int
+		want   string
+	}{
+		{"SSH-2.0-dropbear", 2222, "ssh"},
+		{"220 mail.example ESMTP Postfix", 2525, "smtp"},
+		{"220 Welcome", 25, "smtp"},
+		{"220 Welcome", 21, "ftp"},
+		{"+OK Dovecot ready.", 110, "pop3"},
+		{"* OK IMAP4rev1", 143, "imap"},
+		{"\x00\x01binary", 4444, ""},
+	}
+	for _, tt := range tests {
+		if got := bannerProtocol(tt.banner, tt.port); got != tt.want {
+			t.Errorf("bannerProtocol(%q, %d) = %q, want %q", tt.banner, tt.port, got, tt.want)
+		}
+	}
+}
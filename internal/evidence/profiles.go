@@ -0,0 +1,86 @@
+package evidence
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Profile define como o collector se apresenta ao site (navegador, dispositivo, origem, idioma)
+type Profile struct {
+	Name           string            `yaml:"name"`
+	UserAgent      string            `yaml:"user_agent"`
+	Referer        string            `yaml:"referer,omitempty"`
+	AcceptLanguage string            `yaml:"accept_language,omitempty"`
+	Headers        map[string]string `yaml:"headers,omitempty"`
+}
+
+// DefaultProfiles retorna os perfis padrão (espelha configs/evidence/profiles.yaml)
+func DefaultProfiles() []Profile {
+	return []Profile{
+		{
+			Name:           "desktop",
+			UserAgent:      defaultUserAgent,
+			AcceptLanguage: "en-US,en;q=0.9",
+		},
+		{
+			Name:           "mobile",
+			UserAgent:      "Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1",
+			AcceptLanguage: "en-US,en;q=0.9",
+		},
+		{
+			Name:           "email_click",
+			UserAgent:      defaultUserAgent,
+			Referer:        "https://mail.google.com/",
+			AcceptLanguage: "en-US,en;q=0.9",
+		},
+		{
+			Name:           "pt_br",
+			UserAgent:      "Mozilla/5.0 (Linux; Android 14; SM-S918B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Mobile Safari/537.36",
+			AcceptLanguage: "pt-BR,pt;q=0.9",
+		},
+	}
+}
+
+// profilesFile é o formato de configs/evidence/profiles.yaml
+type profilesFile struct {
+	Profiles []Profile `yaml:"profiles"`
+}
+
+// LoadProfiles carrega os perfis de coleta de um arquivo YAML
+func LoadProfiles(path string) ([]Profile, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- caminho vem da configuração do operador
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profiles: %w", err)
+	}
+	return ParseProfiles(data)
+}
+
+// ParseProfiles interpreta e valida a lista de perfis
+func ParseProfiles(data []byte) ([]Profile, error) {
+	var file profilesFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse profiles: %w", err)
+	}
+	if len(file.Profiles) == 0 {
+		return nil, errors.New("no profiles defined")
+	}
+
+	seen := make(map[string]bool, len(file.Profiles))
+	for i, profile := range file.Profiles {
+		if profile.Name == "" {
+			return nil, fmt.Errorf("profile %d has no name", i+1)
+		}
+		if seen[profile.Name] {
+			return nil, fmt.Errorf("duplicate profile %q", profile.Name)
+		}
+		if profile.UserAgent == "" {
+			return nil, fmt.Errorf("profile %q has no user_agent", profile.Name)
+		}
+		seen[profile.Name] = true
+	}
+
+	return file.Profiles, nil
+}
@@ -0,0 +1,139 @@
+package evidence
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+
+	"github.com/cti-team/takedown/internal/redact"
+	"github.com/cti-team/takedown/pkg/defang"
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// reportTemplate é o resumo legível do bundle; URLs e hosts aparecem sempre defanged
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"defang": defang.Defang,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Evidence {{.Manifest.EvidenceID}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f2f2f2; }
+code { font-size: 0.9em; }
+</style>
+</head>
+<body>
+<h1>Evidence report</h1>
+<table>
+<tr><th>Indicator</th><td><code>{{.Pack.Defanged}}</code></td></tr>
+{{- if .Request}}
+<tr><th>Case</th><td>{{.Request.CaseID}}</td></tr>
+<tr><th>Requested action</th><td>{{.Request.RequestedAction}}</td></tr>
+{{- end}}
+<tr><th>Evidence ID</th><td>{{.Manifest.EvidenceID}}</td></tr>
+<tr><th>Collected at</th><td>{{.Manifest.CollectedAt.Format "2006-01-02 15:04:05 UTC"}}</td></tr>
+<tr><th>Bundle created at</th><td>{{.Manifest.CreatedAt.Format "2006-01-02 15:04:05 UTC"}}</td></tr>
+<tr><th>Risk</th><td>{{.Pack.Risk.Score}}/100 {{.Pack.Risk.Category}} {{.Pack.Risk.Rationale}}</td></tr>
+<tr><th>Redaction</th><td>{{.Audit.Summary}} (details in redaction.json)</td></tr>
+</table>
+
+<h2>HTTP</h2>
+<table>
+<tr><th>Status</th><td>{{.Pack.HTTP.Status}}</td></tr>
+{{- if .Pack.HTTP.Title}}
+<tr><th>Title</th><td>{{.Pack.HTTP.Title}}</td></tr>
+{{- end}}
+{{- range .Pack.HTTP.Chain}}
+<tr><th>Redirect</th><td><code>{{defang .}}</code></td></tr>
+{{- end}}
+</table>
+
+<h2>DNS</h2>
+<table>
+{{- range .Pack.DNS.A}}<tr><th>A</th><td>{{defang .}}</td></tr>{{end}}
+{{- range .Pack.DNS.AAAA}}<tr><th>AAAA</th><td>{{defang .}}</td></tr>{{end}}
+{{- range .Pack.DNS.CNAME}}<tr><th>CNAME</th><td>{{defang .}}</td></tr>{{end}}
+{{- range .Pack.DNS.NS}}<tr><th>NS</th><td>{{defang .}}</td></tr>{{end}}
+{{- range .Pack.DNS.MX}}<tr><th>MX</th><td>{{defang .}}</td></tr>{{end}}
+</table>
+{{- with .Pack.TLS}}
+
+<h2>TLS</h2>
+<table>
+<tr><th>Subject CN</th><td>{{defang .CN}}</td></tr>
+<tr><th>Issuer</th><td>{{.Issuer}}</td></tr>
+<tr><th>Valid</th><td>{{.NotBefore.Format "2006-01-02"}} to {{.NotAfter.Format "2006-01-02"}}</td></tr>
+{{- range .SAN}}
+<tr><th>SAN</th><td>{{defang .}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+
+{{- with .Pack.Services}}
+
+<h2>Services</h2>
+<table>
+<tr><th>Port</th><th>Protocol</th><th>Banner</th><th>TLS CN</th></tr>
+{{- range .}}
+<tr><td>{{.Port}}</td><td>{{.Protocol}}</td><td><code>{{.Banner}}</code></td><td>{{with .TLS}}{{defang .CN}}{{end}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- with .Pack.File}}
+
+<h2>Sample</h2>
+<table>
+{{- if .URL}}
+<tr><th>URL</th><td><code>{{defang .URL}}</code></td></tr>
+{{- end}}
+<tr><th>Size</th><td>{{.Size}} bytes{{if .Truncated}} (truncated){{end}}</td></tr>
+{{- if .FileType}}
+<tr><th>Type</th><td>{{.FileType}} ({{.ContentType}})</td></tr>
+{{- end}}
+{{- if .FirstBytes}}
+<tr><th>First bytes</th><td><code>{{.FirstBytes}}</code></td></tr>
+{{- end}}
+{{- range $family, $hash := .Hashes}}
+<tr><th>{{$family}}</th><td><code>{{$hash}}</code></td></tr>
+{{- end}}
+{{- range .Expected}}
+<tr><th>Reported</th><td><code>{{.}}</code></td></tr>
+{{- end}}
+</table>
+<p>{{.Summary}}</p>
+{{- end}}
+
+<h2>Artifacts</h2>
+<table>
+<tr><th>File</th><th>Kind</th><th>Size</th><th>SHA-256</th></tr>
+{{- range .Manifest.Files}}
+<tr><td>{{.Name}}</td><td>{{.Kind}}</td><td>{{.Size}}</td><td><code>{{.SHA256}}</code></td></tr>
+{{- end}}
+</table>
+
+<p>The file list above is covered by manifest.json, signed with Ed25519 key <code>{{.Manifest.KeyID}}</code>
+(detached signature in manifest.sig). Verify with:</p>
+<pre>takedown evidence verify -key &lt;public-key.pem&gt; {{.Name}}</pre>
+</body>
+</html>
+`))
+
+// renderReport monta o report.html a partir do pack e dos arquivos já descritos no manifest
+func renderReport(pack *models.EvidencePack, request *models.TakedownRequest, manifest *Manifest, audit *redact.Audit) ([]byte, error) {
+	var buffer bytes.Buffer
+	err := reportTemplate.Execute(&buffer, struct {
+		Pack     *models.EvidencePack
+		Request  *models.TakedownRequest
+		Manifest *Manifest
+		Audit    *redact.Audit
+		Name     string
+	}{pack, request, manifest, audit, BundleName(pack)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render report: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
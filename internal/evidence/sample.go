@@ -0,0 +1,90 @@
+package evidence
+
+import (
+	"bytes"
+	"crypto/md5"  // #nosec G501 -- identificação de amostras, não segurança
+	"crypto/sha1" // #nosec G505 -- identificação de amostras, não segurança
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/cti-team/takedown/pkg/indicator"
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+const (
+	// defaultSampleLimit é o tamanho máximo lido de URLs com amostras esperadas
+	defaultSampleLimit = 32 << 20
+	firstBytesLength   = 16
+)
+
+// fileTypes identifica os formatos comuns de amostras pelos magic bytes
+var fileTypes = []struct {
+	magic []byte
+	name  string
+}{
+	{[]byte("MZ"), "PE"},
+	{[]byte("\x7fELF"), "ELF"},
+	{[]byte("PK\x03\x04"), "ZIP"},
+	{[]byte("Rar!\x1a\x07"), "RAR"},
+	{[]byte("7z\xbc\xaf\x27\x1c"), "7Z"},
+	{[]byte("\x1f\x8b"), "GZIP"},
+	{[]byte("%PDF-"), "PDF"},
+	{[]byte("\xd0\xcf\x11\xe0\xa1\xb1\x1a\xe1"), "OLE"},
+	{[]byte("\xcf\xfa\xed\xfe"), "Mach-O"},
+	{[]byte("\xca\xfe\xba\xbe"), "Mach-O universal"},
+	{[]byte("dex\n"), "DEX"},
+	{[]byte("#!"), "script"},
+}
+
+// detectFileType retorna o formato pelos magic bytes (vazio para HTML, texto e desconhecidos)
+func detectFileType(body []byte) string {
+	for _, fileType := range fileTypes {
+		if bytes.HasPrefix(body, fileType.magic) {
+			return fileType.name
+		}
+	}
+	return ""
+}
+
+// sampleFile registra tamanho, tipo, primeiros bytes e hashes do arquivo servido, conferindo
+// os hashes esperados do IOC; sem corpo, o pack guarda ao menos os hashes informados
+func sampleFile(url string, body []byte, expected []string, truncated bool) *models.FileInfo {
+	file := &models.FileInfo{
+		URL:      url,
+		Hashes:   map[string]string{},
+		Expected: expected,
+	}
+	if len(body) == 0 {
+		return file
+	}
+
+	md5Sum := md5.Sum(body)   // #nosec G401 -- identificação de amostras
+	sha1Sum := sha1.Sum(body) // #nosec G401 -- identificação de amostras
+	sha256Sum := sha256.Sum256(body)
+	sha512Sum := sha512.Sum512(body)
+	file.Hashes[indicator.HashMD5] = hex.EncodeToString(md5Sum[:])
+	file.Hashes[indicator.HashSHA1] = hex.EncodeToString(sha1Sum[:])
+	file.Hashes[indicator.HashSHA256] = hex.EncodeToString(sha256Sum[:])
+	file.Hashes[indicator.HashSHA512] = hex.EncodeToString(sha512Sum[:])
+
+	file.Size = len(body)
+	file.Truncated = truncated
+	file.ContentType = http.DetectContentType(body)
+	file.FileType = detectFileType(body)
+	first := body
+	if len(first) > firstBytesLength {
+		first = first[:firstBytesLength]
+	}
+	file.FirstBytes = hex.EncodeToString(first)
+
+	for _, hash := range expected {
+		validated, err := indicator.Validate(hash, models.IOCTypeHash)
+		if err == nil && !truncated && strings.EqualFold(file.Hashes[validated.HashFamily], validated.Value) {
+			file.Matched = true
+		}
+	}
+	return file
+}
@@ -0,0 +1,100 @@
+package evidence
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+func TestCollectorCollectEvidence_RecordsSample(t *testing.T) {
+	payload := append([]byte("\x7fELF\x02\x01\x01\x00"), []byte(strings.Repeat("\x90", 2048))...)
+	sum := sha256.Sum256(payload)
+	sampleHash := hex.EncodeToString(sum[:])
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/download" {
+			http.Redirect(w, r, "/bins/x86", http.StatusFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write(payload)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		hashes      []string
+		bodyLimit   int64
+		wantMatched bool
+		wantTrunc   bool
+	}{
+		{name: "matching sha256", hashes: []string{strings.ToUpper(sampleHash)}, wantMatched: true},
+		{name: "other sample", hashes: []string{strings.Repeat("ab", 16)}},
+		{name: "binary without reported hash"},
+		{name: "truncated download", hashes: []string{sampleHash}, bodyLimit: 1024, wantTrunc: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestCollector()
+			c.SetProfiles(DefaultProfiles()[:1])
+			if tt.bodyLimit > 0 {
+				c.SetBodyLimit(tt.bodyLimit)
+				c.SetSampleLimit(tt.bodyLimit)
+			}
+
+			pack, err := c.CollectEvidence(&models.IOC{IndicatorID: "ioc-s", Type: models.IOCTypeURL, Value: server.URL + "/download", Hashes: tt.hashes})
+			if err != nil {
+				t.Fatal(err)
+			}
+			file := pack.File
+			if file == nil {
+				t.Fatal("Expected file metadata in the evidence pack")
+			}
+			if file.URL != server.URL+"/bins/x86" || file.FileType != "ELF" || file.FirstBytes != "7f454c46020101009090909090909090" {
+				t.Errorf("Unexpected file metadata: %+v", file)
+			}
+			if file.Matched != tt.wantMatched || file.Truncated != tt.wantTrunc {
+				t.Errorf("Expected matched=%v truncated=%v, got %+v", tt.wantMatched, tt.wantTrunc, file)
+			}
+			if !tt.wantTrunc && (file.Size != len(payload) || file.Hashes["sha256"] != sampleHash) {
+				t.Errorf("Expected full sample hashes, got size %d sha256 %s", file.Size, file.Hashes["sha256"])
+			}
+			if len(file.Expected) != len(tt.hashes) {
+				t.Errorf("Expected reported hashes in the pack, got %v", file.Expected)
+			}
+		})
+	}
+}
+
+func TestCollectorCollectEvidence_NoSampleForPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("<html><title>Login</title></html>"))
+	}))
+	defer server.Close()
+
+	c := newTestCollector()
+	c.SetProfiles(DefaultProfiles()[:1])
+	pack, err := c.CollectEvidence(&models.IOC{IndicatorID: "ioc-p", Type: models.IOCTypeURL, Value: server.URL + "/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pack.File != nil {
+		t.Errorf("Expected no file metadata for an HTML page, got %+v", pack.File)
+	}
+
+	// Sem corpo (servidor fora do ar), o hash informado ainda vai no pack
+	url := server.URL + "/"
+	server.Close()
+	pack, err = c.CollectEvidence(&models.IOC{IndicatorID: "ioc-p", Type: models.IOCTypeURL, Value: url, Hashes: []string{strings.Repeat("ab", 32)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pack.File == nil || pack.File.Size != 0 || pack.File.Summary() != "reported sample "+strings.Repeat("ab", 32)+" (file not retrieved)" {
+		t.Errorf("Expected reported hash without file, got %+v", pack.File)
+	}
+}
@@ -0,0 +1,70 @@
+package evidence
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Store grava artefatos de evidência em disco, um diretório por evidence ID
+type Store struct {
+	root string
+}
+
+// StoredFile descreve um artefato gravado e seu hash
+type StoredFile struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// NewStore cria (se necessário) o diretório raiz do evidence store
+func NewStore(root string) (*Store, error) {
+	if err := os.MkdirAll(root, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create evidence store: %w", err)
+	}
+	return &Store{root: root}, nil
+}
+
+// Root retorna o diretório raiz do store
+func (s *Store) Root() string {
+	return s.root
+}
+
+// Save grava um artefato da evidência e calcula seu SHA-256
+func (s *Store) Save(evidenceID, name string, data []byte) (StoredFile, error) {
+	if err := validateName(evidenceID); err != nil {
+		return StoredFile{}, err
+	}
+	if err := validateName(name); err != nil {
+		return StoredFile{}, err
+	}
+
+	dir := filepath.Join(s.root, evidenceID)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return StoredFile{}, fmt.Errorf("failed to create evidence directory: %w", err)
+	}
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return StoredFile{}, fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	sum := sha256.Sum256(data)
+	return StoredFile{
+		Path:   path,
+		SHA256: hex.EncodeToString(sum[:]),
+		Size:   int64(len(data)),
+	}, nil
+}
+
+// validateName impede que IDs/nomes escapem do diretório do store
+func validateName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid evidence file name %q", name)
+	}
+	return nil
+}
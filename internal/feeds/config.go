@@ -0,0 +1,95 @@
+// Package feeds lê feeds públicos de phishing (listas de URLs no estilo OpenPhish,
+// JSON no estilo PhishTank e CSV no estilo URLhaus) e entrega as URLs novas à
+// pipeline com a origem, as tags e a confiança configuradas para cada feed.
+package feeds
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FeedConfig descreve um feed
+type FeedConfig struct {
+	Name            string              `yaml:"name"`
+	Format          string              `yaml:"format"`                     // openphish, phishtank, urlhaus ou um formato registrado
+	URL             string              `yaml:"url"`                        // http(s) ou caminho de arquivo local
+	Source          string              `yaml:"source,omitempty"`           // IOC.Source (padrão: o formato, que casa com feed_reputation do scoring)
+	IntervalSeconds int                 `yaml:"interval_seconds,omitempty"` // padrão: interval_seconds global
+	Confidence      int                 `yaml:"confidence"`                 // confiança da fonte (1-100), levada à triagem e ao score
+	Tags            []string            `yaml:"tags,omitempty"`             // tags de todo IOC do feed (padrão: conforme o formato)
+	TagMap          map[string][]string `yaml:"tag_map,omitempty"`          // tag/alvo informado pelo feed -> tags do IOC
+	IncludeInactive bool                `yaml:"include_inactive,omitempty"` // inclui URLs offline ou não verificadas
+}
+
+// Config define os feeds e o estado incremental
+type Config struct {
+	IntervalSeconds int          `yaml:"interval_seconds"`
+	StatePath       string       `yaml:"state_path"`     // arquivo JSON com as URLs já vistas e os validadores HTTP de cada feed
+	RetentionDays   int          `yaml:"retention_days"` // URLs fora do feed há mais tempo que isso são esquecidas
+	BrandsOnly      bool         `yaml:"brands_only"`    // só entram URLs que citam ou imitam marcas protegidas
+	Feeds           []FeedConfig `yaml:"feeds"`
+}
+
+// DefaultConfig retorna a configuração padrão, sem feeds (espelha configs/feeds/feeds.yaml)
+func DefaultConfig() Config {
+	return Config{
+		IntervalSeconds: 600,
+		StatePath:       "data/feeds-state.json",
+		RetentionDays:   30,
+		BrandsOnly:      true,
+	}
+}
+
+// LoadConfig carrega a configuração de um arquivo YAML sobre os valores padrão
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- caminho vem da configuração do operador
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read feeds config: %w", err)
+	}
+	return ParseConfig(data)
+}
+
+// ParseConfig interpreta e valida a configuração; campos ausentes mantêm o padrão
+func ParseConfig(data []byte) (Config, error) {
+	config := DefaultConfig()
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return Config{}, fmt.Errorf("failed to parse feeds config: %w", err)
+	}
+
+	if config.IntervalSeconds <= 0 {
+		return Config{}, fmt.Errorf("interval_seconds must be positive, got %d", config.IntervalSeconds)
+	}
+	if config.RetentionDays <= 0 {
+		return Config{}, fmt.Errorf("retention_days must be positive, got %d", config.RetentionDays)
+	}
+	if config.StatePath == "" {
+		return Config{}, fmt.Errorf("state_path is required")
+	}
+
+	seen := make(map[string]bool, len(config.Feeds))
+	for i, feed := range config.Feeds {
+		if feed.Name == "" {
+			return Config{}, fmt.Errorf("feed %d has no name", i+1)
+		}
+		if seen[feed.Name] {
+			return Config{}, fmt.Errorf("duplicate feed %q", feed.Name)
+		}
+		seen[feed.Name] = true
+		if _, ok := lookupReader(feed.Format); !ok {
+			return Config{}, fmt.Errorf("feed %q: unknown format %q", feed.Name, feed.Format)
+		}
+		if feed.URL == "" {
+			return Config{}, fmt.Errorf("feed %q: url is required", feed.Name)
+		}
+		if feed.Confidence < 1 || feed.Confidence > 100 {
+			return Config{}, fmt.Errorf("feed %q: confidence %d out of range 1-100", feed.Name, feed.Confidence)
+		}
+		if feed.IntervalSeconds < 0 {
+			return Config{}, fmt.Errorf("feed %q: interval_seconds cannot be negative", feed.Name)
+		}
+	}
+
+	return config, nil
+}
@@ -0,0 +1,64 @@
+package feeds
+
+import (
+	"strings"
+
+	"github.com/cti-team/takedown/internal/brand"
+	"github.com/cti-team/takedown/pkg/indicator"
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// minKeywordLength evita casar nomes curtos de marca em qualquer URL
+const minKeywordLength = 4
+
+// brandKeyword é um termo que identifica a marca na URL
+type brandKeyword struct {
+	brand   string
+	keyword string
+}
+
+// NewBrandFilter aceita só URLs que atingem marcas protegidas: já marcadas com brand:X (tag_map),
+// com host parecido com um domínio protegido (detector) ou que citam o nome ou o rótulo
+// do domínio da marca no host ou no caminho. Os domínios oficiais nunca passam.
+func NewBrandFilter(detector *brand.Detector, brands []brand.Protected) Filter {
+	official := make(map[string]bool)
+	var keywords []brandKeyword
+	for _, protected := range brands {
+		terms := []string{strings.ToLower(strings.ReplaceAll(protected.Name, " ", ""))}
+		for _, domain := range protected.Domains {
+			normalized, err := indicator.Normalize(domain)
+			if err != nil || normalized.RegistrableDomain == "" {
+				continue
+			}
+			official[normalized.RegistrableDomain] = true
+			terms = append(terms, strings.TrimSuffix(normalized.RegistrableDomain, "."+normalized.PublicSuffix))
+		}
+		for _, term := range terms {
+			if len(term) >= minKeywordLength {
+				keywords = append(keywords, brandKeyword{brand: protected.Name, keyword: term})
+			}
+		}
+	}
+
+	return func(ioc *models.IOC) bool {
+		normalized, err := indicator.Normalize(ioc.Value)
+		if err != nil || official[normalized.RegistrableDomain] {
+			return false
+		}
+		if ioc.GetBrand() != "" {
+			return true
+		}
+		if detector != nil && detector.Tag(ioc) != nil {
+			return true
+		}
+
+		value := strings.ToLower(normalized.URL)
+		for _, keyword := range keywords {
+			if strings.Contains(value, keyword.keyword) {
+				ioc.Tags = append(ioc.Tags, "brand:"+keyword.brand)
+				return true
+			}
+		}
+		return false
+	}
+}
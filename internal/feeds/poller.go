@@ -0,0 +1,323 @@
+package feeds
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cti-team/takedown/pkg/indicator"
+	"github.com/cti-team/takedown/pkg/models"
+	"github.com/google/uuid"
+)
+
+const (
+	// RefPrefix identifica o feed e o ID de origem em IOC.IntelRefs (feed:<nome>:<id>)
+	RefPrefix = "feed:"
+
+	maxFeedBytes  = 128 << 20
+	clientTimeout = 2 * time.Minute
+	minTick       = 10 * time.Second
+)
+
+// Sink recebe os IOCs novos (normalmente Machine.ProcessIOC)
+type Sink func(ioc *models.IOC) error
+
+// Filter decide se o IOC entra na pipeline; pode acrescentar tags (ex: brand:X)
+type Filter func(ioc *models.IOC) bool
+
+// feed é um feed configurado com seu leitor
+type feed struct {
+	config   FeedConfig
+	reader   Reader
+	interval time.Duration
+	due      time.Time
+}
+
+// Poller lê os feeds no intervalo de cada um e entrega as URLs ainda não vistas
+type Poller struct {
+	feeds     []*feed
+	state     *StateStore
+	client    *http.Client
+	sink      Sink
+	filter    Filter
+	retention time.Duration
+	stopChan  chan struct{}
+	now       func() time.Time
+}
+
+// NewPoller cria o poller com os feeds da configuração e o armazenamento de estado
+func NewPoller(config Config, state *StateStore) (*Poller, error) {
+	poller := &Poller{
+		state:     state,
+		client:    &http.Client{Timeout: clientTimeout},
+		retention: time.Duration(config.RetentionDays) * 24 * time.Hour,
+		stopChan:  make(chan struct{}),
+		now:       func() time.Time { return time.Now().UTC() },
+	}
+	for _, feedConfig := range config.Feeds {
+		reader, ok := lookupReader(feedConfig.Format)
+		if !ok {
+			return nil, fmt.Errorf("feed %q: unknown format %q", feedConfig.Name, feedConfig.Format)
+		}
+		interval := feedConfig.IntervalSeconds
+		if interval == 0 {
+			interval = config.IntervalSeconds
+		}
+		poller.feeds = append(poller.feeds, &feed{
+			config:   feedConfig,
+			reader:   reader,
+			interval: time.Duration(interval) * time.Second,
+		})
+	}
+	return poller, nil
+}
+
+// SetSink configura quem recebe os IOCs; sem sink, Poll só lê os feeds
+func (p *Poller) SetSink(sink Sink) {
+	p.sink = sink
+}
+
+// SetFilter configura o filtro aplicado antes do sink (ex: NewBrandFilter)
+func (p *Poller) SetFilter(filter Filter) {
+	p.filter = filter
+}
+
+// Poll lê todos os feeds, independente do intervalo
+func (p *Poller) Poll(ctx context.Context) ([]*models.IOC, error) {
+	return p.poll(ctx, func(*feed) bool { return true })
+}
+
+// pollDue lê os feeds cujo intervalo já passou
+func (p *Poller) pollDue(ctx context.Context) ([]*models.IOC, error) {
+	now := p.now()
+	return p.poll(ctx, func(f *feed) bool { return !now.Before(f.due) })
+}
+
+// poll lê os feeds selecionados; a falha de um feed não impede os demais
+func (p *Poller) poll(ctx context.Context, selected func(*feed) bool) ([]*models.IOC, error) {
+	var emitted []*models.IOC
+	var errs []error
+
+	for _, f := range p.feeds {
+		if !selected(f) {
+			continue
+		}
+		f.due = p.now().Add(f.interval)
+		iocs, err := p.pollFeed(ctx, f)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.config.Name, err))
+		}
+		emitted = append(emitted, iocs...)
+	}
+	return emitted, errors.Join(errs...)
+}
+
+// pollFeed baixa o feed, entrega as URLs novas e atualiza o estado. URLs recusadas pelo sink
+// não são marcadas como vistas e voltam no próximo ciclo.
+func (p *Poller) pollFeed(ctx context.Context, f *feed) ([]*models.IOC, error) {
+	name := f.config.Name
+	state := p.state.Get(name)
+	now := p.now()
+
+	body, etag, lastModified, err := p.fetch(ctx, f.config.URL, state.ETag, state.LastModified)
+	if err != nil {
+		return nil, err
+	}
+	state.LastPoll = now
+	if body == nil {
+		// 304: nada mudou desde o último ciclo
+		return nil, p.state.Set(name, state)
+	}
+
+	entries, err := f.reader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	var emitted []*models.IOC
+	failures := 0
+	for _, entry := range entries {
+		if entry.Inactive && !f.config.IncludeInactive {
+			continue
+		}
+		validated, err := indicator.Validate(entry.URL, models.IOCTypeURL)
+		if err != nil {
+			continue
+		}
+		key := validated.Value
+		if _, seen := state.Seen[key]; seen {
+			state.Seen[key] = now
+			continue
+		}
+
+		ioc := p.newIOC(f.config, entry, validated.Value, now)
+		if p.filter != nil && !p.filter(ioc) {
+			state.Seen[key] = now
+			continue
+		}
+		if p.sink != nil {
+			if err := p.sink(ioc); err != nil {
+				failures++
+				log.Printf("Feed %s: failed to open case for %s: %v", name, ioc.Value, err)
+				continue
+			}
+		}
+		state.Seen[key] = now
+		emitted = append(emitted, ioc)
+	}
+
+	for key, lastSeen := range state.Seen {
+		if now.Sub(lastSeen) > p.retention {
+			delete(state.Seen, key)
+		}
+	}
+	state.ETag, state.LastModified = etag, lastModified
+	if failures > 0 {
+		// Sem validadores, o próximo ciclo baixa o feed de novo e tenta as URLs recusadas
+		state.ETag, state.LastModified = "", ""
+	}
+	return emitted, p.state.Set(name, state)
+}
+
+// newIOC monta o IOC da URL com a origem, as tags e a confiança do feed
+func (p *Poller) newIOC(config FeedConfig, entry Entry, value string, now time.Time) *models.IOC {
+	ioc := &models.IOC{
+		IndicatorID: "feed-" + uuid.New().String(),
+		Type:        models.IOCTypeURL,
+		Value:       value,
+		FirstSeen:   entry.Seen,
+		Source:      config.Source,
+		Tags:        feedTags(config, entry.Tags),
+		Confidence:  config.Confidence,
+	}
+	if ioc.Source == "" {
+		ioc.Source = config.Format
+	}
+	if ioc.FirstSeen.IsZero() || ioc.FirstSeen.After(now) {
+		ioc.FirstSeen = now
+	}
+	if entry.ID != "" {
+		ioc.IntelRefs = []string{RefPrefix + config.Name + ":" + entry.ID}
+	}
+	return ioc
+}
+
+// feedTags junta as tags do feed (ou as do formato) com as tags mapeadas de tag_map
+func feedTags(config FeedConfig, native []string) []string {
+	base := config.Tags
+	if len(base) == 0 {
+		base = defaultTags[config.Format]
+	}
+
+	tags := make([]string, 0, len(base)+len(native))
+	seen := make(map[string]bool)
+	add := func(tag string) {
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	for _, tag := range base {
+		add(tag)
+	}
+	for _, value := range native {
+		for key, mapped := range config.TagMap {
+			if strings.EqualFold(key, value) {
+				for _, tag := range mapped {
+					add(tag)
+				}
+			}
+		}
+	}
+	return tags
+}
+
+// fetch lê o feed de um arquivo local ou por HTTP com If-None-Match/If-Modified-Since;
+// body nil indica 304 (sem mudanças)
+func (p *Poller) fetch(ctx context.Context, location, etag, lastModified string) ([]byte, string, string, error) {
+	if !strings.HasPrefix(location, "http://") && !strings.HasPrefix(location, "https://") {
+		data, err := os.ReadFile(strings.TrimPrefix(location, "file://")) // #nosec G304 -- caminho vem da configuração do operador
+		if err != nil {
+			return nil, "", "", fmt.Errorf("failed to read feed: %w", err)
+		}
+		return data, "", "", nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to build feed request: %w", err)
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("feed request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		return nil, etag, lastModified, nil
+	default:
+		return nil, "", "", fmt.Errorf("feed returned %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedBytes+1))
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to read feed: %w", err)
+	}
+	if len(data) > maxFeedBytes {
+		return nil, "", "", fmt.Errorf("feed larger than %d bytes", maxFeedBytes)
+	}
+	return data, resp.Header.Get("ETag"), resp.Header.Get("Last-Modified"), nil
+}
+
+// Start lê cada feed no seu intervalo até Stop
+func (p *Poller) Start() {
+	tick := time.Duration(0)
+	for _, f := range p.feeds {
+		if tick == 0 || f.interval < tick {
+			tick = f.interval
+		}
+	}
+	if tick < minTick {
+		tick = minTick
+	}
+
+	go func() {
+		ticker := time.NewTicker(tick)
+		defer ticker.Stop()
+
+		for {
+			iocs, err := p.pollDue(context.Background())
+			if err != nil {
+				log.Printf("Feed poller: %v", err)
+			}
+			if len(iocs) > 0 {
+				log.Printf("Feed poller: %d URLs ingested", len(iocs))
+			}
+
+			select {
+			case <-ticker.C:
+			case <-p.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Stop encerra o poller iniciado por Start
+func (p *Poller) Stop() {
+	close(p.stopChan)
+}
@@ -0,0 +1,224 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/cti-team/takedown/internal/brand"
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// writeFeed grava o conteúdo do feed em um arquivo local
+func writeFeed(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestPoller(t *testing.T) {
+	dir := t.TempDir()
+	config := DefaultConfig()
+	config.StatePath = filepath.Join(dir, "state", "feeds.json")
+	config.Feeds = []FeedConfig{
+		{Name: "openphish", Format: FormatOpenPhish, URL: writeFeed(t, dir, "feed.txt", openPhishFeed), Confidence: 90},
+		{
+			Name: "phishtank", Format: FormatPhishTank, URL: "file://" + writeFeed(t, dir, "online-valid.json", phishTankFeed),
+			Confidence: 70, TagMap: map[string][]string{"acmebank": {"brand:AcmeBank"}},
+		},
+		{
+			Name: "urlhaus-mirror", Format: FormatURLhaus, URL: writeFeed(t, dir, "urlhaus.csv", urlhausFeed),
+			Source: "urlhaus", Confidence: 95, Tags: []string{"malware", "c2"}, TagMap: map[string][]string{"mirai": {"botnet"}},
+		},
+	}
+
+	failing := map[string]bool{"https://acmebank-secure.com/": true}
+	var received []*models.IOC
+	sink := func(ioc *models.IOC) error {
+		if failing[ioc.Value] {
+			return errors.New("work queue is full")
+		}
+		received = append(received, ioc)
+		return nil
+	}
+	newPoller := func() *Poller {
+		state, err := LoadState(config.StatePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		poller, err := NewPoller(config, state)
+		if err != nil {
+			t.Fatal(err)
+		}
+		poller.SetSink(sink)
+		return poller
+	}
+
+	if _, err := newPoller().Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]*models.IOC{}
+	for _, ioc := range received {
+		got[ioc.Value] = ioc
+	}
+	tests := []struct {
+		value      string
+		source     string
+		confidence int
+		tags       []string
+		refs       []string
+	}{
+		{value: "https://acme-login.com/auth", source: "openphish", confidence: 90, tags: []string{"phishing"}},
+		{value: "https://acme-verify.com/login", source: "phishtank", confidence: 70, tags: []string{"phishing", "brand:AcmeBank"}, refs: []string{"feed:phishtank:8812301"}},
+		{value: "https://other.com/", source: "phishtank", confidence: 70, tags: []string{"phishing"}, refs: []string{"feed:phishtank:8812302"}},
+		{value: "http://45.67.89.7/bins/x86", source: "urlhaus", confidence: 95, tags: []string{"malware", "c2", "botnet"}, refs: []string{"feed:urlhaus-mirror:3300001"}},
+	}
+	if len(received) != len(tests) {
+		t.Fatalf("Expected %d IOCs (inactive and failed skipped), got %d", len(tests), len(received))
+	}
+	for _, tt := range tests {
+		ioc, ok := got[tt.value]
+		if !ok {
+			t.Errorf("Missing IOC %s", tt.value)
+			continue
+		}
+		if ioc.Source != tt.source || ioc.Confidence != tt.confidence || ioc.Type != models.IOCTypeURL {
+			t.Errorf("%s: got source %q confidence %d type %s", tt.value, ioc.Source, ioc.Confidence, ioc.Type)
+		}
+		if !reflect.DeepEqual(ioc.Tags, tt.tags) || !reflect.DeepEqual(ioc.IntelRefs, tt.refs) {
+			t.Errorf("%s: got tags %v refs %v, want %v %v", tt.value, ioc.Tags, ioc.IntelRefs, tt.tags, tt.refs)
+		}
+	}
+	if seen := got["https://acme-verify.com/login"].FirstSeen; !seen.Equal(time.Date(2025, 2, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected first seen from the feed, got %s", seen)
+	}
+
+	// Um novo poller (restart) só entrega a URL nova e a que o sink recusou antes
+	writeFeed(t, dir, "feed.txt", openPhishFeed+"https://acme-new.com/\n")
+	delete(failing, "https://acmebank-secure.com/")
+	received = nil
+	if _, err := newPoller().Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	var values []string
+	for _, ioc := range received {
+		values = append(values, ioc.Value)
+	}
+	sort.Strings(values)
+	if want := []string{"https://acme-new.com/", "https://acmebank-secure.com/"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("Second poll delivered %v, want %v", values, want)
+	}
+}
+
+func TestPoller_Retention(t *testing.T) {
+	dir := t.TempDir()
+	config := DefaultConfig()
+	config.RetentionDays = 1
+	config.Feeds = []FeedConfig{{Name: "openphish", Format: FormatOpenPhish, URL: writeFeed(t, dir, "feed.txt", "https://a.com/\n"), Confidence: 90}}
+
+	state, _ := LoadState("")
+	poller, err := NewPoller(config, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	poller.now = func() time.Time { return now }
+
+	poll := func() int {
+		iocs, err := poller.Poll(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(iocs)
+	}
+	if poll() != 1 {
+		t.Fatal("Expected the URL on the first poll")
+	}
+
+	// Fora do feed por mais que retention_days, a URL é esquecida e volta a ser nova
+	writeFeed(t, dir, "feed.txt", "")
+	now = now.Add(36 * time.Hour)
+	poll()
+	writeFeed(t, dir, "feed.txt", "https://a.com/\n")
+	if poll() != 1 {
+		t.Error("Expected the URL to be delivered again after retention")
+	}
+	if poll() != 0 {
+		t.Error("Expected the URL to be deduplicated while in the feed")
+	}
+}
+
+func TestPoller_ConditionalRequest(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte("https://a.com/\n"))
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.Feeds = []FeedConfig{{Name: "openphish", Format: FormatOpenPhish, URL: server.URL + "/feed.txt", Confidence: 90}}
+	state, _ := LoadState("")
+	poller, err := NewPoller(config, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []int{1, 0} {
+		iocs, err := poller.Poll(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(iocs) != want {
+			t.Errorf("Expected %d IOCs, got %d", want, len(iocs))
+		}
+	}
+	if requests != 2 || state.Get("openphish").ETag != `"v1"` {
+		t.Errorf("Expected 2 requests with the ETag kept, got %d and %+v", requests, state.Get("openphish"))
+	}
+}
+
+func TestBrandFilter(t *testing.T) {
+	brands := []brand.Protected{{Name: "AcmeBank", Domains: []string{"acmebank.com.br"}}}
+	detector := brand.NewDetector(brand.Config{Brands: brands, Threshold: 0.8, MinLabelLength: 5})
+	filter := NewBrandFilter(detector, brands)
+
+	tests := []struct {
+		name      string
+		ioc       *models.IOC
+		want      bool
+		wantBrand string
+	}{
+		{name: "lookalike host", ioc: &models.IOC{Value: "https://acmebamk.com/login"}, want: true, wantBrand: "AcmeBank"},
+		{name: "brand in path", ioc: &models.IOC{Value: "https://compromised.com/wp/acmebank/login.php"}, want: true, wantBrand: "AcmeBank"},
+		{name: "brand from tag map", ioc: &models.IOC{Value: "https://x.com/", Tags: []string{"brand:AcmeBank"}}, want: true, wantBrand: "AcmeBank"},
+		{name: "official domain", ioc: &models.IOC{Value: "https://www.acmebank.com.br/acmebank"}},
+		{name: "unrelated", ioc: &models.IOC{Value: "https://paypal-login.com/"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filter(tt.ioc); got != tt.want {
+				t.Fatalf("filter = %v, want %v", got, tt.want)
+			}
+			if brand := tt.ioc.GetBrand(); brand != tt.wantBrand {
+				t.Errorf("brand = %q, want %q", brand, tt.wantBrand)
+			}
+		})
+	}
+}
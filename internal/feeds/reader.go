@@ -0,0 +1,170 @@
+package feeds
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Formatos embutidos
+const (
+	FormatOpenPhish = "openphish" // uma URL por linha
+	FormatPhishTank = "phishtank" // array JSON do online-valid.json
+	FormatURLhaus   = "urlhaus"   // CSV do csv_recent/csv_online, com cabeçalho comentado
+)
+
+// Entry é uma URL lida do feed
+type Entry struct {
+	ID       string    // identificador no feed, se houver
+	URL      string    // como publicada (pode vir defanged)
+	Seen     time.Time // data informada pelo feed; zero se ausente
+	Tags     []string  // tags, alvo ou ameaça informados pelo feed (chaves de tag_map)
+	Inactive bool      // offline ou não verificada
+}
+
+// Reader interpreta o conteúdo de um feed
+type Reader func(r io.Reader) ([]Entry, error)
+
+var (
+	readers = map[string]Reader{
+		FormatOpenPhish: ReadOpenPhish,
+		FormatPhishTank: ReadPhishTank,
+		FormatURLhaus:   ReadURLhaus,
+	}
+	readersMutex sync.RWMutex
+)
+
+// defaultTags são as tags dos feeds sem tags configuradas
+var defaultTags = map[string][]string{
+	FormatOpenPhish: {"phishing"},
+	FormatPhishTank: {"phishing"},
+	FormatURLhaus:   {"malware"},
+}
+
+// RegisterFormat adiciona (ou substitui) um formato de feed; deve ser chamado antes de ParseConfig
+func RegisterFormat(format string, reader Reader) {
+	readersMutex.Lock()
+	defer readersMutex.Unlock()
+	readers[format] = reader
+}
+
+// lookupReader retorna o leitor do formato
+func lookupReader(format string) (Reader, bool) {
+	readersMutex.RLock()
+	defer readersMutex.RUnlock()
+	reader, ok := readers[format]
+	return reader, ok
+}
+
+// ReadOpenPhish lê uma lista de URLs, uma por linha (linhas vazias e # são ignoradas)
+func ReadOpenPhish(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entries = append(entries, Entry{URL: line})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read URL list: %w", err)
+	}
+	return entries, nil
+}
+
+// phishTankEntry é um item do online-valid.json
+type phishTankEntry struct {
+	PhishID        json.Number `json:"phish_id"`
+	URL            string      `json:"url"`
+	SubmissionTime string      `json:"submission_time"`
+	Verified       string      `json:"verified"`
+	Online         string      `json:"online"`
+	Target         string      `json:"target"`
+}
+
+// ReadPhishTank lê o array JSON do PhishTank; o alvo (exceto "Other") vira tag do feed
+func ReadPhishTank(r io.Reader) ([]Entry, error) {
+	var items []phishTankEntry
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return nil, fmt.Errorf("invalid PhishTank JSON: %w", err)
+	}
+
+	entries := make([]Entry, 0, len(items))
+	for _, item := range items {
+		entry := Entry{
+			ID:       item.PhishID.String(),
+			URL:      item.URL,
+			Inactive: strings.EqualFold(item.Verified, "no") || strings.EqualFold(item.Online, "no"),
+		}
+		if seen, err := time.Parse(time.RFC3339, item.SubmissionTime); err == nil {
+			entry.Seen = seen.UTC()
+		}
+		if item.Target != "" && !strings.EqualFold(item.Target, "Other") {
+			entry.Tags = []string{item.Target}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Colunas do CSV do URLhaus
+const (
+	urlhausID = iota
+	urlhausDateAdded
+	urlhausURL
+	urlhausStatus
+	urlhausLastOnline
+	urlhausThreat
+	urlhausTags
+	urlhausColumns = urlhausTags + 1
+)
+
+// ReadURLhaus lê o CSV do URLhaus; a ameaça e as tags viram tags do feed e URLs offline ficam inativas
+func ReadURLhaus(r io.Reader) ([]Entry, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var entries []Entry
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid URLhaus CSV: %w", err)
+		}
+		if len(record) < urlhausColumns {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("invalid URLhaus CSV: line %d has %d columns, want %d", line, len(record), urlhausColumns)
+		}
+
+		entry := Entry{
+			ID:       record[urlhausID],
+			URL:      record[urlhausURL],
+			Inactive: strings.EqualFold(record[urlhausStatus], "offline"),
+		}
+		if seen, err := time.Parse("2006-01-02 15:04:05", record[urlhausDateAdded]); err == nil {
+			entry.Seen = seen
+		}
+		if threat := strings.TrimSpace(record[urlhausThreat]); threat != "" {
+			entry.Tags = append(entry.Tags, threat)
+		}
+		for _, tag := range strings.Split(record[urlhausTags], ",") {
+			if tag = strings.TrimSpace(tag); tag != "" && tag != "None" {
+				entry.Tags = append(entry.Tags, tag)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,117 @@
+package feeds
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const openPhishFeed = `https://acme-login.com/auth
+# comentário
+
+hxxps://acmebank-secure[.]com/
+`
+
+const phishTankFeed = `[
+  {"phish_id": 8812301, "url": "https://acme-verify.com/login", "submission_time": "2025-02-01T10:00:00+00:00",
+   "verified": "yes", "online": "yes", "target": "AcmeBank"},
+  {"phish_id": 8812302, "url": "https://other.com/", "submission_time": "2025-02-01T11:00:00+00:00",
+   "verified": "yes", "online": "yes", "target": "Other"},
+  {"phish_id": 8812303, "url": "https://pending.com/", "submission_time": "bad",
+   "verified": "no", "online": "yes", "target": "AcmeBank"}
+]`
+
+const urlhausFeed = `################################################################
+# abuse.ch URLhaus Database Dump (CSV - recent URLs)           #
+################################################################
+#
+# id,dateadded,url,url_status,last_online,threat,tags,urlhaus_link,reporter
+"3300001","2025-02-01 10:00:00","http://45.67.89.7/bins/x86","online","2025-02-01 10:00:00","malware_download","elf,mirai","https://urlhaus.abuse.ch/url/3300001/","reporter1"
+"3300002","2025-02-01 11:00:00","http://files.com/invoice.zip","offline","","malware_download","None","https://urlhaus.abuse.ch/url/3300002/","reporter2"
+`
+
+func TestReaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		reader  Reader
+		input   string
+		want    []Entry
+		wantErr bool
+	}{
+		{
+			name:   "openphish",
+			reader: ReadOpenPhish,
+			input:  openPhishFeed,
+			want:   []Entry{{URL: "https://acme-login.com/auth"}, {URL: "hxxps://acmebank-secure[.]com/"}},
+		},
+		{
+			name:   "phishtank",
+			reader: ReadPhishTank,
+			input:  phishTankFeed,
+			want: []Entry{
+				{ID: "8812301", URL: "https://acme-verify.com/login", Seen: time.Date(2025, 2, 1, 10, 0, 0, 0, time.UTC), Tags: []string{"AcmeBank"}},
+				{ID: "8812302", URL: "https://other.com/", Seen: time.Date(2025, 2, 1, 11, 0, 0, 0, time.UTC)},
+				{ID: "8812303", URL: "https://pending.com/", Tags: []string{"AcmeBank"}, Inactive: true},
+			},
+		},
+		{name: "phishtank not an array", reader: ReadPhishTank, input: `{"url": "x"}`, wantErr: true},
+		{
+			name:   "urlhaus",
+			reader: ReadURLhaus,
+			input:  urlhausFeed,
+			want: []Entry{
+				{ID: "3300001", URL: "http://45.67.89.7/bins/x86", Seen: time.Date(2025, 2, 1, 10, 0, 0, 0, time.UTC), Tags: []string{"malware_download", "elf", "mirai"}},
+				{ID: "3300002", URL: "http://files.com/invoice.zip", Seen: time.Date(2025, 2, 1, 11, 0, 0, 0, time.UTC), Tags: []string{"malware_download"}, Inactive: true},
+			},
+		},
+		{name: "urlhaus missing columns", reader: ReadURLhaus, input: `"1","2025-02-01 10:00:00","http://a.com/"` + "\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := tt.reader(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(entries, tt.want) {
+				t.Errorf("entries =\n%+v\nwant\n%+v", entries, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigMatchesDefault(t *testing.T) {
+	config, err := LoadConfig("../../configs/feeds/feeds.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(config, DefaultConfig()) {
+		t.Fatalf("configs/feeds/feeds.yaml differs from DefaultConfig: %+v", config)
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	feed := "feeds:\n  - name: openphish\n    format: openphish\n    url: https://openphish.com/feed.txt\n"
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr bool
+	}{
+		{name: "minimal feed", yaml: feed + "    confidence: 90\n"},
+		{name: "missing confidence", yaml: feed, wantErr: true},
+		{name: "confidence out of range", yaml: feed + "    confidence: 150\n", wantErr: true},
+		{name: "unknown format", yaml: "feeds:\n  - name: x\n    format: rss\n    url: x.txt\n    confidence: 50\n", wantErr: true},
+		{name: "missing url", yaml: "feeds:\n  - name: x\n    format: urlhaus\n    confidence: 50\n", wantErr: true},
+		{name: "duplicate names", yaml: feed + "    confidence: 90\n" + strings.Replace(feed, "feeds:\n", "", 1) + "    confidence: 80\n", wantErr: true},
+		{name: "negative retention", yaml: "retention_days: -1\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseConfig([]byte(tt.yaml)); (err != nil) != tt.wantErr {
+				t.Errorf("ParseConfig error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
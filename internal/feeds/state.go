@@ -0,0 +1,87 @@
+package feeds
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FeedState é o estado incremental de um feed
+type FeedState struct {
+	ETag         string               `json:"etag,omitempty"`
+	LastModified string               `json:"last_modified,omitempty"`
+	LastPoll     time.Time            `json:"last_poll,omitempty"`
+	Seen         map[string]time.Time `json:"seen"` // URL canônica -> última vez vista no feed
+}
+
+// StateStore guarda o estado de cada feed em um arquivo JSON
+type StateStore struct {
+	path  string
+	feeds map[string]FeedState
+	mutex sync.Mutex
+}
+
+// LoadState lê o arquivo de estado; arquivo ausente começa vazio e caminho vazio mantém o estado só em memória
+func LoadState(path string) (*StateStore, error) {
+	store := &StateStore{path: path, feeds: make(map[string]FeedState)}
+	if path == "" {
+		return store, nil
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304 -- caminho vem da configuração do operador
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feeds state: %w", err)
+	}
+	if err := json.Unmarshal(data, &store.feeds); err != nil {
+		return nil, fmt.Errorf("failed to parse feeds state %s: %w", path, err)
+	}
+	return store, nil
+}
+
+// Get retorna uma cópia do estado do feed (vazio se nunca lido)
+func (s *StateStore) Get(feed string) FeedState {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	state := s.feeds[feed]
+	seen := make(map[string]time.Time, len(state.Seen))
+	for key, at := range state.Seen {
+		seen[key] = at
+	}
+	state.Seen = seen
+	return state
+}
+
+// Set atualiza o estado do feed e grava o arquivo (escrita atômica por rename)
+func (s *StateStore) Set(feed string, state FeedState) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.feeds[feed] = state
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(s.feeds)
+	if err != nil {
+		return fmt.Errorf("failed to encode feeds state: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o750); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write feeds state: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to replace feeds state: %w", err)
+	}
+	return nil
+}
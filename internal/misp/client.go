@@ -0,0 +1,142 @@
+package misp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxResponseBytes limita o tamanho das respostas da API
+const maxResponseBytes = 64 << 20
+
+// Client acessa a API REST do MISP
+type Client struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewClient cria um cliente para a instância MISP
+func NewClient(baseURL, apiKey string, timeout time.Duration) *Client {
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// NewClientFromConfig cria o cliente com a URL e a chave (lida de api_key_env) da configuração
+func NewClientFromConfig(config Config) (*Client, error) {
+	if config.URL == "" {
+		return nil, errors.New("misp url is not configured")
+	}
+	if _, err := url.ParseRequestURI(config.URL); err != nil {
+		return nil, fmt.Errorf("invalid misp url: %w", err)
+	}
+	apiKey := os.Getenv(config.APIKeyEnv)
+	if apiKey == "" {
+		return nil, fmt.Errorf("misp api key not set (environment variable %s)", config.APIKeyEnv)
+	}
+	return NewClient(config.URL, apiKey, time.Duration(config.TimeoutSeconds)*time.Second), nil
+}
+
+// Event busca um evento pelo ID ou UUID
+func (c *Client) Event(ctx context.Context, id string) (*Event, error) {
+	var response wrappedEvent
+	if err := c.do(ctx, http.MethodGet, "/events/view/"+url.PathEscape(id), nil, &response); err != nil {
+		return nil, err
+	}
+	if response.Event == nil {
+		return nil, fmt.Errorf("misp event %s not found", id)
+	}
+	return response.Event, nil
+}
+
+// SearchEvents busca eventos com as tags dadas alterados desde since
+func (c *Client) SearchEvents(ctx context.Context, tags []string, since time.Time) ([]Event, error) {
+	query := map[string]interface{}{
+		"returnFormat": "json",
+		"tags":         tags,
+	}
+	if !since.IsZero() {
+		query["timestamp"] = strconv.FormatInt(since.Unix(), 10)
+	}
+
+	var response struct {
+		Response []wrappedEvent `json:"response"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/events/restSearch", query, &response); err != nil {
+		return nil, err
+	}
+	events := make([]Event, 0, len(response.Response))
+	for _, item := range response.Response {
+		if item.Event != nil {
+			events = append(events, *item.Event)
+		}
+	}
+	return events, nil
+}
+
+// TagAttribute adiciona uma tag a um atributo (ou evento) pelo UUID
+func (c *Client) TagAttribute(ctx context.Context, uuid, tag string) error {
+	body := map[string]string{"uuid": uuid, "tag": tag}
+	return c.do(ctx, http.MethodPost, "/tags/attachTagToObject", body, nil)
+}
+
+// AddSighting registra um sighting (tipo 0) no atributo
+func (c *Client) AddSighting(ctx context.Context, uuid, source string) error {
+	body := map[string]string{"uuid": uuid, "type": "0", "source": source}
+	return c.do(ctx, http.MethodPost, "/sightings/add", body, nil)
+}
+
+// do executa uma chamada autenticada e decodifica a resposta JSON em out (se não for nil)
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode misp request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create misp request: %w", err)
+	}
+	req.Header.Set("Authorization", c.apiKey)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("misp request %s failed: %w", path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return fmt.Errorf("failed to read misp response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("misp %s returned status %d: %s", path, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode misp response: %w", err)
+	}
+	return nil
+}
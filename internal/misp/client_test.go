@@ -0,0 +1,111 @@
+package misp
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cti-team/takedown/internal/misp/misptest"
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+func TestClientEvent(t *testing.T) {
+	server := misptest.NewServer("secret")
+	defer server.Close()
+	server.AddEvent(testEvent)
+
+	client := NewClient(server.URL, "secret", 5*time.Second)
+	event, err := client.Event(context.Background(), "1207")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.UUID != "5f0c2b9e-8d51-4a2e-9a7f-3c1e0d4b6a11" || len(event.Attributes) != 6 || len(event.Objects) != 1 {
+		t.Errorf("unexpected event: %+v", event)
+	}
+
+	if _, err := client.Event(context.Background(), "999"); err == nil {
+		t.Error("expected error for missing event")
+	}
+	denied := NewClient(server.URL, "wrong", 5*time.Second)
+	if _, err := denied.Event(context.Background(), "1207"); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("expected authentication failure, got %v", err)
+	}
+}
+
+func TestPoller(t *testing.T) {
+	server := misptest.NewServer("secret")
+	defer server.Close()
+	server.AddEvent(testEvent)
+	server.AddEvent(`{"id": "1300", "uuid": "other", "timestamp": "1738600000", "Tag": [{"name": "tlp:green"}],
+		"Attribute": [{"uuid": "b-url", "type": "url", "value": "https://untagged.com/", "to_ids": true}]}`)
+
+	var received []string
+	poller := NewPoller(NewClient(server.URL, "secret", 5*time.Second), DefaultConfig())
+	poller.since = time.Unix(1738000000, 0)
+	poller.SetSink(func(ioc *models.IOC) error {
+		received = append(received, ioc.Value)
+		return nil
+	})
+
+	iocs, err := poller.Poll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"https://acme-login.com/auth", "acme-login.com", "45.67.89.77"}
+	if len(iocs) != 3 || !reflect.DeepEqual(received, want) {
+		t.Fatalf("unexpected IOCs from first poll: %v", received)
+	}
+
+	// A busca seguinte devolve o mesmo evento (editado); atributos já vistos não abrem casos de novo
+	poller.since = time.Unix(1738000000, 0)
+	iocs, err = poller.Poll(context.Background())
+	if err != nil || len(iocs) != 0 {
+		t.Fatalf("expected no new IOCs, got %d (%v)", len(iocs), err)
+	}
+}
+
+func TestFeedback(t *testing.T) {
+	server := misptest.NewServer("secret")
+	defer server.Close()
+
+	feedback := NewFeedback(NewClient(server.URL, "secret", 5*time.Second), DefaultConfig().Feedback)
+	refs := []string{AttributeRefPrefix + "a-url", EventRefPrefix + "5f0c2b9e", "stix:indicator--x"}
+
+	tests := []struct {
+		status        models.TakedownStatus
+		wantTags      []string
+		wantSightings int
+	}{
+		{status: models.StatusTriage, wantSightings: 0},
+		{status: models.StatusSubmitted, wantTags: []string{"takedown:submitted"}, wantSightings: 1},
+		{status: models.StatusFollowUp, wantTags: []string{"takedown:submitted"}, wantSightings: 1},
+		{status: models.StatusOutcome, wantTags: []string{"takedown:submitted", "takedown:removed"}, wantSightings: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			if err := feedback.StatusChanged(context.Background(), tt.status, refs); err != nil {
+				t.Fatal(err)
+			}
+			if got := server.Tags("a-url"); !reflect.DeepEqual(got, tt.wantTags) {
+				t.Errorf("tags = %v, want %v", got, tt.wantTags)
+			}
+			sightings := server.Sightings()
+			if len(sightings) != tt.wantSightings {
+				t.Fatalf("got %d sightings, want %d", len(sightings), tt.wantSightings)
+			}
+			if len(sightings) > 0 && (sightings[0].UUID != "a-url" || sightings[0].Source != "takedown") {
+				t.Errorf("unexpected sighting %+v", sightings[0])
+			}
+		})
+	}
+
+	if got := server.Tags("5f0c2b9e"); len(got) != 0 {
+		t.Errorf("event should not be tagged, got %v", got)
+	}
+	if err := feedback.StatusChanged(context.Background(), models.StatusSubmitted, []string{"stix:indicator--x"}); err != nil {
+		t.Errorf("cases without MISP attributes should be ignored, got %v", err)
+	}
+}
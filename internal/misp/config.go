@@ -0,0 +1,107 @@
+// Package misp importa eventos do MISP como IOCs e devolve ao MISP o andamento
+// dos casos de takedown (tags takedown:* e sightings nos atributos de origem).
+package misp
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cti-team/takedown/pkg/models"
+	"gopkg.in/yaml.v3"
+)
+
+// PollConfig define a busca periódica de eventos (restSearch)
+type PollConfig struct {
+	IntervalSeconds int      `yaml:"interval_seconds"`
+	Tags            []string `yaml:"tags"`           // só eventos com estas tags viram casos
+	LookbackHours   int      `yaml:"lookback_hours"` // janela da primeira busca
+}
+
+// FeedbackConfig define o que é escrito de volta no MISP quando um caso muda de estado
+type FeedbackConfig struct {
+	StatusTags     map[models.TakedownStatus]string `yaml:"status_tags"` // tag vazia desativa o status padrão
+	Sightings      bool                             `yaml:"sightings"`   // sighting nos atributos quando o caso é submetido
+	SightingSource string                           `yaml:"sighting_source"`
+}
+
+// Config define a instância MISP e o mapeamento dos eventos
+type Config struct {
+	URL            string         `yaml:"url"`
+	APIKeyEnv      string         `yaml:"api_key_env"` // variável de ambiente com a chave de API
+	TimeoutSeconds int            `yaml:"timeout_seconds"`
+	OnlyToIDS      bool           `yaml:"only_to_ids"` // ignora atributos sem a flag to_ids
+	Poll           PollConfig     `yaml:"poll"`
+	Feedback       FeedbackConfig `yaml:"feedback"`
+}
+
+// DefaultConfig retorna a configuração padrão, sem instância (espelha configs/misp/misp.yaml)
+func DefaultConfig() Config {
+	return Config{
+		APIKeyEnv:      "MISP_API_KEY",
+		TimeoutSeconds: 30,
+		OnlyToIDS:      true,
+		Poll: PollConfig{
+			IntervalSeconds: 300,
+			Tags:            []string{"takedown:request"},
+			LookbackHours:   24,
+		},
+		Feedback: FeedbackConfig{
+			StatusTags: map[models.TakedownStatus]string{
+				models.StatusSubmitted: "takedown:submitted",
+				models.StatusAcked:     "takedown:acknowledged",
+				models.StatusOutcome:   "takedown:removed",
+			},
+			Sightings:      true,
+			SightingSource: "takedown",
+		},
+	}
+}
+
+// LoadConfig carrega a configuração de um arquivo YAML sobre os valores padrão
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- caminho vem da configuração do operador
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read misp config: %w", err)
+	}
+	return ParseConfig(data)
+}
+
+// ParseConfig interpreta e valida a configuração; campos ausentes mantêm o padrão
+func ParseConfig(data []byte) (Config, error) {
+	config := DefaultConfig()
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return Config{}, fmt.Errorf("failed to parse misp config: %w", err)
+	}
+
+	if config.TimeoutSeconds <= 0 {
+		return Config{}, fmt.Errorf("timeout_seconds must be positive, got %d", config.TimeoutSeconds)
+	}
+	if config.Poll.IntervalSeconds <= 0 {
+		return Config{}, fmt.Errorf("poll.interval_seconds must be positive, got %d", config.Poll.IntervalSeconds)
+	}
+	if config.Poll.LookbackHours < 0 {
+		return Config{}, fmt.Errorf("poll.lookback_hours cannot be negative, got %d", config.Poll.LookbackHours)
+	}
+	for status := range config.Feedback.StatusTags {
+		if !knownStatus(status) {
+			return Config{}, fmt.Errorf("feedback.status_tags: unknown status %q", status)
+		}
+	}
+	if config.Feedback.Sightings && config.Feedback.SightingSource == "" {
+		return Config{}, fmt.Errorf("feedback.sighting_source is required when sightings are enabled")
+	}
+
+	return config, nil
+}
+
+// knownStatus verifica se o status existe na state machine
+func knownStatus(status models.TakedownStatus) bool {
+	switch status {
+	case models.StatusDiscovered, models.StatusTriage, models.StatusEvidencePack, models.StatusRoute,
+		models.StatusSubmit, models.StatusSubmitted, models.StatusManual, models.StatusAcked, models.StatusFollowUp,
+		models.StatusOutcome, models.StatusClosed:
+		return true
+	default:
+		return false
+	}
+}
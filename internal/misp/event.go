@@ -0,0 +1,107 @@
+package misp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Tag é uma tag MISP (taxonomia, galaxy ou livre)
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// GalaxyCluster é um cluster de galaxy ligado ao evento ou atributo
+type GalaxyCluster struct {
+	Type    string `json:"type"`
+	Value   string `json:"value"`
+	TagName string `json:"tag_name"`
+}
+
+// Galaxy agrupa os clusters de um tipo (mitre-attack-pattern, malpedia...)
+type Galaxy struct {
+	Type     string          `json:"type"`
+	Name     string          `json:"name"`
+	Clusters []GalaxyCluster `json:"GalaxyCluster"`
+}
+
+// Attribute é um atributo de evento MISP
+type Attribute struct {
+	ID        string   `json:"id"`
+	UUID      string   `json:"uuid"`
+	EventID   string   `json:"event_id"`
+	Type      string   `json:"type"`
+	Category  string   `json:"category"`
+	Value     string   `json:"value"`
+	ToIDS     bool     `json:"to_ids"`
+	Deleted   bool     `json:"deleted"`
+	Timestamp string   `json:"timestamp"`  // epoch em segundos, como string
+	FirstSeen string   `json:"first_seen"` // RFC 3339, opcional
+	Tags      []Tag    `json:"Tag,omitempty"`
+	Galaxies  []Galaxy `json:"Galaxy,omitempty"`
+}
+
+// Object é um objeto MISP (url, domain-ip...) com seus atributos
+type Object struct {
+	Name       string      `json:"name"`
+	Attributes []Attribute `json:"Attribute"`
+}
+
+// Event é um evento MISP como devolvido por /events/view e /events/restSearch
+type Event struct {
+	ID         string      `json:"id"`
+	UUID       string      `json:"uuid"`
+	Info       string      `json:"info"`
+	Date       string      `json:"date"` // AAAA-MM-DD
+	Timestamp  string      `json:"timestamp"`
+	Tags       []Tag       `json:"Tag,omitempty"`
+	Galaxies   []Galaxy    `json:"Galaxy,omitempty"`
+	Attributes []Attribute `json:"Attribute,omitempty"`
+	Objects    []Object    `json:"Object,omitempty"`
+}
+
+// wrappedEvent é o envelope {"Event": {...}} usado pela API e pelos exports
+type wrappedEvent struct {
+	Event *Event `json:"Event"`
+}
+
+// ParseEvents interpreta um evento ({"Event": ...}), uma lista de eventos ou a
+// resposta do restSearch ({"response": [...]})
+func ParseEvents(data []byte) ([]Event, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, errors.New("empty MISP document")
+	}
+
+	var wrapped []wrappedEvent
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &wrapped); err != nil {
+			return nil, fmt.Errorf("failed to parse MISP events: %w", err)
+		}
+	} else {
+		var document struct {
+			wrappedEvent
+			Response []wrappedEvent `json:"response"`
+		}
+		if err := json.Unmarshal(data, &document); err != nil {
+			return nil, fmt.Errorf("failed to parse MISP event: %w", err)
+		}
+		wrapped = document.Response
+		if document.Event != nil {
+			wrapped = append(wrapped, document.wrappedEvent)
+		}
+	}
+
+	events := make([]Event, 0, len(wrapped))
+	for _, item := range wrapped {
+		if item.Event == nil {
+			return nil, errors.New("MISP document entry without Event")
+		}
+		events = append(events, *item.Event)
+	}
+	if len(events) == 0 {
+		return nil, errors.New("no MISP events in document")
+	}
+	return events, nil
+}
@@ -0,0 +1,56 @@
+package misp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// Feedback escreve no MISP o andamento dos casos abertos a partir de atributos MISP
+type Feedback struct {
+	client *Client
+	config FeedbackConfig
+}
+
+// NewFeedback cria o feedback com as tags por status da configuração
+func NewFeedback(client *Client, config FeedbackConfig) *Feedback {
+	return &Feedback{client: client, config: config}
+}
+
+// StatusChanged marca os atributos de origem do caso com a tag do novo status e,
+// na submissão, registra um sighting. Casos sem atributos MISP são ignorados.
+func (f *Feedback) StatusChanged(ctx context.Context, status models.TakedownStatus, intelRefs []string) error {
+	attributes := AttributeUUIDs(intelRefs)
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for _, uuid := range attributes {
+		if tag := f.config.StatusTags[status]; tag != "" {
+			if err := f.client.TagAttribute(ctx, uuid, tag); err != nil {
+				errs = append(errs, fmt.Errorf("tag %s on %s: %w", tag, uuid, err))
+			}
+		}
+		if f.config.Sightings && status == models.StatusSubmitted {
+			if err := f.client.AddSighting(ctx, uuid, f.config.SightingSource); err != nil {
+				errs = append(errs, fmt.Errorf("sighting on %s: %w", uuid, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// AttributeUUIDs extrai os UUIDs de atributos MISP das referências do caso
+func AttributeUUIDs(intelRefs []string) []string {
+	var uuids []string
+	for _, ref := range intelRefs {
+		if uuid, ok := strings.CutPrefix(ref, AttributeRefPrefix); ok && uuid != "" {
+			uuids = append(uuids, uuid)
+		}
+	}
+	return uuids
+}
@@ -0,0 +1,315 @@
+package misp
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cti-team/takedown/pkg/indicator"
+	"github.com/cti-team/takedown/pkg/models"
+	"github.com/google/uuid"
+)
+
+const (
+	// SourceMISP é a origem dos IOCs importados do MISP
+	SourceMISP = "misp"
+	// EventRefPrefix e AttributeRefPrefix identificam os UUIDs MISP em IOC.IntelRefs
+	EventRefPrefix     = "misp:event:"
+	AttributeRefPrefix = "misp:attribute:"
+)
+
+// attributeTypes mapeia os tipos de atributo suportados; nos compostos vale a primeira parte
+var attributeTypes = map[string]models.IOCType{
+	"url":         models.IOCTypeURL,
+	"domain":      models.IOCTypeDomain,
+	"hostname":    models.IOCTypeDomain,
+	"domain|ip":   models.IOCTypeDomain,
+	"ip-dst":      models.IOCTypeIP,
+	"ip-dst|port": models.IOCTypeIP,
+}
+
+// categoryKeywords mapeia valores de taxonomias e galaxies para as tags de roteamento
+var categoryKeywords = map[string]string{
+	"phishing":             "phishing",
+	"phish":                "phishing",
+	"spearphishing":        "phishing",
+	"credential-phishing":  "phishing",
+	"malware":              "malware",
+	"malware-distribution": "malware",
+	"ransomware":           "malware",
+	"trojan":               "malware",
+	"dropper":              "malware",
+	"stealer":              "malware",
+	"infostealer":          "malware",
+	"backdoor":             "malware",
+	"c2":                   "c2",
+	"c2-server":            "c2",
+	"c&c":                  "c2",
+	"command-and-control":  "c2",
+	"botnet":               "c2",
+}
+
+// galaxyCategories mapeia tipos de galaxy para tags de roteamento
+var galaxyCategories = map[string]string{
+	"malpedia":      "malware",
+	"mitre-malware": "malware",
+	"ransomware":    "malware",
+	"stealer":       "malware",
+	"banker":        "malware",
+	"android":       "malware",
+	"botnet":        "c2",
+	"rat":           "c2",
+}
+
+// Skipped registra um atributo que não gerou IOC e o motivo
+type Skipped struct {
+	EventID   string `json:"event_id"`
+	Attribute string `json:"attribute"`
+	Reason    string `json:"reason"`
+}
+
+// Result é o resultado do mapeamento de eventos MISP
+type Result struct {
+	Events  int           `json:"events"`
+	IOCs    []*models.IOC `json:"iocs"`
+	Skipped []Skipped     `json:"skipped,omitempty"`
+}
+
+// hashTypes são os atributos de amostra e a posição do hash no valor (filename|sha256 traz o nome antes)
+var hashTypes = map[string]int{
+	"md5":             0,
+	"sha1":            0,
+	"sha256":          0,
+	"sha512":          0,
+	"filename|md5":    1,
+	"filename|sha1":   1,
+	"filename|sha256": 1,
+	"filename|sha512": 1,
+}
+
+// scopedAttribute guarda o índice do objeto de origem (-1 para atributos soltos no evento)
+type scopedAttribute struct {
+	Attribute
+	object int
+}
+
+// Map converte os atributos url, domain, hostname e ip-dst dos eventos (e dos seus
+// objetos) em IOCs. As tags tlp:* e as categorias vindas de taxonomias e galaxies
+// do evento e do atributo viram tags de roteamento. Hashes de amostra saem depois,
+// ligados às URLs do mesmo objeto ou, na falta delas, às URLs do evento.
+func Map(events []Event, onlyToIDS bool) *Result {
+	result := &Result{IOCs: []*models.IOC{}}
+	for i := range events {
+		event := &events[i]
+		result.Events++
+		eventTags := collectTags(event.Tags, event.Galaxies)
+
+		attributes := make([]scopedAttribute, 0, len(event.Attributes))
+		for _, attribute := range event.Attributes {
+			attributes = append(attributes, scopedAttribute{Attribute: attribute, object: -1})
+		}
+		for index, object := range event.Objects {
+			for _, attribute := range object.Attributes {
+				attributes = append(attributes, scopedAttribute{Attribute: attribute, object: index})
+			}
+		}
+
+		seen := make(map[string]bool)
+		var hashes []scopedAttribute
+		var eventURLs []string
+		objectURLs := make(map[int][]string)
+		for _, scoped := range attributes {
+			attribute := scoped.Attribute
+			if _, ok := hashTypes[attribute.Type]; ok {
+				hashes = append(hashes, scoped)
+				continue
+			}
+			iocType, ok := attributeTypes[attribute.Type]
+			if !ok {
+				continue
+			}
+			value := strings.TrimSpace(strings.SplitN(attribute.Value, "|", 2)[0])
+			if !result.accept(event, &attribute, value, onlyToIDS) {
+				continue
+			}
+			validated, err := indicator.Validate(value, iocType)
+			if err != nil {
+				result.Skipped = append(result.Skipped, Skipped{EventID: event.ID, Attribute: attribute.UUID, Reason: err.Error()})
+				continue
+			}
+			value = validated.Value
+			if iocType == models.IOCTypeURL {
+				if !seen["url|"+value] {
+					eventURLs = append(eventURLs, value)
+				}
+				objectURLs[scoped.object] = append(objectURLs[scoped.object], value)
+			}
+			if seen[string(iocType)+"|"+value] {
+				continue
+			}
+			seen[string(iocType)+"|"+value] = true
+			result.IOCs = append(result.IOCs, newIOC(event, &attribute, eventTags, iocType, value))
+		}
+
+		for _, scoped := range hashes {
+			attribute := scoped.Attribute
+			value := ""
+			if parts := strings.Split(attribute.Value, "|"); hashTypes[attribute.Type] < len(parts) {
+				value = strings.TrimSpace(parts[hashTypes[attribute.Type]])
+			}
+			if !result.accept(event, &attribute, value, onlyToIDS) {
+				continue
+			}
+			validated, err := indicator.Validate(value, models.IOCTypeHash)
+			if err != nil {
+				result.Skipped = append(result.Skipped, Skipped{EventID: event.ID, Attribute: attribute.UUID, Reason: err.Error()})
+				continue
+			}
+			urls := objectURLs[scoped.object]
+			if scoped.object < 0 || len(urls) == 0 {
+				urls = eventURLs
+			}
+			if len(urls) == 0 {
+				result.Skipped = append(result.Skipped, Skipped{EventID: event.ID, Attribute: attribute.UUID, Reason: "hash has no distribution URL in the event"})
+				continue
+			}
+			if seen["hash|"+validated.Value] {
+				continue
+			}
+			seen["hash|"+validated.Value] = true
+			ioc := newIOC(event, &attribute, eventTags, models.IOCTypeHash, validated.Value)
+			ioc.DistributionURLs = urls
+			result.IOCs = append(result.IOCs, ioc)
+		}
+	}
+	return result
+}
+
+// accept descarta atributos apagados, sem to_ids (quando exigido) ou vazios
+func (r *Result) accept(event *Event, attribute *Attribute, value string, onlyToIDS bool) bool {
+	switch {
+	case attribute.Deleted:
+		r.Skipped = append(r.Skipped, Skipped{EventID: event.ID, Attribute: attribute.UUID, Reason: "attribute deleted"})
+		return false
+	case onlyToIDS && !attribute.ToIDS:
+		r.Skipped = append(r.Skipped, Skipped{EventID: event.ID, Attribute: attribute.UUID, Reason: "to_ids disabled"})
+		return false
+	}
+	return value != ""
+}
+
+// newIOC monta o IOC com as tags do evento e do atributo e as referências MISP
+func newIOC(event *Event, attribute *Attribute, eventTags map[string]bool, iocType models.IOCType, value string) *models.IOC {
+	tags := collectTags(attribute.Tags, attribute.Galaxies)
+	for tag := range eventTags {
+		tags[tag] = true
+	}
+	refs := []string{}
+	if attribute.UUID != "" {
+		refs = append(refs, AttributeRefPrefix+attribute.UUID)
+	}
+	if event.UUID != "" {
+		refs = append(refs, EventRefPrefix+event.UUID)
+	}
+
+	return &models.IOC{
+		IndicatorID: "misp-" + uuid.New().String(),
+		Type:        iocType,
+		Value:       value,
+		FirstSeen:   firstSeen(event, attribute),
+		Source:      SourceMISP,
+		Tags:        sortedTags(tags),
+		IntelRefs:   refs,
+	}
+}
+
+// collectTags extrai as tags de roteamento das tags e galaxies MISP
+func collectTags(tags []Tag, galaxies []Galaxy) map[string]bool {
+	collected := make(map[string]bool)
+	for _, tag := range tags {
+		routingTag(collected, tag.Name)
+	}
+	for _, galaxy := range galaxies {
+		for _, cluster := range galaxy.Clusters {
+			if cluster.TagName != "" {
+				routingTag(collected, cluster.TagName)
+			} else {
+				galaxyTag(collected, galaxy.Type, cluster.Value)
+			}
+		}
+	}
+	return collected
+}
+
+// routingTag interpreta uma tag: tlp:*, misp-galaxy:<tipo>="<valor>" ou taxonomia namespace:predicado="valor"
+func routingTag(tags map[string]bool, name string) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	switch {
+	case strings.HasPrefix(name, "tlp:"):
+		tags[name] = true
+	case strings.HasPrefix(name, "misp-galaxy:"):
+		galaxy, value, _ := strings.Cut(strings.TrimPrefix(name, "misp-galaxy:"), "=")
+		galaxyTag(tags, galaxy, strings.Trim(value, `"`))
+	default:
+		predicate, value, found := strings.Cut(name, "=")
+		if found {
+			addCategory(tags, strings.Trim(value, `"`))
+		}
+		if _, after, ok := strings.Cut(predicate, ":"); ok {
+			predicate = after
+		}
+		addCategory(tags, predicate)
+	}
+}
+
+// galaxyTag mapeia um cluster: técnicas de phishing do ATT&CK, famílias de malware e botnets
+func galaxyTag(tags map[string]bool, galaxy, value string) {
+	value = strings.ToLower(value)
+	if strings.HasPrefix(galaxy, "mitre-attack-pattern") || strings.HasPrefix(galaxy, "mitre-enterprise-attack-attack-pattern") {
+		if strings.Contains(value, "phishing") || strings.Contains(value, "t1566") || strings.Contains(value, "t1598") {
+			tags["phishing"] = true
+		}
+		return
+	}
+	if category, ok := galaxyCategories[galaxy]; ok {
+		tags[category] = true
+		if category == "c2" {
+			tags["malware"] = true
+		}
+	}
+	addCategory(tags, value)
+}
+
+// addCategory adiciona a tag de um termo conhecido
+func addCategory(tags map[string]bool, term string) {
+	if category, ok := categoryKeywords[strings.TrimSpace(term)]; ok {
+		tags[category] = true
+	}
+}
+
+// firstSeen usa first_seen do atributo, depois o timestamp do atributo e por fim a data do evento
+func firstSeen(event *Event, attribute *Attribute) time.Time {
+	if attribute.FirstSeen != "" {
+		if parsed, err := time.Parse(time.RFC3339Nano, attribute.FirstSeen); err == nil {
+			return parsed.UTC()
+		}
+	}
+	if seconds, err := strconv.ParseInt(attribute.Timestamp, 10, 64); err == nil && seconds > 0 {
+		return time.Unix(seconds, 0).UTC()
+	}
+	if parsed, err := time.Parse("2006-01-02", event.Date); err == nil {
+		return parsed.UTC()
+	}
+	return time.Time{}
+}
+
+// sortedTags devolve as tags em ordem
+func sortedTags(tags map[string]bool) []string {
+	list := make([]string, 0, len(tags))
+	for tag := range tags {
+		list = append(list, tag)
+	}
+	sort.Strings(list)
+	return list
+}
@@ -0,0 +1,232 @@
+package misp
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// testEvent é um evento de phishing com um atributo de C2 em objeto e atributos ignorados
+const testEvent = `{
+  "id": "1207",
+  "uuid": "5f0c2b9e-8d51-4a2e-9a7f-3c1e0d4b6a11",
+  "info": "Phishing campaign against AcmeBank customers",
+  "date": "2025-02-03",
+  "timestamp": "1738600000",
+  "Tag": [
+    {"name": "tlp:amber"},
+    {"name": "takedown:request"},
+    {"name": "rsit:fraud=\"phishing\""}
+  ],
+  "Galaxy": [{
+    "type": "mitre-attack-pattern",
+    "GalaxyCluster": [{"type": "mitre-attack-pattern", "value": "Phishing - T1566", "tag_name": "misp-galaxy:mitre-attack-pattern=\"Phishing - T1566\""}]
+  }],
+  "Attribute": [
+    {"uuid": "a-url", "type": "url", "category": "Network activity", "value": "https://acme-login.com/auth", "to_ids": true, "timestamp": "1738590000", "first_seen": "2025-02-02T21:15:00.000000+00:00"},
+    {"uuid": "a-host", "type": "hostname", "value": "acme-login.com", "to_ids": true, "timestamp": "1738590000"},
+    {"uuid": "a-dup", "type": "domain", "value": "acme-login.com", "to_ids": true},
+    {"uuid": "a-noids", "type": "domain", "value": "cdn.com", "to_ids": false},
+    {"uuid": "a-deleted", "type": "url", "value": "https://old.com/", "to_ids": true, "deleted": true},
+    {"uuid": "a-email", "type": "email-src", "value": "phisher@example.org", "to_ids": true}
+  ],
+  "Object": [{
+    "name": "ip-port",
+    "Attribute": [
+      {"uuid": "a-c2", "type": "ip-dst|port", "value": "45.67.89.77|8443", "to_ids": true, "timestamp": "1738500000",
+       "Tag": [{"name": "misp-galaxy:botnet=\"ExampleBot\""}, {"name": "tlp:red"}]}
+    ]
+  }]
+}`
+
+func TestParseEvents(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{name: "single event", input: `{"Event": ` + testEvent + `}`, want: 1},
+		{name: "event list", input: `[{"Event": ` + testEvent + `}, {"Event": {"id": "2"}}]`, want: 2},
+		{name: "restSearch response", input: `{"response": [{"Event": ` + testEvent + `}]}`, want: 1},
+		{name: "empty response", input: `{"response": []}`, wantErr: true},
+		{name: "not misp", input: `{"type": "bundle"}`, wantErr: true},
+		{name: "invalid json", input: `{"Event": `, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := ParseEvents([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseEvents error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(events) != tt.want {
+				t.Fatalf("got %d events, want %d", len(events), tt.want)
+			}
+		})
+	}
+}
+
+func TestMap(t *testing.T) {
+	events, err := ParseEvents([]byte(`{"Event": ` + testEvent + `}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := Map(events, true)
+
+	tests := []struct {
+		value     string
+		iocType   models.IOCType
+		tags      []string
+		firstSeen string
+		attribute string
+	}{
+		{value: "https://acme-login.com/auth", iocType: models.IOCTypeURL, tags: []string{"phishing", "tlp:amber"}, firstSeen: "2025-02-02T21:15:00Z", attribute: "a-url"},
+		{value: "acme-login.com", iocType: models.IOCTypeDomain, tags: []string{"phishing", "tlp:amber"}, firstSeen: "2025-02-03T13:40:00Z", attribute: "a-host"},
+		{value: "45.67.89.77", iocType: models.IOCTypeIP, tags: []string{"c2", "malware", "phishing", "tlp:amber", "tlp:red"}, firstSeen: "2025-02-02T12:40:00Z", attribute: "a-c2"},
+	}
+	if len(result.IOCs) != len(tests) {
+		t.Fatalf("got %d IOCs, want %d: %+v", len(result.IOCs), len(tests), result.IOCs)
+	}
+	for i, tt := range tests {
+		ioc := result.IOCs[i]
+		if ioc.Value != tt.value || ioc.Type != tt.iocType || ioc.Source != SourceMISP {
+			t.Errorf("IOC %d = %s %s (%s), want %s %s", i, ioc.Type, ioc.Value, ioc.Source, tt.iocType, tt.value)
+		}
+		if !reflect.DeepEqual(ioc.Tags, tt.tags) {
+			t.Errorf("IOC %s tags = %v, want %v", tt.value, ioc.Tags, tt.tags)
+		}
+		if got := ioc.FirstSeen.Format(time.RFC3339); got != tt.firstSeen {
+			t.Errorf("IOC %s first seen = %s, want %s", tt.value, got, tt.firstSeen)
+		}
+		wantRefs := []string{AttributeRefPrefix + tt.attribute, EventRefPrefix + "5f0c2b9e-8d51-4a2e-9a7f-3c1e0d4b6a11"}
+		if !reflect.DeepEqual(ioc.IntelRefs, wantRefs) {
+			t.Errorf("IOC %s refs = %v, want %v", tt.value, ioc.IntelRefs, wantRefs)
+		}
+	}
+
+	if len(result.Skipped) != 2 || result.Skipped[0].Attribute != "a-noids" || result.Skipped[1].Attribute != "a-deleted" {
+		t.Errorf("unexpected skipped attributes: %+v", result.Skipped)
+	}
+	if all := Map(events, false); len(all.IOCs) != 4 || !strings.Contains(all.IOCs[2].Value, "cdn.com") {
+		t.Errorf("expected to_ids=false attribute with only_to_ids disabled, got %+v", all.IOCs)
+	}
+}
+
+func TestMapHashes(t *testing.T) {
+	sha256 := strings.Repeat("c0", 32)
+	md5 := strings.Repeat("d1", 16)
+	events, err := ParseEvents([]byte(`{"Event": {
+  "id": "88", "uuid": "e-88", "date": "2025-02-03",
+  "Tag": [{"name": "misp-galaxy:malpedia=\"Emotet\""}],
+  "Attribute": [
+    {"uuid": "a-loose", "type": "url", "value": "hxxp://drop[.]com/a.exe", "to_ids": true},
+    {"uuid": "a-md5", "type": "md5", "value": "` + strings.ToUpper(md5) + `", "to_ids": true}
+  ],
+  "Object": [
+    {"name": "file", "Attribute": [
+      {"uuid": "a-file", "type": "filename|sha256", "value": "invoice.exe|` + sha256 + `", "to_ids": true},
+      {"uuid": "a-obj-url", "type": "url", "value": "https://files.com/invoice.exe", "to_ids": true}
+    ]},
+    {"name": "file", "Attribute": [
+      {"uuid": "a-bad", "type": "sha1", "value": "not-a-hash", "to_ids": true}
+    ]}
+  ]
+}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := Map(events, true)
+
+	tests := []struct {
+		value string
+		urls  []string
+	}{
+		{value: "http://drop.com/a.exe"},
+		{value: "https://files.com/invoice.exe"},
+		{value: md5, urls: []string{"http://drop.com/a.exe", "https://files.com/invoice.exe"}},
+		{value: sha256, urls: []string{"https://files.com/invoice.exe"}},
+	}
+	if len(result.IOCs) != len(tests) {
+		t.Fatalf("got %d IOCs, want %d: %+v", len(result.IOCs), len(tests), result.IOCs)
+	}
+	for i, tt := range tests {
+		ioc := result.IOCs[i]
+		if ioc.Value != tt.value || !reflect.DeepEqual(ioc.DistributionURLs, tt.urls) {
+			t.Errorf("IOC %d = %s %v, want %s %v", i, ioc.Value, ioc.DistributionURLs, tt.value, tt.urls)
+		}
+		if !ioc.HasTag("malware") {
+			t.Errorf("IOC %s tags = %v, want malware", ioc.Value, ioc.Tags)
+		}
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0].Attribute != "a-bad" {
+		t.Errorf("unexpected skipped attributes: %+v", result.Skipped)
+	}
+
+	// Sem URL no evento o hash não tem o que derrubar
+	events[0].Attributes = events[0].Attributes[1:]
+	events[0].Objects = nil
+	if orphan := Map(events, true); len(orphan.IOCs) != 0 || len(orphan.Skipped) != 1 || !strings.Contains(orphan.Skipped[0].Reason, "no distribution URL") {
+		t.Errorf("expected orphan hash to be skipped, got %+v / %+v", orphan.IOCs, orphan.Skipped)
+	}
+}
+
+func TestRoutingTag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want []string
+	}{
+		{tag: "TLP:AMBER+STRICT", want: []string{"tlp:amber+strict"}},
+		{tag: `misp-galaxy:malpedia="Emotet"`, want: []string{"malware"}},
+		{tag: `misp-galaxy:mitre-attack-pattern="Spearphishing Link - T1566.002"`, want: []string{"phishing"}},
+		{tag: `misp-galaxy:mitre-attack-pattern="Exploit Public-Facing Application - T1190"`, want: []string{}},
+		{tag: `ecsirt:malicious-code="c2-server"`, want: []string{"c2"}},
+		{tag: `circl:incident-classification="phishing"`, want: []string{"phishing"}},
+		{tag: "malware", want: []string{"malware"}},
+		{tag: "takedown:request", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			tags := make(map[string]bool)
+			routingTag(tags, tt.tag)
+			if got := sortedTags(tags); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("routingTag(%q) = %v, want %v", tt.tag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigMatchesDefault(t *testing.T) {
+	config, err := LoadConfig("../../configs/misp/misp.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(config, DefaultConfig()) {
+		t.Fatalf("configs/misp/misp.yaml differs from DefaultConfig: %+v", config)
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr bool
+	}{
+		{name: "disable status tag", yaml: "feedback:\n  status_tags:\n    acked: \"\"\n"},
+		{name: "unknown status", yaml: "feedback:\n  status_tags:\n    removed: takedown:removed\n", wantErr: true},
+		{name: "sightings without source", yaml: "feedback:\n  sighting_source: \"\"\n", wantErr: true},
+		{name: "zero interval", yaml: "poll:\n  interval_seconds: 0\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseConfig([]byte(tt.yaml)); (err != nil) != tt.wantErr {
+				t.Errorf("ParseConfig error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+// Package misptest fornece uma instância MISP local (view, restSearch, tags e
+// sightings) para testes da integração, no estilo de net/http/httptest.
+package misptest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Sighting é um sighting recebido pelo servidor
+type Sighting struct {
+	UUID   string `json:"uuid"`
+	Type   string `json:"type"`
+	Source string `json:"source"`
+}
+
+// event guarda o JSON original e os campos usados nos filtros
+type event struct {
+	raw       json.RawMessage
+	id        string
+	uuid      string
+	timestamp int64
+	tags      map[string]bool
+}
+
+// Server é um MISP em memória servido por httptest
+type Server struct {
+	*httptest.Server
+	apiKey    string
+	events    []event
+	tags      map[string][]string
+	sightings []Sighting
+	mutex     sync.Mutex
+}
+
+// NewServer inicia uma instância vazia que aceita apenas a chave dada
+func NewServer(apiKey string) *Server {
+	server := &Server{apiKey: apiKey, tags: make(map[string][]string)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/events/view/", server.handleView)
+	mux.HandleFunc("/events/restSearch", server.handleSearch)
+	mux.HandleFunc("/tags/attachTagToObject", server.handleAttachTag)
+	mux.HandleFunc("/sightings/add", server.handleSighting)
+	server.Server = httptest.NewServer(server.authenticate(mux))
+	return server
+}
+
+// AddEvent registra um evento no formato da API ({"id": ..., "uuid": ..., "Attribute": [...]}, sem o envelope)
+func (s *Server) AddEvent(eventJSON string) {
+	var fields struct {
+		ID        string `json:"id"`
+		UUID      string `json:"uuid"`
+		Timestamp string `json:"timestamp"`
+		Tag       []struct {
+			Name string `json:"name"`
+		} `json:"Tag"`
+	}
+	if err := json.Unmarshal([]byte(eventJSON), &fields); err != nil {
+		panic("misptest: invalid event JSON: " + err.Error())
+	}
+	stored := event{raw: json.RawMessage(eventJSON), id: fields.ID, uuid: fields.UUID, tags: make(map[string]bool)}
+	stored.timestamp, _ = strconv.ParseInt(fields.Timestamp, 10, 64)
+	for _, tag := range fields.Tag {
+		stored.tags[tag.Name] = true
+	}
+
+	s.mutex.Lock()
+	s.events = append(s.events, stored)
+	s.mutex.Unlock()
+}
+
+// Tags retorna as tags anexadas a um atributo ou evento
+func (s *Server) Tags(uuid string) []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string(nil), s.tags[uuid]...)
+}
+
+// Sightings retorna os sightings recebidos
+func (s *Server) Sightings() []Sighting {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]Sighting(nil), s.sightings...)
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != s.apiKey {
+			writeJSON(w, http.StatusForbidden, map[string]string{"message": "Authentication failed."})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleView(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/events/view/")
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, stored := range s.events {
+		if stored.id == id || stored.uuid == id {
+			writeJSON(w, http.StatusOK, map[string]json.RawMessage{"Event": stored.raw})
+			return
+		}
+	}
+	writeJSON(w, http.StatusNotFound, map[string]string{"message": "Invalid event"})
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	var query struct {
+		Tags      []string `json:"tags"`
+		Timestamp string   `json:"timestamp"`
+	}
+	if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&query) != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": "Invalid request"})
+		return
+	}
+	since, _ := strconv.ParseInt(query.Timestamp, 10, 64)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	response := []map[string]json.RawMessage{}
+	for _, stored := range s.events {
+		if stored.timestamp < since || !matchesTags(stored.tags, query.Tags) {
+			continue
+		}
+		response = append(response, map[string]json.RawMessage{"Event": stored.raw})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"response": response})
+}
+
+func (s *Server) handleAttachTag(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		UUID string `json:"uuid"`
+		Tag  string `json:"tag"`
+	}
+	if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&request) != nil || request.UUID == "" || request.Tag == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": "Invalid request"})
+		return
+	}
+	s.mutex.Lock()
+	s.tags[request.UUID] = append(s.tags[request.UUID], request.Tag)
+	s.mutex.Unlock()
+	writeJSON(w, http.StatusOK, map[string]string{"saved": "true", "success": "Tag attached."})
+}
+
+func (s *Server) handleSighting(w http.ResponseWriter, r *http.Request) {
+	var sighting Sighting
+	if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&sighting) != nil || sighting.UUID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": "Invalid request"})
+		return
+	}
+	s.mutex.Lock()
+	s.sightings = append(s.sightings, sighting)
+	s.mutex.Unlock()
+	writeJSON(w, http.StatusOK, map[string]string{"message": "Sighting added"})
+}
+
+// matchesTags aceita o evento se ele tiver alguma das tags (ou se o filtro for vazio)
+func matchesTags(eventTags map[string]bool, filter []string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, tag := range filter {
+		if eventTags[tag] {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,136 @@
+package misp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// maxSeenAttributes limita a memória da deduplicação; ao estourar, o conjunto recomeça
+const maxSeenAttributes = 100000
+
+// Sink recebe os IOCs novos (normalmente Machine.ProcessIOC)
+type Sink func(ioc *models.IOC) error
+
+// Poller busca periodicamente eventos com as tags configuradas e abre casos para os atributos novos
+type Poller struct {
+	client   *Client
+	config   Config
+	sink     Sink
+	since    time.Time
+	seen     map[string]bool // atributos (UUID ou tipo|valor) já emitidos
+	mutex    sync.Mutex
+	stopChan chan struct{}
+	now      func() time.Time
+}
+
+// NewPoller cria o poller; a primeira busca cobre poll.lookback_hours
+func NewPoller(client *Client, config Config) *Poller {
+	now := func() time.Time { return time.Now().UTC() }
+	return &Poller{
+		client:   client,
+		config:   config,
+		since:    now().Add(-time.Duration(config.Poll.LookbackHours) * time.Hour),
+		seen:     make(map[string]bool),
+		stopChan: make(chan struct{}),
+		now:      now,
+	}
+}
+
+// SetSink configura quem recebe os IOCs
+func (p *Poller) SetSink(sink Sink) {
+	p.sink = sink
+}
+
+// Poll busca os eventos alterados desde a última busca e emite os IOCs ainda não vistos
+func (p *Poller) Poll(ctx context.Context) ([]*models.IOC, error) {
+	started := p.now()
+	events, err := p.client.SearchEvents(ctx, p.config.Poll.Tags, p.since)
+	if err != nil {
+		return nil, err
+	}
+
+	var emitted []*models.IOC
+	var errs []error
+	for _, ioc := range Map(events, p.config.OnlyToIDS).IOCs {
+		key := dedupKey(ioc)
+		if !p.remember(key) {
+			continue
+		}
+		if p.sink != nil {
+			if err := p.sink(ioc); err != nil {
+				errs = append(errs, fmt.Errorf("failed to open case for %s: %w", ioc.Value, err))
+				p.forget(key)
+				continue
+			}
+		}
+		emitted = append(emitted, ioc)
+	}
+
+	// Eventos editados durante a busca voltam no próximo ciclo; a deduplicação evita casos repetidos
+	p.since = started
+	return emitted, errors.Join(errs...)
+}
+
+// Start busca a cada poll.interval_seconds até Stop
+func (p *Poller) Start() {
+	go func() {
+		ticker := time.NewTicker(time.Duration(p.config.Poll.IntervalSeconds) * time.Second)
+		defer ticker.Stop()
+
+		for {
+			iocs, err := p.Poll(context.Background())
+			if err != nil {
+				log.Printf("MISP poller: %v", err)
+			}
+			if len(iocs) > 0 {
+				log.Printf("MISP poller: %d new indicators", len(iocs))
+			}
+
+			select {
+			case <-ticker.C:
+			case <-p.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Stop encerra o poller iniciado por Start
+func (p *Poller) Stop() {
+	close(p.stopChan)
+}
+
+// dedupKey identifica o atributo de origem do IOC
+func dedupKey(ioc *models.IOC) string {
+	if uuids := AttributeUUIDs(ioc.IntelRefs); len(uuids) > 0 {
+		return uuids[0]
+	}
+	return string(ioc.Type) + "|" + ioc.Value
+}
+
+// remember registra o atributo e indica se ele é novo
+func (p *Poller) remember(key string) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.seen[key] {
+		return false
+	}
+	if len(p.seen) >= maxSeenAttributes {
+		p.seen = make(map[string]bool)
+	}
+	p.seen[key] = true
+	return true
+}
+
+// forget permite emitir o atributo de novo (falha ao abrir o caso)
+func (p *Poller) forget(key string) {
+	p.mutex.Lock()
+	delete(p.seen, key)
+	p.mutex.Unlock()
+}
@@ -0,0 +1,141 @@
+// Package redact remove dados pessoais e segredos (emails, cartões, CPF/CNPJ, tokens,
+// cookies, IPs da nossa infraestrutura) das evidências antes de saírem da organização.
+package redact
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Regras embutidas
+const (
+	RuleEmail      = "email"
+	RuleCard       = "card"
+	RuleCPF        = "cpf"
+	RuleCNPJ       = "cnpj"
+	RuleToken      = "token"      // JWT, Bearer e headers de autenticação
+	RuleCookie     = "cookie"     // headers Cookie/Set-Cookie e cookies do HAR
+	RuleCredential = "credential" // valores de parâmetros sensíveis (senha, sessão, OTP)
+	RuleIP         = "ip"         // endereços das redes de analyst_networks
+)
+
+// Rules lista as regras embutidas na ordem em que são aplicadas
+var Rules = []string{RuleToken, RuleCookie, RuleCredential, RuleCNPJ, RuleCPF, RuleCard, RuleEmail, RuleIP}
+
+// CustomRule é uma expressão regular do operador; o trecho casado inteiro é substituído
+type CustomRule struct {
+	Name    string `yaml:"name"`
+	Pattern string `yaml:"pattern"`
+}
+
+// Config define as regras ativas e as listas usadas por elas
+type Config struct {
+	Rules            []string     `yaml:"rules"`             // regras embutidas ativas
+	SensitiveHeaders []string     `yaml:"sensitive_headers"` // valor substituído inteiro (regra token)
+	SensitiveParams  []string     `yaml:"sensitive_params"`  // parâmetros de query, form e JSON (regra credential)
+	AnalystNetworks  []string     `yaml:"analyst_networks"`  // IPs/CIDRs da nossa coleta (regra ip)
+	Allow            []string     `yaml:"allow"`             // valores nunca redigidos (ex.: nosso contato de abuse)
+	Custom           []CustomRule `yaml:"custom"`
+}
+
+// DefaultConfig retorna a configuração padrão (espelha configs/redaction/redaction.yaml)
+func DefaultConfig() Config {
+	return Config{
+		Rules: append([]string(nil), Rules...),
+		SensitiveHeaders: []string{
+			"authorization", "proxy-authorization", "x-api-key", "x-auth-token", "x-csrf-token",
+		},
+		SensitiveParams: []string{
+			"password", "passwd", "pass", "pwd", "senha", "pin", "otp", "token", "access_token",
+			"refresh_token", "session", "sessionid", "sid", "cvv", "cvc", "secret", "api_key", "apikey",
+		},
+	}
+}
+
+// LoadConfig carrega a configuração de um arquivo YAML sobre os valores padrão
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- caminho vem da configuração do operador
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read redaction config: %w", err)
+	}
+	return ParseConfig(data)
+}
+
+// ParseConfig interpreta e valida a configuração; campos ausentes mantêm o padrão
+func ParseConfig(data []byte) (Config, error) {
+	config := DefaultConfig()
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return Config{}, fmt.Errorf("failed to parse redaction config: %w", err)
+	}
+	if err := config.validate(); err != nil {
+		return Config{}, err
+	}
+	return config, nil
+}
+
+// validate confere nomes de regras, redes e expressões customizadas
+func (c Config) validate() error {
+	for _, rule := range c.Rules {
+		if !knownRule(rule) {
+			return fmt.Errorf("unknown redaction rule %q", rule)
+		}
+	}
+	if _, err := parseNetworks(c.AnalystNetworks); err != nil {
+		return err
+	}
+
+	names := make(map[string]bool)
+	for _, custom := range c.Custom {
+		if custom.Name == "" || strings.ContainsAny(custom.Name, " []") {
+			return fmt.Errorf("invalid custom rule name %q", custom.Name)
+		}
+		if knownRule(custom.Name) || names[custom.Name] {
+			return fmt.Errorf("duplicate redaction rule %q", custom.Name)
+		}
+		names[custom.Name] = true
+		if _, err := regexp.Compile(custom.Pattern); err != nil {
+			return fmt.Errorf("custom rule %q: %w", custom.Name, err)
+		}
+	}
+	return nil
+}
+
+// knownRule indica se a regra é embutida
+func knownRule(rule string) bool {
+	for _, known := range Rules {
+		if rule == known {
+			return true
+		}
+	}
+	return false
+}
+
+// parseNetworks aceita IPs isolados e CIDRs
+func parseNetworks(values []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(values))
+	for _, value := range values {
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid analyst network %q", value)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid analyst network %q: %w", value, err)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
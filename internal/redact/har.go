@@ -0,0 +1,83 @@
+package redact
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cti-team/takedown/pkg/har"
+)
+
+// HAR redige uma captura HAR: URLs, headers, cookies, parâmetros, corpos enviados e
+// conteúdo textual das respostas. Conteúdo binário (base64) é mantido.
+func (r *Redactor) HAR(data []byte, audit *Audit) ([]byte, error) {
+	var capture har.HAR
+	if err := json.Unmarshal(data, &capture); err != nil {
+		return nil, fmt.Errorf("failed to parse HAR: %w", err)
+	}
+
+	for i := range capture.Log.Pages {
+		page := &capture.Log.Pages[i]
+		page.Title = r.String(page.Title, "har.pages", audit)
+	}
+	for i := range capture.Log.Entries {
+		entry := &capture.Log.Entries[i]
+		r.harRequest(&entry.Request, audit)
+		r.harResponse(&entry.Response, audit)
+		entry.Error = r.String(entry.Error, "har.errors", audit)
+	}
+
+	redacted, err := capture.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode HAR: %w", err)
+	}
+	return redacted, nil
+}
+
+// harRequest redige a requisição de uma entrada
+func (r *Redactor) harRequest(request *har.Request, audit *Audit) {
+	request.URL = r.String(request.URL, "har.request.url", audit)
+	r.harCookies(request.Cookies, "har.request.cookies", audit)
+	r.harHeaders(request.Headers, "har.request.headers", audit)
+	r.harParams(request.QueryString, "har.request.query", audit)
+	if request.PostData != nil {
+		r.harParams(request.PostData.Params, "har.request.post", audit)
+		request.PostData.Text = r.String(request.PostData.Text, "har.request.post", audit)
+	}
+}
+
+// harResponse redige a resposta de uma entrada
+func (r *Redactor) harResponse(response *har.Response, audit *Audit) {
+	r.harCookies(response.Cookies, "har.response.cookies", audit)
+	r.harHeaders(response.Headers, "har.response.headers", audit)
+	response.RedirectURL = r.String(response.RedirectURL, "har.response.redirect", audit)
+	if response.Content.Encoding != "base64" {
+		response.Content.Text = r.String(response.Content.Text, "har.response.content", audit)
+	}
+}
+
+// harCookies substitui os valores dos cookies
+func (r *Redactor) harCookies(cookies []har.Cookie, location string, audit *Audit) {
+	if !r.cookies {
+		return
+	}
+	for i := range cookies {
+		if cookies[i].Value != "" && !redacted(cookies[i].Value) {
+			cookies[i].Value = Placeholder(RuleCookie)
+			audit.add(RuleCookie, location, 1)
+		}
+	}
+}
+
+// harHeaders redige os headers na própria lista
+func (r *Redactor) harHeaders(headers []har.NameValue, location string, audit *Audit) {
+	for i := range headers {
+		headers[i].Value = r.Header(headers[i].Name, headers[i].Value, location, audit)
+	}
+}
+
+// harParams redige os parâmetros na própria lista
+func (r *Redactor) harParams(params []har.NameValue, location string, audit *Audit) {
+	for i := range params {
+		params[i].Value = r.Param(params[i].Name, params[i].Value, location, audit)
+	}
+}
@@ -0,0 +1,345 @@
+package redact
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cti-team/takedown/pkg/defang"
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// Finding registra quantos valores uma regra redigiu em um local da evidência.
+// Os valores originais nunca entram na auditoria.
+type Finding struct {
+	Rule     string `json:"rule"`
+	Location string `json:"location"`
+	Count    int    `json:"count"`
+}
+
+// Audit é o registro do que foi redigido em uma evidência
+type Audit struct {
+	Findings []Finding `json:"findings"`
+}
+
+// add soma ocorrências de uma regra em um local
+func (a *Audit) add(rule, location string, count int) {
+	if a == nil || count == 0 {
+		return
+	}
+	for i := range a.Findings {
+		if a.Findings[i].Rule == rule && a.Findings[i].Location == location {
+			a.Findings[i].Count += count
+			return
+		}
+	}
+	a.Findings = append(a.Findings, Finding{Rule: rule, Location: location, Count: count})
+}
+
+// Total retorna o número de valores redigidos
+func (a *Audit) Total() int {
+	if a == nil {
+		return 0
+	}
+	total := 0
+	for _, finding := range a.Findings {
+		total += finding.Count
+	}
+	return total
+}
+
+// Summary resume a auditoria por regra em uma linha (eventos do caso)
+func (a *Audit) Summary() string {
+	if a.Total() == 0 {
+		return "nothing redacted"
+	}
+	byRule := make(map[string]int)
+	for _, finding := range a.Findings {
+		byRule[finding.Rule] += finding.Count
+	}
+	rules := make([]string, 0, len(byRule))
+	for rule := range byRule {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+
+	parts := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		parts = append(parts, fmt.Sprintf("%s: %d", rule, byRule[rule]))
+	}
+	return fmt.Sprintf("%d values redacted (%s)", a.Total(), strings.Join(parts, ", "))
+}
+
+// auditKey guarda no contexto a auditoria do pack já redigido
+type auditKey struct{}
+
+// WithAudit marca no contexto da submissão que o pack já foi redigido, com a auditoria resultante
+func WithAudit(ctx context.Context, audit *Audit) context.Context {
+	return context.WithValue(ctx, auditKey{}, audit)
+}
+
+// AuditFromContext retorna a auditoria registrada por WithAudit
+func AuditFromContext(ctx context.Context) (*Audit, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	audit, ok := ctx.Value(auditKey{}).(*Audit)
+	return audit, ok && audit != nil
+}
+
+// Redactor aplica as regras da configuração a textos, headers, evidence packs e HARs
+type Redactor struct {
+	config  Config
+	rules   []textRule
+	cookies bool
+	tokens  bool
+	allow   map[string]bool
+	ioc     string // IOC refangado do pack sendo redigido (ver forIOC)
+}
+
+// NewRedactor valida a configuração e compila as regras
+func NewRedactor(config Config) (*Redactor, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	networks, err := parseNetworks(config.AnalystNetworks)
+	if err != nil {
+		return nil, err
+	}
+
+	redactor := &Redactor{
+		config: config,
+		rules:  builtinTextRules(config, networks),
+		allow:  make(map[string]bool),
+	}
+	for _, rule := range config.Rules {
+		redactor.cookies = redactor.cookies || rule == RuleCookie
+		redactor.tokens = redactor.tokens || rule == RuleToken
+	}
+	for _, value := range config.Allow {
+		redactor.allow[strings.ToLower(value)] = true
+	}
+	return redactor, nil
+}
+
+// Default retorna um redactor com a configuração padrão
+func Default() *Redactor {
+	redactor, err := NewRedactor(DefaultConfig())
+	if err != nil {
+		panic(err) // a configuração padrão é fixa
+	}
+	return redactor
+}
+
+// String redige um texto livre, registrando as ocorrências em audit com o local dado
+func (r *Redactor) String(text, location string, audit *Audit) string {
+	if text == "" || r.isIOC(text) {
+		return text
+	}
+	for _, rule := range r.rules {
+		count := 0
+		placeholder := Placeholder(rule.name)
+		text = rule.pattern.ReplaceAllStringFunc(text, func(match string) string {
+			if r.allowed(match) || redacted(match) || (rule.check != nil && !rule.check(match)) {
+				return match
+			}
+			count++
+			if rule.replace != nil {
+				return rule.replace(match, placeholder)
+			}
+			return placeholder
+		})
+		audit.add(rule.name, location, count)
+	}
+	return text
+}
+
+// Header redige o valor de um header: Cookie/Set-Cookie e headers sensíveis são substituídos inteiros
+func (r *Redactor) Header(name, value, location string, audit *Audit) string {
+	if value == "" || redacted(value) {
+		return value
+	}
+	switch {
+	case r.cookies && cookieHeaders[strings.ToLower(name)]:
+		audit.add(RuleCookie, location, 1)
+		return Placeholder(RuleCookie)
+	case r.tokens && sensitiveParam(r.config.SensitiveHeaders, name):
+		audit.add(RuleToken, location, 1)
+		return Placeholder(RuleToken)
+	default:
+		return r.String(value, location, audit)
+	}
+}
+
+// Headers retorna uma cópia redigida dos headers
+func (r *Redactor) Headers(headers map[string]string, location string, audit *Audit) map[string]string {
+	if headers == nil {
+		return nil
+	}
+	result := make(map[string]string, len(headers))
+	for name, value := range headers {
+		result[name] = r.Header(name, value, location, audit)
+	}
+	return result
+}
+
+// Param redige o valor de um parâmetro de query ou form pelo nome
+func (r *Redactor) Param(name, value, location string, audit *Audit) string {
+	if value != "" && !redacted(value) && r.credentials() && sensitiveParam(r.config.SensitiveParams, name) && !r.allowed(value) {
+		audit.add(RuleCredential, location, 1)
+		return Placeholder(RuleCredential)
+	}
+	return r.String(value, location, audit)
+}
+
+// Pack retorna uma cópia redigida do evidence pack e a auditoria; o pack original não muda.
+// Screenshots e o HAR em disco são tratados por quem exporta os arquivos.
+// O IOC (Defanged) e os valores iguais a ele nunca são redigidos.
+func (r *Redactor) Pack(pack *models.EvidencePack) (*models.EvidencePack, *Audit) {
+	audit := &Audit{Findings: []Finding{}}
+	if pack == nil {
+		return nil, audit
+	}
+
+	// O IOC é o objeto da denúncia: Defanged e valores iguais a ele ficam legíveis
+	r = r.forIOC(pack.Defanged)
+
+	redacted := *pack
+	redacted.Body = nil // o corpo bruto só serve ao scoring e não é redigido
+	redacted.HTTP = r.httpInfo(pack.HTTP, "http", audit)
+	redacted.Content = r.content(pack.Content, "content", audit)
+	redacted.Risk.Rationale = r.String(pack.Risk.Rationale, "risk", audit)
+	redacted.DNS.TXT = r.list(pack.DNS.TXT, "dns.txt", audit)
+	redacted.DNS.SOA = r.String(pack.DNS.SOA, "dns.soa", audit)
+	redacted.TLS = r.tlsInfo(pack.TLS, "tls", audit)
+
+	if pack.Variants != nil {
+		redacted.Variants = make([]models.FetchVariant, len(pack.Variants))
+		for i, variant := range pack.Variants {
+			location := "variants." + variant.Profile
+			variant.HTTP = r.httpInfo(variant.HTTP, location+".http", audit)
+			variant.Content = r.content(variant.Content, location+".content", audit)
+			variant.Error = r.String(variant.Error, location, audit)
+			redacted.Variants[i] = variant
+		}
+	}
+	if pack.Cloaking != nil {
+		cloaking := *pack.Cloaking
+		cloaking.Reasons = r.list(pack.Cloaking.Reasons, "cloaking", audit)
+		redacted.Cloaking = &cloaking
+	}
+	if pack.Services != nil {
+		redacted.Services = make([]models.ServiceInfo, len(pack.Services))
+		for i, service := range pack.Services {
+			location := fmt.Sprintf("services.%d", service.Port)
+			service.Banner = r.String(service.Banner, location+".banner", audit)
+			service.TLS = r.tlsInfo(service.TLS, location+".tls", audit)
+			redacted.Services[i] = service
+		}
+	}
+	redacted.PhishingURLs = r.list(pack.PhishingURLs, "phishing_urls", audit)
+	if pack.File != nil {
+		file := *pack.File
+		file.URL = r.String(pack.File.URL, "file", audit)
+		redacted.File = &file
+	}
+
+	return &redacted, audit
+}
+
+// httpInfo redige headers, corpo, título e a cadeia de redirects
+func (r *Redactor) httpInfo(info models.HTTPInfo, location string, audit *Audit) models.HTTPInfo {
+	info.Headers = r.Headers(info.Headers, location+".headers", audit)
+	info.Body = r.String(info.Body, location+".body", audit)
+	info.Title = r.String(info.Title, location+".title", audit)
+	info.Chain = r.list(info.Chain, location+".chain", audit)
+	return info
+}
+
+// tlsInfo redige o subject, o emissor e os SANs do certificado
+func (r *Redactor) tlsInfo(info *models.TLSInfo, location string, audit *Audit) *models.TLSInfo {
+	if info == nil {
+		return nil
+	}
+	redacted := *info
+	redacted.CN = r.String(info.CN, location+".cn", audit)
+	redacted.Issuer = r.String(info.Issuer, location+".issuer", audit)
+	redacted.SAN = r.list(info.SAN, location+".san", audit)
+	return &redacted
+}
+
+// content redige os trechos da análise de conteúdo que vêm da página
+func (r *Redactor) content(content *models.ContentAnalysis, location string, audit *Audit) *models.ContentAnalysis {
+	if content == nil {
+		return nil
+	}
+	redacted := *content
+	redacted.PageURL = r.String(content.PageURL, location, audit)
+	redacted.ExternalPosts = r.list(content.ExternalPosts, location, audit)
+	if content.Forms != nil {
+		redacted.Forms = make([]models.FormFinding, len(content.Forms))
+		for i, form := range content.Forms {
+			form.Action = r.String(form.Action, location+".forms", audit)
+			redacted.Forms[i] = form
+		}
+	}
+	if content.KitMarkers != nil {
+		redacted.KitMarkers = make([]models.KitMarker, len(content.KitMarkers))
+		for i, marker := range content.KitMarkers {
+			marker.Evidence = r.String(marker.Evidence, location+".kit_markers", audit)
+			redacted.KitMarkers[i] = marker
+		}
+	}
+	return &redacted
+}
+
+// list redige uma lista, devolvendo uma cópia
+func (r *Redactor) list(values []string, location string, audit *Audit) []string {
+	if values == nil {
+		return nil
+	}
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = r.String(value, location, audit)
+	}
+	return result
+}
+
+// credentials indica se a regra credential está ativa
+func (r *Redactor) credentials() bool {
+	for _, rule := range r.config.Rules {
+		if rule == RuleCredential {
+			return true
+		}
+	}
+	return false
+}
+
+// forIOC retorna uma cópia do redactor que preserva o IOC do pack: textos iguais a ele
+// (URLs de phishing, arquivo, cadeia de redirects) e suas ocorrências dentro de outros textos
+func (r *Redactor) forIOC(value string) *Redactor {
+	ioc := strings.ToLower(defang.Refang(value))
+	if ioc == "" {
+		return r
+	}
+	scoped := *r
+	scoped.ioc = ioc
+	scoped.allow = make(map[string]bool, len(r.allow)+1)
+	for allowed := range r.allow {
+		scoped.allow[allowed] = true
+	}
+	scoped.allow[ioc] = true
+	return &scoped
+}
+
+// isIOC indica se o texto inteiro é o IOC do pack, defanged ou não
+func (r *Redactor) isIOC(text string) bool {
+	return r.ioc != "" && strings.ToLower(defang.Refang(text)) == r.ioc
+}
+
+// allowed indica se o valor está na lista allow, mesmo defanged ou codificado em URL
+func (r *Redactor) allowed(value string) bool {
+	value = strings.ToLower(value)
+	return r.allow[value] || r.allow[strings.ReplaceAll(defang.Refang(value), "%40", "@")]
+}
@@ -0,0 +1,343 @@
+package redact
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cti-team/takedown/pkg/har"
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+func TestRedactorString(t *testing.T) {
+	config := DefaultConfig()
+	config.AnalystNetworks = []string{"198.51.100.0/24", "2001:db8::1"}
+	config.Allow = []string{"abuse@example.com"}
+	config.Custom = []CustomRule{{Name: "account", Pattern: `ACC-\d{6}`}}
+	redactor, err := NewRedactor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+		rule  string
+	}{
+		{"email", "contact victim.name@bank.com.br now", "contact [REDACTED:email] now", RuleEmail},
+		{"defanged email", "from victim[@]bank[.]com", "from [REDACTED:email]", RuleEmail},
+		{"url encoded email", "/login?u=victim%40bank.com", "/login?u=[REDACTED:email]", RuleEmail},
+		{"allowed email", "reply to abuse@example.com", "reply to abuse@example.com", ""},
+		{"card", "card 4111 1111 1111 1111 exp", "card [REDACTED:card] exp", RuleCard},
+		{"card fails luhn", "order 4111111111111112", "order 4111111111111112", ""},
+		{"timestamp is not a card", "ts=1700000000000", "ts=1700000000000", ""},
+		{"cpf formatted", "cpf 529.982.247-25", "cpf [REDACTED:cpf]", RuleCPF},
+		{"cpf digits", "doc=52998224725&x=1", "doc=[REDACTED:cpf]&x=1", RuleCPF},
+		{"cpf invalid check digits", "cpf 529.982.247-26", "cpf 529.982.247-26", ""},
+		{"cnpj", "empresa 11.222.333/0001-81", "empresa [REDACTED:cnpj]", RuleCNPJ},
+		{"jwt", "t=eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxMjM0In0.sig_123", "t=[REDACTED:token]", RuleToken},
+		{"bearer", "Authorization: Bearer abcdef123456", "Authorization: Bearer [REDACTED:token]", RuleToken},
+		{"query credential", "/auth?user=x&senha=hunter2&next=/", "/auth?user=x&senha=[REDACTED:credential]&next=/", RuleCredential},
+		{"json credential", `{"login":"x","password":"p\"w"}`, `{"login":"x","password":"[REDACTED:credential]"}`, RuleCredential},
+		{"analyst ipv4", "X-Forwarded-For: 198.51.100.7", "X-Forwarded-For: [REDACTED:ip]", RuleIP},
+		{"analyst ipv6", "client 2001:db8::1", "client [REDACTED:ip]", RuleIP},
+		{"other ip kept", "server 203.0.113.5", "server 203.0.113.5", ""},
+		{"custom", "conta ACC-123456", "conta [REDACTED:account]", "account"},
+		{"already redacted", "senha=[REDACTED:credential]", "senha=[REDACTED:credential]", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			audit := &Audit{}
+			got := redactor.String(tt.input, "test", audit)
+			if got != tt.want {
+				t.Fatalf("String(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+			if tt.rule == "" {
+				if audit.Total() != 0 {
+					t.Fatalf("unexpected audit %+v", audit.Findings)
+				}
+				return
+			}
+			if len(audit.Findings) != 1 || audit.Findings[0].Rule != tt.rule || audit.Findings[0].Location != "test" {
+				t.Fatalf("unexpected audit %+v", audit.Findings)
+			}
+		})
+	}
+}
+
+func TestRedactorPack(t *testing.T) {
+	pack := &models.EvidencePack{
+		EvidenceID: "ev-1",
+		Defanged:   "hxxps://bank-login[.]example/?email=victim@bank.com",
+		HTTP: models.HTTPInfo{
+			Chain:  []string{"https://bank-login.example/?email=victim@bank.com", "https://bank-login.example/home?email=victim@bank.com"},
+			Status: 200,
+			Headers: map[string]string{
+				"Set-Cookie":    "session=abc123",
+				"Authorization": "Basic dXNlcjpwYXNz",
+				"Server":        "nginx",
+			},
+			Body: `<input name="cpf" value="529.982.247-25">`,
+		},
+		Content: &models.ContentAnalysis{
+			Forms: []models.FormFinding{{Action: "https://drop.example/post.php?to=kit@mail.example"}},
+		},
+		Variants: []models.FetchVariant{{Profile: "mobile", HTTP: models.HTTPInfo{Body: "owner@bank.com"}}},
+	}
+
+	redacted, audit := Default().Pack(pack)
+
+	// O IOC e os valores iguais a ele são o objeto da denúncia e não são redigidos
+	if redacted.Defanged != pack.Defanged {
+		t.Errorf("defanged IOC was redacted: %q", redacted.Defanged)
+	}
+	if redacted.HTTP.Chain[0] != pack.HTTP.Chain[0] || redacted.HTTP.Chain[1] != "https://bank-login.example/home?email=[REDACTED:email]" {
+		t.Errorf("unexpected redirect chain %v", redacted.HTTP.Chain)
+	}
+	if redacted.HTTP.Headers["Set-Cookie"] != "[REDACTED:cookie]" || redacted.HTTP.Headers["Authorization"] != "[REDACTED:token]" {
+		t.Errorf("sensitive headers not redacted: %v", redacted.HTTP.Headers)
+	}
+	if redacted.HTTP.Headers["Server"] != "nginx" {
+		t.Errorf("unrelated header changed: %v", redacted.HTTP.Headers)
+	}
+	if strings.Contains(redacted.HTTP.Body, "529.982.247-25") {
+		t.Errorf("CPF left in body: %s", redacted.HTTP.Body)
+	}
+	if redacted.Content.Forms[0].Action != "https://drop.example/post.php?to=[REDACTED:email]" {
+		t.Errorf("unexpected form action %q", redacted.Content.Forms[0].Action)
+	}
+	if redacted.Variants[0].HTTP.Body != "[REDACTED:email]" {
+		t.Errorf("variant body not redacted: %q", redacted.Variants[0].HTTP.Body)
+	}
+
+	// O pack original continua intacto para o evidence store
+	if pack.HTTP.Headers["Set-Cookie"] != "session=abc123" || pack.Variants[0].HTTP.Body != "owner@bank.com" ||
+		pack.Content.Forms[0].Action != "https://drop.example/post.php?to=kit@mail.example" {
+		t.Errorf("original pack was modified")
+	}
+
+	if audit.Total() != 6 {
+		t.Errorf("audit total = %d, want 6 (%+v)", audit.Total(), audit.Findings)
+	}
+	if summary := audit.Summary(); summary != "6 values redacted (cookie: 1, cpf: 1, email: 3, token: 1)" {
+		t.Errorf("unexpected summary %q", summary)
+	}
+
+	// Redigir de novo não altera nem conta de novo
+	again, second := Default().Pack(redacted)
+	if second.Total() != 0 || again.Defanged != redacted.Defanged {
+		t.Errorf("second pass changed the pack: %+v", second.Findings)
+	}
+}
+
+func TestRedactorPack_EmailIOC(t *testing.T) {
+	pack := &models.EvidencePack{
+		EvidenceID:   "ev-email",
+		Defanged:     "drop[@]gmail[.]com",
+		HTTP:         models.HTTPInfo{Body: "send logins to Drop@Gmail.com, copy to victim@bank.com"},
+		PhishingURLs: []string{"hxxps://acme-login[.]example/?to=drop%40gmail.com"},
+	}
+
+	redacted, audit := Default().Pack(pack)
+
+	if redacted.Defanged != "drop[@]gmail[.]com" {
+		t.Errorf("email IOC was redacted: %q", redacted.Defanged)
+	}
+	if redacted.HTTP.Body != "send logins to Drop@Gmail.com, copy to [REDACTED:email]" {
+		t.Errorf("unexpected body %q", redacted.HTTP.Body)
+	}
+	if redacted.PhishingURLs[0] != pack.PhishingURLs[0] {
+		t.Errorf("IOC inside phishing URL was redacted: %q", redacted.PhishingURLs[0])
+	}
+	if summary := audit.Summary(); summary != "1 values redacted (email: 1)" {
+		t.Errorf("unexpected summary %q", summary)
+	}
+
+	// A liberação vale só para o pack do IOC
+	if other, _ := Default().Pack(&models.EvidencePack{Defanged: "other[@]gmail[.]com", HTTP: pack.HTTP}); !strings.Contains(other.HTTP.Body, "[REDACTED:email], copy") {
+		t.Errorf("IOC of another pack leaked into the allow list: %q", other.HTTP.Body)
+	}
+}
+
+func TestRedactorPack_IOCValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		defanged string
+		value    string
+		want     string
+	}{
+		{"url ioc defanged", "hxxps://drop[.]example/?to=kit@mail.example", "hxxps://drop[.]example/?to=kit@mail.example", "hxxps://drop[.]example/?to=kit@mail.example"},
+		{"url ioc refanged", "hxxps://drop[.]example/?to=kit@mail.example", "https://drop.example/?to=kit@mail.example", "https://drop.example/?to=kit@mail.example"},
+		{"other url", "hxxps://drop[.]example/?to=kit@mail.example", "https://drop.example/next?to=kit@mail.example", "https://drop.example/next?to=[REDACTED:email]"},
+		{"email ioc", "kit[@]mail[.]example", "kit@mail.example", "kit@mail.example"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pack := &models.EvidencePack{
+				Defanged:     tt.defanged,
+				PhishingURLs: []string{tt.value},
+				File:         &models.FileInfo{URL: tt.value},
+			}
+			redacted, _ := Default().Pack(pack)
+			if redacted.Defanged != tt.defanged {
+				t.Errorf("defanged IOC was redacted: %q", redacted.Defanged)
+			}
+			if redacted.PhishingURLs[0] != tt.want || redacted.File.URL != tt.want {
+				t.Errorf("got phishing URL %q and file URL %q, want %q", redacted.PhishingURLs[0], redacted.File.URL, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactorPack_NetworkEvidence(t *testing.T) {
+	tlsInfo := &models.TLSInfo{
+		CN:     "panel emailAddress=operator@mail.example",
+		Issuer: "R3",
+		SAN:    []string{"panel.example", "owner@bank.com"},
+	}
+	pack := &models.EvidencePack{
+		Defanged: "203[.]0[.]113[.]10",
+		DNS: models.DNSRecord{
+			A:   []string{"203.0.113.10"},
+			TXT: []string{"v=spf1 -all", "contact=victim@bank.com"},
+			SOA: "ns1.example hostmaster@bank.com 2024010101",
+		},
+		TLS: tlsInfo,
+		Services: []models.ServiceInfo{
+			{Port: 21, Protocol: "ftp", Banner: "220 FTP ready, token Bearer abcdef123456"},
+			{Port: 443, Protocol: "https", Banner: "HTTP/1.1 200 OK", TLS: tlsInfo},
+		},
+	}
+
+	redacted, audit := Default().Pack(pack)
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"dns a", redacted.DNS.A[0], "203.0.113.10"},
+		{"dns txt kept", redacted.DNS.TXT[0], "v=spf1 -all"},
+		{"dns txt", redacted.DNS.TXT[1], "contact=[REDACTED:email]"},
+		{"dns soa", redacted.DNS.SOA, "ns1.example [REDACTED:email] 2024010101"},
+		{"tls cn", redacted.TLS.CN, "panel emailAddress=[REDACTED:email]"},
+		{"tls san", redacted.TLS.SAN[1], "[REDACTED:email]"},
+		{"service banner", redacted.Services[0].Banner, "220 FTP ready, token Bearer [REDACTED:token]"},
+		{"service tls", redacted.Services[1].TLS.SAN[1], "[REDACTED:email]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+
+	// O pack original continua intacto para o evidence store
+	if pack.DNS.TXT[1] != "contact=victim@bank.com" || pack.TLS.SAN[1] != "owner@bank.com" || pack.Services[0].Banner != "220 FTP ready, token Bearer abcdef123456" {
+		t.Errorf("original pack was modified")
+	}
+
+	locations := make(map[string]bool)
+	for _, finding := range audit.Findings {
+		locations[finding.Location] = true
+	}
+	for _, location := range []string{"dns.txt", "dns.soa", "tls.cn", "tls.san", "services.21.banner", "services.443.tls.cn", "services.443.tls.san"} {
+		if !locations[location] {
+			t.Errorf("missing audit finding for %s (%+v)", location, audit.Findings)
+		}
+	}
+}
+
+func TestRedactorHAR(t *testing.T) {
+	capture := har.HAR{Log: har.Log{Version: har.Version, Entries: []har.Entry{{
+		Request: har.Request{
+			Method:      "POST",
+			URL:         "https://kit.example/login?email=victim@bank.com",
+			Cookies:     []har.Cookie{{Name: "PHPSESSID", Value: "s3cr3t"}},
+			Headers:     []har.NameValue{{Name: "Cookie", Value: "PHPSESSID=s3cr3t"}, {Name: "Accept", Value: "*/*"}},
+			QueryString: []har.NameValue{{Name: "email", Value: "victim@bank.com"}},
+			PostData: &har.PostData{
+				MimeType: "application/x-www-form-urlencoded",
+				Params:   []har.NameValue{{Name: "password", Value: "hunter2"}},
+				Text:     "password=hunter2",
+			},
+		},
+		Response: har.Response{
+			Status:  200,
+			Headers: []har.NameValue{{Name: "Set-Cookie", Value: "PHPSESSID=s3cr3t; path=/"}},
+			Content: har.Content{MimeType: "text/html", Text: "<p>Olá victim@bank.com</p>"},
+		},
+	}}}}
+	data, err := capture.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	audit := &Audit{}
+	redacted, err := Default().HAR(data, audit)
+	if err != nil {
+		t.Fatalf("HAR returned error: %v", err)
+	}
+	for _, secret := range []string{"victim@bank.com", "s3cr3t", "hunter2"} {
+		if strings.Contains(string(redacted), secret) {
+			t.Errorf("redacted HAR still contains %q", secret)
+		}
+	}
+
+	var parsed har.HAR
+	if err := json.Unmarshal(redacted, &parsed); err != nil {
+		t.Fatalf("redacted HAR is not valid JSON: %v", err)
+	}
+	if parsed.Log.Entries[0].Request.Headers[1].Value != "*/*" {
+		t.Errorf("unrelated header changed")
+	}
+	if audit.Total() != 8 {
+		t.Errorf("audit total = %d, want 8 (%+v)", audit.Total(), audit.Findings)
+	}
+
+	if _, err := Default().HAR([]byte("not json"), audit); err == nil {
+		t.Errorf("expected error for invalid HAR")
+	}
+}
+
+func TestLoadConfigMatchesDefault(t *testing.T) {
+	config, err := LoadConfig("../../configs/redaction/redaction.yaml")
+	if err != nil {
+		t.Fatalf("LoadConfig returned error: %v", err)
+	}
+	defaults := DefaultConfig()
+	if strings.Join(config.Rules, ",") != strings.Join(defaults.Rules, ",") ||
+		strings.Join(config.SensitiveHeaders, ",") != strings.Join(defaults.SensitiveHeaders, ",") ||
+		strings.Join(config.SensitiveParams, ",") != strings.Join(defaults.SensitiveParams, ",") {
+		t.Fatalf("configs/redaction/redaction.yaml differs from DefaultConfig: %+v", config)
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr bool
+	}{
+		{name: "defaults", yaml: "{}"},
+		{name: "subset", yaml: "rules: [email, cookie]\nanalyst_networks: [10.0.0.0/8, 192.0.2.10]"},
+		{name: "custom rule", yaml: "custom:\n  - name: protocol\n    pattern: 'PRT-\\d+'"},
+		{name: "unknown rule", yaml: "rules: [ssn]", wantErr: true},
+		{name: "bad network", yaml: "analyst_networks: [10.0.0.0/33]", wantErr: true},
+		{name: "bad pattern", yaml: "custom:\n  - name: broken\n    pattern: '('", wantErr: true},
+		{name: "builtin name", yaml: "custom:\n  - name: email\n    pattern: 'x'", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseConfig([]byte(tt.yaml))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseConfig error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package redact
+
+import (
+	"net"
+	"regexp"
+	"strings"
+)
+
+var (
+	// Emails, inclusive defanged (user[@]example[.]com) e codificados em URLs (%40)
+	emailPattern  = regexp.MustCompile(`(?i)[a-z0-9._%+\-]+(?:@|\[@\]|%40)[a-z0-9\-]+(?:(?:\.|\[\.\])[a-z0-9\-]+)*(?:\.|\[\.\])[a-z]{2,}`)
+	cardPattern   = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+	cpfPattern    = regexp.MustCompile(`\b\d{3}\.?\d{3}\.?\d{3}-?\d{2}\b`)
+	cnpjPattern   = regexp.MustCompile(`\b\d{2}\.?\d{3}\.?\d{3}/?\d{4}-?\d{2}\b`)
+	jwtPattern    = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]*`)
+	bearerPattern = regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/=-]{8,}`)
+	ipv4Pattern   = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+	ipv6Pattern   = regexp.MustCompile(`(?i)\b(?:[0-9a-f]{0,4}:){2,7}[0-9a-f]{0,4}\b`)
+)
+
+// Cookie e Set-Cookie são sempre redigidos inteiros pela regra cookie
+var cookieHeaders = map[string]bool{"cookie": true, "set-cookie": true}
+
+// textRule substitui os trechos que casam com pattern; check descarta falsos positivos
+type textRule struct {
+	name    string
+	pattern *regexp.Regexp
+	check   func(match string) bool
+	// replace gera o texto no lugar do trecho; nil substitui o trecho inteiro
+	replace func(match, placeholder string) string
+}
+
+// Placeholder é o texto que entra no lugar do valor redigido
+func Placeholder(rule string) string {
+	return "[REDACTED:" + rule + "]"
+}
+
+// redacted indica trechos que já passaram pela redação (evita contar duas vezes)
+func redacted(value string) bool {
+	return strings.Contains(value, "[REDACTED:")
+}
+
+// builtinTextRules monta as regras de texto ativas na ordem de Rules
+func builtinTextRules(config Config, networks []*net.IPNet) []textRule {
+	enabled := make(map[string]bool)
+	for _, rule := range config.Rules {
+		enabled[rule] = true
+	}
+
+	var rules []textRule
+	for _, name := range Rules {
+		if !enabled[name] {
+			continue
+		}
+		switch name {
+		case RuleToken:
+			rules = append(rules,
+				textRule{name: RuleToken, pattern: jwtPattern},
+				textRule{name: RuleToken, pattern: bearerPattern, replace: func(match, placeholder string) string {
+					return strings.Fields(match)[0] + " " + placeholder
+				}},
+			)
+		case RuleCredential:
+			rules = append(rules, credentialRules(config.SensitiveParams)...)
+		case RuleCNPJ:
+			rules = append(rules, textRule{name: RuleCNPJ, pattern: cnpjPattern, check: validCNPJ})
+		case RuleCPF:
+			rules = append(rules, textRule{name: RuleCPF, pattern: cpfPattern, check: validCPF})
+		case RuleCard:
+			rules = append(rules, textRule{name: RuleCard, pattern: cardPattern, check: validCard})
+		case RuleEmail:
+			rules = append(rules, textRule{name: RuleEmail, pattern: emailPattern})
+		case RuleIP:
+			if len(networks) == 0 {
+				continue
+			}
+			inNetworks := func(match string) bool { return containsIP(networks, match) }
+			rules = append(rules,
+				textRule{name: RuleIP, pattern: ipv4Pattern, check: inNetworks},
+				textRule{name: RuleIP, pattern: ipv6Pattern, check: inNetworks},
+			)
+		}
+		// RuleCookie só atua em headers e cookies estruturados
+	}
+
+	for _, custom := range config.Custom {
+		rules = append(rules, textRule{name: custom.Name, pattern: regexp.MustCompile(custom.Pattern)})
+	}
+	return rules
+}
+
+// credentialRules redige o valor de parâmetros sensíveis em query strings, forms e JSON
+func credentialRules(params []string) []textRule {
+	if len(params) == 0 {
+		return nil
+	}
+	quoted := make([]string, 0, len(params))
+	for _, param := range params {
+		quoted = append(quoted, regexp.QuoteMeta(param))
+	}
+	names := strings.Join(quoted, "|")
+
+	form := regexp.MustCompile(`(?i)(^|[?&;,\s"'])(` + names + `)=([^&\s"'#;,]+)`)
+	json := regexp.MustCompile(`(?i)("(?:` + names + `)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	return []textRule{
+		{name: RuleCredential, pattern: form, replace: func(match, placeholder string) string {
+			groups := form.FindStringSubmatch(match)
+			return groups[1] + groups[2] + "=" + placeholder
+		}},
+		{name: RuleCredential, pattern: json, replace: func(match, placeholder string) string {
+			return json.FindStringSubmatch(match)[1] + `"` + placeholder + `"`
+		}},
+	}
+}
+
+// sensitiveParam indica se o nome do parâmetro está em SensitiveParams
+func sensitiveParam(params []string, name string) bool {
+	for _, param := range params {
+		if strings.EqualFold(param, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsIP indica se o trecho é um IP dentro de uma das redes
+func containsIP(networks []*net.IPNet, value string) bool {
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return false
+	}
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// digits extrai os dígitos do trecho
+func digits(value string) []int {
+	result := make([]int, 0, len(value))
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			result = append(result, int(r-'0'))
+		}
+	}
+	return result
+}
+
+// repeated indica sequências de um único dígito (000.000.000-00), que passam nos dígitos verificadores
+func repeated(values []int) bool {
+	for _, value := range values {
+		if value != values[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// validCard confere o tamanho (13-19), o primeiro dígito das bandeiras (2-6) e o dígito de Luhn
+func validCard(value string) bool {
+	values := digits(value)
+	if len(values) < 13 || len(values) > 19 || values[0] < 2 || values[0] > 6 || repeated(values) {
+		return false
+	}
+	sum := 0
+	for i := len(values) - 1; i >= 0; i-- {
+		digit := values[i]
+		if (len(values)-1-i)%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	return sum%10 == 0
+}
+
+// validCPF confere os dois dígitos verificadores do CPF
+func validCPF(value string) bool {
+	values := digits(value)
+	if len(values) != 11 || repeated(values) {
+		return false
+	}
+	for position := 9; position <= 10; position++ {
+		sum := 0
+		for i := 0; i < position; i++ {
+			sum += values[i] * (position + 1 - i)
+		}
+		check := sum * 10 % 11 % 10
+		if check != values[position] {
+			return false
+		}
+	}
+	return true
+}
+
+// validCNPJ confere os dois dígitos verificadores do CNPJ
+func validCNPJ(value string) bool {
+	values := digits(value)
+	if len(values) != 14 || repeated(values) {
+		return false
+	}
+	weights := []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+	for position := 12; position <= 13; position++ {
+		sum := 0
+		offset := 13 - position
+		for i := 0; i < position; i++ {
+			sum += values[i] * weights[i+offset]
+		}
+		check := 11 - sum%11
+		if check >= 10 {
+			check = 0
+		}
+		if check != values[position] {
+			return false
+		}
+	}
+	return true
+}
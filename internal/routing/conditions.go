@@ -0,0 +1,87 @@
+package routing
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// rulesFile é o formato de configs/routing/rules.yaml; a engine lê apenas as condições de país
+type rulesFile struct {
+	Rules []struct {
+		Name    string `yaml:"name"`
+		Actions []struct {
+			TargetType string `yaml:"target_type"`
+			Conditions struct {
+				Country []string `yaml:"country"`
+			} `yaml:"conditions"`
+		} `yaml:"actions"`
+	} `yaml:"rules"`
+}
+
+// LoadConditions aplica às regras da engine as condições de país de um arquivo rules.yaml
+func (e *Engine) LoadConditions(path string) error {
+	data, err := os.ReadFile(path) // #nosec G304 -- caminho vem da configuração do operador
+	if err != nil {
+		return fmt.Errorf("failed to read routing rules: %w", err)
+	}
+	return e.ApplyConditions(data)
+}
+
+// ApplyConditions aplica as condições de país por nome de regra e tipo de target. Targets que a
+// engine ainda não aciona são ignorados; para os demais, uma condição que não encontra a ação
+// correspondente é erro, para não ficar sem efeito em silêncio
+func (e *Engine) ApplyConditions(data []byte) error {
+	var file rulesFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse routing rules: %w", err)
+	}
+
+	for _, rule := range file.Rules {
+		for _, action := range rule.Actions {
+			countries := action.Conditions.Country
+			if len(countries) == 0 || !e.routes(action.TargetType) {
+				continue
+			}
+			for _, country := range countries {
+				if country = strings.ToLower(country); len(country) != 2 {
+					return fmt.Errorf("rule %q: invalid country %q (ISO alpha-2 or \"eu\")", rule.Name, country)
+				}
+			}
+			if !e.setCountries(rule.Name, action.TargetType, countries) {
+				return fmt.Errorf("rule %q: no %s action to apply the country condition to", rule.Name, action.TargetType)
+			}
+		}
+	}
+	return nil
+}
+
+// routes indica se alguma regra da engine aciona o tipo de target
+func (e *Engine) routes(targetType string) bool {
+	for _, rule := range e.rules {
+		for _, action := range rule.Actions {
+			if action.Target.Type == targetType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// setCountries define a condição de país da ação da regra nomeada
+func (e *Engine) setCountries(ruleName, targetType string, countries []string) bool {
+	for i := range e.rules {
+		if e.rules[i].Name != ruleName {
+			continue
+		}
+		for j := range e.rules[i].Actions {
+			if e.rules[i].Actions[j].Target.Type == targetType {
+				e.rules[i].Actions[j].Countries = countries
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package routing
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+func TestEngine_LoadConditions(t *testing.T) {
+	engine := NewEngine()
+	if err := engine.LoadConditions("../../configs/routing/rules.yaml"); err != nil {
+		t.Fatal(err)
+	}
+
+	contacts := func(country string) *models.AbuseContact {
+		return &models.AbuseContact{
+			Domain:  "c2.example",
+			Host:    "c2.example",
+			Hosting: &models.HostingInfo{Name: "Example Hosting", Country: country, Abuse: models.ContactInfo{Email: "abuse@hosting.example"}},
+			CERT:    &models.CERTInfo{Name: "Example CERT", Abuse: models.ContactInfo{Email: "cert@cert.example"}},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		country  string
+		wantCERT bool
+	}{
+		{name: "listed country", country: "BR", wantCERT: true},
+		{name: "eu member", country: "NL", wantCERT: true},
+		{name: "other country", country: "RU"},
+		{name: "unknown country"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found := false
+			for _, action := range engine.DetermineActions([]string{"c2"}, contacts(tt.country)) {
+				found = found || action.Target.Type == "cert"
+			}
+			if found != tt.wantCERT {
+				t.Errorf("Expected CERT action %v for country %q", tt.wantCERT, tt.country)
+			}
+		})
+	}
+}
+
+func TestEngine_ApplyConditions(t *testing.T) {
+	contacts := func(country string) *models.AbuseContact {
+		return &models.AbuseContact{
+			Domain:  "c2.example",
+			Hosting: &models.HostingInfo{Name: "Example Hosting", Country: country, Abuse: models.ContactInfo{Email: "abuse@hosting.example"}},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		yaml        string
+		country     string
+		wantHosting bool
+		wantErr     string
+	}{
+		{
+			name:        "listed country",
+			yaml:        "rules:\n  - name: c2_infrastructure\n    actions:\n      - target_type: hosting\n        conditions:\n          country: [br, eu]\n",
+			country:     "BR",
+			wantHosting: true,
+		},
+		{
+			name:        "eu member",
+			yaml:        "rules:\n  - name: c2_infrastructure\n    actions:\n      - target_type: hosting\n        conditions:\n          country: [br, eu]\n",
+			country:     "NL",
+			wantHosting: true,
+		},
+		{
+			name:    "other country",
+			yaml:    "rules:\n  - name: c2_infrastructure\n    actions:\n      - target_type: hosting\n        conditions:\n          country: [br, eu]\n",
+			country: "RU",
+		},
+		{
+			name:        "rules without conditions are ignored",
+			yaml:        "rules:\n  - name: mass_campaign\n    actions:\n      - target_type: cdn\n",
+			country:     "RU",
+			wantHosting: true,
+		},
+		{
+			name:        "targets the engine does not route are ignored",
+			yaml:        "rules:\n  - name: c2_infrastructure\n    actions:\n      - target_type: isp\n        conditions:\n          country: [br]\n",
+			country:     "RU",
+			wantHosting: true,
+		},
+		{
+			name:    "condition without matching action",
+			yaml:    "rules:\n  - name: brand_protection\n    actions:\n      - target_type: hosting\n        conditions:\n          country: [br]\n",
+			wantErr: "no hosting action",
+		},
+		{
+			name:    "invalid country",
+			yaml:    "rules:\n  - name: c2_infrastructure\n    actions:\n      - target_type: hosting\n        conditions:\n          country: [brazil]\n",
+			wantErr: "invalid country",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewEngine()
+			err := engine.ApplyConditions([]byte(tt.yaml))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			found := false
+			for _, action := range engine.DetermineActions([]string{"c2"}, contacts(tt.country)) {
+				found = found || action.Target.Type == "hosting"
+			}
+			if found != tt.wantHosting {
+				t.Errorf("Expected hosting action %v for country %q", tt.wantHosting, tt.country)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package routing
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/cti-team/takedown/pkg/models"
@@ -13,15 +14,20 @@ type Engine struct {
 
 // Rule representa uma regra de roteamento
 type Rule struct {
-	Match   []string           // tags que devem estar presentes
-	Actions []ActionDefinition // ações a serem executadas
+	Name     string             // nome da regra em configs/routing/rules.yaml
+	Match    []string           // tags que devem estar presentes
+	Actions  []ActionDefinition // ações a serem executadas
+	IPOnly   bool               // aplica-se apenas a IOCs de IP (sem domínio)
+	Email    bool               // regra de IOCs de email; as demais não se aplicam a contas
+	Platform bool               // aplica-se apenas a sites em plataformas catalogadas (github.io, Wix...)
 }
 
 // ActionDefinition define uma ação específica
 type ActionDefinition struct {
-	Target models.TakedownTarget
-	Action models.TakedownAction
-	SLA    models.SLA
+	Target    models.TakedownTarget
+	Action    models.TakedownAction
+	SLA       models.SLA
+	Countries []string // condição opcional: país do hosting (ISO alpha-2 ou "eu")
 }
 
 // NewEngine cria uma nova engine de roteamento
@@ -36,6 +42,7 @@ func (e *Engine) loadDefaultRules() {
 	e.rules = []Rule{
 		// Regra para phishing
 		{
+			Name:  "phishing_campaign",
 			Match: []string{"phishing"},
 			Actions: []ActionDefinition{
 				createActionDefinition("registrar", models.ActionSuspendDomain, getSLAForRegistrarPhishing()),
@@ -47,6 +54,7 @@ func (e *Engine) loadDefaultRules() {
 
 		// Regra para malware
 		{
+			Name:  "malware_distribution",
 			Match: []string{"malware"},
 			Actions: []ActionDefinition{
 				createActionDefinition("hosting", models.ActionRemoveContent, getSLAForSearchAndBlocklist()),
@@ -54,17 +62,50 @@ func (e *Engine) loadDefaultRules() {
 			},
 		},
 
-		// Regra para C2
+		// Regra para C2; o CERT só coordena onde há acordo de cooperação (condição de país)
 		{
+			Name:  "c2_infrastructure",
 			Match: []string{"c2"},
 			Actions: []ActionDefinition{
 				createActionDefinition("hosting", models.ActionRemoveContent, getSLAForC2Hosting()),
 				createActionDefinition("registrar", models.ActionSuspendDomain, getSLAForSearchAndBlocklist()),
+				withCountries(createActionDefinition("cert", models.ActionCoordinate, getSLAForCERT()), "br", "us", "eu"),
+			},
+		},
+
+		// Regra para IOCs de IP: sem registrar, escala do hosting para o upstream e o CERT nacional
+		{
+			Name:   "ip_infrastructure",
+			IPOnly: true,
+			Actions: []ActionDefinition{
+				createActionDefinition("hosting", models.ActionRemoveContent, getSLAForHostingStandard()),
+				createActionDefinition("upstream", models.ActionNullRoute, getSLAForUpstream()),
+				createActionDefinition("cert", models.ActionCoordinate, getSLAForCERT()),
+			},
+		},
+
+		// Regra para IOCs de email (drops e remetentes): o provedor desativa a conta
+		{
+			Name:  "email_account",
+			Email: true,
+			Actions: []ActionDefinition{
+				createActionDefinition("mailbox", models.ActionDisableAccount, getSLAForMailbox()),
+			},
+		},
+
+		// Regra para sites em plataformas: a plataforma remove o site; registrar, hosting e CDN
+		// do domínio da plataforma não são acionados
+		{
+			Name:     "platform_hosted",
+			Platform: true,
+			Actions: []ActionDefinition{
+				createActionDefinition("platform", models.ActionRemoveContent, getSLAForHostingStandard()),
 			},
 		},
 
 		// Regra para typosquatting/brand (marca)
 		{
+			Name:  "brand_protection",
 			Match: []string{"brand:*"},
 			Actions: []ActionDefinition{
 				createActionDefinition("registrar", models.ActionSuspendDomain, getSLAForBrand()),
@@ -73,6 +114,12 @@ func (e *Engine) loadDefaultRules() {
 	}
 }
 
+// withCountries restringe a ação a hostings nos países dados
+func withCountries(action ActionDefinition, countries ...string) ActionDefinition {
+	action.Countries = countries
+	return action
+}
+
 // createActionDefinition cria uma definição de ação
 func createActionDefinition(targetType string, action models.TakedownAction, sla models.SLA) ActionDefinition {
 	return ActionDefinition{
@@ -118,6 +165,33 @@ func getSLAForC2Hosting() models.SLA {
 	}
 }
 
+// getSLAForUpstream retorna SLA para o upstream, acionado quando o hosting não responde
+func getSLAForUpstream() models.SLA {
+	return models.SLA{
+		FirstResponseHours: 72,
+		EscalateAfterHours: 168,
+		RetryIntervalHours: 48,
+	}
+}
+
+// getSLAForCERT retorna SLA para coordenação com CERTs nacionais
+func getSLAForCERT() models.SLA {
+	return models.SLA{
+		FirstResponseHours: 72,
+		EscalateAfterHours: 168,
+		RetryIntervalHours: 72,
+	}
+}
+
+// getSLAForMailbox retorna SLA para provedores de email (contas drop param o kit mesmo com o site no ar)
+func getSLAForMailbox() models.SLA {
+	return models.SLA{
+		FirstResponseHours: 24,
+		EscalateAfterHours: 72,
+		RetryIntervalHours: 24,
+	}
+}
+
 // getSLAForBrand retorna SLA para casos de marca/typosquatting
 func getSLAForBrand() models.SLA {
 	return models.SLA{
@@ -133,6 +207,15 @@ func (e *Engine) DetermineActions(tags []string, contacts *models.AbuseContact)
 
 	// Encontrar regras que fazem match com as tags
 	for _, rule := range e.rules {
+		if rule.IPOnly && !contacts.IsIP() {
+			continue
+		}
+		if rule.Email != contacts.IsEmail() {
+			continue
+		}
+		if rule.Platform && !contacts.OnPlatform() {
+			continue
+		}
 		if e.matchRule(rule.Match, tags) {
 			// Aplicar ações da regra, populando com contatos reais
 			for _, actionDef := range rule.Actions {
@@ -174,34 +257,83 @@ func (e *Engine) matchRule(ruleMatch, tags []string) bool {
 
 // enrichAction enriquece uma ação com informações de contato reais
 func (e *Engine) enrichAction(actionDef ActionDefinition, contacts *models.AbuseContact) *ActionDefinition {
+	if !e.matchCountry(actionDef.Countries, contacts) {
+		return nil
+	}
+
 	enriched := actionDef
 
 	switch actionDef.Target.Type {
 	case "registrar":
-		if contacts.Registrar != nil {
+		// Em plataformas o domínio registrável é da plataforma: suspendê-lo derrubaria todos os sites
+		if contacts.Registrar != nil && contacts.Platform == nil {
 			enriched.Target.Entity = contacts.Registrar.Name
-			enriched.Target.Email = contacts.GetPrimaryAbuseEmail()
+			enriched.Target.Domain = contacts.Domain // o registrar suspende o domínio registrável
+			enriched.Target.ApplyContact(contacts.GetPrimaryAbuseContact())
 		} else {
 			return nil // Sem registrar disponível
 		}
 
 	case "hosting":
-		if contacts.Hosting != nil {
+		// Sem ASN nem contato publicado no RDAP não há provedor identificado para acionar
+		known := contacts.Hosting != nil && (contacts.Hosting.ASN != 0 || contacts.Hosting.Abuse.Email != "")
+		if known && !contacts.OnPlatform() {
 			enriched.Target.Entity = contacts.Hosting.Name
-			enriched.Target.Email = contacts.Hosting.Abuse.Email
+			enriched.Target.Domain = contacts.TargetHost()
+			enriched.Target.ApplyContact(contacts.Hosting.Abuse)
 		} else {
 			return nil // Sem hosting disponível
 		}
 
 	case "cdn":
-		if contacts.CDN != nil {
+		if contacts.CDN != nil && !contacts.OnPlatform() {
 			enriched.Target.Entity = contacts.CDN.Name
-			enriched.Target.Email = contacts.CDN.Abuse.Email
-			enriched.Target.Webform = contacts.CDN.Webform
+			enriched.Target.Domain = contacts.TargetHost()
+			enriched.Target.ApplyContact(contacts.CDN.Abuse)
+			if contacts.CDN.Webform != "" {
+				enriched.Target.Webform = contacts.CDN.Webform
+			}
 		} else {
 			return nil // Sem CDN disponível
 		}
 
+	case "upstream":
+		if contacts.Upstream != nil && contacts.Upstream.Abuse.Email != "" {
+			enriched.Target.Entity = contacts.Upstream.DisplayName()
+			enriched.Target.Domain = contacts.TargetHost()
+			enriched.Target.ApplyContact(contacts.Upstream.Abuse)
+		} else {
+			return nil // Sem alocação pai com contato próprio
+		}
+
+	case "cert":
+		if contacts.CERT != nil {
+			enriched.Target.Entity = contacts.CERT.Name
+			enriched.Target.Domain = contacts.TargetHost()
+			enriched.Target.ApplyContact(contacts.CERT.Abuse)
+		} else {
+			return nil // País sem CERT no diretório
+		}
+
+	case "mailbox":
+		if contacts.Mailbox != nil {
+			enriched.Target.Entity = contacts.Mailbox.Name
+			enriched.Target.Domain = contacts.Mailbox.Account
+			enriched.Target.ApplyContact(contacts.Mailbox.Abuse)
+		} else {
+			return nil // Sem provedor de email identificado
+		}
+
+	case "platform":
+		if contacts.OnPlatform() {
+			enriched.Target.Entity = contacts.Platform.Name
+			enriched.Target.Domain = contacts.TargetHost()
+			enriched.Target.ApplyContact(contacts.Platform.Abuse)
+			enriched.Target.API = contacts.Platform.API
+		} else {
+			return nil // Fora de plataforma catalogada
+		}
+
 	case "search":
 		// Search engines/warnings - usar contatos padrão
 		enriched.Target.Entity = "Google Safe Browsing"
@@ -219,6 +351,33 @@ func (e *Engine) enrichAction(actionDef ActionDefinition, contacts *models.Abuse
 	return &enriched
 }
 
+// euCountries lista os membros da UE para a condição "eu" de rules.yaml
+var euCountries = map[string]bool{
+	"at": true, "be": true, "bg": true, "hr": true, "cy": true, "cz": true, "dk": true,
+	"ee": true, "fi": true, "fr": true, "de": true, "gr": true, "hu": true, "ie": true,
+	"it": true, "lv": true, "lt": true, "lu": true, "mt": true, "nl": true, "pl": true,
+	"pt": true, "ro": true, "sk": true, "si": true, "es": true, "se": true,
+}
+
+// matchCountry verifica a condição de país da ação contra o país do hosting
+func (e *Engine) matchCountry(countries []string, contacts *models.AbuseContact) bool {
+	if len(countries) == 0 {
+		return true
+	}
+	if contacts.Hosting == nil || contacts.Hosting.Country == "" {
+		return false
+	}
+
+	country := strings.ToLower(contacts.Hosting.Country)
+	for _, allowed := range countries {
+		allowed = strings.ToLower(allowed)
+		if allowed == country || (allowed == "eu" && euCountries[country]) {
+			return true
+		}
+	}
+	return false
+}
+
 // prioritizeActions remove duplicatas e prioriza ações
 func (e *Engine) prioritizeActions(actions []ActionDefinition) []ActionDefinition {
 	// Mapa para remover duplicatas baseado no tipo de target
@@ -229,8 +388,12 @@ func (e *Engine) prioritizeActions(actions []ActionDefinition) []ActionDefinitio
 		"hosting":   1, // Mais rápido para remover conteúdo
 		"cdn":       2, // CDN pode ser rápido também
 		"registrar": 3, // Registrar é mais demorado mas mais efetivo
-		"search":    4, // Warnings são complementares
-		"blocklist": 5, // Blocklists são complementares
+		"mailbox":   1, // Desativar a conta drop corta a exfiltração
+		"platform":  1, // A plataforma remove o site do cliente
+		"upstream":  4, // Upstream só age sobre IPs quando o hosting não responde
+		"search":    5, // Warnings são complementares
+		"blocklist": 6, // Blocklists são complementares
+		"cert":      7, // CERTs coordenam, não removem
 	}
 
 	for _, action := range actions {
@@ -242,11 +405,18 @@ func (e *Engine) prioritizeActions(actions []ActionDefinition) []ActionDefinitio
 		}
 	}
 
-	// Converter de volta para slice
+	// Converter de volta para slice, na ordem de prioridade (o primeiro target é o acionado)
 	var result []ActionDefinition
 	for _, action := range seen {
 		result = append(result, action)
 	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].Target.Type, result[j].Target.Type
+		if priority[a] != priority[b] {
+			return priority[a] < priority[b]
+		}
+		return a < b
+	})
 
 	return result
 }
@@ -1,6 +1,8 @@
 package routing
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/cti-team/takedown/pkg/models"
@@ -153,6 +155,73 @@ func TestEngine_DetermineActions_C2(t *testing.T) {
 	}
 }
 
+func TestEngine_DetermineActions_IP(t *testing.T) {
+	engine := NewEngine()
+
+	contacts := &models.AbuseContact{
+		Host: "203.0.113.10",
+		Hosting: &models.HostingInfo{
+			ASN:   64500,
+			Name:  "Example Hosting",
+			Abuse: models.ContactInfo{Email: "security@customer.example", Source: models.ContactSourceRDAP},
+		},
+		Upstream: &models.NetworkInfo{
+			Handle: "NET-203-0-0-0-1",
+			Org:    "Transit Telecom S.A.",
+			Abuse:  models.ContactInfo{Email: "abuse@transit.example", Source: models.ContactSourceRDAP},
+		},
+		CERT: &models.CERTInfo{Name: "CERT.br", Country: "BR", Abuse: models.ContactInfo{Email: "cert@cert.br"}},
+	}
+
+	tests := []struct {
+		name        string
+		tags        []string
+		contacts    *models.AbuseContact
+		wantTypes   []string
+		wantHosting int // FirstResponseHours do hosting
+	}{
+		{name: "c2 on bare ip", tags: []string{"c2"}, contacts: contacts, wantTypes: []string{"hosting", "upstream", "cert"}, wantHosting: 12},
+		{name: "untagged ip", contacts: contacts, wantTypes: []string{"hosting", "upstream", "cert"}, wantHosting: 48},
+		{
+			name: "ip without upstream or cert", tags: []string{"malware"},
+			contacts:  &models.AbuseContact{Host: "203.0.113.10", Hosting: contacts.Hosting},
+			wantTypes: []string{"hosting", "blocklist"}, wantHosting: 24,
+		},
+		{
+			name: "domain ignores ip rule", tags: []string{"c2"},
+			contacts:  &models.AbuseContact{Domain: "c2.example", Host: "c2.example", Hosting: contacts.Hosting, CERT: contacts.CERT},
+			wantTypes: []string{"hosting"}, wantHosting: 12,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actions := engine.DetermineActions(tt.tags, tt.contacts)
+			var types []string
+			for _, action := range actions {
+				types = append(types, action.Target.Type)
+				if action.Target.Type == "hosting" && action.SLA.FirstResponseHours != tt.wantHosting {
+					t.Errorf("Expected %dh hosting SLA, got %d", tt.wantHosting, action.SLA.FirstResponseHours)
+				}
+				if action.Target.Type != "blocklist" && action.Target.Domain != tt.contacts.Host {
+					t.Errorf("Expected %s target on %s, got %q", action.Target.Type, tt.contacts.Host, action.Target.Domain)
+				}
+			}
+			if strings.Join(types, ",") != strings.Join(tt.wantTypes, ",") {
+				t.Errorf("Expected targets %v in priority order, got %v", tt.wantTypes, types)
+			}
+		})
+	}
+
+	actions := engine.DetermineActions([]string{"c2"}, contacts)
+	if upstream := actions[1]; upstream.Action != models.ActionNullRoute || upstream.Target.Entity != "Transit Telecom S.A." || upstream.Target.Email != "abuse@transit.example" {
+		t.Errorf("Unexpected upstream action: %+v", upstream)
+	}
+	if cert := actions[2]; cert.Action != models.ActionCoordinate || cert.Target.Email != "cert@cert.br" {
+		t.Errorf("Unexpected CERT action: %+v", cert)
+	}
+}
+
 func TestEngine_DetermineActions_Brand(t *testing.T) {
 	engine := NewEngine()
 
@@ -337,6 +406,30 @@ func TestEngine_EnrichAction(t *testing.T) {
 	}
 }
 
+func TestEngine_TargetDomains(t *testing.T) {
+	engine := NewEngine()
+	contacts := &models.AbuseContact{
+		Domain:    "example.com.br",
+		Host:      "login.bank.example.com.br",
+		Registrar: &models.RegistrarInfo{Name: "Registro.br"},
+		Abuse:     models.ContactInfo{Email: "abuse@registro.br"},
+		Hosting:   &models.HostingInfo{Name: "Example Hosting", Abuse: models.ContactInfo{Email: "abuse@examplehosting.com"}},
+	}
+
+	for _, action := range engine.DetermineActions([]string{"phishing"}, contacts) {
+		switch action.Target.Type {
+		case "registrar":
+			if action.Target.Domain != "example.com.br" {
+				t.Errorf("Expected registrar to target the registrable domain, got %q", action.Target.Domain)
+			}
+		case "hosting":
+			if action.Target.Domain != "login.bank.example.com.br" {
+				t.Errorf("Expected hosting to target the full host, got %q", action.Target.Domain)
+			}
+		}
+	}
+}
+
 func TestEngine_PrioritizeActions(t *testing.T) {
 	engine := NewEngine()
 
@@ -472,3 +565,133 @@ func TestEngine_LoadDefaultRules(t *testing.T) {
 		}
 	}
 }
+
+func TestEngine_CountryCondition(t *testing.T) {
+	engine := &Engine{}
+	engine.AddRule(Rule{
+		Match: []string{"c2"},
+		Actions: []ActionDefinition{
+			{
+				Target:    models.TakedownTarget{Type: "hosting"},
+				Action:    models.ActionRemoveContent,
+				Countries: []string{"br", "eu"},
+			},
+		},
+	})
+
+	tests := []struct {
+		name     string
+		hosting  *models.HostingInfo
+		expected int
+	}{
+		{"Matching country", &models.HostingInfo{ASN: 64500, Name: "BR Host", Country: "BR"}, 1},
+		{"EU member via eu alias", &models.HostingInfo{ASN: 64501, Name: "DE Host", Country: "DE"}, 1},
+		{"Non-matching country", &models.HostingInfo{ASN: 64502, Name: "US Host", Country: "US"}, 0},
+		{"Unknown country", &models.HostingInfo{ASN: 64503, Name: "Unknown Host"}, 0},
+		{"Country only, no provider", &models.HostingInfo{Country: "BR"}, 0},
+		{"RDAP abuse contact without ASN", &models.HostingInfo{Name: "BR-NET", Country: "BR", Abuse: models.ContactInfo{Email: "abuse@br-net.com.br"}}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actions := engine.DetermineActions([]string{"c2"}, &models.AbuseContact{Hosting: tt.hosting})
+			if len(actions) != tt.expected {
+				t.Errorf("Expected %d actions, got %d", tt.expected, len(actions))
+			}
+		})
+	}
+}
+
+func TestEngine_DetermineActions_Email(t *testing.T) {
+	engine := NewEngine()
+
+	contacts := &models.AbuseContact{
+		Host: "gmail.com",
+		Mailbox: &models.MailboxInfo{
+			Name:    "Google (Gmail)",
+			Account: "acme.suporte@gmail.com",
+			Abuse:   models.ContactInfo{Webform: "https://support.google.com/mail/contact/abuse", Source: models.ContactSourceDirectory},
+		},
+	}
+
+	actions := engine.DetermineActions([]string{"phishing", "brand:AcmeBank"}, contacts)
+	if len(actions) != 1 {
+		t.Fatalf("Expected only the mailbox action for an email IOC, got %+v", actions)
+	}
+	mailbox := actions[0]
+	if mailbox.Target.Type != "mailbox" || mailbox.Action != models.ActionDisableAccount {
+		t.Errorf("Unexpected action: %+v", mailbox)
+	}
+	if mailbox.Target.Domain != "acme.suporte@gmail.com" || mailbox.Target.Entity != "Google (Gmail)" || mailbox.Target.Webform == "" {
+		t.Errorf("Expected mailbox target for the account, got %+v", mailbox.Target)
+	}
+
+	// Domínios não recebem a regra de email
+	domain := &models.AbuseContact{Domain: "acme-login.example", Host: "acme-login.example", Mailbox: &models.MailboxInfo{Name: "Example Mail"}}
+	for _, action := range engine.DetermineActions([]string{"phishing"}, domain) {
+		if action.Target.Type == "mailbox" {
+			t.Errorf("Unexpected mailbox action for a domain: %+v", action)
+		}
+	}
+}
+
+func TestEngine_DetermineActions_Platform(t *testing.T) {
+	engine := NewEngine()
+
+	tests := []struct {
+		name      string
+		platform  *models.PlatformInfo
+		wantTypes []string
+	}{
+		{
+			name: "catalogued platform replaces registrar, hosting and cdn",
+			platform: &models.PlatformInfo{
+				Name:   "GitHub, Inc.",
+				Suffix: "github.io",
+				Site:   "acme-login.github.io",
+				Abuse:  models.ContactInfo{Webform: "https://support.github.com/contact/report-abuse", Source: models.ContactSourceDirectory},
+			},
+			wantTypes: []string{"platform", "search", "blocklist"},
+		},
+		{
+			name:      "uncatalogued private suffix keeps hosting but never the registrar",
+			platform:  &models.PlatformInfo{Name: "pages.example", Suffix: "pages.example", Site: "acme-login.pages.example"},
+			wantTypes: []string{"hosting", "search", "blocklist"},
+		},
+		{
+			name:      "regular domain",
+			wantTypes: []string{"hosting", "registrar", "search", "blocklist"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contacts := &models.AbuseContact{
+				Domain:    "github.io",
+				Host:      "acme-login.github.io",
+				Registrar: &models.RegistrarInfo{Name: "MarkMonitor Inc."},
+				Abuse:     models.ContactInfo{Email: "abusecomplaints@markmonitor.com"},
+				Hosting:   &models.HostingInfo{Name: "Fastly", Abuse: models.ContactInfo{Email: "abuse@fastly.com"}},
+				CDN:       &models.CDNInfo{Name: "Fastly", Abuse: models.ContactInfo{Email: "abuse@fastly.com"}},
+				Platform:  tt.platform,
+			}
+
+			actions := engine.DetermineActions([]string{"phishing", "brand:AcmeBank"}, contacts)
+			var types []string
+			for _, action := range actions {
+				types = append(types, action.Target.Type)
+			}
+			if !reflect.DeepEqual(types, tt.wantTypes) {
+				t.Fatalf("Expected targets %v, got %v", tt.wantTypes, types)
+			}
+
+			if tt.platform.HasContact() {
+				platform := actions[0]
+				if platform.Action != models.ActionRemoveContent || platform.Target.Entity != "GitHub, Inc." ||
+					platform.Target.Domain != "acme-login.github.io" || platform.Target.Webform == "" {
+					t.Errorf("Unexpected platform action: %+v", platform)
+				}
+			}
+		})
+	}
+}
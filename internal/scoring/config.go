@@ -0,0 +1,78 @@
+package scoring
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config define os pesos dos sinais e os parâmetros usados por eles
+type Config struct {
+	Weights         map[string]int     `yaml:"signals"`
+	YoungDomainDays int                `yaml:"young_domain_days"`
+	FreshCertDays   int                `yaml:"fresh_cert_days"`
+	SuspiciousTLDs  []string           `yaml:"suspicious_tlds"`
+	Brands          []string           `yaml:"brands"`
+	FeedReputation  map[string]float64 `yaml:"feed_reputation"`
+}
+
+// DefaultConfig retorna a configuração padrão (espelha configs/scoring/weights.yaml)
+func DefaultConfig() Config {
+	return Config{
+		Weights: map[string]int{
+			SignalDomainAge:       25,
+			SignalFreshTLSCert:    10,
+			SignalBrandKeywords:   20,
+			SignalPasswordForm:    20,
+			SignalSuspiciousTLD:   10,
+			SignalRedirectChain:   5,
+			SignalFeedReputation:  15,
+			SignalCredentialExfil: 15,
+			SignalPhishingKit:     15,
+			SignalCloaking:        15,
+		},
+		YoungDomainDays: 90,
+		FreshCertDays:   30,
+		SuspiciousTLDs: []string{
+			"zip", "mov", "top", "xyz", "icu", "click", "link", "live", "shop", "online",
+			"site", "buzz", "cfd", "sbs", "rest", "monster", "tk", "ml", "ga", "cf", "gq",
+		},
+		FeedReputation: map[string]float64{
+			"manual":    1.0,
+			"openphish": 0.9,
+			"phishtank": 0.8,
+			"urlhaus":   0.9,
+		},
+	}
+}
+
+// LoadConfig carrega a configuração de um arquivo YAML sobre os valores padrão
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- caminho vem da configuração do operador
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read scoring config: %w", err)
+	}
+	return ParseConfig(data)
+}
+
+// ParseConfig interpreta a configuração em YAML; campos ausentes mantêm o padrão
+func ParseConfig(data []byte) (Config, error) {
+	config := DefaultConfig()
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return Config{}, fmt.Errorf("failed to parse scoring config: %w", err)
+	}
+
+	for name, weight := range config.Weights {
+		if weight < 0 || weight > 100 {
+			return Config{}, fmt.Errorf("signal %q: weight %d out of range 0-100", name, weight)
+		}
+	}
+	for source, reputation := range config.FeedReputation {
+		if reputation < 0 || reputation > 1 {
+			return Config{}, fmt.Errorf("feed %q: reputation %.2f out of range 0-1", source, reputation)
+		}
+	}
+
+	return config, nil
+}
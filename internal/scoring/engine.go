@@ -0,0 +1,150 @@
+package scoring
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// Input reúne os dados disponíveis para o cálculo do risco
+type Input struct {
+	IOC      *models.IOC
+	Evidence *models.EvidencePack
+	Contact  *models.AbuseContact // dados de registro (RDAP/WHOIS), se já enriquecido
+	Body     string               // corpo completo da página final, se o chamador o tem (senão vale o preview de 1KB)
+	Now      time.Time
+}
+
+// Signal é um indicador de risco; Evaluate retorna a intensidade (0-1) e o detalhe observado
+type Signal interface {
+	Name() string
+	Evaluate(input *Input, config *Config) (strength float64, detail string)
+}
+
+// Contribution é a parcela de um sinal no score final
+type Contribution struct {
+	Signal string  `json:"signal"`
+	Points int     `json:"points"`
+	Weight int     `json:"weight"`
+	Value  float64 `json:"strength"`
+	Detail string  `json:"detail"`
+}
+
+// Result é o resultado detalhado da avaliação
+type Result struct {
+	Score         int            `json:"score"`
+	Category      string         `json:"category"`
+	Contributions []Contribution `json:"contributions"`
+}
+
+// Engine calcula o score de risco a partir de sinais com pesos configuráveis
+type Engine struct {
+	config  Config
+	signals []Signal
+}
+
+// NewEngine cria uma engine com os sinais padrão
+func NewEngine(config Config) *Engine {
+	engine := &Engine{config: config}
+	for _, signal := range DefaultSignals() {
+		engine.Register(signal)
+	}
+	return engine
+}
+
+// Register adiciona um sinal (o peso vem de config.Weights pelo nome do sinal)
+func (e *Engine) Register(signal Signal) {
+	e.signals = append(e.signals, signal)
+}
+
+// Evaluate calcula o score e as contribuições de cada sinal
+func (e *Engine) Evaluate(input Input) Result {
+	if input.Now.IsZero() {
+		input.Now = time.Now().UTC()
+	}
+
+	result := Result{Category: category(input.IOC)}
+	total := 0
+
+	for _, signal := range e.signals {
+		weight := e.config.Weights[signal.Name()]
+		if weight <= 0 {
+			continue
+		}
+
+		strength, detail := signal.Evaluate(&input, &e.config)
+		if strength <= 0 {
+			continue
+		}
+		strength = math.Min(strength, 1)
+
+		points := int(math.Round(float64(weight) * strength))
+		if points == 0 {
+			continue
+		}
+
+		total += points
+		result.Contributions = append(result.Contributions, Contribution{
+			Signal: signal.Name(),
+			Points: points,
+			Weight: weight,
+			Value:  strength,
+			Detail: detail,
+		})
+	}
+
+	// Maiores contribuições primeiro na rationale
+	sort.SliceStable(result.Contributions, func(i, j int) bool {
+		return result.Contributions[i].Points > result.Contributions[j].Points
+	})
+
+	if total > 100 {
+		total = 100
+	}
+	result.Score = total
+
+	return result
+}
+
+// Assess calcula o RiskAssessment do EvidencePack com a rationale de cada sinal
+func (e *Engine) Assess(input Input) models.RiskAssessment {
+	result := e.Evaluate(input)
+	return models.RiskAssessment{
+		Score:     result.Score,
+		Rationale: result.Rationale(),
+		Category:  result.Category,
+	}
+}
+
+// Rationale descreve os sinais que contribuíram para o score
+func (r Result) Rationale() string {
+	if len(r.Contributions) == 0 {
+		return "No risk signals observed"
+	}
+
+	parts := make([]string, 0, len(r.Contributions))
+	for _, contribution := range r.Contributions {
+		parts = append(parts, fmt.Sprintf("%s (+%d): %s", contribution.Signal, contribution.Points, contribution.Detail))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// category deriva a categoria das tags do IOC
+func category(ioc *models.IOC) string {
+	if ioc == nil {
+		return "suspicious"
+	}
+	for _, candidate := range []string{"phishing", "malware", "c2"} {
+		if ioc.HasTag(candidate) {
+			return candidate
+		}
+	}
+	if ioc.GetBrand() != "" {
+		return "brand"
+	}
+	return "suspicious"
+}
@@ -0,0 +1,291 @@
+package scoring
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+var now = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func phishingInput() Input {
+	created := now.Add(-3 * 24 * time.Hour)
+	return Input{
+		IOC: &models.IOC{
+			Type:   models.IOCTypeURL,
+			Value:  "https://acme-login.xyz/start",
+			Source: "openphish",
+			Tags:   []string{"phishing", "brand:AcmeBank"},
+		},
+		Evidence: &models.EvidencePack{
+			HTTP: models.HTTPInfo{
+				Status: 200,
+				Title:  "AcmeBank - Sign in",
+				Body:   `<form><input name="user"><input type="password" name="pass"></form>`,
+				Chain:  []string{"https://bit.example/x", "https://acme-login.xyz/start", "https://acme-login.xyz/login"},
+			},
+			TLS: &models.TLSInfo{Issuer: "CN=R3,O=Let's Encrypt,C=US", NotBefore: now.Add(-2 * 24 * time.Hour)},
+		},
+		Contact: &models.AbuseContact{CreatedAt: &created},
+		Now:     now,
+	}
+}
+
+func TestEngine_Evaluate_AllSignals(t *testing.T) {
+	engine := NewEngine(DefaultConfig())
+	result := engine.Evaluate(phishingInput())
+
+	expected := map[string]int{
+		SignalDomainAge:      25,
+		SignalFreshTLSCert:   10,
+		SignalBrandKeywords:  20,
+		SignalPasswordForm:   20,
+		SignalSuspiciousTLD:  10,
+		SignalRedirectChain:  3,
+		SignalFeedReputation: 14,
+	}
+
+	if len(result.Contributions) != len(expected) {
+		t.Fatalf("Expected %d contributions, got %+v", len(expected), result.Contributions)
+	}
+	for _, contribution := range result.Contributions {
+		if expected[contribution.Signal] != contribution.Points {
+			t.Errorf("%s: expected %d points, got %d", contribution.Signal, expected[contribution.Signal], contribution.Points)
+		}
+	}
+	if result.Score != 100 {
+		t.Errorf("Expected score capped at 100, got %d", result.Score)
+	}
+	if result.Category != "phishing" {
+		t.Errorf("Expected phishing category, got %s", result.Category)
+	}
+}
+
+func TestEngine_Assess_Rationale(t *testing.T) {
+	engine := NewEngine(DefaultConfig())
+	risk := engine.Assess(phishingInput())
+
+	for _, fragment := range []string{
+		"domain_age (+25): domain registered 3 days ago",
+		"fresh_tls_cert (+10): TLS certificate issued 2 days ago by R3",
+		`brand_keywords (+20): brand "AcmeBank" in page title`,
+		"password_form (+20)",
+		"suspicious_tld (+10): TLD .xyz",
+		"redirect_chain (+3): 2 redirects across 2 hosts",
+		"feed_reputation (+14): reported by openphish",
+	} {
+		if !strings.Contains(risk.Rationale, fragment) {
+			t.Errorf("Rationale missing %q: %s", fragment, risk.Rationale)
+		}
+	}
+	if !strings.HasPrefix(risk.Rationale, "domain_age") {
+		t.Errorf("Expected largest contribution first: %s", risk.Rationale)
+	}
+}
+
+func TestEngine_NoSignals(t *testing.T) {
+	engine := NewEngine(DefaultConfig())
+	risk := engine.Assess(Input{
+		IOC:      &models.IOC{Type: models.IOCTypeURL, Value: "https://example.com/"},
+		Evidence: &models.EvidencePack{HTTP: models.HTTPInfo{Title: "Example Domain"}},
+		Now:      now,
+	})
+
+	if risk.Score != 0 || risk.Rationale != "No risk signals observed" || risk.Category != "suspicious" {
+		t.Errorf("Unexpected assessment: %+v", risk)
+	}
+}
+
+func TestEngine_WeightsFromConfig(t *testing.T) {
+	config, err := ParseConfig([]byte(`
+signals:
+  password_form: 50
+  suspicious_tld: 0
+young_domain_days: 10
+`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	input := phishingInput()
+	old := now.Add(-60 * 24 * time.Hour)
+	input.Contact.CreatedAt = &old
+
+	result := NewEngine(config).Evaluate(input)
+	points := map[string]int{}
+	for _, contribution := range result.Contributions {
+		points[contribution.Signal] = contribution.Points
+	}
+
+	if points[SignalPasswordForm] != 50 {
+		t.Errorf("Expected configured weight 50, got %d", points[SignalPasswordForm])
+	}
+	if _, found := points[SignalSuspiciousTLD]; found {
+		t.Error("Expected signal with weight 0 to be disabled")
+	}
+	if _, found := points[SignalDomainAge]; found {
+		t.Error("Expected 60-day-old domain not to score with young_domain_days=10")
+	}
+	if points[SignalBrandKeywords] != 20 {
+		t.Errorf("Expected default weight to be kept, got %d", points[SignalBrandKeywords])
+	}
+}
+
+func TestFeedReputation_Confidence(t *testing.T) {
+	tests := []struct {
+		name       string
+		ioc        *models.IOC
+		wantPoints int
+		wantDetail string
+	}{
+		{name: "configured reputation", ioc: &models.IOC{Source: "phishtank"}, wantPoints: 12, wantDetail: "reputation 0.80"},
+		{name: "feed confidence wins", ioc: &models.IOC{Source: "phishtank", Confidence: 40}, wantPoints: 6, wantDetail: "confidence 40"},
+		{name: "confidence from unknown source", ioc: &models.IOC{Source: "partner-feed", Confidence: 100}, wantPoints: 15, wantDetail: "reported by partner-feed"},
+		{name: "unknown source", ioc: &models.IOC{Source: "partner-feed"}},
+	}
+
+	engine := NewEngine(DefaultConfig())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := engine.Evaluate(Input{IOC: tt.ioc, Now: now})
+			points, detail := 0, ""
+			for _, contribution := range result.Contributions {
+				if contribution.Signal == SignalFeedReputation {
+					points, detail = contribution.Points, contribution.Detail
+				}
+			}
+			if points != tt.wantPoints || !strings.Contains(detail, tt.wantDetail) {
+				t.Errorf("Expected %d points (%q), got %d (%q)", tt.wantPoints, tt.wantDetail, points, detail)
+			}
+		})
+	}
+}
+
+func TestParseConfig_Invalid(t *testing.T) {
+	for _, data := range []string{
+		"signals:\n  domain_age: 150\n",
+		"feed_reputation:\n  openphish: 2\n",
+		"signals: [",
+	} {
+		if _, err := ParseConfig([]byte(data)); err == nil {
+			t.Errorf("Expected error for %q", data)
+		}
+	}
+}
+
+func TestLoadConfig_RepositoryFile(t *testing.T) {
+	config, err := LoadConfig(filepath.Join("..", "..", "configs", "scoring", "weights.yaml"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for name, weight := range DefaultConfig().Weights {
+		if config.Weights[name] != weight {
+			t.Errorf("%s: weights.yaml has %d, DefaultConfig has %d", name, config.Weights[name], weight)
+		}
+	}
+}
+
+// customSignal verifica que sinais externos podem ser registrados
+type customSignal struct{}
+
+func (customSignal) Name() string { return "custom" }
+
+func (customSignal) Evaluate(_ *Input, _ *Config) (float64, string) {
+	return 0.5, "custom detail"
+}
+
+func TestEngine_RegisterCustomSignal(t *testing.T) {
+	config := DefaultConfig()
+	config.Weights["custom"] = 30
+
+	engine := NewEngine(config)
+	engine.Register(customSignal{})
+
+	result := engine.Evaluate(Input{Now: now})
+	if result.Score != 15 || !strings.Contains(result.Rationale(), "custom (+15): custom detail") {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+}
+
+func TestPasswordForm_UsesFullBody(t *testing.T) {
+	body := strings.Repeat("<p>filler</p>", 200) + `<input type='password' name='pin'>`
+	preview := &Input{Evidence: &models.EvidencePack{HTTP: models.HTTPInfo{Body: body[:1024]}}}
+	if strength, _ := (passwordFormSignal{}).Evaluate(preview, nil); strength != 0 {
+		t.Error("Expected no password input within the 1KB preview")
+	}
+
+	input := &Input{Evidence: preview.Evidence, Body: body}
+	if strength, _ := (passwordFormSignal{}).Evaluate(input, nil); strength != 1 {
+		t.Error("Expected password input beyond the 1KB preview to be found in the full body")
+	}
+}
+
+func TestEngine_ContentAnalysisSignals(t *testing.T) {
+	input := Input{
+		IOC: &models.IOC{Type: models.IOCTypeURL, Value: "https://login.example.net/", Tags: []string{"brand:AcmeBank"}},
+		Evidence: &models.EvidencePack{
+			HTTP: models.HTTPInfo{Title: "Sign in"},
+			Content: &models.ContentAnalysis{
+				HasLoginForm:    true,
+				HasPasswordForm: true,
+				Forms:           []models.FormFinding{{Action: "https://drop.example.org/next.php", Method: "POST", PasswordFields: 1, Login: true, CrossDomain: true}},
+				ExternalPosts:   []string{"https://drop.example.org/next.php"},
+				Brands:          []string{"AcmeBank"},
+				KitMarkers:      []models.KitMarker{{Name: "16shop"}, {Name: "antibot"}},
+			},
+		},
+		Now: now,
+	}
+
+	result := NewEngine(DefaultConfig()).Evaluate(input)
+	points := map[string]int{}
+	for _, contribution := range result.Contributions {
+		points[contribution.Signal] = contribution.Points
+	}
+
+	expected := map[string]int{
+		SignalBrandKeywords:   20,
+		SignalPasswordForm:    20,
+		SignalCredentialExfil: 15,
+		SignalPhishingKit:     12,
+	}
+	for signal, want := range expected {
+		if points[signal] != want {
+			t.Errorf("%s: expected %d points, got %d", signal, want, points[signal])
+		}
+	}
+	for _, fragment := range []string{
+		`brand "AcmeBank" impersonated on page`,
+		"credential form posts to https://drop.example.org/next.php",
+		"phishing kit markers: 16shop, antibot",
+	} {
+		if !strings.Contains(result.Rationale(), fragment) {
+			t.Errorf("Rationale missing %q: %s", fragment, result.Rationale())
+		}
+	}
+
+	// A análise de conteúdo prevalece sobre a busca por regex no corpo
+	input.Evidence.Content = &models.ContentAnalysis{}
+	input.Evidence.HTTP.Body = `<input type="password">`
+	if strength, _ := (passwordFormSignal{}).Evaluate(&input, nil); strength != 0 {
+		t.Errorf("Expected content analysis to take precedence, got strength %.2f", strength)
+	}
+}
+
+func TestCloakingSignal(t *testing.T) {
+	input := &Input{Evidence: &models.EvidencePack{Cloaking: &models.CloakingResult{
+		Detected: true, Baseline: "desktop", VictimProfile: "mobile", Reasons: []string{"mobile vs desktop: title"},
+	}}}
+	strength, detail := (cloakingSignal{}).Evaluate(input, nil)
+	if strength != 1 || detail != "content served to mobile differs from desktop" {
+		t.Errorf("Unexpected cloaking evaluation: %.2f %q", strength, detail)
+	}
+
+	input.Evidence.Cloaking.Detected = false
+	if strength, _ := (cloakingSignal{}).Evaluate(input, nil); strength != 0 {
+		t.Errorf("Expected no points without cloaking, got %.2f", strength)
+	}
+}
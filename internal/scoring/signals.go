@@ -0,0 +1,335 @@
+package scoring
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Nomes dos sinais padrão (chaves de signals em configs/scoring/weights.yaml)
+const (
+	SignalDomainAge       = "domain_age"
+	SignalFreshTLSCert    = "fresh_tls_cert"
+	SignalBrandKeywords   = "brand_keywords"
+	SignalPasswordForm    = "password_form"
+	SignalSuspiciousTLD   = "suspicious_tld"
+	SignalRedirectChain   = "redirect_chain"
+	SignalFeedReputation  = "feed_reputation"
+	SignalCredentialExfil = "credential_exfiltration"
+	SignalPhishingKit     = "phishing_kit"
+	SignalCloaking        = "cloaking"
+)
+
+// passwordInputPattern detecta campos de senha em formulários
+var passwordInputPattern = regexp.MustCompile(`(?i)<input[^>]+type\s*=\s*["']?password`)
+
+// DefaultSignals retorna os sinais embutidos
+func DefaultSignals() []Signal {
+	return []Signal{
+		domainAgeSignal{},
+		freshCertSignal{},
+		brandKeywordSignal{},
+		passwordFormSignal{},
+		suspiciousTLDSignal{},
+		redirectChainSignal{},
+		feedReputationSignal{},
+		credentialExfilSignal{},
+		phishingKitSignal{},
+		cloakingSignal{},
+	}
+}
+
+// domainAgeSignal pontua domínios registrados recentemente
+type domainAgeSignal struct{}
+
+func (domainAgeSignal) Name() string { return SignalDomainAge }
+
+func (domainAgeSignal) Evaluate(input *Input, config *Config) (float64, string) {
+	if input.Contact == nil || input.Contact.CreatedAt == nil || config.YoungDomainDays <= 0 {
+		return 0, ""
+	}
+
+	days := daysBetween(*input.Contact.CreatedAt, input.Now)
+	if days > config.YoungDomainDays {
+		return 0, ""
+	}
+	return ageStrength(days, config.YoungDomainDays), fmt.Sprintf("domain registered %d days ago", days)
+}
+
+// freshCertSignal pontua certificados TLS emitidos há poucos dias
+type freshCertSignal struct{}
+
+func (freshCertSignal) Name() string { return SignalFreshTLSCert }
+
+func (freshCertSignal) Evaluate(input *Input, config *Config) (float64, string) {
+	if input.Evidence == nil || input.Evidence.TLS == nil || input.Evidence.TLS.NotBefore.IsZero() || config.FreshCertDays <= 0 {
+		return 0, ""
+	}
+
+	days := daysBetween(input.Evidence.TLS.NotBefore, input.Now)
+	if days > config.FreshCertDays {
+		return 0, ""
+	}
+
+	detail := fmt.Sprintf("TLS certificate issued %d days ago", days)
+	if input.Evidence.TLS.Issuer != "" {
+		detail += " by " + issuerName(input.Evidence.TLS.Issuer)
+	}
+	return ageStrength(days, config.FreshCertDays), detail
+}
+
+// brandKeywordSignal pontua marcas monitoradas citadas na página
+type brandKeywordSignal struct{}
+
+func (brandKeywordSignal) Name() string { return SignalBrandKeywords }
+
+func (brandKeywordSignal) Evaluate(input *Input, config *Config) (float64, string) {
+	brands := append([]string(nil), config.Brands...)
+	if input.IOC != nil && input.IOC.GetBrand() != "" {
+		brands = append(brands, input.IOC.GetBrand())
+	}
+	if len(brands) == 0 || input.Evidence == nil {
+		return 0, ""
+	}
+
+	// Marcas identificadas pela análise de conteúdo (título, og:site_name, logos)
+	if content := input.Evidence.Content; content != nil {
+		for _, brand := range brands {
+			for _, found := range content.Brands {
+				if strings.EqualFold(strings.TrimSpace(brand), found) {
+					return 1, fmt.Sprintf("brand %q impersonated on page", found)
+				}
+			}
+		}
+	}
+
+	title := strings.ToLower(input.Evidence.HTTP.Title)
+	body := strings.ToLower(pageBody(input))
+	host := strings.ToLower(targetHost(input))
+
+	for _, brand := range brands {
+		keyword := strings.ToLower(strings.TrimSpace(brand))
+		if keyword == "" {
+			continue
+		}
+		switch {
+		case strings.Contains(title, keyword):
+			return 1, fmt.Sprintf("brand %q in page title %q", brand, input.Evidence.HTTP.Title)
+		case strings.Contains(body, keyword):
+			return 0.7, fmt.Sprintf("brand %q in page content", brand)
+		case strings.Contains(host, keyword):
+			return 0.5, fmt.Sprintf("brand %q in hostname %s", brand, host)
+		}
+	}
+	return 0, ""
+}
+
+// passwordFormSignal pontua páginas com campo de senha
+type passwordFormSignal struct{}
+
+func (passwordFormSignal) Name() string { return SignalPasswordForm }
+
+func (passwordFormSignal) Evaluate(input *Input, _ *Config) (float64, string) {
+	if input.Evidence != nil && input.Evidence.Content != nil {
+		content := input.Evidence.Content
+		switch {
+		case content.HasPasswordForm:
+			return 1, "page contains a password form"
+		case content.HasLoginForm:
+			return 0.5, "page contains a login form without password field"
+		}
+		return 0, ""
+	}
+	if passwordInputPattern.MatchString(pageBody(input)) {
+		return 1, "page contains a password input"
+	}
+	return 0, ""
+}
+
+// suspiciousTLDSignal pontua TLDs com alto índice de abuso
+type suspiciousTLDSignal struct{}
+
+func (suspiciousTLDSignal) Name() string { return SignalSuspiciousTLD }
+
+func (suspiciousTLDSignal) Evaluate(input *Input, config *Config) (float64, string) {
+	host := targetHost(input)
+	idx := strings.LastIndex(host, ".")
+	if idx < 0 {
+		return 0, ""
+	}
+
+	tld := strings.ToLower(host[idx+1:])
+	for _, suspicious := range config.SuspiciousTLDs {
+		if strings.EqualFold(strings.TrimPrefix(suspicious, "."), tld) {
+			return 1, fmt.Sprintf("TLD .%s is frequently abused", tld)
+		}
+	}
+	return 0, ""
+}
+
+// redirectChainSignal pontua cadeias de redirect (3 ou mais saltos recebem o peso total)
+type redirectChainSignal struct{}
+
+func (redirectChainSignal) Name() string { return SignalRedirectChain }
+
+func (redirectChainSignal) Evaluate(input *Input, _ *Config) (float64, string) {
+	if input.Evidence == nil || len(input.Evidence.HTTP.Chain) < 2 {
+		return 0, ""
+	}
+
+	chain := input.Evidence.HTTP.Chain
+	hops := len(chain) - 1
+	hosts := map[string]bool{}
+	for _, hop := range chain {
+		if parsed, err := url.Parse(hop); err == nil {
+			hosts[strings.ToLower(parsed.Hostname())] = true
+		}
+	}
+
+	detail := fmt.Sprintf("%d redirects across %d hosts before the final page", hops, len(hosts))
+	return float64(hops) / 3, detail
+}
+
+// feedReputationSignal pontua conforme a confiança informada pela fonte ou a reputação configurada dela
+type feedReputationSignal struct{}
+
+func (feedReputationSignal) Name() string { return SignalFeedReputation }
+
+func (feedReputationSignal) Evaluate(input *Input, config *Config) (float64, string) {
+	if input.IOC == nil || input.IOC.Source == "" {
+		return 0, ""
+	}
+	// Confiança informada pelo feed prevalece sobre a reputação fixa da fonte
+	if input.IOC.Confidence > 0 {
+		confidence := float64(input.IOC.Confidence) / 100
+		return confidence, fmt.Sprintf("reported by %s (confidence %d)", input.IOC.Source, input.IOC.Confidence)
+	}
+
+	reputation, ok := config.FeedReputation[strings.ToLower(input.IOC.Source)]
+	if !ok {
+		return 0, ""
+	}
+	return reputation, fmt.Sprintf("reported by %s (reputation %.2f)", input.IOC.Source, reputation)
+}
+
+// credentialExfilSignal pontua páginas que enviam dados para outros domínios, bots do Telegram ou contas de email
+type credentialExfilSignal struct{}
+
+func (credentialExfilSignal) Name() string { return SignalCredentialExfil }
+
+func (credentialExfilSignal) Evaluate(input *Input, _ *Config) (float64, string) {
+	if input.Evidence == nil || input.Evidence.Content == nil {
+		return 0, ""
+	}
+
+	content := input.Evidence.Content
+	if len(content.TelegramBots) > 0 {
+		return 1, "data sent to Telegram bot " + strings.Join(content.TelegramBots, ", ")
+	}
+	if len(content.DropMailboxes) > 0 {
+		return 1, "data sent to mailbox " + strings.Join(content.DropMailboxes, ", ")
+	}
+	for _, form := range content.Forms {
+		if form.CrossDomain && (form.Login || form.PasswordFields > 0) {
+			return 1, "credential form posts to " + form.Action
+		}
+	}
+	if len(content.ExternalPosts) > 0 {
+		return 0.5, "page sends data to " + strings.Join(content.ExternalPosts, ", ")
+	}
+	return 0, ""
+}
+
+// phishingKitSignal pontua marcadores de phishing kits (3 ou mais recebem o peso total)
+type phishingKitSignal struct{}
+
+func (phishingKitSignal) Name() string { return SignalPhishingKit }
+
+func (phishingKitSignal) Evaluate(input *Input, _ *Config) (float64, string) {
+	if input.Evidence == nil || input.Evidence.Content == nil || len(input.Evidence.Content.KitMarkers) == 0 {
+		return 0, ""
+	}
+
+	markers := input.Evidence.Content.KitMarkers
+	names := make([]string, 0, len(markers))
+	for _, marker := range markers {
+		names = append(names, marker.Name)
+	}
+	return 0.6 + 0.2*float64(len(markers)-1), "phishing kit markers: " + strings.Join(names, ", ")
+}
+
+// cloakingSignal pontua páginas que servem conteúdo diferente conforme o visitante
+type cloakingSignal struct{}
+
+func (cloakingSignal) Name() string { return SignalCloaking }
+
+func (cloakingSignal) Evaluate(input *Input, _ *Config) (float64, string) {
+	if input.Evidence == nil || input.Evidence.Cloaking == nil || !input.Evidence.Cloaking.Detected {
+		return 0, ""
+	}
+	cloaking := input.Evidence.Cloaking
+	return 1, fmt.Sprintf("content served to %s differs from %s", cloaking.VictimProfile, cloaking.Baseline)
+}
+
+// pageBody retorna o corpo da página final, preferindo o corpo completo lido pelo coletor ao preview de 1KB
+func pageBody(input *Input) string {
+	if input.Body != "" {
+		return input.Body
+	}
+	if input.Evidence == nil {
+		return ""
+	}
+	return input.Evidence.HTTP.Body
+}
+
+// targetHost retorna o host da página final (ou do IOC)
+func targetHost(input *Input) string {
+	candidates := []string{}
+	if input.Evidence != nil && len(input.Evidence.HTTP.Chain) > 0 {
+		candidates = append(candidates, input.Evidence.HTTP.Chain[len(input.Evidence.HTTP.Chain)-1])
+	}
+	if input.IOC != nil {
+		candidates = append(candidates, input.IOC.Value)
+	}
+
+	for _, candidate := range candidates {
+		if !strings.Contains(candidate, "://") {
+			candidate = "http://" + candidate
+		}
+		if parsed, err := url.Parse(candidate); err == nil && parsed.Hostname() != "" {
+			return strings.TrimSuffix(parsed.Hostname(), ".")
+		}
+	}
+	return ""
+}
+
+// ageStrength retorna 1 até 7 dias e decai linearmente até o limite
+func ageStrength(days, limit int) float64 {
+	const fullStrengthDays = 7
+	if days <= fullStrengthDays || limit <= fullStrengthDays {
+		return 1
+	}
+	return 1 - float64(days-fullStrengthDays)/float64(limit-fullStrengthDays+1)
+}
+
+// daysBetween retorna o número de dias inteiros entre duas datas
+func daysBetween(from, to time.Time) int {
+	days := int(to.Sub(from).Hours() / 24)
+	if days < 0 {
+		return 0
+	}
+	return days
+}
+
+// issuerName extrai o CN/O do emissor para a rationale
+func issuerName(issuer string) string {
+	for _, part := range strings.Split(issuer, ",") {
+		part = strings.TrimSpace(part)
+		if strings.HasPrefix(part, "CN=") {
+			return strings.TrimPrefix(part, "CN=")
+		}
+	}
+	return issuer
+}
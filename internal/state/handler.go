@@ -0,0 +1,86 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// maxResumeBytes limita o corpo de um pedido de retomada
+const maxResumeBytes = 64 << 10
+
+// ResumeRequest é o corpo do endpoint de retomada: sem email nem webform, o caso é roteado de novo;
+// só com webform, o operador informa que já enviou a denúncia pelo formulário
+type ResumeRequest struct {
+	CaseID  string `json:"case_id"`
+	Email   string `json:"email,omitempty"`
+	Webform string `json:"webform,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// ResumeResponse informa para onde o caso voltou: route (novo enrichment), submit (email do operador)
+// ou submitted (denúncia enviada pelo operador via webform)
+type ResumeResponse struct {
+	CaseID string                `json:"case_id"`
+	Status models.TakedownStatus `json:"status"`
+}
+
+// ResumeHandler expõe Machine.Resume por HTTP: POST com um ResumeRequest em JSON
+type ResumeHandler struct {
+	machine *Machine
+}
+
+// NewResumeHandler cria o endpoint de retomada de casos em needs_manual
+func NewResumeHandler(machine *Machine) *ResumeHandler {
+	return &ResumeHandler{machine: machine}
+}
+
+// ServeHTTP implementa http.Handler
+func (h *ResumeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	var resume ResumeRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxResumeBytes)).Decode(&resume); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid resume request: " + err.Error()})
+		return
+	}
+	if resume.CaseID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "case_id is required"})
+		return
+	}
+
+	var contact *models.ContactInfo
+	if resume.Email != "" || resume.Webform != "" {
+		contact = &models.ContactInfo{Email: resume.Email, Webform: resume.Webform, Reason: resume.Reason}
+	}
+
+	status, err := h.machine.Resume(resume.CaseID, contact)
+	if err != nil {
+		code := http.StatusBadRequest
+		switch {
+		case errors.Is(err, errCaseNotFound):
+			code = http.StatusNotFound
+		case errors.Is(err, errNotManual):
+			code = http.StatusConflict
+		}
+		writeJSON(w, code, map[string]string{"error": err.Error()})
+		return
+	}
+
+	// O worker pode já ter avançado o caso: respondemos o estado em que Resume o colocou
+	writeJSON(w, http.StatusOK, ResumeResponse{CaseID: resume.CaseID, Status: status})
+}
+
+// writeJSON grava a resposta em JSON
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
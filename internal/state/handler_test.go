@@ -0,0 +1,72 @@
+package state
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+func TestResumeHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		status     models.TakedownStatus
+		wantCode   int
+		wantStatus models.TakedownStatus
+		wantEmail  string
+	}{
+		{name: "operator contact", body: `{"case_id": "tdk-manual", "email": "noc@examplehosting.net"}`, status: models.StatusManual, wantCode: http.StatusOK, wantStatus: models.StatusSubmit, wantEmail: "noc@examplehosting.net"},
+		{name: "webform submitted by the operator", body: `{"case_id": "tdk-manual", "webform": "https://abuse.examplehosting.net/report"}`, status: models.StatusManual, wantCode: http.StatusOK, wantStatus: models.StatusSubmitted},
+		{name: "invalid operator email", body: `{"case_id": "tdk-manual", "email": "noc@10.0.0.1"}`, status: models.StatusManual, wantCode: http.StatusBadRequest},
+		{name: "route again", body: `{"case_id": "tdk-manual"}`, status: models.StatusManual, wantCode: http.StatusOK, wantStatus: models.StatusRoute},
+		{name: "unknown case", body: `{"case_id": "tdk-missing"}`, status: models.StatusManual, wantCode: http.StatusNotFound},
+		{name: "case not waiting for the operator", body: `{"case_id": "tdk-manual"}`, status: models.StatusSubmitted, wantCode: http.StatusConflict},
+		{name: "missing case id", body: `{"email": "noc@examplehosting.net"}`, status: models.StatusManual, wantCode: http.StatusBadRequest},
+		{name: "invalid json", body: `{`, status: models.StatusManual, wantCode: http.StatusBadRequest},
+		{name: "get", method: http.MethodGet, status: models.StatusManual, wantCode: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			machine := NewMachine(nil, nil, nil)
+			defer machine.ticker.Stop()
+
+			request := &models.TakedownRequest{
+				CaseID: "tdk-manual",
+				Status: tt.status,
+				Target: models.TakedownTarget{Type: "hosting", Entity: "Example Hosting", Email: "abuse@examplehosting.com", Source: models.ContactSourceGuess},
+			}
+			machine.requests[request.CaseID] = request
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			recorder := httptest.NewRecorder()
+			NewResumeHandler(machine).ServeHTTP(recorder, httptest.NewRequest(method, "/api/v1/cases/resume", strings.NewReader(tt.body)))
+
+			if recorder.Code != tt.wantCode {
+				t.Fatalf("Expected %d, got %d: %s", tt.wantCode, recorder.Code, recorder.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var response ResumeResponse
+			if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+				t.Fatal(err)
+			}
+			if response.CaseID != request.CaseID || response.Status != tt.wantStatus || request.Status != tt.wantStatus {
+				t.Errorf("Expected case in %s, got response %+v and case %s", tt.wantStatus, response, request.Status)
+			}
+			if tt.wantEmail != "" && (request.Target.Email != tt.wantEmail || request.Target.Source != models.ContactSourceManual) {
+				t.Errorf("Expected operator contact on the target, got %+v", request.Target)
+			}
+		})
+	}
+}
@@ -2,14 +2,21 @@ package state
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/cti-team/takedown/internal/brand"
 	"github.com/cti-team/takedown/internal/enrichment"
 	"github.com/cti-team/takedown/internal/evidence"
+	"github.com/cti-team/takedown/internal/redact"
 	"github.com/cti-team/takedown/internal/routing"
+	"github.com/cti-team/takedown/internal/scoring"
+	"github.com/cti-team/takedown/pkg/defang"
+	"github.com/cti-team/takedown/pkg/indicator"
 	"github.com/cti-team/takedown/pkg/models"
 	"github.com/google/uuid"
 )
@@ -19,9 +26,21 @@ type Machine struct {
 	collector  *evidence.Collector
 	enricher   *enrichment.Service
 	router     *routing.Engine
+	scorer     *scoring.Engine
+	brands     *brand.Detector
+	redactor   *redact.Redactor
 	connectors map[string]Connector
+	listeners  []StatusListener
+	policy     ContactPolicy
+	minConf    int // confiança mínima do IOC na triagem (0 = desativado)
 	requests   map[string]*models.TakedownRequest
+	iocs       map[string]*models.IOC // IOC de origem por case ID
 	mutex      sync.RWMutex
+	evidence   map[string]*models.EvidencePack // evidence packs por evidence ID
+	packMutex  sync.RWMutex                    // protege evidence; nenhum outro lock é adquirido com ele
+	inflight   map[string]int                  // etapas enfileiradas ou em execução por case ID
+	links      map[string][]func()             // vínculos de outros casos aguardando o fim da etapa em execução
+	caseMutex  sync.Mutex                      // protege inflight, links e a troca de status; adquirido depois de mutex, nunca antes
 	workers    int
 	workChan   chan *models.TakedownRequest
 	stopChan   chan struct{}
@@ -43,16 +62,37 @@ type StatusUpdate struct {
 	NextFollowUp *time.Time
 }
 
+// StatusChange descreve uma transição de estado de um caso
+type StatusChange struct {
+	CaseID    string
+	From      models.TakedownStatus
+	To        models.TakedownStatus
+	Tags      []string
+	IntelRefs []string
+	At        time.Time
+}
+
+// StatusListener é notificado das transições de estado (ex: feedback para a plataforma de CTI)
+type StatusListener func(change StatusChange)
+
 // NewMachine cria uma nova state machine
 func NewMachine(collector *evidence.Collector, enricher *enrichment.Service, router *routing.Engine) *Machine {
 	return &Machine{
 		collector:  collector,
 		enricher:   enricher,
 		router:     router,
+		scorer:     scoring.NewEngine(scoring.DefaultConfig()),
+		brands:     brand.NewDetector(brand.DefaultConfig()),
+		redactor:   redact.Default(),
 		connectors: make(map[string]Connector),
+		policy:     DefaultContactPolicy(),
 		requests:   make(map[string]*models.TakedownRequest),
+		iocs:       make(map[string]*models.IOC),
+		evidence:   make(map[string]*models.EvidencePack),
+		inflight:   make(map[string]int),
+		links:      make(map[string][]func()),
 		workers:    5,
-		workChan:   make(chan *models.TakedownRequest, 100),
+		workChan:   make(chan *models.TakedownRequest, 1000), // importações em lote abrem centenas de casos de uma vez
 		stopChan:   make(chan struct{}),
 		ticker:     time.NewTicker(1 * time.Minute), // Check a cada minuto
 	}
@@ -63,6 +103,37 @@ func (m *Machine) RegisterConnector(connector Connector) {
 	m.connectors[connector.GetType()] = connector
 }
 
+// SetContactPolicy configura a política de uso de contatos de abuse
+func (m *Machine) SetContactPolicy(policy ContactPolicy) {
+	m.policy = policy
+}
+
+// SetScorer configura a engine de score de risco (pesos de configs/scoring)
+func (m *Machine) SetScorer(scorer *scoring.Engine) {
+	m.scorer = scorer
+}
+
+// SetBrandDetector configura a detecção de domínios parecidos com as marcas protegidas (configs/brands)
+func (m *Machine) SetBrandDetector(detector *brand.Detector) {
+	m.brands = detector
+}
+
+// SetMinConfidence fecha na triagem os casos cujo IOC informa confiança abaixo do mínimo (0-100)
+func (m *Machine) SetMinConfidence(confidence int) {
+	m.minConf = confidence
+}
+
+// SetRedactor configura a redação aplicada às evidências antes da submissão
+func (m *Machine) SetRedactor(redactor *redact.Redactor) {
+	m.redactor = redactor
+}
+
+// AddStatusListener registra um listener de transições; deve ser chamado antes de Start.
+// Cada notificação roda em sua própria goroutine para não segurar os workers.
+func (m *Machine) AddStatusListener(listener StatusListener) {
+	m.listeners = append(m.listeners, listener)
+}
+
 // Start inicia a state machine
 func (m *Machine) Start() {
 	log.Println("Starting takedown state machine...")
@@ -87,6 +158,21 @@ func (m *Machine) Stop() {
 
 // ProcessIOC processa um novo IOC através da pipeline completa
 func (m *Machine) ProcessIOC(ioc *models.IOC) error {
+	// Valores malformados, privados ou reservados param aqui, antes do enrichment; o valor
+	// segue canônico (refangado, host em punycode) e com o tipo detectado quando ausente
+	if err := validateIOC(ioc); err != nil {
+		return err
+	}
+	if ioc.Type == models.IOCTypeHash {
+		return m.processHash(ioc)
+	}
+
+	// Lookalikes de domínios protegidos recebem brand:X e typosquatting antes do roteamento
+	var similarity *models.BrandSimilarity
+	if m.brands != nil {
+		similarity = m.brands.Tag(ioc)
+	}
+
 	// Criar caso base
 	caseID := fmt.Sprintf("tdk-%s", uuid.New().String())
 
@@ -97,35 +183,277 @@ func (m *Machine) ProcessIOC(ioc *models.IOC) error {
 		UpdatedAt: time.Now().UTC(),
 		Priority:  ioc.GetSeverity(),
 		Tags:      ioc.Tags,
+		IntelRefs: ioc.IntelRefs,
 	}
 
 	request.AddEvent("case_created", "system", "", fmt.Sprintf("Processing IOC: %s", ioc.Value))
+	if len(ioc.IntelRefs) > 0 {
+		request.AddEvent("intel_refs", "system", ioc.Source, strings.Join(ioc.IntelRefs, ", "))
+	}
+	if similarity != nil {
+		request.AddEvent("brand_similarity", "system", similarity.Domain,
+			fmt.Sprintf("Looks like %s (%s, score %.2f): %s", similarity.Domain, similarity.Brand, similarity.Score,
+				strings.Join(similarity.Reasons, "; ")))
+	}
 
 	// Registrar caso
 	m.mutex.Lock()
 	m.requests[caseID] = request
+	m.iocs[caseID] = ioc
 	m.mutex.Unlock()
 
 	// Iniciar processamento
 	return m.transitionTo(request, models.StatusTriage)
 }
 
+// processHash abre (ou reaproveita) um caso de URL por URL de distribuição da amostra; o hash
+// segue no IOC da URL para que o provedor localize o arquivo
+func (m *Machine) processHash(ioc *models.IOC) error {
+	linked, err := linkedURLs(ioc)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, urlIOC := range linked {
+		if err := validateIOC(urlIOC); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", urlIOC.Value, err))
+			continue
+		}
+		if caseID, open := m.OpenCase(models.IOCTypeURL, urlIOC.Value); open {
+			m.attachHash(caseID, ioc)
+			continue
+		}
+		if err := m.ProcessIOC(urlIOC); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", urlIOC.Value, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// linkedURLs monta os IOCs de URL de um hash, com as tags, origem e referências do hash
+func linkedURLs(ioc *models.IOC) ([]*models.IOC, error) {
+	if len(ioc.DistributionURLs) == 0 {
+		return nil, fmt.Errorf("invalid IOC: hash %s has no distribution URLs to take down", ioc.Value)
+	}
+
+	tags := append([]string(nil), ioc.Tags...)
+	if !ioc.HasTag("malware") {
+		tags = append(tags, "malware")
+	}
+	linked := make([]*models.IOC, 0, len(ioc.DistributionURLs))
+	for i, url := range ioc.DistributionURLs {
+		linked = append(linked, &models.IOC{
+			IndicatorID: fmt.Sprintf("%s-url-%d", ioc.IndicatorID, i+1),
+			Type:        models.IOCTypeURL,
+			Value:       url,
+			FirstSeen:   ioc.FirstSeen,
+			Source:      ioc.Source,
+			Tags:        tags,
+			IntelRefs:   ioc.IntelRefs,
+			Confidence:  ioc.Confidence,
+			Hashes:      []string{ioc.Value},
+		})
+	}
+	return linked, nil
+}
+
+// attachHash acrescenta o hash ao IOC de um caso de URL já aberto
+func (m *Machine) attachHash(caseID string, hash *models.IOC) {
+	m.linkCase(caseID, func(ioc *models.IOC, request *models.TakedownRequest) {
+		for _, known := range ioc.Hashes {
+			if strings.EqualFold(known, hash.Value) {
+				return
+			}
+		}
+		ioc.Hashes = append(ioc.Hashes, hash.Value)
+		request.AddEvent("sample_linked", "system", hash.Value, fmt.Sprintf("Sample %s reported at this URL by %s", hash.Value, hash.Source))
+	})
+}
+
+// linkMailboxes abre casos de email para as contas informadas com a URL e as encontradas na
+// página: o provedor desativa a conta em paralelo ao takedown do site
+func (m *Machine) linkMailboxes(request *models.TakedownRequest, ioc *models.IOC, evidence *models.EvidencePack) {
+	if ioc.Type == models.IOCTypeEmail {
+		return
+	}
+	accounts := append([]string(nil), ioc.Emails...)
+	if evidence.Content != nil {
+		accounts = append(accounts, evidence.Content.DropMailboxes...)
+	}
+
+	seen := make(map[string]bool, len(accounts))
+	for i, account := range accounts {
+		mailbox := &models.IOC{
+			IndicatorID:  fmt.Sprintf("%s-mailbox-%d", ioc.IndicatorID, i+1),
+			Type:         models.IOCTypeEmail,
+			Value:        account,
+			FirstSeen:    ioc.FirstSeen,
+			Source:       ioc.Source,
+			Tags:         append([]string(nil), ioc.Tags...),
+			IntelRefs:    ioc.IntelRefs,
+			Confidence:   ioc.Confidence,
+			PhishingURLs: []string{ioc.Value},
+		}
+		if err := validateIOC(mailbox); err != nil {
+			request.AddEvent("mailbox_invalid", "system", account, err.Error())
+			continue
+		}
+		if seen[mailbox.Value] {
+			continue
+		}
+		seen[mailbox.Value] = true
+
+		if caseID, open := m.OpenCase(models.IOCTypeEmail, mailbox.Value); open {
+			m.attachPhishingURL(caseID, ioc.Value)
+		} else if err := m.ProcessIOC(mailbox); err != nil {
+			request.AddEvent("mailbox_failed", "system", mailbox.Value, err.Error())
+			continue
+		}
+		request.AddEvent("mailbox_linked", "system", mailbox.Value, fmt.Sprintf("Mailbox %s receives data from this page", mailbox.Value))
+	}
+}
+
+// attachPhishingURL acrescenta a página ao IOC e ao evidence pack de um caso de email já aberto;
+// os conectores listam as páginas a partir do pack
+func (m *Machine) attachPhishingURL(caseID, url string) {
+	m.linkCase(caseID, func(ioc *models.IOC, request *models.TakedownRequest) {
+		for _, known := range ioc.PhishingURLs {
+			if known == url {
+				return
+			}
+		}
+		ioc.PhishingURLs = append(ioc.PhishingURLs, url)
+		if pack := m.getEvidence(request.EvidenceID); pack != nil {
+			pack.PhishingURLs = appendPage(pack.PhishingURLs, defang.Defang(url))
+		}
+		request.AddEvent("phishing_url_linked", "system", url, fmt.Sprintf("Page %s also sends data to this mailbox", url))
+	})
+}
+
+// appendPage acrescenta a página (defanged) à lista do pack, sem repetir
+func appendPage(pages []string, page string) []string {
+	for _, known := range pages {
+		if known == page {
+			return pages
+		}
+	}
+	return append(pages, page)
+}
+
+// linkCase altera o IOC e o histórico de outro caso. As etapas leem ambos sem lock, então com
+// uma etapa do caso enfileirada ou em execução o vínculo espera e é aplicado pelo worker do caso
+func (m *Machine) linkCase(caseID string, link func(ioc *models.IOC, request *models.TakedownRequest)) {
+	m.mutex.RLock()
+	ioc, request := m.iocs[caseID], m.requests[caseID]
+	m.mutex.RUnlock()
+	if ioc == nil || request == nil {
+		return
+	}
+
+	m.caseMutex.Lock()
+	defer m.caseMutex.Unlock()
+	if m.inflight[caseID] > 0 {
+		m.links[caseID] = append(m.links[caseID], func() { link(ioc, request) })
+		return
+	}
+	link(ioc, request)
+}
+
+// applyLinks aplica os vínculos pendentes do caso; chamado com caseMutex pelo dono do caso
+func (m *Machine) applyLinks(caseID string) {
+	for _, link := range m.links[caseID] {
+		link()
+	}
+	delete(m.links, caseID)
+}
+
+// finishStep encerra uma etapa do caso; sem outra etapa na fila, os vínculos pendentes são aplicados
+func (m *Machine) finishStep(caseID string) {
+	m.caseMutex.Lock()
+	defer m.caseMutex.Unlock()
+
+	m.inflight[caseID]--
+	if m.inflight[caseID] > 0 {
+		return
+	}
+	delete(m.inflight, caseID)
+	m.applyLinks(caseID)
+}
+
+// pipelineTypes são os tipos de IOC que a pipeline sabe levar até um takedown; hashes
+// chegam lá pelas URLs de distribuição
+var pipelineTypes = map[models.IOCType]bool{
+	models.IOCTypeURL:    true,
+	models.IOCTypeDomain: true,
+	models.IOCTypeIP:     true,
+	models.IOCTypeHash:   true,
+	models.IOCTypeEmail:  true,
+}
+
+// validateIOC valida e canoniza o IOC e recusa tipos que a pipeline não trata
+func validateIOC(ioc *models.IOC) error {
+	validated, err := indicator.Validate(ioc.Value, ioc.Type)
+	if err != nil {
+		return fmt.Errorf("invalid IOC: %w", err)
+	}
+	if !pipelineTypes[validated.Type] {
+		return fmt.Errorf("invalid IOC: type %s is not supported by the takedown pipeline", validated.Type)
+	}
+	ioc.Type, ioc.Value = validated.Type, validated.Value
+	return nil
+}
+
 // transitionTo move o request para um novo estado
 func (m *Machine) transitionTo(request *models.TakedownRequest, newStatus models.TakedownStatus) error {
+	m.caseMutex.Lock()
+	oldStatus := m.setStatus(request, newStatus)
+	m.caseMutex.Unlock()
+
+	return m.enqueue(request, oldStatus)
+}
+
+// setStatus troca o status com caseMutex, pois OpenCase e Resume o leem de outros goroutines; a
+// etapa seguinte conta como em andamento desde já
+func (m *Machine) setStatus(request *models.TakedownRequest, newStatus models.TakedownStatus) models.TakedownStatus {
 	oldStatus := request.Status
 	request.UpdateStatus(newStatus, fmt.Sprintf("Transitioned from %s to %s", oldStatus, newStatus))
+	m.inflight[request.CaseID]++
+	return oldStatus
+}
 
-	log.Printf("Case %s: %s -> %s", request.CaseID, oldStatus, newStatus)
+// enqueue registra a transição feita por setStatus e coloca o request na fila de processamento
+func (m *Machine) enqueue(request *models.TakedownRequest, oldStatus models.TakedownStatus) error {
+	log.Printf("Case %s: %s -> %s", request.CaseID, oldStatus, request.Status)
+	m.notifyStatus(request, oldStatus)
 
-	// Adicionar à fila de processamento
 	select {
 	case m.workChan <- request:
 		return nil
 	default:
+		m.finishStep(request.CaseID)
 		return fmt.Errorf("work queue is full")
 	}
 }
 
+// notifyStatus entrega a transição aos listeners com uma cópia dos dados do caso
+func (m *Machine) notifyStatus(request *models.TakedownRequest, oldStatus models.TakedownStatus) {
+	if len(m.listeners) == 0 || oldStatus == request.Status {
+		return
+	}
+	change := StatusChange{
+		CaseID:    request.CaseID,
+		From:      oldStatus,
+		To:        request.Status,
+		Tags:      append([]string(nil), request.Tags...),
+		IntelRefs: append([]string(nil), request.IntelRefs...),
+		At:        request.UpdatedAt,
+	}
+	for _, listener := range m.listeners {
+		go listener(change)
+	}
+}
+
 // worker processa requests da fila
 func (m *Machine) worker(id int) {
 	log.Printf("Worker %d started", id)
@@ -133,10 +461,7 @@ func (m *Machine) worker(id int) {
 	for {
 		select {
 		case request := <-m.workChan:
-			if err := m.processRequest(request); err != nil {
-				log.Printf("Worker %d: Error processing %s: %v", id, request.CaseID, err)
-				request.AddEvent("error", "system", "", err.Error())
-			}
+			m.runStep(id, request)
 
 		case <-m.stopChan:
 			log.Printf("Worker %d stopped", id)
@@ -145,6 +470,20 @@ func (m *Machine) worker(id int) {
 	}
 }
 
+// runStep executa a etapa atual do caso. Vínculos de outros casos recebidos enquanto a etapa
+// estava na fila entram antes dela; os recebidos durante a etapa, depois dela
+func (m *Machine) runStep(id int, request *models.TakedownRequest) {
+	m.caseMutex.Lock()
+	m.applyLinks(request.CaseID)
+	m.caseMutex.Unlock()
+
+	if err := m.processRequest(request); err != nil {
+		log.Printf("Worker %d: Error processing %s: %v", id, request.CaseID, err)
+		request.AddEvent("error", "system", "", err.Error())
+	}
+	m.finishStep(request.CaseID)
+}
+
 // processRequest processa um request baseado no seu estado atual
 func (m *Machine) processRequest(request *models.TakedownRequest) error {
 	ctx := context.Background()
@@ -165,6 +504,10 @@ func (m *Machine) processRequest(request *models.TakedownRequest) error {
 	case models.StatusFollowUp:
 		return m.handleFollowUp(ctx, request)
 
+	case models.StatusManual:
+		// O operador define o contato e reenvia o caso; nada a fazer automaticamente
+		return nil
+
 	default:
 		return fmt.Errorf("unknown status: %s", request.Status)
 	}
@@ -175,7 +518,16 @@ func (m *Machine) handleTriage(ctx context.Context, request *models.TakedownRequ
 	// Análise básica de prioridade e validade
 	request.AddEvent("triage_started", "system", "", "Starting triage analysis")
 
-	// Por enquanto, aprovamos todos os casos
+	// IOCs de feeds trazem a confiança da fonte; abaixo do mínimo o caso não segue
+	if ioc := m.caseIOC(request.CaseID); ioc != nil && ioc.Confidence > 0 {
+		if ioc.Confidence < m.minConf {
+			request.AddEvent("low_confidence", "system", ioc.Source,
+				fmt.Sprintf("Source confidence %d below minimum %d", ioc.Confidence, m.minConf))
+			return m.transitionTo(request, models.StatusClosed)
+		}
+		request.AddEvent("source_confidence", "system", ioc.Source, fmt.Sprintf("Source confidence %d", ioc.Confidence))
+	}
+
 	// TODO: Implementar regras de triagem mais sofisticadas
 
 	return m.transitionTo(request, models.StatusEvidencePack)
@@ -186,14 +538,16 @@ func (m *Machine) handleEvidenceCollection(ctx context.Context, request *models.
 	request.AddEvent("evidence_collection_started", "system", "", "Starting evidence collection")
 
 	// Buscar IOC original
-	// TODO: Implementar storage/retrieve de IOCs
-	// Por enquanto, vamos simular
-
-	ioc := &models.IOC{
-		IndicatorID: "temp-ioc",
-		Type:        models.IOCTypeURL,
-		Value:       "https://suspicious-domain.com/login",
-		Tags:        request.Tags,
+	ioc := m.caseIOC(request.CaseID)
+	if ioc == nil {
+		// TODO: Implementar storage/retrieve de IOCs
+		// Sem IOC registrado, vamos simular
+		ioc = &models.IOC{
+			IndicatorID: "temp-ioc",
+			Type:        models.IOCTypeURL,
+			Value:       "https://suspicious-domain.com/login",
+			Tags:        request.Tags,
+		}
 	}
 
 	// Coletar evidências
@@ -202,9 +556,17 @@ func (m *Machine) handleEvidenceCollection(ctx context.Context, request *models.
 		return fmt.Errorf("evidence collection failed: %w", err)
 	}
 
+	evidence.IntelRefs = append(evidence.IntelRefs, ioc.IntelRefs...)
+
+	// Score inicial; dados de registro (idade do domínio) entram após o enrichment
+	evidence.Risk = m.scorer.Assess(scoring.Input{IOC: ioc, Evidence: evidence, Body: string(evidence.Body)})
+	m.storeEvidence(evidence)
+
 	request.EvidenceID = evidence.EvidenceID
 	request.AddEvent("evidence_collected", "system", evidence.EvidenceID,
 		fmt.Sprintf("Evidence collected, risk score: %d", evidence.Risk.Score))
+	request.AddEvent("risk_assessed", "system", evidence.EvidenceID, evidence.Risk.Rationale)
+	m.linkMailboxes(request, ioc, evidence)
 
 	return m.transitionTo(request, models.StatusRoute)
 }
@@ -214,11 +576,29 @@ func (m *Machine) handleRouting(ctx context.Context, request *models.TakedownReq
 	request.AddEvent("routing_started", "system", "", "Determining takedown targets")
 
 	// Usar enrichment service para descobrir contatos
-	contacts, err := m.enricher.EnrichIOC(ctx, request.EvidenceID)
+	var (
+		contacts *models.AbuseContact
+		err      error
+	)
+	if ioc := m.caseIOC(request.CaseID); ioc != nil && ioc.Value != "" {
+		contacts, err = m.enricher.EnrichIndicator(ctx, ioc.Value)
+	} else {
+		contacts, err = m.enricher.EnrichIOC(ctx, request.EvidenceID)
+	}
 	if err != nil {
 		return fmt.Errorf("enrichment failed: %w", err)
 	}
 
+	// Reavaliar o risco com os dados de registro do domínio
+	if evidence := m.getEvidence(request.EvidenceID); evidence != nil {
+		risk := m.scorer.Assess(scoring.Input{IOC: m.caseIOC(request.CaseID), Evidence: evidence, Contact: contacts, Body: string(evidence.Body)})
+		if risk != evidence.Risk {
+			evidence.Risk = risk
+			request.AddEvent("risk_assessed", "system", evidence.EvidenceID,
+				fmt.Sprintf("Risk score %d: %s", risk.Score, risk.Rationale))
+		}
+	}
+
 	// Usar routing engine para determinar actions
 	actions := m.router.DetermineActions(request.Tags, contacts)
 
@@ -227,34 +607,74 @@ func (m *Machine) handleRouting(ctx context.Context, request *models.TakedownReq
 		return m.transitionTo(request, models.StatusClosed)
 	}
 
-	// Criar requests para cada target
-	for _, action := range actions {
-		// Por enquanto, usar o primeiro target
-		request.Target = action.Target
-		request.RequestedAction = action.Action
-		break
+	if !m.selectTarget(request, actions) {
+		request.AddEvent("no_connector", "system", "", "No registered connector for the routed targets")
+		return m.transitionTo(request, models.StatusManual)
 	}
 
 	request.AddEvent("routing_completed", "system", "",
 		fmt.Sprintf("Target: %s (%s)", request.Target.Entity, request.Target.Type))
+	request.AddEvent("contact_selected", "system", request.Target.Email, describeContact(request.Target))
 
 	return m.transitionTo(request, models.StatusSubmit)
 }
 
+// selectTarget usa a primeira action com connector registrado; as anteriores ficam no histórico.
+// Sem nenhum connector, o target da primeira action fica no caso para o operador.
+func (m *Machine) selectTarget(request *models.TakedownRequest, actions []routing.ActionDefinition) bool {
+	for _, action := range actions {
+		if _, exists := m.connectors[action.Target.Type]; exists {
+			request.Target = action.Target
+			request.RequestedAction = action.Action
+			return true
+		}
+		request.AddEvent("target_skipped", "system", action.Target.Email,
+			fmt.Sprintf("No connector for %s target %s", action.Target.Type, action.Target.Entity))
+	}
+	request.Target = actions[0].Target
+	request.RequestedAction = actions[0].Action
+	return false
+}
+
 // handleSubmission submete o takedown request
 func (m *Machine) handleSubmission(ctx context.Context, request *models.TakedownRequest) error {
+	// Targets sem connector (upstream, CERT, ...) são acionados pelo operador
 	connector, exists := m.connectors[request.Target.Type]
 	if !exists {
-		return fmt.Errorf("no connector found for type: %s", request.Target.Type)
+		request.AddEvent("no_connector", "system", request.Target.Email,
+			fmt.Sprintf("No connector for %s target %s", request.Target.Type, request.Target.Entity))
+		return m.transitionTo(request, models.StatusManual)
+	}
+
+	// Contatos deduzidos ou de baixa confiança ficam aguardando definição manual
+	if err := m.policy.Check(request.Target); err != nil {
+		request.AddEvent("contact_rejected", "system", request.Target.Email, err.Error())
+		return m.transitionTo(request, models.StatusManual)
+	}
+
+	// Provedores e plataformas que só aceitam webform ou API ficam para o operador, com o endereço no histórico
+	if channel, url := manualChannel(request.Target); channel != "" {
+		request.AddEvent("manual_submission_required", channel, url,
+			fmt.Sprintf("%s only accepts reports via %s %s", request.Target.Entity, channel, url))
+		return m.transitionTo(request, models.StatusManual)
 	}
 
 	request.AddEvent("submission_started", "system", "",
 		fmt.Sprintf("Submitting to %s", request.Target.Entity))
 
-	// TODO: Carregar evidence pack
-	evidence := &models.EvidencePack{} // placeholder
+	evidence := m.getEvidence(request.EvidenceID)
+	if evidence == nil {
+		evidence = &models.EvidencePack{EvidenceID: request.EvidenceID}
+	}
+
+	// Os connectors só recebem a cópia redigida; o pack original fica no evidence store
+	evidence, audit := m.redactor.Pack(evidence)
+	if audit.Total() > 0 {
+		request.AddEvent("evidence_redacted", "system", "", audit.Summary())
+	}
 
-	err := connector.Submit(ctx, request, evidence)
+	// O bundle anexado pelos connectors reaproveita a auditoria em vez de redigir de novo
+	err := connector.Submit(redact.WithAudit(ctx, audit), request, evidence)
 	if err != nil {
 		return fmt.Errorf("submission failed: %w", err)
 	}
@@ -378,6 +798,150 @@ func (m *Machine) continueFollowUp(request *models.TakedownRequest) {
 	}
 }
 
+// caseIOC retorna o IOC que originou o caso
+func (m *Machine) caseIOC(caseID string) *models.IOC {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.iocs[caseID]
+}
+
+// storeEvidence guarda o evidence pack coletado
+func (m *Machine) storeEvidence(evidence *models.EvidencePack) {
+	m.packMutex.Lock()
+	m.evidence[evidence.EvidenceID] = evidence
+	m.packMutex.Unlock()
+}
+
+// getEvidence retorna um evidence pack pelo ID
+func (m *Machine) getEvidence(evidenceID string) *models.EvidencePack {
+	m.packMutex.RLock()
+	defer m.packMutex.RUnlock()
+	return m.evidence[evidenceID]
+}
+
+// OpenCase retorna o caso ainda aberto (não encerrado) para o mesmo tipo e valor de IOC
+func (m *Machine) OpenCase(iocType models.IOCType, value string) (string, bool) {
+	value = defang.Refang(value)
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	m.caseMutex.Lock()
+	defer m.caseMutex.Unlock()
+
+	for caseID, ioc := range m.iocs {
+		if ioc.Type != iocType || !strings.EqualFold(ioc.Value, value) {
+			continue
+		}
+		if request, ok := m.requests[caseID]; ok &&
+			request.Status != models.StatusOutcome && request.Status != models.StatusClosed {
+			return caseID, true
+		}
+	}
+	return "", false
+}
+
+// PreviewRouting mostra para onde o IOC seria roteado, sem abrir caso (enrichment + regras)
+func (m *Machine) PreviewRouting(ctx context.Context, ioc *models.IOC) ([]routing.ActionDefinition, error) {
+	preview := *ioc
+	preview.Tags = append([]string(nil), ioc.Tags...)
+	if err := validateIOC(&preview); err != nil {
+		return nil, err
+	}
+	if preview.Type == models.IOCTypeHash {
+		return m.previewHash(ctx, &preview)
+	}
+	if m.brands != nil {
+		m.brands.Tag(&preview)
+	}
+
+	contacts, err := m.enricher.EnrichIndicator(ctx, preview.Value)
+	if err != nil {
+		return nil, fmt.Errorf("enrichment failed: %w", err)
+	}
+	return m.router.DetermineActions(preview.Tags, contacts), nil
+}
+
+// previewHash junta as prévias das URLs de distribuição do hash
+func (m *Machine) previewHash(ctx context.Context, ioc *models.IOC) ([]routing.ActionDefinition, error) {
+	linked, err := linkedURLs(ioc)
+	if err != nil {
+		return nil, err
+	}
+	var actions []routing.ActionDefinition
+	for _, urlIOC := range linked {
+		urlActions, err := m.PreviewRouting(ctx, urlIOC)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", urlIOC.Value, err)
+		}
+		actions = append(actions, urlActions...)
+	}
+	return actions, nil
+}
+
+// Erros de Resume que a API traduz em 404 e 409
+var (
+	errCaseNotFound = errors.New("case not found")
+	errNotManual    = fmt.Errorf("only %s cases can be resumed", models.StatusManual)
+)
+
+// Resume devolve à pipeline um caso parado em needs_manual e retorna o novo status. Sem contact,
+// o caso volta a route e refaz o enrichment (ex: depois de um override no diretório de contatos);
+// com email, o operador define o contato (source=manual) e o caso volta a submit; só com webform,
+// o operador já enviou a denúncia pelo formulário e o caso segue como submitted
+func (m *Machine) Resume(caseID string, contact *models.ContactInfo) (models.TakedownStatus, error) {
+	request, exists := m.GetRequest(caseID)
+	if !exists {
+		return "", fmt.Errorf("case %s: %w", caseID, errCaseNotFound)
+	}
+
+	next := models.StatusRoute
+	var manual models.ContactInfo
+	if contact != nil {
+		manual = *contact
+		switch {
+		case manual.Email != "":
+			validated, err := indicator.Validate(manual.Email, models.IOCTypeEmail)
+			if err != nil {
+				return "", fmt.Errorf("case %s: invalid operator email: %w", caseID, err)
+			}
+			manual.Email = validated.Value
+			next = models.StatusSubmit
+		case manual.Webform != "":
+			next = models.StatusSubmitted
+		default:
+			return "", fmt.Errorf("case %s: operator contact needs an email or webform", caseID)
+		}
+		manual.Source = models.ContactSourceManual
+		manual.Confidence = manualContactConfidence
+		if manual.Reason == "" {
+			manual.Reason = "set by operator"
+		}
+	}
+
+	// Conferência e troca de status juntas: dois resumes simultâneos não enfileiram o caso duas vezes
+	m.caseMutex.Lock()
+	if request.Status != models.StatusManual {
+		status := request.Status
+		m.caseMutex.Unlock()
+		return "", fmt.Errorf("case %s is %s: %w", caseID, status, errNotManual)
+	}
+	switch next {
+	case models.StatusRoute:
+		request.AddEvent("manual_resumed", "operator", "", "Routing again with the current contact directory")
+	case models.StatusSubmit:
+		request.Target.ApplyContact(manual)
+		request.AddEvent("manual_resumed", "operator", manual.Email, describeContact(request.Target))
+	case models.StatusSubmitted:
+		request.Target.ApplyContact(manual)
+		request.AddEvent("manual_resumed", "operator", manual.Webform, describeContact(request.Target))
+		request.AddEvent("submitted", "webform", manual.Webform,
+			fmt.Sprintf("Operator submitted the report to %s via webform", request.Target.Entity))
+	}
+	oldStatus := m.setStatus(request, next)
+	m.caseMutex.Unlock()
+
+	return next, m.enqueue(request, oldStatus)
+}
+
 // GetRequest retorna informações de um request
 func (m *Machine) GetRequest(caseID string) (*models.TakedownRequest, bool) {
 	m.mutex.RLock()
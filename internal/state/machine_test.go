@@ -0,0 +1,376 @@
+package state
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cti-team/takedown/internal/evidence"
+	"github.com/cti-team/takedown/internal/routing"
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+func TestMachine_StatusListener(t *testing.T) {
+	machine := NewMachine(nil, nil, nil)
+	defer machine.ticker.Stop()
+
+	changes := make(chan StatusChange, 1)
+	machine.AddStatusListener(func(change StatusChange) { changes <- change })
+
+	request := &models.TakedownRequest{
+		CaseID:    "tdk-listener",
+		Status:    models.StatusSubmit,
+		Tags:      []string{"phishing"},
+		IntelRefs: []string{"misp:attribute:5e1b", "misp:event:9a0c"},
+		Target:    models.TakedownTarget{Type: "hosting", Entity: "Example Hosting", Email: "abuse@example.net", Source: models.ContactSourceRDAP, Confidence: 90},
+	}
+	machine.RegisterConnector(&stubConnector{connectorType: "hosting"})
+
+	if err := machine.handleSubmission(context.Background(), request); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	select {
+	case change := <-changes:
+		if change.CaseID != request.CaseID || change.From != models.StatusSubmit || change.To != models.StatusSubmitted {
+			t.Errorf("Unexpected change %+v", change)
+		}
+		if !reflect.DeepEqual(change.IntelRefs, request.IntelRefs) {
+			t.Errorf("Expected intel refs %v, got %v", request.IntelRefs, change.IntelRefs)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Listener was not notified")
+	}
+}
+
+func TestMachine_TriageMinConfidence(t *testing.T) {
+	tests := []struct {
+		name       string
+		confidence int
+		want       models.TakedownStatus
+	}{
+		{name: "below minimum", confidence: 40, want: models.StatusClosed},
+		{name: "at minimum", confidence: 60, want: models.StatusEvidencePack},
+		{name: "not informed", confidence: 0, want: models.StatusEvidencePack},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			machine := NewMachine(nil, nil, nil)
+			defer machine.ticker.Stop()
+			machine.SetMinConfidence(60)
+
+			request := &models.TakedownRequest{CaseID: "tdk-triage", Status: models.StatusTriage}
+			machine.requests[request.CaseID] = request
+			machine.iocs[request.CaseID] = &models.IOC{Type: models.IOCTypeURL, Value: "https://a.com/", Source: "openphish", Confidence: tt.confidence}
+
+			if err := machine.handleTriage(context.Background(), request); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if request.Status != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, request.Status)
+			}
+		})
+	}
+}
+
+func TestMachine_ProcessIOCValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		ioc       models.IOC
+		wantType  models.IOCType
+		wantValue string
+		wantErr   string
+	}{
+		{name: "defanged url", ioc: models.IOC{Value: "hxxps://Acme-Login[.]example[.]com/auth"}, wantType: models.IOCTypeURL, wantValue: "https://acme-login.example.com/auth"},
+		{name: "declared domain", ioc: models.IOC{Type: models.IOCTypeDomain, Value: "Acme-Login.example.com."}, wantType: models.IOCTypeDomain, wantValue: "acme-login.example.com"},
+		{name: "hostname with bad tld", ioc: models.IOC{Type: models.IOCTypeDomain, Value: "malware-distribution.evil"}, wantErr: "unknown TLD"},
+		{name: "private ip", ioc: models.IOC{Value: "10.1.2.3"}, wantErr: "reserved range"},
+		{name: "type mismatch", ioc: models.IOC{Type: models.IOCTypeDomain, Value: "https://a.example.com/x"}, wantErr: "looks like url"},
+		{name: "unsupported type", ioc: models.IOC{Value: "45.67.89.0/24"}, wantErr: "not supported by the takedown pipeline"},
+		{name: "hash without urls", ioc: models.IOC{Value: strings.Repeat("a", 64)}, wantErr: "no distribution URLs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			machine := NewMachine(nil, nil, nil)
+			defer machine.ticker.Stop()
+
+			ioc := tt.ioc
+			err := machine.ProcessIOC(&ioc)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error %q, got %v", tt.wantErr, err)
+				}
+				if len(machine.requests) != 0 {
+					t.Error("Expected no case for an invalid IOC")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if ioc.Type != tt.wantType || ioc.Value != tt.wantValue {
+				t.Errorf("Expected %s %q, got %s %q", tt.wantType, tt.wantValue, ioc.Type, ioc.Value)
+			}
+		})
+	}
+}
+
+func TestMachine_ProcessHash(t *testing.T) {
+	machine := NewMachine(nil, nil, nil)
+	defer machine.ticker.Stop()
+
+	existing := &models.IOC{Type: models.IOCTypeURL, Value: "https://files.example.com/a.exe", Tags: []string{"malware"}}
+	if err := machine.ProcessIOC(existing); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	existingCase, _ := machine.OpenCase(models.IOCTypeURL, existing.Value)
+	// Sem workers a triagem fica na fila: encerrá-la deixa o caso parado, recebendo vínculos na hora
+	machine.finishStep(existingCase)
+
+	hash := strings.Repeat("ab", 32)
+	ioc := &models.IOC{
+		Value:            strings.ToUpper(hash),
+		Source:           "misp",
+		Tags:             []string{"emotet"},
+		IntelRefs:        []string{"misp:attribute:77"},
+		DistributionURLs: []string{"hxxps://files[.]example[.]com/a.exe", "https://cdn.example.org/b.zip"},
+	}
+	if err := machine.ProcessIOC(ioc); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(machine.requests) != 2 {
+		t.Fatalf("Expected 2 cases, got %d", len(machine.requests))
+	}
+	if got := machine.iocs[existingCase].Hashes; !reflect.DeepEqual(got, []string{hash}) {
+		t.Errorf("Expected hash attached to the open case, got %v", got)
+	}
+
+	newCase, ok := machine.OpenCase(models.IOCTypeURL, "https://cdn.example.org/b.zip")
+	if !ok {
+		t.Fatal("Expected a linked URL case")
+	}
+	linked := machine.iocs[newCase]
+	if !reflect.DeepEqual(linked.Hashes, []string{hash}) || linked.Source != "misp" {
+		t.Errorf("Unexpected linked IOC %+v", linked)
+	}
+	if !linked.HasTag("malware") || !linked.HasTag("emotet") {
+		t.Errorf("Expected malware and emotet tags, got %v", linked.Tags)
+	}
+	if !reflect.DeepEqual(linked.IntelRefs, ioc.IntelRefs) {
+		t.Errorf("Expected intel refs %v, got %v", ioc.IntelRefs, linked.IntelRefs)
+	}
+
+	// Reenviar o mesmo hash não duplica casos nem o hash
+	if err := machine.ProcessIOC(ioc); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(machine.requests) != 2 || len(machine.iocs[existingCase].Hashes) != 1 {
+		t.Errorf("Expected resubmission to be idempotent, got %d cases and hashes %v", len(machine.requests), machine.iocs[existingCase].Hashes)
+	}
+}
+
+func TestMachine_LinkMailboxes(t *testing.T) {
+	machine := NewMachine(nil, nil, nil)
+	defer machine.ticker.Stop()
+
+	existing := &models.IOC{Type: models.IOCTypeEmail, Value: "logs@yahoo.com", PhishingURLs: []string{"https://old.example.com/"}}
+	if err := machine.ProcessIOC(existing); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	existingCase, _ := machine.OpenCase(models.IOCTypeEmail, existing.Value)
+	machine.finishStep(existingCase)
+	pack := &models.EvidencePack{EvidenceID: "ev-mailbox", PhishingURLs: []string{"hxxps://old[.]example[.]com/"}}
+	machine.storeEvidence(pack)
+	machine.requests[existingCase].EvidenceID = pack.EvidenceID
+
+	ioc := &models.IOC{
+		IndicatorID: "ioc-1",
+		Type:        models.IOCTypeURL,
+		Value:       "https://acme-login.example.com/auth",
+		Source:      "analyst",
+		Tags:        []string{"phishing"},
+		Emails:      []string{"Drop.Box[@]gmail[.]com", "not-an-address"},
+	}
+	request := &models.TakedownRequest{CaseID: "tdk-url"}
+	evidence := &models.EvidencePack{Content: &models.ContentAnalysis{DropMailboxes: []string{"logs@yahoo.com", "drop.box@gmail.com"}}}
+
+	machine.linkMailboxes(request, ioc, evidence)
+
+	caseID, ok := machine.OpenCase(models.IOCTypeEmail, "drop.box@gmail.com")
+	if !ok {
+		t.Fatal("Expected a linked email case")
+	}
+	linked := machine.iocs[caseID]
+	if !reflect.DeepEqual(linked.PhishingURLs, []string{ioc.Value}) || !linked.HasTag("phishing") || linked.Source != "analyst" {
+		t.Errorf("Unexpected linked IOC %+v", linked)
+	}
+	if got := machine.iocs[existingCase].PhishingURLs; !reflect.DeepEqual(got, []string{"https://old.example.com/", ioc.Value}) {
+		t.Errorf("Expected page attached to the open email case, got %v", got)
+	}
+	if want := []string{"hxxps://old[.]example[.]com/", "hxxps://acme-login[.]example[.]com/auth"}; !reflect.DeepEqual(pack.PhishingURLs, want) {
+		t.Errorf("Expected page attached to the evidence pack as %v, got %v", want, pack.PhishingURLs)
+	}
+	if len(machine.requests) != 2 {
+		t.Errorf("Expected 2 email cases, got %d", len(machine.requests))
+	}
+
+	events := map[string]int{}
+	for _, event := range request.History {
+		events[event.Event]++
+	}
+	if events["mailbox_linked"] != 2 || events["mailbox_invalid"] != 1 {
+		t.Errorf("Unexpected events %v", events)
+	}
+}
+
+// blockingTransport segura a primeira requisição até release ser fechado
+type blockingTransport struct {
+	started chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func (b *blockingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	b.once.Do(func() { close(b.started) })
+	<-b.release
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/octet-stream"}},
+		Body:       io.NopCloser(strings.NewReader("MZ")),
+		Request:    req,
+	}, nil
+}
+
+func TestMachine_LinkDuringEvidenceCollection(t *testing.T) {
+	transport := &blockingTransport{started: make(chan struct{}), release: make(chan struct{})}
+	collector := evidence.NewCollector()
+	collector.SetTransport(transport)
+	machine := NewMachine(collector, nil, nil)
+	defer machine.ticker.Stop()
+
+	page := &models.IOC{Type: models.IOCTypeURL, Value: "https://files.example.com/a.exe", Tags: []string{"malware"}}
+	if err := machine.ProcessIOC(page); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	caseID, _ := machine.OpenCase(models.IOCTypeURL, page.Value)
+
+	// Triagem, e então a coleta de evidências em outro goroutine, como num worker
+	machine.runStep(0, <-machine.workChan)
+	request := <-machine.workChan
+	if request.Status != models.StatusEvidencePack {
+		t.Fatalf("Expected %s, got %s", models.StatusEvidencePack, request.Status)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		machine.runStep(1, request)
+	}()
+	<-transport.started
+
+	// O vínculo corre em paralelo com o restante da coleta, sem ordem entre os dois
+	hash := strings.Repeat("cd", 32)
+	linkErr := make(chan error, 1)
+	go func() {
+		linkErr <- machine.ProcessIOC(&models.IOC{Value: hash, Source: "misp", DistributionURLs: []string{page.Value}})
+	}()
+	close(transport.release)
+	if err := <-linkErr; err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	<-done
+
+	// O roteamento ficou na fila: o vínculo entra quando o caso deixa de ter etapa pendente
+	machine.finishStep(caseID)
+	if got := machine.iocs[caseID].Hashes; !reflect.DeepEqual(got, []string{hash}) {
+		t.Errorf("Expected hash attached after evidence collection, got %v", got)
+	}
+	linked := false
+	for _, event := range request.History {
+		if event.Event == "sample_linked" {
+			linked = true
+		}
+	}
+	if !linked {
+		t.Error("Expected a sample_linked event")
+	}
+	if len(machine.requests) != 1 {
+		t.Errorf("Expected no new case, got %d cases", len(machine.requests))
+	}
+}
+
+func TestMachine_SelectTarget(t *testing.T) {
+	hosting := routing.ActionDefinition{Target: models.TakedownTarget{Type: "hosting", Entity: "Example Hosting"}, Action: models.ActionRemoveContent}
+	upstream := routing.ActionDefinition{Target: models.TakedownTarget{Type: "upstream", Entity: "Transit Co"}, Action: models.ActionNullRoute}
+	cert := routing.ActionDefinition{Target: models.TakedownTarget{Type: "cert", Entity: "CERT.br"}, Action: models.ActionCoordinate}
+
+	tests := []struct {
+		name        string
+		actions     []routing.ActionDefinition
+		wantOK      bool
+		wantType    string
+		wantSkipped int
+	}{
+		{name: "first action has a connector", actions: []routing.ActionDefinition{hosting, upstream}, wantOK: true, wantType: "hosting"},
+		{name: "falls back to the next action", actions: []routing.ActionDefinition{upstream, cert, hosting}, wantOK: true, wantType: "hosting", wantSkipped: 2},
+		{name: "no connector keeps the first target", actions: []routing.ActionDefinition{upstream, cert}, wantType: "upstream", wantSkipped: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			machine := NewMachine(nil, nil, nil)
+			defer machine.ticker.Stop()
+			machine.RegisterConnector(&stubConnector{connectorType: "hosting"})
+
+			request := &models.TakedownRequest{CaseID: "tdk-route", Status: models.StatusRoute}
+			if ok := machine.selectTarget(request, tt.actions); ok != tt.wantOK {
+				t.Fatalf("Expected %v, got %v", tt.wantOK, ok)
+			}
+			if request.Target.Type != tt.wantType {
+				t.Errorf("Expected %s target, got %s", tt.wantType, request.Target.Type)
+			}
+			skipped := 0
+			for _, event := range request.History {
+				if event.Event == "target_skipped" {
+					skipped++
+				}
+			}
+			if skipped != tt.wantSkipped {
+				t.Errorf("Expected %d skipped targets, got %d", tt.wantSkipped, skipped)
+			}
+		})
+	}
+}
+
+func TestMachine_SubmissionWithoutConnector(t *testing.T) {
+	machine := NewMachine(nil, nil, nil)
+	defer machine.ticker.Stop()
+
+	request := &models.TakedownRequest{
+		CaseID: "tdk-upstream",
+		Status: models.StatusSubmit,
+		Target: models.TakedownTarget{Type: "upstream", Entity: "Transit Co", Email: "abuse@transit.net", Source: models.ContactSourceRDAP, Confidence: 90},
+	}
+	if err := machine.handleSubmission(context.Background(), request); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if request.Status != models.StatusManual {
+		t.Errorf("Expected %s, got %s", models.StatusManual, request.Status)
+	}
+	found := false
+	for _, event := range request.History {
+		if event.Event == "no_connector" && strings.Contains(event.Notes, "upstream target Transit Co") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected no_connector event, got %+v", request.History)
+	}
+}
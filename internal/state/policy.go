@@ -0,0 +1,75 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// ContactPolicy define quais contatos podem receber email automaticamente
+type ContactPolicy struct {
+	AllowGuessed  bool // permite enviar para contatos deduzidos (source=guess)
+	MinConfidence int  // confiança mínima do contato (0 desativa a checagem)
+}
+
+// defaultMinContactConfidence recusa contatos que a validação marcou como sem email (5) ou inválidos (0)
+const defaultMinContactConfidence = 30
+
+// manualContactConfidence é a confiança de um contato definido pelo operador em Resume
+const manualContactConfidence = 100
+
+// DefaultContactPolicy bloqueia contatos deduzidos e os de confiança abaixo de defaultMinContactConfidence
+func DefaultContactPolicy() ContactPolicy {
+	return ContactPolicy{MinConfidence: defaultMinContactConfidence}
+}
+
+// Check verifica se o email do target pode ser usado na submissão
+func (p ContactPolicy) Check(target models.TakedownTarget) error {
+	if target.Email == "" {
+		return nil
+	}
+
+	if target.Source == models.ContactSourceGuess && !p.AllowGuessed {
+		return fmt.Errorf("contact %s was guessed and policy does not allow emailing guessed contacts", target.Email)
+	}
+
+	if p.MinConfidence > 0 && target.Confidence < p.MinConfidence {
+		return fmt.Errorf("contact %s has confidence %d, below policy minimum %d",
+			target.Email, target.Confidence, p.MinConfidence)
+	}
+
+	return nil
+}
+
+// manualChannel devolve o webform ou a API de targets sem email, que só o operador consegue submeter
+func manualChannel(target models.TakedownTarget) (channel, url string) {
+	switch {
+	case target.Email != "":
+		return "", ""
+	case target.Webform != "":
+		return "webform", target.Webform
+	case target.API != "":
+		return "api", target.API
+	}
+	return "", ""
+}
+
+// describeContact resume origem, confiança e motivo da escolha do contato para o histórico
+func describeContact(target models.TakedownTarget) string {
+	source := string(target.Source)
+	if source == "" {
+		source = "unknown"
+	}
+
+	contact := target.Email
+	if contact == "" {
+		contact = target.Webform
+	}
+
+	description := fmt.Sprintf("Contact %s for %s (source=%s, confidence=%d)",
+		contact, target.Entity, source, target.Confidence)
+	if target.Reason != "" {
+		description += ": " + target.Reason
+	}
+	return description
+}
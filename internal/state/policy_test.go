@@ -0,0 +1,372 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+func TestContactPolicy_Check(t *testing.T) {
+	guessed := models.TakedownTarget{Entity: "Example Hosting", Email: "abuse@examplehosting.com", Source: models.ContactSourceGuess, Confidence: 25}
+	rdap := models.TakedownTarget{Entity: "Registrar", Email: "abuse@registrar.test", Source: models.ContactSourceRDAP, Confidence: 90}
+	webformOnly := models.TakedownTarget{Entity: "Google Safe Browsing", Webform: "https://safebrowsing.google.com/"}
+	noMail := models.TakedownTarget{Entity: "Registrar", Email: "abuse@registrar.test", Source: models.ContactSourceRDAP, Confidence: 5, Reason: "domain does not receive email"}
+
+	tests := []struct {
+		name    string
+		policy  ContactPolicy
+		target  models.TakedownTarget
+		wantErr string
+	}{
+		{"Default rejects guessed", DefaultContactPolicy(), guessed, "guessed"},
+		{"Allow guessed", ContactPolicy{AllowGuessed: true}, guessed, ""},
+		{"Minimum confidence", ContactPolicy{AllowGuessed: true, MinConfidence: 50}, guessed, "below policy minimum"},
+		{"RDAP accepted", ContactPolicy{MinConfidence: 50}, rdap, ""},
+		{"Webform only", DefaultContactPolicy(), webformOnly, ""},
+		{"Default rejects domain without email", DefaultContactPolicy(), noMail, "below policy minimum"},
+		{"Default accepts RDAP", DefaultContactPolicy(), rdap, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Check(tt.target)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMachine_SubmissionRejectsContact(t *testing.T) {
+	tests := []struct {
+		name   string
+		target models.TakedownTarget
+	}{
+		{
+			name: "guessed",
+			target: models.TakedownTarget{
+				Entity: "Example Hosting",
+				Email:  "abuse@examplehosting.com",
+				Source: models.ContactSourceGuess,
+				Reason: `guessed from provider name "Example Hosting"`,
+			},
+		},
+		{
+			name: "domain without email",
+			target: models.TakedownTarget{
+				Entity:     "Example Hosting",
+				Email:      "abuse@examplehosting.com",
+				Source:     models.ContactSourceRDAP,
+				Confidence: 5,
+				Reason:     "source=rdap; domain does not receive email",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			machine := NewMachine(nil, nil, nil)
+			defer machine.ticker.Stop()
+
+			tt.target.Type = "hosting"
+			request := &models.TakedownRequest{CaseID: "tdk-test", Status: models.StatusSubmit, Target: tt.target}
+			connector := &stubConnector{connectorType: "hosting"}
+			machine.RegisterConnector(connector)
+
+			if err := machine.handleSubmission(context.Background(), request); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if connector.submitted != 0 {
+				t.Error("Expected rejected contact not to be submitted")
+			}
+			if request.Status != models.StatusManual {
+				t.Errorf("Expected case to move to %s, got %s", models.StatusManual, request.Status)
+			}
+
+			rejected := false
+			for _, event := range request.History {
+				if event.Event == "contact_rejected" && event.Reference == "abuse@examplehosting.com" {
+					rejected = true
+				}
+			}
+			if !rejected {
+				t.Errorf("Expected contact_rejected event, got %+v", request.History)
+			}
+			if err := machine.processRequest(request); err != nil {
+				t.Errorf("Expected case to wait for the operator, got %v", err)
+			}
+		})
+	}
+}
+
+func TestMachine_SubmissionManualChannel(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  models.TakedownTarget
+		channel string
+		url     string
+	}{
+		{
+			name:    "webform-only mail provider",
+			target:  models.TakedownTarget{Type: "mailbox", Entity: "Google (Gmail / Workspace)", Domain: "drop@gmail.com", Webform: "https://support.google.com/mail/contact/abuse", Source: models.ContactSourceDirectory, Confidence: 90},
+			channel: "webform",
+			url:     "https://support.google.com/mail/contact/abuse",
+		},
+		{
+			name:    "webform-only platform",
+			target:  models.TakedownTarget{Type: "platform", Entity: "GitHub Pages", Domain: "acme-login.github.io", Webform: "https://support.github.com/contact/report-abuse", Source: models.ContactSourceDirectory, Confidence: 90},
+			channel: "webform",
+			url:     "https://support.github.com/contact/report-abuse",
+		},
+		{
+			name:    "api-only platform",
+			target:  models.TakedownTarget{Type: "platform", Entity: "Example Pages", Domain: "acme.pages.example", API: "https://api.pages.example/abuse", Source: models.ContactSourceDirectory, Confidence: 90},
+			channel: "api",
+			url:     "https://api.pages.example/abuse",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			machine := NewMachine(nil, nil, nil)
+			defer machine.ticker.Stop()
+
+			connector := &stubConnector{connectorType: tt.target.Type}
+			machine.RegisterConnector(connector)
+			request := &models.TakedownRequest{CaseID: "tdk-webform", Status: models.StatusSubmit, Target: tt.target}
+
+			if err := machine.handleSubmission(context.Background(), request); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if connector.submitted != 0 {
+				t.Error("Expected the connector not to be called")
+			}
+			if request.Status != models.StatusManual {
+				t.Errorf("Expected %s, got %s", models.StatusManual, request.Status)
+			}
+
+			found := false
+			for _, event := range request.History {
+				if event.Event == "manual_submission_required" && event.Channel == tt.channel && event.Reference == tt.url {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("Expected manual_submission_required event for %s, got %+v", tt.url, request.History)
+			}
+			if err := machine.processRequest(request); err != nil {
+				t.Errorf("Expected case to wait for the operator, got %v", err)
+			}
+		})
+	}
+}
+
+func TestMachine_Resume(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     models.TakedownStatus
+		contact    *models.ContactInfo
+		wantStatus models.TakedownStatus
+		wantErr    string
+	}{
+		{name: "route again", status: models.StatusManual, wantStatus: models.StatusRoute},
+		{
+			name:       "operator contact",
+			status:     models.StatusManual,
+			contact:    &models.ContactInfo{Email: "noc@examplehosting.net"},
+			wantStatus: models.StatusSubmit,
+		},
+		{
+			name:       "webform submitted by the operator",
+			status:     models.StatusManual,
+			contact:    &models.ContactInfo{Webform: "https://abuse.examplehosting.net/report"},
+			wantStatus: models.StatusSubmitted,
+		},
+		{name: "contact without channel", status: models.StatusManual, contact: &models.ContactInfo{}, wantErr: "email or webform"},
+		{name: "invalid operator email", status: models.StatusManual, contact: &models.ContactInfo{Email: "noc@localhost"}, wantErr: "invalid operator email"},
+		{name: "case not waiting for the operator", status: models.StatusSubmitted, wantErr: "only needs_manual"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			machine := NewMachine(nil, nil, nil)
+			defer machine.ticker.Stop()
+
+			request := &models.TakedownRequest{
+				CaseID: "tdk-manual",
+				Status: tt.status,
+				Target: models.TakedownTarget{Type: "hosting", Entity: "Example Hosting", Email: "abuse@examplehosting.com", Source: models.ContactSourceGuess},
+			}
+			machine.requests[request.CaseID] = request
+
+			status, err := machine.Resume(request.CaseID, tt.contact)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				if request.Status != tt.status || request.Target.Source != models.ContactSourceGuess {
+					t.Errorf("Expected case untouched, got %s with %+v", request.Status, request.Target)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if status != tt.wantStatus || request.Status != tt.wantStatus {
+				t.Errorf("Expected %s, got %s (case %s)", tt.wantStatus, status, request.Status)
+			}
+			if tt.contact != nil {
+				if request.Target.Email != tt.contact.Email || request.Target.Webform != tt.contact.Webform || request.Target.Source != models.ContactSourceManual {
+					t.Errorf("Expected operator contact on the target, got %+v", request.Target)
+				}
+			}
+			switch status {
+			case models.StatusSubmit:
+				// O contato do operador passa pela política e vai ao connector, não de volta a needs_manual
+				if err := machine.policy.Check(request.Target); err != nil {
+					t.Errorf("Expected operator contact to pass the policy, got %v", err)
+				}
+				if channel, _ := manualChannel(request.Target); channel != "" {
+					t.Errorf("Expected an email submission, got manual channel %s", channel)
+				}
+			case models.StatusSubmitted:
+				if request.NextActionAt == nil {
+					t.Error("Expected the follow-up to be scheduled")
+				}
+			}
+		})
+	}
+
+	if _, err := NewMachine(nil, nil, nil).Resume("tdk-missing", nil); err == nil {
+		t.Error("Expected error for unknown case")
+	}
+}
+
+func TestMachine_ResumeConcurrent(t *testing.T) {
+	machine := NewMachine(nil, nil, nil)
+	defer machine.ticker.Stop()
+
+	request := &models.TakedownRequest{CaseID: "tdk-manual", Status: models.StatusManual}
+	machine.requests[request.CaseID] = request
+
+	const attempts = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := machine.Resume(request.CaseID, &models.ContactInfo{Email: "noc@examplehosting.net"})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	resumed := 0
+	for err := range errs {
+		if err == nil {
+			resumed++
+		} else if !errors.Is(err, errNotManual) {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+	if resumed != 1 || len(machine.workChan) != 1 {
+		t.Errorf("Expected the case resumed and queued once, got %d resumes and %d queued", resumed, len(machine.workChan))
+	}
+}
+
+func TestMachine_SubmissionRedactsEvidence(t *testing.T) {
+	machine := NewMachine(nil, nil, nil)
+	defer machine.ticker.Stop()
+
+	pack := &models.EvidencePack{
+		EvidenceID: "ev-redact",
+		Defanged:   "hxxps://bank-login[.]example/?user=victim@bank.com",
+		HTTP: models.HTTPInfo{
+			Headers: map[string]string{"Set-Cookie": "session=abc123"},
+			Body:    "welcome owner@bank.com",
+		},
+	}
+	machine.evidence[pack.EvidenceID] = pack
+
+	request := &models.TakedownRequest{
+		CaseID:     "tdk-redact",
+		EvidenceID: pack.EvidenceID,
+		Status:     models.StatusSubmit,
+		Target:     models.TakedownTarget{Type: "hosting", Entity: "Example Hosting", Email: "abuse@example.net", Source: models.ContactSourceRDAP, Confidence: 90},
+	}
+	connector := &stubConnector{connectorType: "hosting"}
+	machine.RegisterConnector(connector)
+
+	if err := machine.handleSubmission(context.Background(), request); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if connector.evidence == nil || strings.Contains(connector.evidence.HTTP.Body, "owner@bank.com") ||
+		connector.evidence.HTTP.Headers["Set-Cookie"] != "[REDACTED:cookie]" {
+		t.Fatalf("Connector received unredacted evidence: %+v", connector.evidence)
+	}
+	// O IOC é o objeto da denúncia e chega intacto ao connector
+	if connector.evidence.Defanged != pack.Defanged {
+		t.Errorf("IOC was redacted: %q", connector.evidence.Defanged)
+	}
+	if pack.HTTP.Headers["Set-Cookie"] != "session=abc123" {
+		t.Error("Stored evidence pack was modified")
+	}
+
+	found := false
+	for _, event := range request.History {
+		if event.Event == "evidence_redacted" {
+			found = event.Notes == "2 values redacted (cookie: 1, email: 1)"
+		}
+	}
+	if !found {
+		t.Errorf("Expected evidence_redacted event with the audit summary, got %+v", request.History)
+	}
+}
+
+func TestDescribeContact(t *testing.T) {
+	description := describeContact(models.TakedownTarget{
+		Entity:     "GoDaddy.com, LLC",
+		Email:      "abuse@godaddy.com",
+		Source:     models.ContactSourceRDAP,
+		Confidence: 90,
+		Reason:     "mx ok",
+	})
+
+	expected := "Contact abuse@godaddy.com for GoDaddy.com, LLC (source=rdap, confidence=90): mx ok"
+	if description != expected {
+		t.Errorf("Expected %q, got %q", expected, description)
+	}
+}
+
+// stubConnector registra submissões sem enviar nada
+type stubConnector struct {
+	connectorType string
+	submitted     int
+	evidence      *models.EvidencePack
+}
+
+func (c *stubConnector) Submit(_ context.Context, _ *models.TakedownRequest, evidence *models.EvidencePack) error {
+	c.submitted++
+	c.evidence = evidence
+	return nil
+}
+
+func (c *stubConnector) CheckStatus(_ context.Context, _ *models.TakedownRequest) (*StatusUpdate, error) {
+	return &StatusUpdate{Status: models.StatusFollowUp}, nil
+}
+
+func (c *stubConnector) GetType() string {
+	return c.connectorType
+}
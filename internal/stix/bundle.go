@@ -0,0 +1,87 @@
+// Package stix importa indicadores STIX 2.1 (bundles de TIPs e objetos de coleções TAXII)
+// como IOCs da pipeline de takedown.
+package stix
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Tipos de objeto STIX usados no mapeamento
+const (
+	TypeBundle            = "bundle"
+	TypeIndicator         = "indicator"
+	TypeMalware           = "malware"
+	TypeAttackPattern     = "attack-pattern"
+	TypeRelationship      = "relationship"
+	TypeMarkingDefinition = "marking-definition"
+)
+
+// Bundle é o envelope de objetos STIX 2.1
+type Bundle struct {
+	Type    string   `json:"type"`
+	ID      string   `json:"id"`
+	Objects []Object `json:"objects"`
+}
+
+// Object reúne os campos usados dos objetos STIX; cada tipo preenche só os seus
+type Object struct {
+	Type              string              `json:"type"`
+	ID                string              `json:"id"`
+	SpecVersion       string              `json:"spec_version,omitempty"`
+	Created           time.Time           `json:"created,omitempty"`
+	Modified          time.Time           `json:"modified,omitempty"`
+	CreatedByRef      string              `json:"created_by_ref,omitempty"`
+	Revoked           bool                `json:"revoked,omitempty"`
+	Labels            []string            `json:"labels,omitempty"`
+	Confidence        int                 `json:"confidence,omitempty"`
+	ObjectMarkingRefs []string            `json:"object_marking_refs,omitempty"`
+	ExternalRefs      []ExternalReference `json:"external_references,omitempty"`
+	Name              string              `json:"name,omitempty"`
+
+	// indicator
+	Pattern        string     `json:"pattern,omitempty"`
+	PatternType    string     `json:"pattern_type,omitempty"`
+	ValidFrom      time.Time  `json:"valid_from,omitempty"`
+	ValidUntil     *time.Time `json:"valid_until,omitempty"`
+	IndicatorTypes []string   `json:"indicator_types,omitempty"`
+
+	// malware e attack-pattern
+	MalwareTypes    []string         `json:"malware_types,omitempty"`
+	KillChainPhases []KillChainPhase `json:"kill_chain_phases,omitempty"`
+
+	// relationship
+	RelationshipType string `json:"relationship_type,omitempty"`
+	SourceRef        string `json:"source_ref,omitempty"`
+	TargetRef        string `json:"target_ref,omitempty"`
+
+	// marking-definition (TLP 1.0 em definition, TLP 2.0 só no nome)
+	DefinitionType string            `json:"definition_type,omitempty"`
+	Definition     map[string]string `json:"definition,omitempty"`
+}
+
+// ExternalReference aponta para fontes externas (ex.: técnica do MITRE ATT&CK)
+type ExternalReference struct {
+	SourceName string `json:"source_name"`
+	ExternalID string `json:"external_id,omitempty"`
+	URL        string `json:"url,omitempty"`
+}
+
+// KillChainPhase é uma fase de kill chain (ex.: mitre-attack/command-and-control)
+type KillChainPhase struct {
+	KillChainName string `json:"kill_chain_name"`
+	PhaseName     string `json:"phase_name"`
+}
+
+// ParseBundle interpreta um bundle STIX 2.1
+func ParseBundle(data []byte) (*Bundle, error) {
+	var bundle Bundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("invalid STIX bundle: %w", err)
+	}
+	if bundle.Type != TypeBundle {
+		return nil, fmt.Errorf("expected a STIX bundle, got type %q", bundle.Type)
+	}
+	return &bundle, nil
+}
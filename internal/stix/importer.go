@@ -0,0 +1,114 @@
+package stix
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// maxBundleBytes limita o tamanho dos bundles recebidos pela API
+const maxBundleBytes = 32 << 20
+
+// Sink recebe os IOCs importados (normalmente Machine.ProcessIOC)
+type Sink func(ioc *models.IOC) error
+
+// Report é o resultado de uma importação: o mapeamento e os casos abertos
+type Report struct {
+	*Result
+	Submitted int      `json:"submitted"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+// Importer mapeia bundles STIX e entrega os IOCs ao sink
+type Importer struct {
+	sink Sink
+	now  func() time.Time
+}
+
+// NewImporter cria um importador sem sink (apenas mapeia, como um dry-run)
+func NewImporter() *Importer {
+	return &Importer{now: func() time.Time { return time.Now().UTC() }}
+}
+
+// SetSink configura quem recebe os IOCs
+func (i *Importer) SetSink(sink Sink) {
+	i.sink = sink
+}
+
+// Import interpreta o bundle e entrega cada IOC ao sink; falhas do sink não interrompem os demais
+func (i *Importer) Import(data []byte, dryRun bool) (*Report, error) {
+	bundle, err := ParseBundle(data)
+	if err != nil {
+		return nil, err
+	}
+	result := Map(bundle, i.now())
+	if dryRun {
+		return &Report{Result: result}, nil
+	}
+	return i.Submit(result), nil
+}
+
+// Submit entrega os IOCs já mapeados ao sink (usado também pelo cliente TAXII)
+func (i *Importer) Submit(result *Result) *Report {
+	report := &Report{Result: result}
+	if i.sink == nil {
+		return report
+	}
+	for _, ioc := range result.IOCs {
+		if err := i.sink(ioc); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", ioc.Value, err))
+			continue
+		}
+		report.Submitted++
+	}
+	return report
+}
+
+// Handler expõe a importação por HTTP: POST com o bundle no corpo; ?dry_run=true só mapeia
+type Handler struct {
+	importer *Importer
+}
+
+// NewHandler cria o endpoint de importação
+func NewHandler(importer *Importer) *Handler {
+	return &Handler{importer: importer}
+}
+
+// ServeHTTP implementa http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxBundleBytes+1))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "failed to read body"})
+		return
+	}
+	if len(data) > maxBundleBytes {
+		writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": "bundle too large"})
+		return
+	}
+
+	report, err := h.importer.Import(data, dryRun)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// writeJSON grava a resposta em JSON
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
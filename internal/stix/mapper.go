@@ -0,0 +1,248 @@
+package stix
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cti-team/takedown/pkg/indicator"
+	"github.com/cti-team/takedown/pkg/models"
+	"github.com/google/uuid"
+)
+
+const (
+	// SourceSTIX é a origem dos IOCs importados de STIX
+	SourceSTIX = "stix"
+	// RefPrefix identifica os IDs STIX em IOC.IntelRefs
+	RefPrefix = "stix:"
+)
+
+// tlpMarkings são as marking-definitions TLP 1.0 predefinidas pela especificação
+var tlpMarkings = map[string]string{
+	"marking-definition--613f2e26-407d-48c7-9eca-b8e91df99dc9": "white",
+	"marking-definition--34098fce-860f-48ae-8e50-ebd3cc5e41da": "green",
+	"marking-definition--f88d31f6-486f-44da-b317-01333bde0b82": "amber",
+	"marking-definition--5e57c739-391a-4eb3-b6be-7d15ca92d5ed": "red",
+}
+
+// categoryKeywords mapeia termos de labels, indicator_types e malware_types para as tags de roteamento
+var categoryKeywords = map[string]string{
+	"phishing":             "phishing",
+	"phish":                "phishing",
+	"credential-phishing":  "phishing",
+	"spearphishing":        "phishing",
+	"malware":              "malware",
+	"ransomware":           "malware",
+	"trojan":               "malware",
+	"dropper":              "malware",
+	"downloader":           "malware",
+	"stealer":              "malware",
+	"infostealer":          "malware",
+	"backdoor":             "malware",
+	"spyware":              "malware",
+	"worm":                 "malware",
+	"exploit-kit":          "malware",
+	"malware-distribution": "malware",
+	"c2":                   "c2",
+	"c&c":                  "c2",
+	"command-and-control":  "c2",
+	"botnet":               "c2",
+	"bot":                  "c2",
+	"remote-access-trojan": "c2",
+}
+
+// Skipped registra um objeto que não gerou IOC e o motivo
+type Skipped struct {
+	ObjectID string `json:"object_id"`
+	Reason   string `json:"reason"`
+}
+
+// Result é o resultado do mapeamento de um conjunto de objetos
+type Result struct {
+	Indicators int           `json:"indicators"`
+	IOCs       []*models.IOC `json:"iocs"`
+	Skipped    []Skipped     `json:"skipped,omitempty"`
+}
+
+// Map converte os indicadores do bundle em IOCs
+func Map(bundle *Bundle, now time.Time) *Result {
+	result := MapObjects(bundle.Objects, now)
+	if bundle.ID != "" {
+		for _, ioc := range result.IOCs {
+			ioc.IntelRefs = append(ioc.IntelRefs, RefPrefix+bundle.ID)
+		}
+	}
+	return result
+}
+
+// MapObjects converte os indicadores em IOCs, com tags vindas de labels, TLP e dos objetos
+// malware e attack-pattern ligados por relacionamentos "indicates". Indicadores revogados,
+// expirados ou com padrão que não seja STIX são ignorados.
+func MapObjects(objects []Object, now time.Time) *Result {
+	byID := make(map[string]*Object, len(objects))
+	for i := range objects {
+		byID[objects[i].ID] = &objects[i]
+	}
+	related := make(map[string][]*Object)
+	for i := range objects {
+		object := &objects[i]
+		if object.Type != TypeRelationship || object.RelationshipType != "indicates" {
+			continue
+		}
+		if target, ok := byID[object.TargetRef]; ok {
+			related[object.SourceRef] = append(related[object.SourceRef], target)
+		}
+	}
+
+	result := &Result{IOCs: []*models.IOC{}}
+	for i := range objects {
+		indicator := &objects[i]
+		if indicator.Type != TypeIndicator {
+			continue
+		}
+		result.Indicators++
+
+		if reason := unusable(indicator, now); reason != "" {
+			result.Skipped = append(result.Skipped, Skipped{ObjectID: indicator.ID, Reason: reason})
+			continue
+		}
+		observables, err := ParsePattern(indicator.Pattern)
+		if err != nil {
+			result.Skipped = append(result.Skipped, Skipped{ObjectID: indicator.ID, Reason: err.Error()})
+			continue
+		}
+		if len(observables) == 0 {
+			result.Skipped = append(result.Skipped, Skipped{ObjectID: indicator.ID, Reason: "no url, domain or IP equality in pattern"})
+			continue
+		}
+
+		tags, refs := indicatorContext(indicator, related[indicator.ID], byID)
+		for _, observable := range observables {
+			value, err := validObservable(observable)
+			if err != nil {
+				result.Skipped = append(result.Skipped, Skipped{ObjectID: indicator.ID, Reason: err.Error()})
+				continue
+			}
+			result.IOCs = append(result.IOCs, &models.IOC{
+				IndicatorID: "stix-" + uuid.New().String(),
+				Type:        observable.Type,
+				Value:       value,
+				FirstSeen:   firstSeen(indicator),
+				Source:      SourceSTIX,
+				Tags:        append([]string(nil), tags...),
+				IntelRefs:   append([]string(nil), refs...),
+			})
+		}
+	}
+	return result
+}
+
+// validObservable valida o valor extraído do padrão e retorna a forma canônica
+func validObservable(observable Observable) (string, error) {
+	validated, err := indicator.Validate(observable.Value, observable.Type)
+	if err != nil {
+		return "", err
+	}
+	return validated.Value, nil
+}
+
+// unusable retorna o motivo para ignorar o indicador (vazio se for utilizável)
+func unusable(indicator *Object, now time.Time) string {
+	switch {
+	case indicator.Revoked:
+		return "indicator revoked"
+	case indicator.PatternType != "" && indicator.PatternType != "stix":
+		return fmt.Sprintf("unsupported pattern type %q", indicator.PatternType)
+	case indicator.ValidUntil != nil && indicator.ValidUntil.Before(now):
+		return "indicator expired at " + indicator.ValidUntil.Format(time.RFC3339)
+	default:
+		return ""
+	}
+}
+
+// firstSeen usa valid_from e, na falta dele, a criação do objeto
+func firstSeen(indicator *Object) time.Time {
+	if !indicator.ValidFrom.IsZero() {
+		return indicator.ValidFrom.UTC()
+	}
+	return indicator.Created.UTC()
+}
+
+// indicatorContext monta as tags e as referências STIX do indicador
+func indicatorContext(indicator *Object, related []*Object, byID map[string]*Object) ([]string, []string) {
+	tags := make(map[string]bool)
+	refs := []string{RefPrefix + indicator.ID}
+
+	addCategories(tags, indicator.Labels...)
+	addCategories(tags, indicator.IndicatorTypes...)
+	for _, marking := range indicator.ObjectMarkingRefs {
+		if level := tlpLevel(marking, byID); level != "" {
+			tags["tlp:"+level] = true
+		}
+	}
+
+	for _, object := range related {
+		refs = append(refs, RefPrefix+object.ID)
+		switch object.Type {
+		case TypeMalware:
+			tags["malware"] = true
+			addCategories(tags, object.MalwareTypes...)
+			addCategories(tags, object.Labels...)
+		case TypeAttackPattern:
+			addAttackPattern(tags, object)
+		}
+	}
+
+	list := make([]string, 0, len(tags))
+	for tag := range tags {
+		list = append(list, tag)
+	}
+	sort.Strings(list)
+	return list, refs
+}
+
+// addCategories adiciona as tags dos termos conhecidos
+func addCategories(tags map[string]bool, terms ...string) {
+	for _, term := range terms {
+		if category, ok := categoryKeywords[strings.ToLower(strings.TrimSpace(term))]; ok {
+			tags[category] = true
+		}
+	}
+}
+
+// addAttackPattern mapeia técnicas do ATT&CK: phishing (T1566, T1598) e fases de comando e controle
+func addAttackPattern(tags map[string]bool, pattern *Object) {
+	if strings.Contains(strings.ToLower(pattern.Name), "phishing") {
+		tags["phishing"] = true
+	}
+	for _, ref := range pattern.ExternalRefs {
+		if strings.HasPrefix(ref.ExternalID, "T1566") || strings.HasPrefix(ref.ExternalID, "T1598") {
+			tags["phishing"] = true
+		}
+	}
+	for _, phase := range pattern.KillChainPhases {
+		if phase.PhaseName == "command-and-control" {
+			tags["c2"] = true
+		}
+	}
+}
+
+// tlpLevel resolve o nível TLP de uma marking (predefinida ou definida no próprio bundle)
+func tlpLevel(ref string, byID map[string]*Object) string {
+	if level, ok := tlpMarkings[ref]; ok {
+		return level
+	}
+	marking, ok := byID[ref]
+	if !ok || marking.Type != TypeMarkingDefinition {
+		return ""
+	}
+	if level := marking.Definition["tlp"]; level != "" && marking.DefinitionType == "tlp" {
+		return strings.ToLower(level)
+	}
+	// TLP 2.0 usa extensões; o nome segue o formato "TLP:AMBER+STRICT"
+	if name := strings.ToUpper(marking.Name); strings.HasPrefix(name, "TLP:") {
+		return strings.ToLower(strings.TrimPrefix(name, "TLP:"))
+	}
+	return ""
+}
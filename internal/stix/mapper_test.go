@@ -0,0 +1,215 @@
+package stix
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// testBundle é um bundle de TIP com phishing, C2, um indicador expirado e um YARA
+const testBundle = `{
+  "type": "bundle",
+  "id": "bundle--5d0092c5-5f74-4287-9642-33f4c354e56d",
+  "objects": [
+    {
+      "type": "indicator", "spec_version": "2.1",
+      "id": "indicator--a1",
+      "created": "2025-02-01T10:00:00Z",
+      "valid_from": "2025-02-03T08:30:00Z",
+      "labels": ["Phishing"],
+      "pattern_type": "stix",
+      "pattern": "[url:value = 'https://acme-login.com/auth'] OR [domain-name:value = 'acme-login.com']",
+      "object_marking_refs": ["marking-definition--f88d31f6-486f-44da-b317-01333bde0b82"]
+    },
+    {
+      "type": "indicator", "spec_version": "2.1",
+      "id": "indicator--c2",
+      "created": "2025-02-02T00:00:00Z",
+      "valid_from": "2025-02-02T00:00:00Z",
+      "indicator_types": ["malicious-activity"],
+      "pattern_type": "stix",
+      "pattern": "[ipv4-addr:value = '45.67.89.77']",
+      "object_marking_refs": ["marking-definition--tlp2-red"]
+    },
+    {
+      "type": "indicator", "spec_version": "2.1",
+      "id": "indicator--old",
+      "valid_from": "2024-01-01T00:00:00Z",
+      "valid_until": "2024-02-01T00:00:00Z",
+      "pattern_type": "stix",
+      "pattern": "[domain-name:value = 'old.com']"
+    },
+    {
+      "type": "indicator", "spec_version": "2.1",
+      "id": "indicator--yara",
+      "valid_from": "2025-02-01T00:00:00Z",
+      "pattern_type": "yara",
+      "pattern": "rule x { condition: true }"
+    },
+    {
+      "type": "malware", "spec_version": "2.1",
+      "id": "malware--rat", "name": "ExampleRAT",
+      "malware_types": ["remote-access-trojan"], "is_family": true
+    },
+    {
+      "type": "attack-pattern", "spec_version": "2.1",
+      "id": "attack-pattern--t1566", "name": "Phishing",
+      "external_references": [{"source_name": "mitre-attack", "external_id": "T1566"}]
+    },
+    {
+      "type": "relationship", "spec_version": "2.1",
+      "id": "relationship--1", "relationship_type": "indicates",
+      "source_ref": "indicator--c2", "target_ref": "malware--rat"
+    },
+    {
+      "type": "relationship", "spec_version": "2.1",
+      "id": "relationship--2", "relationship_type": "indicates",
+      "source_ref": "indicator--a1", "target_ref": "attack-pattern--t1566"
+    },
+    {
+      "type": "marking-definition", "spec_version": "2.1",
+      "id": "marking-definition--tlp2-red", "name": "TLP:RED"
+    }
+  ]
+}`
+
+func TestMap(t *testing.T) {
+	bundle, err := ParseBundle([]byte(testBundle))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := Map(bundle, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	if result.Indicators != 4 {
+		t.Errorf("Indicators = %d, want 4", result.Indicators)
+	}
+	if len(result.Skipped) != 2 || !strings.Contains(result.Skipped[0].Reason, "expired") ||
+		!strings.Contains(result.Skipped[1].Reason, "yara") {
+		t.Errorf("unexpected skipped objects: %+v", result.Skipped)
+	}
+
+	tests := []struct {
+		value     string
+		iocType   models.IOCType
+		tags      []string
+		refs      []string
+		firstSeen string
+	}{
+		{
+			value: "https://acme-login.com/auth", iocType: models.IOCTypeURL,
+			tags:      []string{"phishing", "tlp:amber"},
+			refs:      []string{"stix:indicator--a1", "stix:attack-pattern--t1566", "stix:bundle--5d0092c5-5f74-4287-9642-33f4c354e56d"},
+			firstSeen: "2025-02-03T08:30:00Z",
+		},
+		{
+			value: "acme-login.com", iocType: models.IOCTypeDomain,
+			tags:      []string{"phishing", "tlp:amber"},
+			refs:      []string{"stix:indicator--a1", "stix:attack-pattern--t1566", "stix:bundle--5d0092c5-5f74-4287-9642-33f4c354e56d"},
+			firstSeen: "2025-02-03T08:30:00Z",
+		},
+		{
+			value: "45.67.89.77", iocType: models.IOCTypeIP,
+			tags:      []string{"c2", "malware", "tlp:red"},
+			refs:      []string{"stix:indicator--c2", "stix:malware--rat", "stix:bundle--5d0092c5-5f74-4287-9642-33f4c354e56d"},
+			firstSeen: "2025-02-02T00:00:00Z",
+		},
+	}
+	if len(result.IOCs) != len(tests) {
+		t.Fatalf("got %d IOCs, want %d", len(result.IOCs), len(tests))
+	}
+	for i, tt := range tests {
+		ioc := result.IOCs[i]
+		if ioc.Value != tt.value || ioc.Type != tt.iocType || ioc.Source != SourceSTIX {
+			t.Errorf("IOC %d = %s %s (%s), want %s %s", i, ioc.Type, ioc.Value, ioc.Source, tt.iocType, tt.value)
+		}
+		if !reflect.DeepEqual(ioc.Tags, tt.tags) {
+			t.Errorf("IOC %s tags = %v, want %v", tt.value, ioc.Tags, tt.tags)
+		}
+		if !reflect.DeepEqual(ioc.IntelRefs, tt.refs) {
+			t.Errorf("IOC %s refs = %v, want %v", tt.value, ioc.IntelRefs, tt.refs)
+		}
+		if got := ioc.FirstSeen.Format(time.RFC3339); got != tt.firstSeen {
+			t.Errorf("IOC %s first seen = %s, want %s", tt.value, got, tt.firstSeen)
+		}
+		if !strings.HasPrefix(ioc.IndicatorID, "stix-") {
+			t.Errorf("unexpected indicator ID %s", ioc.IndicatorID)
+		}
+	}
+}
+
+func TestParseBundleRejectsOtherTypes(t *testing.T) {
+	for _, input := range []string{`{"type": "indicator"}`, `not json`} {
+		if _, err := ParseBundle([]byte(input)); err == nil {
+			t.Errorf("ParseBundle(%q) accepted invalid input", input)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	var received []string
+	importer := NewImporter()
+	importer.SetSink(func(ioc *models.IOC) error {
+		if ioc.Type == models.IOCTypeIP {
+			return errors.New("queue full")
+		}
+		received = append(received, ioc.Value)
+		return nil
+	})
+	server := httptest.NewServer(NewHandler(importer))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		method        string
+		query         string
+		body          string
+		wantStatus    int
+		wantSubmitted int
+		wantErrors    int
+		wantReceived  int
+	}{
+		{name: "import", method: http.MethodPost, body: testBundle, wantStatus: http.StatusOK, wantSubmitted: 2, wantErrors: 1, wantReceived: 2},
+		{name: "dry run", method: http.MethodPost, query: "?dry_run=true", body: testBundle, wantStatus: http.StatusOK},
+		{name: "invalid bundle", method: http.MethodPost, body: `{"type":"x"}`, wantStatus: http.StatusBadRequest},
+		{name: "wrong method", method: http.MethodGet, wantStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received = nil
+			req, err := http.NewRequest(tt.method, server.URL+tt.query, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var report Report
+			if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+				t.Fatal(err)
+			}
+			if len(report.IOCs) != 3 || report.Submitted != tt.wantSubmitted || len(report.Errors) != tt.wantErrors {
+				t.Errorf("unexpected report: %d IOCs, %d submitted, errors %v", len(report.IOCs), report.Submitted, report.Errors)
+			}
+			if len(received) != tt.wantReceived {
+				t.Errorf("sink received %v", received)
+			}
+		})
+	}
+}
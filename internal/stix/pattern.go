@@ -0,0 +1,121 @@
+package stix
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// Observable é um valor extraído de uma comparação de igualdade do padrão
+type Observable struct {
+	ObjectType string         `json:"object_type"` // url, domain-name, ipv4-addr, ipv6-addr
+	Type       models.IOCType `json:"type"`
+	Value      string         `json:"value"`
+}
+
+// observableTypes mapeia os objetos de observação suportados para tipos de IOC
+var observableTypes = map[string]models.IOCType{
+	"url":         models.IOCTypeURL,
+	"domain-name": models.IOCTypeDomain,
+	"ipv4-addr":   models.IOCTypeIP,
+	"ipv6-addr":   models.IOCTypeIP,
+}
+
+// comparisonPattern casa "<objeto>:value = '<literal>'" no esqueleto do padrão (literais trocados por '#n')
+var comparisonPattern = regexp.MustCompile(`([a-z0-9][a-z0-9-]*):value\s*=\s*'#(\d+)'`)
+
+// ParsePattern extrai os valores de url, domain-name e ipv4/ipv6-addr de um padrão STIX.
+// Cada comparação de igualdade vira um observable; LIKE, MATCHES e outros objetos são ignorados.
+func ParsePattern(pattern string) ([]Observable, error) {
+	skeleton, literals, err := splitLiterals(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(strings.TrimSpace(skeleton), "[") {
+		return nil, errors.New("pattern must start with an observation expression")
+	}
+
+	var observables []Observable
+	seen := make(map[string]bool)
+	for _, match := range comparisonPattern.FindAllStringSubmatch(skeleton, -1) {
+		iocType, ok := observableTypes[match[1]]
+		if !ok {
+			continue
+		}
+		index, _ := strconv.Atoi(match[2])
+		value := strings.TrimSpace(literals[index])
+		if iocType == models.IOCTypeIP {
+			// Um /32 (ou /128) é um único host; faixas não são alvos de takedown
+			if host, ok := singleHost(value); ok {
+				value = host
+			} else {
+				continue
+			}
+		}
+		if value == "" || seen[match[1]+"|"+value] {
+			continue
+		}
+		seen[match[1]+"|"+value] = true
+		observables = append(observables, Observable{ObjectType: match[1], Type: iocType, Value: value})
+	}
+	return observables, nil
+}
+
+// splitLiterals troca os literais entre aspas por '#n', devolvendo os literais sem escapes
+func splitLiterals(pattern string) (string, []string, error) {
+	var skeleton strings.Builder
+	var literals []string
+
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '\'' {
+			skeleton.WriteByte(pattern[i])
+			continue
+		}
+
+		var literal strings.Builder
+		closed := false
+		for i++; i < len(pattern); i++ {
+			switch pattern[i] {
+			case '\\':
+				if i+1 < len(pattern) {
+					i++
+					literal.WriteByte(pattern[i])
+				}
+				continue
+			case '\'':
+				closed = true
+			default:
+				literal.WriteByte(pattern[i])
+				continue
+			}
+			break
+		}
+		if !closed {
+			return "", nil, errors.New("unterminated string literal in pattern")
+		}
+		fmt.Fprintf(&skeleton, "'#%d'", len(literals))
+		literals = append(literals, literal.String())
+	}
+	return skeleton.String(), literals, nil
+}
+
+// singleHost aceita um IP ou um CIDR de um único endereço
+func singleHost(value string) (string, bool) {
+	if !strings.Contains(value, "/") {
+		return value, net.ParseIP(value) != nil
+	}
+	ip, network, err := net.ParseCIDR(value)
+	if err != nil {
+		return "", false
+	}
+	ones, bits := network.Mask.Size()
+	if ones != bits {
+		return "", false
+	}
+	return ip.String(), true
+}
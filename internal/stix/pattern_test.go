@@ -0,0 +1,84 @@
+package stix
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+func TestParsePattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		want    []Observable
+		wantErr bool
+	}{
+		{
+			name:    "url",
+			pattern: "[url:value = 'https://acme-login.example/auth']",
+			want:    []Observable{{ObjectType: "url", Type: models.IOCTypeURL, Value: "https://acme-login.example/auth"}},
+		},
+		{
+			name:    "domain",
+			pattern: "[domain-name:value = 'acme-login.example']",
+			want:    []Observable{{ObjectType: "domain-name", Type: models.IOCTypeDomain, Value: "acme-login.example"}},
+		},
+		{
+			name:    "ipv4 with host cidr",
+			pattern: "[ipv4-addr:value = '203.0.113.10/32']",
+			want:    []Observable{{ObjectType: "ipv4-addr", Type: models.IOCTypeIP, Value: "203.0.113.10"}},
+		},
+		{
+			name:    "ipv4 network ignored",
+			pattern: "[ipv4-addr:value = '203.0.113.0/24']",
+		},
+		{
+			name:    "ipv6",
+			pattern: "[ipv6-addr:value = '2001:db8::10']",
+			want:    []Observable{{ObjectType: "ipv6-addr", Type: models.IOCTypeIP, Value: "2001:db8::10"}},
+		},
+		{
+			name:    "or within and across observations",
+			pattern: "[domain-name:value = 'a.example' OR domain-name:value = 'b.example'] OR [url:value = 'http://c.example/']",
+			want: []Observable{
+				{ObjectType: "domain-name", Type: models.IOCTypeDomain, Value: "a.example"},
+				{ObjectType: "domain-name", Type: models.IOCTypeDomain, Value: "b.example"},
+				{ObjectType: "url", Type: models.IOCTypeURL, Value: "http://c.example/"},
+			},
+		},
+		{
+			name:    "escaped quote",
+			pattern: `[url:value = 'http://x.example/it\'s']`,
+			want:    []Observable{{ObjectType: "url", Type: models.IOCTypeURL, Value: "http://x.example/it's"}},
+		},
+		{
+			name:    "comparison text inside literal",
+			pattern: "[url:value = 'http://x.example/?q=domain-name:value = evil'] AND [file:name = 'a.exe']",
+			want:    []Observable{{ObjectType: "url", Type: models.IOCTypeURL, Value: "http://x.example/?q=domain-name:value = evil"}},
+		},
+		{
+			name:    "unsupported object and operator",
+			pattern: "[file:hashes.'SHA-256' = 'abc'] OR [domain-name:value LIKE '%.example']",
+		},
+		{
+			name:    "duplicate values",
+			pattern: "[domain-name:value = 'a.example'] OR [domain-name:value = 'a.example']",
+			want:    []Observable{{ObjectType: "domain-name", Type: models.IOCTypeDomain, Value: "a.example"}},
+		},
+		{name: "unterminated literal", pattern: "[url:value = 'http://x", wantErr: true},
+		{name: "not an observation", pattern: "url:value = 'http://x'", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePattern(tt.pattern)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePattern error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParsePattern = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package taxii
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cti-team/takedown/internal/stix"
+)
+
+const (
+	// MediaType é o tipo de conteúdo TAXII 2.1
+	MediaType = "application/taxii+json;version=2.1"
+	// DateAddedLastHeader traz o date_added do último objeto da página
+	DateAddedLastHeader = "X-TAXII-Date-Added-Last"
+	// TimestampFormat é o formato dos filtros added_after
+	TimestampFormat = "2006-01-02T15:04:05.000Z"
+
+	// maxPageBytes limita o tamanho de uma página de objetos
+	maxPageBytes = 64 << 20
+)
+
+// Page é uma página do endpoint de objetos (envelope TAXII)
+type Page struct {
+	More          bool          `json:"more"`
+	Next          string        `json:"next,omitempty"`
+	Objects       []stix.Object `json:"objects"`
+	DateAddedLast string        `json:"-"`
+}
+
+// Collection acessa o endpoint de objetos de uma coleção TAXII 2.1
+type Collection struct {
+	name       string
+	objectsURL string
+	auth       AuthConfig
+	username   string
+	secret     string // senha (basic) ou token (bearer)
+	pageSize   int
+	httpClient *http.Client
+}
+
+// NewCollection cria o acesso à coleção, lendo as credenciais das variáveis de ambiente configuradas
+func NewCollection(config CollectionConfig, timeout time.Duration) (*Collection, error) {
+	root, err := url.Parse(config.APIRoot)
+	if err != nil || root.Scheme == "" || root.Host == "" {
+		return nil, fmt.Errorf("collection %q: invalid api_root %q", config.Name, config.APIRoot)
+	}
+
+	collection := &Collection{
+		name:       config.Name,
+		objectsURL: strings.TrimSuffix(config.APIRoot, "/") + "/collections/" + url.PathEscape(config.CollectionID) + "/objects/",
+		auth:       config.Auth,
+		pageSize:   config.PageSize,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+	switch config.Auth.Type {
+	case AuthBasic:
+		collection.username = os.Getenv(config.Auth.UsernameEnv)
+		collection.secret = os.Getenv(config.Auth.PasswordEnv)
+		if collection.username == "" || collection.secret == "" {
+			return nil, fmt.Errorf("collection %q: %s and %s must be set", config.Name, config.Auth.UsernameEnv, config.Auth.PasswordEnv)
+		}
+	case AuthBearer:
+		collection.secret = os.Getenv(config.Auth.TokenEnv)
+		if collection.secret == "" {
+			return nil, fmt.Errorf("collection %q: %s must be set", config.Name, config.Auth.TokenEnv)
+		}
+	}
+	return collection, nil
+}
+
+// Name retorna o nome configurado da coleção
+func (c *Collection) Name() string {
+	return c.name
+}
+
+// Objects busca uma página de objetos adicionados depois de addedAfter; next continua a paginação
+func (c *Collection) Objects(ctx context.Context, addedAfter, next string) (*Page, error) {
+	query := url.Values{}
+	if addedAfter != "" {
+		query.Set("added_after", addedAfter)
+	}
+	if next != "" {
+		query.Set("next", next)
+	}
+	if c.pageSize > 0 {
+		query.Set("limit", strconv.Itoa(c.pageSize))
+	}
+	requestURL := c.objectsURL
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create taxii request: %w", err)
+	}
+	req.Header.Set("Accept", MediaType)
+	switch c.auth.Type {
+	case AuthBasic:
+		req.SetBasicAuth(c.username, c.secret)
+	case AuthBearer:
+		req.Header.Set("Authorization", "Bearer "+c.secret)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("taxii request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("taxii server returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var page Page
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxPageBytes)).Decode(&page); err != nil {
+		return nil, fmt.Errorf("failed to decode taxii envelope: %w", err)
+	}
+	page.DateAddedLast = resp.Header.Get(DateAddedLastHeader)
+	return &page, nil
+}
@@ -0,0 +1,118 @@
+// Package taxii consulta coleções TAXII 2.1 e entrega os indicadores STIX à
+// pipeline de takedown, guardando em disco até onde cada coleção já foi lida.
+package taxii
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Tipos de autenticação suportados
+const (
+	AuthNone   = ""
+	AuthBasic  = "basic"
+	AuthBearer = "bearer"
+)
+
+// AuthConfig define as credenciais, sempre lidas de variáveis de ambiente
+type AuthConfig struct {
+	Type        string `yaml:"type"` // basic, bearer ou vazio
+	UsernameEnv string `yaml:"username_env,omitempty"`
+	PasswordEnv string `yaml:"password_env,omitempty"`
+	TokenEnv    string `yaml:"token_env,omitempty"`
+}
+
+// CollectionConfig descreve uma coleção TAXII
+type CollectionConfig struct {
+	Name         string     `yaml:"name"`
+	APIRoot      string     `yaml:"api_root"` // ex: https://taxii.isac.example/api1/
+	CollectionID string     `yaml:"collection_id"`
+	Auth         AuthConfig `yaml:"auth"`
+	PageSize     int        `yaml:"page_size,omitempty"`     // limit por página (0: padrão do servidor)
+	AddedAfter   string     `yaml:"added_after,omitempty"`   // início da leitura quando ainda não há cursor
+	LookbackDays int        `yaml:"lookback_days,omitempty"` // alternativa a added_after: dias antes da primeira leitura
+}
+
+// Config define as coleções e o ritmo do poller
+type Config struct {
+	IntervalSeconds int                `yaml:"interval_seconds"`
+	MaxPagesPerPoll int                `yaml:"max_pages_per_poll"` // limita o trabalho por ciclo em coleções grandes
+	CursorPath      string             `yaml:"cursor_path"`        // arquivo JSON com o cursor de cada coleção
+	Collections     []CollectionConfig `yaml:"collections"`
+}
+
+// DefaultConfig retorna a configuração padrão, sem coleções (espelha configs/taxii/taxii.yaml)
+func DefaultConfig() Config {
+	return Config{
+		IntervalSeconds: 900,
+		MaxPagesPerPoll: 50,
+		CursorPath:      "data/taxii-cursor.json",
+	}
+}
+
+// LoadConfig carrega a configuração de um arquivo YAML sobre os valores padrão
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- caminho vem da configuração do operador
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read taxii config: %w", err)
+	}
+	return ParseConfig(data)
+}
+
+// ParseConfig interpreta e valida a configuração; campos ausentes mantêm o padrão
+func ParseConfig(data []byte) (Config, error) {
+	config := DefaultConfig()
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return Config{}, fmt.Errorf("failed to parse taxii config: %w", err)
+	}
+
+	if config.IntervalSeconds <= 0 {
+		return Config{}, fmt.Errorf("interval_seconds must be positive, got %d", config.IntervalSeconds)
+	}
+	if config.MaxPagesPerPoll <= 0 {
+		return Config{}, fmt.Errorf("max_pages_per_poll must be positive, got %d", config.MaxPagesPerPoll)
+	}
+	if config.CursorPath == "" {
+		return Config{}, fmt.Errorf("cursor_path is required")
+	}
+
+	seen := make(map[string]bool, len(config.Collections))
+	for i, collection := range config.Collections {
+		if collection.Name == "" {
+			return Config{}, fmt.Errorf("collection %d has no name", i+1)
+		}
+		if seen[collection.Name] {
+			return Config{}, fmt.Errorf("duplicate collection %q", collection.Name)
+		}
+		seen[collection.Name] = true
+		if collection.APIRoot == "" || collection.CollectionID == "" {
+			return Config{}, fmt.Errorf("collection %q needs api_root and collection_id", collection.Name)
+		}
+		if collection.PageSize < 0 || collection.LookbackDays < 0 {
+			return Config{}, fmt.Errorf("collection %q: page_size and lookback_days cannot be negative", collection.Name)
+		}
+		if collection.AddedAfter != "" {
+			if _, err := time.Parse(time.RFC3339Nano, collection.AddedAfter); err != nil {
+				return Config{}, fmt.Errorf("collection %q: invalid added_after: %w", collection.Name, err)
+			}
+		}
+		switch collection.Auth.Type {
+		case AuthNone:
+		case AuthBasic:
+			if collection.Auth.UsernameEnv == "" || collection.Auth.PasswordEnv == "" {
+				return Config{}, fmt.Errorf("collection %q: basic auth needs username_env and password_env", collection.Name)
+			}
+		case AuthBearer:
+			if collection.Auth.TokenEnv == "" {
+				return Config{}, fmt.Errorf("collection %q: bearer auth needs token_env", collection.Name)
+			}
+		default:
+			return Config{}, fmt.Errorf("collection %q: unknown auth type %q", collection.Name, collection.Auth.Type)
+		}
+	}
+
+	return config, nil
+}
@@ -0,0 +1,71 @@
+package taxii
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// CursorStore guarda, por coleção, o date_added do último objeto lido (usado como added_after)
+type CursorStore struct {
+	path    string
+	cursors map[string]string
+	mutex   sync.Mutex
+}
+
+// LoadCursors lê o arquivo de cursores; arquivo ausente começa vazio e caminho vazio mantém os cursores só em memória
+func LoadCursors(path string) (*CursorStore, error) {
+	store := &CursorStore{path: path, cursors: make(map[string]string)}
+	if path == "" {
+		return store, nil
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304 -- caminho vem da configuração do operador
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read taxii cursors: %w", err)
+	}
+	if err := json.Unmarshal(data, &store.cursors); err != nil {
+		return nil, fmt.Errorf("failed to parse taxii cursors %s: %w", path, err)
+	}
+	return store, nil
+}
+
+// Get retorna o cursor da coleção (vazio se nunca lida)
+func (s *CursorStore) Get(collection string) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.cursors[collection]
+}
+
+// Set atualiza o cursor e grava o arquivo (escrita atômica por rename)
+func (s *CursorStore) Set(collection, cursor string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.cursors[collection] = cursor
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s.cursors, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode taxii cursors: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o750); err != nil {
+		return fmt.Errorf("failed to create cursor directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write taxii cursors: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to replace taxii cursors: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package taxii
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/cti-team/takedown/internal/stix"
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// RefPrefix identifica a coleção de origem em IOC.IntelRefs
+const RefPrefix = "taxii:"
+
+// Poller lê periodicamente as coleções a partir do cursor e entrega os indicadores à pipeline
+type Poller struct {
+	collections []*Collection
+	initial     map[string]string // added_after da primeira leitura, por coleção
+	cursors     *CursorStore
+	importer    *stix.Importer
+	interval    time.Duration
+	maxPages    int
+	stopChan    chan struct{}
+	now         func() time.Time
+}
+
+// NewPoller cria o poller com as coleções da configuração e o armazenamento de cursores
+func NewPoller(config Config, cursors *CursorStore) (*Poller, error) {
+	poller := &Poller{
+		initial:  make(map[string]string),
+		cursors:  cursors,
+		importer: stix.NewImporter(),
+		interval: time.Duration(config.IntervalSeconds) * time.Second,
+		maxPages: config.MaxPagesPerPoll,
+		stopChan: make(chan struct{}),
+		now:      func() time.Time { return time.Now().UTC() },
+	}
+	for _, collectionConfig := range config.Collections {
+		collection, err := NewCollection(collectionConfig, 60*time.Second)
+		if err != nil {
+			return nil, err
+		}
+		poller.collections = append(poller.collections, collection)
+
+		switch {
+		case collectionConfig.AddedAfter != "":
+			poller.initial[collection.Name()] = collectionConfig.AddedAfter
+		case collectionConfig.LookbackDays > 0:
+			start := poller.now().AddDate(0, 0, -collectionConfig.LookbackDays)
+			poller.initial[collection.Name()] = start.Format(TimestampFormat)
+		}
+	}
+	return poller, nil
+}
+
+// SetSink configura quem recebe os IOCs (normalmente Machine.ProcessIOC); sem sink, Poll só mapeia
+func (p *Poller) SetSink(sink stix.Sink) {
+	p.importer.SetSink(sink)
+}
+
+// Poll lê cada coleção desde o cursor e entrega os IOCs mapeados. O cursor só avança depois
+// que os objetos lidos foram entregues; falhas de sink em IOCs isolados não seguram a coleção.
+func (p *Poller) Poll(ctx context.Context) ([]*models.IOC, error) {
+	var emitted []*models.IOC
+	var errs []error
+
+	for _, collection := range p.collections {
+		iocs, err := p.pollCollection(ctx, collection)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", collection.Name(), err))
+		}
+		emitted = append(emitted, iocs...)
+	}
+	return emitted, errors.Join(errs...)
+}
+
+// pollCollection pagina a coleção (até max_pages_per_poll), mapeia os objetos juntos e atualiza o cursor
+func (p *Poller) pollCollection(ctx context.Context, collection *Collection) ([]*models.IOC, error) {
+	addedAfter := p.cursors.Get(collection.Name())
+	if addedAfter == "" {
+		addedAfter = p.initial[collection.Name()]
+	}
+	started := p.now()
+
+	var objects []stix.Object
+	cursor := ""
+	next := ""
+	complete := false
+pages:
+	for page := 0; page < p.maxPages; page++ {
+		result, err := collection.Objects(ctx, addedAfter, next)
+		if err != nil {
+			if len(objects) == 0 {
+				return nil, err
+			}
+			// Entrega o que já foi lido; o cursor fica na última página recebida
+			log.Printf("TAXII %s: %v", collection.Name(), err)
+			break
+		}
+		objects = append(objects, result.Objects...)
+		if result.DateAddedLast != "" {
+			cursor = result.DateAddedLast
+		}
+		if !result.More {
+			complete = true
+			break
+		}
+		switch {
+		case result.Next != "":
+			next = result.Next
+		case result.DateAddedLast != "":
+			// Servidores sem next paginam pelo added_after da última página
+			addedAfter = result.DateAddedLast
+		default:
+			log.Printf("TAXII %s: server reported more objects without next or %s", collection.Name(), DateAddedLastHeader)
+			break pages
+		}
+	}
+	if cursor == "" && complete && len(objects) > 0 {
+		// Servidor sem X-TAXII-Date-Added-Last: a leitura completa cobre até o início do ciclo
+		cursor = started.Format(TimestampFormat)
+	}
+
+	result := stix.MapObjects(objects, p.now())
+	for _, ioc := range result.IOCs {
+		ioc.IntelRefs = append(ioc.IntelRefs, RefPrefix+collection.Name())
+	}
+	report := p.importer.Submit(result)
+	for _, failure := range report.Errors {
+		log.Printf("TAXII %s: failed to open case for %s", collection.Name(), failure)
+	}
+
+	if cursor != "" {
+		if err := p.cursors.Set(collection.Name(), cursor); err != nil {
+			return result.IOCs, err
+		}
+	}
+	return result.IOCs, nil
+}
+
+// Start lê as coleções a cada interval_seconds até Stop
+func (p *Poller) Start() {
+	go func() {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			iocs, err := p.Poll(context.Background())
+			if err != nil {
+				log.Printf("TAXII poller: %v", err)
+			}
+			if len(iocs) > 0 {
+				log.Printf("TAXII poller: %d indicators ingested", len(iocs))
+			}
+
+			select {
+			case <-ticker.C:
+			case <-p.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Stop encerra o poller iniciado por Start
+func (p *Poller) Stop() {
+	close(p.stopChan)
+}
@@ -0,0 +1,186 @@
+package taxii
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cti-team/takedown/internal/taxii/taxiitest"
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// indicator monta um indicador STIX para o padrão dado
+func indicator(id, pattern string) string {
+	return `{"type": "indicator", "spec_version": "2.1", "id": "` + id + `", "labels": ["phishing"],
+		"valid_from": "2025-02-01T00:00:00Z", "pattern_type": "stix", "pattern": "` + pattern + `"}`
+}
+
+func TestPoller(t *testing.T) {
+	server := taxiitest.NewServer("phishing")
+	defer server.Close()
+	server.RequireBasicAuth("isac", "s3cret")
+	t.Setenv("TEST_TAXII_USER", "isac")
+	t.Setenv("TEST_TAXII_PASSWORD", "s3cret")
+
+	added := time.Date(2025, 2, 1, 10, 0, 0, 0, time.UTC)
+	server.AddObject(indicator("indicator--1", "[url:value = 'https://acme-login.com/a']"), added)
+	server.AddObject(indicator("indicator--2", "[domain-name:value = 'acme-verify.com']"), added.Add(time.Minute))
+	server.AddObject(indicator("indicator--3", "[ipv4-addr:value = '45.67.89.9']"), added.Add(2*time.Minute))
+
+	config := DefaultConfig()
+	config.CursorPath = filepath.Join(t.TempDir(), "state", "cursor.json")
+	config.Collections = []CollectionConfig{{
+		Name:         "isac",
+		APIRoot:      server.URL + taxiitest.APIRoot,
+		CollectionID: "phishing",
+		Auth:         AuthConfig{Type: AuthBasic, UsernameEnv: "TEST_TAXII_USER", PasswordEnv: "TEST_TAXII_PASSWORD"},
+		PageSize:     2,
+		AddedAfter:   "2025-01-01T00:00:00.000Z",
+	}}
+
+	var received []string
+	sink := func(ioc *models.IOC) error {
+		received = append(received, ioc.Value)
+		return nil
+	}
+	newPoller := func() *Poller {
+		cursors, err := LoadCursors(config.CursorPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		poller, err := NewPoller(config, cursors)
+		if err != nil {
+			t.Fatal(err)
+		}
+		poller.SetSink(sink)
+		return poller
+	}
+
+	iocs, err := newPoller().Poll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"https://acme-login.com/a", "acme-verify.com", "45.67.89.9"}
+	if len(iocs) != 3 || !reflect.DeepEqual(received, want) {
+		t.Fatalf("first poll received %v, want %v", received, want)
+	}
+	if refs := iocs[0].IntelRefs; !reflect.DeepEqual(refs, []string{"stix:indicator--1", "taxii:isac"}) {
+		t.Errorf("unexpected intel refs %v", refs)
+	}
+	requests := server.Requests()
+	if len(requests) != 2 || !strings.Contains(requests[1], "next=2") {
+		t.Errorf("expected two paged requests, got %v", requests)
+	}
+
+	// Um novo processo retoma do cursor gravado em disco
+	received = nil
+	iocs, err = newPoller().Poll(context.Background())
+	if err != nil || len(iocs) != 0 || len(received) != 0 {
+		t.Fatalf("restart re-ingested objects: %v (%v)", received, err)
+	}
+	if last := server.Requests()[2]; !strings.Contains(last, "added_after=2025-02-01T10%3A02%3A00.000Z") {
+		t.Errorf("restart did not use the persisted cursor: %s", last)
+	}
+
+	server.AddObject(indicator("indicator--4", "[url:value = 'https://acme-login.com/b']"), added.Add(time.Hour))
+	if _, err := newPoller().Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(received, []string{"https://acme-login.com/b"}) {
+		t.Errorf("expected only the new indicator, got %v", received)
+	}
+}
+
+func TestPollerAuth(t *testing.T) {
+	server := taxiitest.NewServer("feed")
+	defer server.Close()
+	server.RequireBearer("good-token")
+	server.AddObject(indicator("indicator--1", "[domain-name:value = 'a.com']"), time.Now())
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "valid token", token: "good-token"},
+		{name: "wrong token", token: "bad-token", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_TAXII_TOKEN", tt.token)
+			config := DefaultConfig()
+			config.Collections = []CollectionConfig{{
+				Name: "feed", APIRoot: server.URL + taxiitest.APIRoot, CollectionID: "feed",
+				Auth: AuthConfig{Type: AuthBearer, TokenEnv: "TEST_TAXII_TOKEN"},
+			}}
+			cursors, _ := LoadCursors("")
+			poller, err := NewPoller(config, cursors)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			iocs, err := poller.Poll(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Poll error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if cursors.Get("feed") != "" {
+					t.Error("cursor advanced after a failed poll")
+				}
+				return
+			}
+			if len(iocs) != 1 || cursors.Get("feed") == "" {
+				t.Errorf("expected one IOC and a cursor, got %d IOCs and cursor %q", len(iocs), cursors.Get("feed"))
+			}
+		})
+	}
+}
+
+func TestNewCollectionRequiresCredentials(t *testing.T) {
+	t.Setenv("TEST_TAXII_TOKEN", "")
+	_, err := NewCollection(CollectionConfig{
+		Name: "feed", APIRoot: "https://taxii.com/api1/", CollectionID: "feed",
+		Auth: AuthConfig{Type: AuthBearer, TokenEnv: "TEST_TAXII_TOKEN"},
+	}, time.Second)
+	if err == nil || !strings.Contains(err.Error(), "TEST_TAXII_TOKEN") {
+		t.Errorf("expected missing token error, got %v", err)
+	}
+}
+
+func TestLoadConfigMatchesDefault(t *testing.T) {
+	config, err := LoadConfig("../../configs/taxii/taxii.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(config, DefaultConfig()) {
+		t.Fatalf("configs/taxii/taxii.yaml differs from DefaultConfig: %+v", config)
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	collection := "collections:\n  - name: isac\n    api_root: https://taxii.com/api1/\n    collection_id: c1\n"
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr bool
+	}{
+		{name: "minimal collection", yaml: collection},
+		{name: "basic auth without password", yaml: collection + "    auth:\n      type: basic\n      username_env: U\n", wantErr: true},
+		{name: "unknown auth", yaml: collection + "    auth:\n      type: oauth\n", wantErr: true},
+		{name: "invalid added_after", yaml: collection + "    added_after: yesterday\n", wantErr: true},
+		{name: "missing collection id", yaml: "collections:\n  - name: isac\n    api_root: https://taxii.com/\n", wantErr: true},
+		{name: "duplicate names", yaml: collection + "  - name: isac\n    api_root: https://x.com/\n    collection_id: c2\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseConfig([]byte(tt.yaml)); (err != nil) != tt.wantErr {
+				t.Errorf("ParseConfig error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
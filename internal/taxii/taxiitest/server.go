@@ -0,0 +1,147 @@
+// Package taxiitest fornece um servidor TAXII 2.1 local (endpoint de objetos de
+// uma coleção, com added_after, limit e next) para testes, no estilo de net/http/httptest.
+package taxiitest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// APIRoot é o caminho da API root servida
+const APIRoot = "/api1/"
+
+// timestampFormat é o formato de date_added nos cabeçalhos e filtros
+const timestampFormat = "2006-01-02T15:04:05.000Z"
+
+type entry struct {
+	object    json.RawMessage
+	dateAdded time.Time
+}
+
+// Server é uma coleção TAXII em memória servida por httptest
+type Server struct {
+	*httptest.Server
+	collectionID string
+	username     string
+	password     string
+	token        string
+	entries      []entry
+	requests     []string // query strings recebidas
+	mutex        sync.Mutex
+}
+
+// NewServer inicia uma coleção vazia com o ID dado
+func NewServer(collectionID string) *Server {
+	server := &Server{collectionID: collectionID}
+	mux := http.NewServeMux()
+	mux.HandleFunc(APIRoot+"collections/"+collectionID+"/objects/", server.handleObjects)
+	server.Server = httptest.NewServer(mux)
+	return server
+}
+
+// RequireBasicAuth exige as credenciais basic dadas
+func (s *Server) RequireBasicAuth(username, password string) {
+	s.username, s.password = username, password
+}
+
+// RequireBearer exige o token bearer dado
+func (s *Server) RequireBearer(token string) {
+	s.token = token
+}
+
+// AddObject adiciona um objeto STIX (JSON) com o date_added dado; adicione em ordem de date_added
+func (s *Server) AddObject(object string, dateAdded time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.entries = append(s.entries, entry{object: json.RawMessage(object), dateAdded: dateAdded.UTC()})
+}
+
+// Requests retorna as query strings recebidas pelo endpoint de objetos
+func (s *Server) Requests() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) handleObjects(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="taxii"`)
+		writeTAXII(w, http.StatusUnauthorized, map[string]string{"title": "Unauthorized"})
+		return
+	}
+	if !strings.HasPrefix(r.Header.Get("Accept"), "application/taxii+json") {
+		writeTAXII(w, http.StatusNotAcceptable, map[string]string{"title": "Not Acceptable"})
+		return
+	}
+
+	query := r.URL.Query()
+	var addedAfter time.Time
+	if value := query.Get("added_after"); value != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			writeTAXII(w, http.StatusBadRequest, map[string]string{"title": "invalid added_after"})
+			return
+		}
+		addedAfter = parsed
+	}
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	offset, _ := strconv.Atoi(query.Get("next"))
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.requests = append(s.requests, r.URL.RawQuery)
+
+	var matched []entry
+	for _, e := range s.entries {
+		if e.dateAdded.After(addedAfter) {
+			matched = append(matched, e)
+		}
+	}
+	if offset > len(matched) {
+		offset = len(matched)
+	}
+	page := matched[offset:]
+	more := false
+	if limit > 0 && len(page) > limit {
+		page = page[:limit]
+		more = true
+	}
+
+	envelope := map[string]interface{}{"more": more}
+	if more {
+		envelope["next"] = strconv.Itoa(offset + len(page))
+	}
+	if len(page) > 0 {
+		objects := make([]json.RawMessage, 0, len(page))
+		for _, e := range page {
+			objects = append(objects, e.object)
+		}
+		envelope["objects"] = objects
+		w.Header().Set("X-TAXII-Date-Added-First", page[0].dateAdded.Format(timestampFormat))
+		w.Header().Set("X-TAXII-Date-Added-Last", page[len(page)-1].dateAdded.Format(timestampFormat))
+	}
+	writeTAXII(w, http.StatusOK, envelope)
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	switch {
+	case s.username != "":
+		username, password, ok := r.BasicAuth()
+		return ok && username == s.username && password == s.password
+	case s.token != "":
+		return r.Header.Get("Authorization") == "Bearer "+s.token
+	default:
+		return true
+	}
+}
+
+func writeTAXII(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/taxii+json;version=2.1")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,208 @@
+// Package ctlog implementa o cliente da API de logs de Certificate Transparency
+// (RFC 6962, seção 4) e a extração dos nomes dos certificados registrados.
+package ctlog
+
+import (
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Tipos de entrada do log (RFC 6962, LogEntryType)
+const (
+	entryTypeX509    = 0
+	entryTypePrecert = 1
+)
+
+// maxResponseBytes limita o tamanho das respostas do log
+const maxResponseBytes = 32 << 20
+
+// Client consulta um log de CT pela API RFC 6962
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	userAgent  string
+}
+
+// NewClient cria um cliente para o log em baseURL (ex: https://ct.googleapis.com/logs/us1/argon2025h2/)
+func NewClient(baseURL string) *Client {
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		userAgent: "CTI-Takedown/1.0",
+	}
+}
+
+// SetHTTPClient substitui o cliente HTTP (timeouts, proxy)
+func (c *Client) SetHTTPClient(client *http.Client) {
+	c.httpClient = client
+}
+
+// SignedTreeHead é a resposta de get-sth
+type SignedTreeHead struct {
+	TreeSize  int64  `json:"tree_size"`
+	Timestamp int64  `json:"timestamp"`
+	RootHash  string `json:"sha256_root_hash"`
+}
+
+// RawEntry é uma entrada de get-entries ainda codificada
+type RawEntry struct {
+	LeafInput []byte `json:"leaf_input"`
+	ExtraData []byte `json:"extra_data"`
+}
+
+// Entry é um certificado (ou pré-certificado) registrado no log
+type Entry struct {
+	Index     int64     `json:"index"`
+	Timestamp time.Time `json:"timestamp"`
+	Precert   bool      `json:"precert"`
+	Names     []string  `json:"names"` // SANs DNS e o CN, em minúsculas e sem duplicatas
+	Issuer    string    `json:"issuer,omitempty"`
+	Serial    string    `json:"serial,omitempty"`
+	NotBefore time.Time `json:"not_before"`
+}
+
+// GetSTH retorna o tamanho atual da árvore do log
+func (c *Client) GetSTH() (*SignedTreeHead, error) {
+	var sth SignedTreeHead
+	if err := c.get("/ct/v1/get-sth", &sth); err != nil {
+		return nil, fmt.Errorf("get-sth failed: %w", err)
+	}
+	return &sth, nil
+}
+
+// GetEntries busca as entradas [start, end]; o log pode devolver menos do que o pedido
+func (c *Client) GetEntries(start, end int64) ([]Entry, error) {
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("invalid range %d-%d", start, end)
+	}
+
+	var response struct {
+		Entries []RawEntry `json:"entries"`
+	}
+	if err := c.get(fmt.Sprintf("/ct/v1/get-entries?start=%d&end=%d", start, end), &response); err != nil {
+		return nil, fmt.Errorf("get-entries failed: %w", err)
+	}
+
+	entries := make([]Entry, 0, len(response.Entries))
+	for i, raw := range response.Entries {
+		entry, err := ParseEntry(raw)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %w", start+int64(i), err)
+		}
+		entry.Index = start + int64(i)
+		entries = append(entries, *entry)
+	}
+	return entries, nil
+}
+
+// ParseEntry decodifica o MerkleTreeLeaf e o certificado da entrada
+func ParseEntry(raw RawEntry) (*Entry, error) {
+	leaf := raw.LeafInput
+	// version(1) + leaf_type(1) + timestamp(8) + entry_type(2)
+	if len(leaf) < 12 {
+		return nil, errors.New("leaf_input too short")
+	}
+	if leaf[0] != 0 || leaf[1] != 0 {
+		return nil, fmt.Errorf("unsupported leaf version %d / type %d", leaf[0], leaf[1])
+	}
+
+	entry := &Entry{Timestamp: time.UnixMilli(int64(binary.BigEndian.Uint64(leaf[2:10]))).UTC()}
+
+	var der []byte
+	switch binary.BigEndian.Uint16(leaf[10:12]) {
+	case entryTypeX509:
+		cert, _, err := readASN1Cert(leaf[12:])
+		if err != nil {
+			return nil, fmt.Errorf("x509_entry: %w", err)
+		}
+		der = cert
+	case entryTypePrecert:
+		// O TBSCertificate do leaf não é um certificado completo; o pré-certificado vem em extra_data
+		entry.Precert = true
+		cert, _, err := readASN1Cert(raw.ExtraData)
+		if err != nil {
+			return nil, fmt.Errorf("precert extra_data: %w", err)
+		}
+		der = cert
+	default:
+		return nil, fmt.Errorf("unknown entry type %d", binary.BigEndian.Uint16(leaf[10:12]))
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+	entry.Names = certificateNames(cert)
+	entry.Issuer = cert.Issuer.String()
+	entry.Serial = hex.EncodeToString(cert.SerialNumber.Bytes())
+	entry.NotBefore = cert.NotBefore.UTC()
+	return entry, nil
+}
+
+// readASN1Cert lê um ASN.1Cert com prefixo de tamanho de 3 bytes e retorna o restante
+func readASN1Cert(data []byte) ([]byte, []byte, error) {
+	if len(data) < 3 {
+		return nil, nil, errors.New("truncated certificate length")
+	}
+	length := int(data[0])<<16 | int(data[1])<<8 | int(data[2])
+	if length == 0 || len(data) < 3+length {
+		return nil, nil, fmt.Errorf("truncated certificate (%d bytes declared)", length)
+	}
+	return data[3 : 3+length], data[3+length:], nil
+}
+
+// certificateNames junta SANs DNS e o CN (se parecer hostname), sem duplicatas
+func certificateNames(cert *x509.Certificate) []string {
+	seen := make(map[string]bool)
+	var names []string
+	add := func(name string) {
+		name = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+		if name == "" || seen[name] || strings.ContainsAny(name, " /:@") || !strings.Contains(name, ".") {
+			return
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+
+	for _, name := range cert.DNSNames {
+		add(name)
+	}
+	add(cert.Subject.CommonName)
+	return names
+}
+
+// get faz o GET e decodifica o JSON da resposta
+func (c *Client) get(path string, target interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, target)
+}
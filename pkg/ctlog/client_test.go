@@ -0,0 +1,86 @@
+package ctlog
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cti-team/takedown/pkg/ctlog/ctlogtest"
+)
+
+func TestClient_GetEntries(t *testing.T) {
+	server := ctlogtest.NewServer(0)
+	defer server.Close()
+
+	issued := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	server.AddCertificate(ctlogtest.Certificate("www.example.com", "example.com", "WWW.Example.com"), issued)
+	server.AddPrecertificate(ctlogtest.Certificate("login.acmebnak.com"), issued.Add(time.Minute))
+
+	client := NewClient(server.URL + "/")
+	sth, err := client.GetSTH()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sth.TreeSize != 2 {
+		t.Fatalf("Expected tree size 2, got %d", sth.TreeSize)
+	}
+
+	entries, err := client.GetEntries(0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+
+	first := entries[0]
+	if first.Index != 0 || first.Precert || !first.Timestamp.Equal(issued) {
+		t.Errorf("Unexpected x509 entry: %+v", first)
+	}
+	if strings.Join(first.Names, ",") != "www.example.com,example.com" {
+		t.Errorf("Expected deduplicated names, got %v", first.Names)
+	}
+	if first.Issuer != "CN=www.example.com" || first.Serial == "" {
+		t.Errorf("Expected issuer and serial, got %+v", first)
+	}
+
+	second := entries[1]
+	if second.Index != 1 || !second.Precert || strings.Join(second.Names, ",") != "login.acmebnak.com" {
+		t.Errorf("Unexpected precert entry: %+v", second)
+	}
+}
+
+func TestClient_GetEntriesErrors(t *testing.T) {
+	server := ctlogtest.NewServer(0)
+	defer server.Close()
+	client := NewClient(server.URL)
+
+	if _, err := client.GetEntries(5, 1); err == nil {
+		t.Error("Expected error for inverted range")
+	}
+	if _, err := client.GetEntries(0, 0); err == nil {
+		t.Error("Expected HTTP error for empty log")
+	}
+}
+
+func TestParseEntry_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  RawEntry
+	}{
+		{"short leaf", RawEntry{LeafInput: []byte{0, 0, 1}}},
+		{"bad version", RawEntry{LeafInput: []byte{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0}}},
+		{"unknown type", RawEntry{LeafInput: []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 9, 0, 0, 0}}},
+		{"truncated cert", RawEntry{LeafInput: []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 9, 1}}},
+		{"garbage cert", RawEntry{LeafInput: []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 1, 2}}},
+		{"precert without extra data", RawEntry{LeafInput: []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseEntry(tt.raw); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
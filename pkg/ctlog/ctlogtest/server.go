@@ -0,0 +1,147 @@
+// Package ctlogtest fornece um log de CT local (get-sth e get-entries da RFC 6962)
+// para testes do cliente e do watcher, no estilo de net/http/httptest.
+package ctlogtest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Server é um log de CT em memória servido por httptest
+type Server struct {
+	*httptest.Server
+	entries  []entry
+	maxBatch int
+	mutex    sync.Mutex
+}
+
+type entry struct {
+	LeafInput []byte `json:"leaf_input"`
+	ExtraData []byte `json:"extra_data"`
+}
+
+// NewServer inicia um log vazio; maxBatch limita quantas entradas get-entries devolve por chamada
+func NewServer(maxBatch int) *Server {
+	server := &Server{maxBatch: maxBatch}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ct/v1/get-sth", server.handleSTH)
+	mux.HandleFunc("/ct/v1/get-entries", server.handleEntries)
+	server.Server = httptest.NewServer(mux)
+	return server
+}
+
+// AddCertificate registra um certificado final (x509_entry)
+func (s *Server) AddCertificate(der []byte, timestamp time.Time) {
+	leaf := leafHeader(timestamp, 0)
+	leaf = append(leaf, asn1Cert(der)...)
+	leaf = append(leaf, 0, 0) // extensions vazias
+	s.append(entry{LeafInput: leaf, ExtraData: []byte{0, 0, 0}})
+}
+
+// AddPrecertificate registra um pré-certificado (precert_entry); o TBS do leaf é apenas simbólico
+func (s *Server) AddPrecertificate(der []byte, timestamp time.Time) {
+	leaf := leafHeader(timestamp, 1)
+	leaf = append(leaf, make([]byte, 32)...) // issuer_key_hash
+	leaf = append(leaf, asn1Cert([]byte{0x30, 0x00})...)
+	leaf = append(leaf, 0, 0)
+
+	extra := asn1Cert(der)
+	extra = append(extra, 0, 0, 0) // cadeia vazia
+	s.append(entry{LeafInput: leaf, ExtraData: extra})
+}
+
+// Size retorna o número de entradas do log
+func (s *Server) Size() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.entries)
+}
+
+// Certificate gera um certificado autoassinado com os nomes dados (o primeiro vira o CN)
+func Certificate(names ...string) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		panic(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: names[0]},
+		DNSNames:     names,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(90 * 24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		panic(err)
+	}
+	return der
+}
+
+func (s *Server) append(e entry) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.entries = append(s.entries, e)
+}
+
+func (s *Server) handleSTH(w http.ResponseWriter, _ *http.Request) {
+	s.mutex.Lock()
+	size := len(s.entries)
+	s.mutex.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"tree_size":        size,
+		"timestamp":        time.Now().UnixMilli(),
+		"sha256_root_hash": "",
+	})
+}
+
+func (s *Server) handleEntries(w http.ResponseWriter, r *http.Request) {
+	start, errStart := strconv.Atoi(r.URL.Query().Get("start"))
+	end, errEnd := strconv.Atoi(r.URL.Query().Get("end"))
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if errStart != nil || errEnd != nil || start < 0 || end < start || start >= len(s.entries) {
+		http.Error(w, "invalid range", http.StatusBadRequest)
+		return
+	}
+	if end >= len(s.entries) {
+		end = len(s.entries) - 1
+	}
+	if s.maxBatch > 0 && end-start+1 > s.maxBatch {
+		end = start + s.maxBatch - 1
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"entries": s.entries[start : end+1]})
+}
+
+// leafHeader monta version, leaf_type, timestamp e entry_type do MerkleTreeLeaf
+func leafHeader(timestamp time.Time, entryType uint16) []byte {
+	header := make([]byte, 12)
+	binary.BigEndian.PutUint64(header[2:10], uint64(timestamp.UnixMilli()))
+	binary.BigEndian.PutUint16(header[10:12], entryType)
+	return header
+}
+
+// asn1Cert prefixa o DER com o tamanho em 3 bytes
+func asn1Cert(der []byte) []byte {
+	return append([]byte{byte(len(der) >> 16), byte(len(der) >> 8), byte(len(der))}, der...)
+}
@@ -0,0 +1,164 @@
+// Package defang converte indicadores entre a forma "defanged" (segura para
+// relatórios e emails) e a forma original usada nas consultas e coletas.
+package defang
+
+import (
+	"net"
+	"regexp"
+	"strings"
+)
+
+var (
+	// dotPattern cobre [.], (.), {.}, [dot], (dot), {dot} e \. (com espaços opcionais)
+	dotPattern = regexp.MustCompile(`(?i)\s*[\[\(\{]\s*(?:\.|dot)\s*[\]\)\}]\s*|\\\.`)
+	// atPattern cobre [@], (@), {@}, [at], (at), {at}
+	atPattern = regexp.MustCompile(`(?i)\s*[\[\(\{]\s*(?:@|at)\s*[\]\)\}]\s*`)
+	// colonPattern cobre [:], (:), {:}
+	colonPattern = regexp.MustCompile(`[\[\(\{]\s*:\s*[\]\)\}]`)
+	// slashPattern cobre [/], [//] e [://]
+	slashPattern = regexp.MustCompile(`[\[\(\{]\s*(:?/+)\s*[\]\)\}]`)
+	// schemePattern cobre hxxp, hXXp, h**p, hxxps, fxp e fxps
+	schemePattern = regexp.MustCompile(`(?i)\b(?:h(?:xx|\*\*|tt)ps?|f[xt]ps?)://`)
+)
+
+// ideographicDots são separadores de rótulo equivalentes ao "." em nomes IDN (UTS #46)
+var ideographicDots = strings.NewReplacer("。", ".", "．", ".", "｡", ".")
+
+// schemes mapeia esquemas originais para a forma defanged
+var schemes = map[string]string{
+	"http":  "hxxp",
+	"https": "hxxps",
+	"ftp":   "fxp",
+	"ftps":  "fxps",
+}
+
+// Refang desfaz as notações de defang comuns, retornando o indicador original
+func Refang(value string) string {
+	value = strings.TrimSpace(value)
+	value = ideographicDots.Replace(value)
+	value = slashPattern.ReplaceAllString(value, "$1")
+	value = colonPattern.ReplaceAllString(value, ":")
+	value = dotPattern.ReplaceAllString(value, ".")
+	value = atPattern.ReplaceAllString(value, "@")
+	value = schemePattern.ReplaceAllStringFunc(value, func(match string) string {
+		lower := strings.ToLower(match)
+		secure := strings.HasSuffix(strings.TrimSuffix(lower, "://"), "s")
+		scheme := "http"
+		if strings.HasPrefix(lower, "f") {
+			scheme = "ftp"
+		}
+		if secure {
+			scheme += "s"
+		}
+		return scheme + "://"
+	})
+	return value
+}
+
+// Defang detecta o tipo do indicador (URL, IP, email ou domínio) e aplica o defang adequado.
+// Entradas já defanged são normalizadas primeiro, então o resultado é sempre o mesmo.
+func Defang(value string) string {
+	value = Refang(value)
+	switch {
+	case strings.Contains(value, "://"):
+		return URL(value)
+	case net.ParseIP(strings.Trim(value, "[]")) != nil:
+		return IP(value)
+	case strings.Contains(value, "@") && !strings.ContainsAny(value, "/ "):
+		return Email(value)
+	default:
+		return Domain(value)
+	}
+}
+
+// URL defanga o esquema (hxxp, hxxps, fxp) e o host da URL; caminho e query não são alterados
+func URL(value string) string {
+	value = Refang(value)
+
+	rest := value
+	prefix := ""
+	if idx := strings.Index(value, "://"); idx >= 0 {
+		scheme := strings.ToLower(value[:idx])
+		if defanged, ok := schemes[scheme]; ok {
+			scheme = defanged
+		}
+		prefix = scheme + "://"
+		rest = value[idx+3:]
+	}
+
+	authority, path := rest, ""
+	if idx := strings.IndexAny(rest, "/?#"); idx >= 0 {
+		authority, path = rest[:idx], rest[idx:]
+	}
+
+	return prefix + defangAuthority(authority) + path
+}
+
+// Domain defanga os pontos de um domínio (example[.]com)
+func Domain(value string) string {
+	return strings.ReplaceAll(Refang(value), ".", "[.]")
+}
+
+// IP defanga endereços IPv4 (1[.]2[.]3[.]4) e IPv6 (2001[:]db8[:][:]1)
+func IP(value string) string {
+	value = Refang(value)
+	if strings.Contains(value, ":") {
+		return strings.ReplaceAll(value, ":", "[:]")
+	}
+	return strings.ReplaceAll(value, ".", "[.]")
+}
+
+// Email defanga o "@" e o domínio do endereço (user[@]example[.]com)
+func Email(value string) string {
+	value = Refang(value)
+	at := strings.LastIndex(value, "@")
+	if at < 0 {
+		return Domain(value)
+	}
+	return value[:at] + "[@]" + Domain(value[at+1:])
+}
+
+// Host retorna o hostname (sem porta, em minúsculas) de uma URL ou domínio, defanged ou não
+func Host(value string) string {
+	value = Refang(value)
+	if idx := strings.Index(value, "://"); idx >= 0 {
+		value = value[idx+3:]
+	}
+	if idx := strings.IndexAny(value, "/?#"); idx >= 0 {
+		value = value[:idx]
+	}
+	if idx := strings.LastIndex(value, "@"); idx >= 0 {
+		value = value[idx+1:]
+	}
+
+	if strings.HasPrefix(value, "[") {
+		if end := strings.Index(value, "]"); end > 0 {
+			return strings.ToLower(value[1:end])
+		}
+	}
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+	return strings.ToLower(strings.TrimSuffix(value, "."))
+}
+
+// defangAuthority defanga userinfo@host:porta de uma URL
+func defangAuthority(authority string) string {
+	userinfo := ""
+	if at := strings.LastIndex(authority, "@"); at >= 0 {
+		userinfo, authority = authority[:at]+"[@]", authority[at+1:]
+	}
+
+	// IPv6 literal: [2001:db8::1]:8080
+	if strings.HasPrefix(authority, "[") {
+		if end := strings.Index(authority, "]"); end > 0 {
+			return userinfo + "[" + IP(authority[1:end]) + "]" + authority[end+1:]
+		}
+	}
+
+	host, port := authority, ""
+	if idx := strings.LastIndex(authority, ":"); idx >= 0 {
+		host, port = authority[:idx], authority[idx:]
+	}
+	return userinfo + strings.ReplaceAll(host, ".", "[.]") + port
+}
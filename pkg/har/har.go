@@ -0,0 +1,167 @@
+package har
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Version é a versão do formato HAR gerado
+const Version = "1.2"
+
+// HAR é o documento raiz de um arquivo HTTP Archive 1.2
+type HAR struct {
+	Log Log `json:"log"`
+}
+
+// Log contém as páginas e requisições registradas
+type Log struct {
+	Version string  `json:"version"`
+	Creator Creator `json:"creator"`
+	Pages   []Page  `json:"pages,omitempty"`
+	Entries []Entry `json:"entries"`
+	Comment string  `json:"comment,omitempty"`
+}
+
+// Creator identifica a aplicação que gerou o HAR
+type Creator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Page representa uma página (uma coleta de evidência)
+type Page struct {
+	StartedDateTime time.Time   `json:"startedDateTime"`
+	ID              string      `json:"id"`
+	Title           string      `json:"title"`
+	PageTimings     PageTimings `json:"pageTimings"`
+}
+
+// PageTimings contém os tempos de carregamento da página (-1 quando não aplicável)
+type PageTimings struct {
+	OnContentLoad float64 `json:"onContentLoad"`
+	OnLoad        float64 `json:"onLoad"`
+}
+
+// Entry representa um par requisição/resposta
+type Entry struct {
+	Pageref         string    `json:"pageref,omitempty"`
+	StartedDateTime time.Time `json:"startedDateTime"`
+	Time            float64   `json:"time"` // ms, soma dos timings
+	Request         Request   `json:"request"`
+	Response        Response  `json:"response"`
+	Cache           Cache     `json:"cache"`
+	Timings         Timings   `json:"timings"`
+	ServerIPAddress string    `json:"serverIPAddress,omitempty"`
+	Connection      string    `json:"connection,omitempty"`
+	TLS             *TLS      `json:"_tls,omitempty"`   // campo customizado: dados da conexão TLS
+	Error           string    `json:"_error,omitempty"` // campo customizado: falha de rede
+}
+
+// Request representa a requisição HTTP
+type Request struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []Cookie    `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	QueryString []NameValue `json:"queryString"`
+	PostData    *PostData   `json:"postData,omitempty"`
+	HeadersSize int64       `json:"headersSize"`
+	BodySize    int64       `json:"bodySize"`
+}
+
+// Response representa a resposta HTTP
+type Response struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []Cookie    `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	Content     Content     `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int64       `json:"headersSize"`
+	BodySize    int64       `json:"bodySize"`
+}
+
+// NameValue representa um header ou parâmetro
+type NameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Cookie representa um cookie enviado ou recebido
+type Cookie struct {
+	Name     string     `json:"name"`
+	Value    string     `json:"value"`
+	Path     string     `json:"path,omitempty"`
+	Domain   string     `json:"domain,omitempty"`
+	Expires  *time.Time `json:"expires,omitempty"`
+	HTTPOnly bool       `json:"httpOnly,omitempty"`
+	Secure   bool       `json:"secure,omitempty"`
+}
+
+// PostData representa o corpo enviado na requisição
+type PostData struct {
+	MimeType string      `json:"mimeType"`
+	Params   []NameValue `json:"params"`
+	Text     string      `json:"text"`
+}
+
+// Content representa o corpo da resposta
+type Content struct {
+	Size      int64  `json:"size"`
+	MimeType  string `json:"mimeType"`
+	Text      string `json:"text,omitempty"`
+	Encoding  string `json:"encoding,omitempty"`   // "base64" para conteúdo binário
+	Truncated bool   `json:"_truncated,omitempty"` // campo customizado: corpo cortado no limite
+}
+
+// Cache é obrigatório no HAR; a coleta nunca usa cache
+type Cache struct{}
+
+// Timings contém os tempos da requisição em ms (-1 quando não aplicável)
+type Timings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"` // inclui o tempo de SSL, conforme a spec
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
+
+// Total soma os tempos (SSL já está incluído em Connect)
+func (t Timings) Total() float64 {
+	total := 0.0
+	for _, value := range []float64{t.Blocked, t.DNS, t.Connect, t.Send, t.Wait, t.Receive} {
+		if value > 0 {
+			total += value
+		}
+	}
+	return total
+}
+
+// TLS resume a conexão TLS de uma entrada
+type TLS struct {
+	Version      string        `json:"version"`
+	CipherSuite  string        `json:"cipherSuite"`
+	ServerName   string        `json:"serverName,omitempty"`
+	Protocol     string        `json:"protocol,omitempty"` // ALPN negociado
+	Certificates []Certificate `json:"certificates,omitempty"`
+}
+
+// Certificate resume um certificado da cadeia apresentada pelo servidor
+type Certificate struct {
+	Subject   string    `json:"subject"`
+	Issuer    string    `json:"issuer"`
+	DNSNames  []string  `json:"dnsNames,omitempty"`
+	Serial    string    `json:"serial"`
+	NotBefore time.Time `json:"notBefore"`
+	NotAfter  time.Time `json:"notAfter"`
+	SHA256    string    `json:"sha256"`
+}
+
+// Marshal serializa o HAR com indentação
+func (h *HAR) Marshal() ([]byte, error) {
+	return json.MarshalIndent(h, "", "  ")
+}
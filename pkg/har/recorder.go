@@ -0,0 +1,452 @@
+package har
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultBodyLimit é o tamanho máximo de corpo gravado por entrada
+const DefaultBodyLimit = 1 << 20
+
+// Recorder é um http.RoundTripper que registra cada requisição/resposta em HAR
+type Recorder struct {
+	transport http.RoundTripper
+	bodyLimit int64
+	creator   Creator
+	pages     []Page
+	entries   []Entry
+	mutex     sync.Mutex
+}
+
+// NewRecorder cria um recorder sobre o transport informado (http.DefaultTransport se nil)
+func NewRecorder(transport http.RoundTripper, bodyLimit int64) *Recorder {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if bodyLimit <= 0 {
+		bodyLimit = DefaultBodyLimit
+	}
+
+	return &Recorder{
+		transport: transport,
+		bodyLimit: bodyLimit,
+		creator:   Creator{Name: "cti-takedown", Version: "1.0.0"},
+	}
+}
+
+// StartPage inicia uma página; as entradas seguintes são associadas a ela
+func (r *Recorder) StartPage(title string) string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	id := fmt.Sprintf("page_%d", len(r.pages)+1)
+	r.pages = append(r.pages, Page{
+		StartedDateTime: time.Now().UTC(),
+		ID:              id,
+		Title:           title,
+		PageTimings:     PageTimings{OnContentLoad: -1, OnLoad: -1},
+	})
+	return id
+}
+
+// HAR retorna o documento com tudo que foi registrado até agora
+func (r *Recorder) HAR() *HAR {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	entries := make([]Entry, len(r.entries))
+	copy(entries, r.entries)
+	pages := make([]Page, len(r.pages))
+	copy(pages, r.pages)
+
+	return &HAR{Log: Log{
+		Version: Version,
+		Creator: r.creator,
+		Pages:   pages,
+		Entries: entries,
+	}}
+}
+
+// Entries retorna as entradas registradas
+func (r *Recorder) Entries() []Entry {
+	return r.HAR().Log.Entries
+}
+
+// RoundTrip executa a requisição registrando headers, timings, TLS e corpo
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	trace := &timingTrace{}
+	entry := Entry{
+		Pageref:         r.currentPage(),
+		StartedDateTime: time.Now().UTC(),
+		Request:         r.buildRequest(req),
+	}
+
+	trace.start = time.Now()
+	resp, err := r.transport.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), trace.clientTrace())))
+	if err != nil {
+		entry.Timings = trace.timings(time.Now())
+		entry.Time = entry.Timings.Total()
+		entry.Error = err.Error()
+		entry.Response = Response{Cookies: []Cookie{}, Headers: []NameValue{}, HeadersSize: -1, BodySize: -1}
+		entry.ServerIPAddress, entry.Connection = trace.remote()
+		r.addEntry(entry)
+		return nil, err
+	}
+
+	body, truncated, readErr := r.captureBody(resp)
+	entry.Timings = trace.timings(time.Now())
+	entry.Time = entry.Timings.Total()
+	entry.Response = r.buildResponse(resp, body, truncated)
+	entry.ServerIPAddress, entry.Connection = trace.remote()
+	if resp.TLS != nil {
+		entry.TLS = buildTLS(resp.TLS)
+	}
+	if readErr != nil {
+		entry.Error = readErr.Error()
+	}
+
+	r.addEntry(entry)
+	return resp, nil
+}
+
+// currentPage retorna o ID da página atual
+func (r *Recorder) currentPage() string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if len(r.pages) == 0 {
+		return ""
+	}
+	return r.pages[len(r.pages)-1].ID
+}
+
+// addEntry adiciona uma entrada ao log
+func (r *Recorder) addEntry(entry Entry) {
+	r.mutex.Lock()
+	r.entries = append(r.entries, entry)
+	r.mutex.Unlock()
+}
+
+// captureBody lê o corpo até o limite e o devolve intacto ao cliente
+func (r *Recorder) captureBody(resp *http.Response) ([]byte, bool, error) {
+	if resp.Body == nil {
+		return nil, false, nil
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, r.bodyLimit+1))
+	body := raw
+	truncated := int64(len(raw)) > r.bodyLimit
+	if truncated {
+		body = raw[:r.bodyLimit]
+	}
+
+	// O cliente continua lendo o corpo completo: o que já foi lido + o restante do stream
+	original := resp.Body
+	resp.Body = readCloser{
+		Reader: io.MultiReader(bytes.NewReader(raw), original),
+		Closer: original,
+	}
+
+	if err != nil {
+		return body, truncated, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return body, truncated, nil
+}
+
+// readCloser combina um Reader com o Closer do corpo original
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// buildRequest converte a requisição para HAR
+func (r *Recorder) buildRequest(req *http.Request) Request {
+	request := Request{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		HTTPVersion: httpVersion(req.Proto),
+		Cookies:     []Cookie{},
+		Headers:     headerList(req.Header),
+		QueryString: []NameValue{},
+		HeadersSize: -1,
+		BodySize:    0,
+	}
+
+	if req.Host != "" && req.Host != req.URL.Host {
+		request.Headers = append(request.Headers, NameValue{Name: "Host", Value: req.Host})
+	}
+
+	for _, cookie := range req.Cookies() {
+		request.Cookies = append(request.Cookies, Cookie{Name: cookie.Name, Value: cookie.Value})
+	}
+
+	for name, values := range req.URL.Query() {
+		for _, value := range values {
+			request.QueryString = append(request.QueryString, NameValue{Name: name, Value: value})
+		}
+	}
+
+	if req.Body != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(io.LimitReader(body, r.bodyLimit))
+			_ = body.Close()
+			request.BodySize = int64(len(data))
+			request.PostData = &PostData{
+				MimeType: req.Header.Get("Content-Type"),
+				Params:   []NameValue{},
+				Text:     string(data),
+			}
+		}
+	}
+
+	return request
+}
+
+// buildResponse converte a resposta para HAR
+func (r *Recorder) buildResponse(resp *http.Response, body []byte, truncated bool) Response {
+	mimeType := resp.Header.Get("Content-Type")
+	response := Response{
+		Status:      resp.StatusCode,
+		StatusText:  strings.TrimSpace(strings.TrimPrefix(resp.Status, fmt.Sprintf("%d", resp.StatusCode))),
+		HTTPVersion: httpVersion(resp.Proto),
+		Cookies:     []Cookie{},
+		Headers:     headerList(resp.Header),
+		RedirectURL: resp.Header.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    int64(len(body)),
+		Content: Content{
+			Size:      int64(len(body)),
+			MimeType:  mimeType,
+			Truncated: truncated,
+		},
+	}
+
+	if truncated {
+		response.BodySize = -1
+		if resp.ContentLength > 0 {
+			response.Content.Size = resp.ContentLength
+		}
+	}
+
+	for _, cookie := range resp.Cookies() {
+		harCookie := Cookie{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Path:     cookie.Path,
+			Domain:   cookie.Domain,
+			HTTPOnly: cookie.HttpOnly,
+			Secure:   cookie.Secure,
+		}
+		if !cookie.Expires.IsZero() {
+			expires := cookie.Expires.UTC()
+			harCookie.Expires = &expires
+		}
+		response.Cookies = append(response.Cookies, harCookie)
+	}
+
+	if len(body) > 0 {
+		if isTextual(mimeType) && utf8.Valid(body) {
+			response.Content.Text = string(body)
+		} else {
+			response.Content.Text = base64.StdEncoding.EncodeToString(body)
+			response.Content.Encoding = "base64"
+		}
+	}
+
+	return response
+}
+
+// buildTLS extrai versão, cipher e cadeia de certificados da conexão
+func buildTLS(state *tls.ConnectionState) *TLS {
+	info := &TLS{
+		Version:     tls.VersionName(state.Version),
+		CipherSuite: tls.CipherSuiteName(state.CipherSuite),
+		ServerName:  state.ServerName,
+		Protocol:    state.NegotiatedProtocol,
+	}
+
+	for _, cert := range state.PeerCertificates {
+		info.Certificates = append(info.Certificates, certificateInfo(cert))
+	}
+
+	return info
+}
+
+// certificateInfo resume um certificado X.509
+func certificateInfo(cert *x509.Certificate) Certificate {
+	sum := sha256.Sum256(cert.Raw)
+	return Certificate{
+		Subject:   cert.Subject.String(),
+		Issuer:    cert.Issuer.String(),
+		DNSNames:  cert.DNSNames,
+		Serial:    cert.SerialNumber.String(),
+		NotBefore: cert.NotBefore.UTC(),
+		NotAfter:  cert.NotAfter.UTC(),
+		SHA256:    hex.EncodeToString(sum[:]),
+	}
+}
+
+// headerList converte headers para a lista ordenada do HAR
+func headerList(header http.Header) []NameValue {
+	list := []NameValue{}
+	for name, values := range header {
+		for _, value := range values {
+			list = append(list, NameValue{Name: name, Value: value})
+		}
+	}
+	// Ordenado por nome para um HAR determinístico
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// httpVersion normaliza a versão do protocolo
+func httpVersion(proto string) string {
+	if proto == "" {
+		return "HTTP/1.1"
+	}
+	return proto
+}
+
+// isTextual verifica se o mime type é texto
+func isTextual(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(contentType)
+	}
+	return strings.HasPrefix(mediaType, "text/") ||
+		strings.Contains(mediaType, "json") ||
+		strings.Contains(mediaType, "xml") ||
+		strings.Contains(mediaType, "javascript") ||
+		mediaType == "application/x-www-form-urlencoded"
+}
+
+// timingTrace coleta os tempos de cada fase da requisição via httptrace
+type timingTrace struct {
+	mutex                     sync.Mutex
+	start                     time.Time
+	dnsStart, dnsDone         time.Time
+	connectStart, connectDone time.Time
+	tlsStart, tlsDone         time.Time
+	gotConn, wroteRequest     time.Time
+	firstByte                 time.Time
+	remoteAddr                net.Addr
+	connectionID              string
+}
+
+// clientTrace cria os hooks de httptrace
+func (t *timingTrace) clientTrace() *httptrace.ClientTrace {
+	now := func(target *time.Time) {
+		t.mutex.Lock()
+		*target = time.Now()
+		t.mutex.Unlock()
+	}
+
+	return &httptrace.ClientTrace{
+		DNSStart:          func(httptrace.DNSStartInfo) { now(&t.dnsStart) },
+		DNSDone:           func(httptrace.DNSDoneInfo) { now(&t.dnsDone) },
+		ConnectStart:      func(string, string) { now(&t.connectStart) },
+		ConnectDone:       func(string, string, error) { now(&t.connectDone) },
+		TLSHandshakeStart: func() { now(&t.tlsStart) },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { now(&t.tlsDone) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mutex.Lock()
+			t.gotConn = time.Now()
+			if info.Conn != nil {
+				t.remoteAddr = info.Conn.RemoteAddr()
+				t.connectionID = info.Conn.LocalAddr().String()
+			}
+			t.mutex.Unlock()
+		},
+		WroteRequest:         func(httptrace.WroteRequestInfo) { now(&t.wroteRequest) },
+		GotFirstResponseByte: func() { now(&t.firstByte) },
+	}
+}
+
+// timings converte os instantes coletados para o formato HAR
+func (t *timingTrace) timings(end time.Time) Timings {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	timings := Timings{Blocked: -1, DNS: -1, Connect: -1, Send: -1, Wait: -1, Receive: -1, SSL: -1}
+
+	connectStart := t.connectStart
+	if !t.dnsStart.IsZero() && !t.dnsDone.IsZero() {
+		timings.DNS = millis(t.dnsDone.Sub(t.dnsStart))
+	}
+	if !connectStart.IsZero() {
+		connectEnd := t.connectDone
+		if !t.tlsDone.IsZero() {
+			connectEnd = t.tlsDone
+		}
+		if !connectEnd.IsZero() {
+			timings.Connect = millis(connectEnd.Sub(connectStart))
+		}
+	}
+	if !t.tlsStart.IsZero() && !t.tlsDone.IsZero() {
+		timings.SSL = millis(t.tlsDone.Sub(t.tlsStart))
+	}
+
+	// Tempo até obter a conexão que não foi gasto em DNS/connect
+	if !t.gotConn.IsZero() {
+		blocked := millis(t.gotConn.Sub(t.start))
+		for _, phase := range []float64{timings.DNS, timings.Connect} {
+			if phase > 0 {
+				blocked -= phase
+			}
+		}
+		if blocked < 0 {
+			blocked = 0
+		}
+		timings.Blocked = blocked
+	}
+
+	if !t.gotConn.IsZero() && !t.wroteRequest.IsZero() {
+		timings.Send = millis(t.wroteRequest.Sub(t.gotConn))
+	}
+	if !t.wroteRequest.IsZero() && !t.firstByte.IsZero() {
+		timings.Wait = millis(t.firstByte.Sub(t.wroteRequest))
+	}
+	if !t.firstByte.IsZero() {
+		timings.Receive = millis(end.Sub(t.firstByte))
+	}
+
+	return timings
+}
+
+// remote retorna o IP do servidor e o identificador da conexão
+func (t *timingTrace) remote() (string, string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.remoteAddr == nil {
+		return "", ""
+	}
+
+	host, _, err := net.SplitHostPort(t.remoteAddr.String())
+	if err != nil {
+		host = t.remoteAddr.String()
+	}
+	return host, t.connectionID
+}
+
+// millis converte uma duração para milissegundos com precisão de microssegundos
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
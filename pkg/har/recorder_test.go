@@ -0,0 +1,138 @@
+package har
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRecorder_RecordsRequestAndResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Test", "1")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	recorder := NewRecorder(nil, 0)
+	pageID := recorder.StartPage("test")
+	client := &http.Client{Transport: recorder}
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/submit?user=a&user=b", strings.NewReader("user=victim"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	if string(body) != `{"ok":true}` {
+		t.Errorf("client should still receive the full body, got %q", body)
+	}
+
+	entries := recorder.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	entry := entries[0]
+
+	if entry.Pageref != pageID || entry.Request.Method != http.MethodPost || len(entry.Request.QueryString) != 2 {
+		t.Errorf("unexpected request: %+v", entry.Request)
+	}
+	if entry.Request.PostData == nil || entry.Request.PostData.Text != "user=victim" {
+		t.Errorf("expected post data to be recorded, got %+v", entry.Request.PostData)
+	}
+	if entry.Response.Status != 200 || entry.Response.StatusText != "OK" || entry.Response.Content.Text != `{"ok":true}` {
+		t.Errorf("unexpected response: %+v", entry.Response)
+	}
+	if entry.Timings.Wait < 0 || entry.Timings.Send < 0 || entry.Time <= 0 {
+		t.Errorf("expected timings to be recorded, got %+v", entry.Timings)
+	}
+	if entry.Timings.SSL != -1 {
+		t.Errorf("expected ssl -1 for plain HTTP, got %v", entry.Timings.SSL)
+	}
+	if entry.ServerIPAddress != "127.0.0.1" {
+		t.Errorf("unexpected server IP: %s", entry.ServerIPAddress)
+	}
+
+	found := false
+	for _, header := range entry.Response.Headers {
+		if header.Name == "X-Test" && header.Value == "1" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected X-Test header, got %+v", entry.Response.Headers)
+	}
+}
+
+func TestRecorder_BinaryBodyIsBase64(t *testing.T) {
+	payload := []byte{0x4d, 0x5a, 0x90, 0x00, 0xff}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write(payload)
+	}))
+	defer server.Close()
+
+	recorder := NewRecorder(nil, 0)
+	resp, err := (&http.Client{Transport: recorder}).Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	if !bytes.Equal(body, payload) {
+		t.Errorf("client body changed: %v", body)
+	}
+
+	content := recorder.Entries()[0].Response.Content
+	if content.Encoding != "base64" || content.Text != "TVqQAP8=" || content.Size != 5 {
+		t.Errorf("unexpected content: %+v", content)
+	}
+}
+
+func TestRecorder_TLSAndTruncation(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(strings.Repeat("a", 100)))
+	}))
+	defer server.Close()
+
+	recorder := NewRecorder(server.Client().Transport, 10)
+	resp, err := (&http.Client{Transport: recorder}).Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	if len(body) != 100 {
+		t.Errorf("client should receive the untruncated body, got %d bytes", len(body))
+	}
+
+	entry := recorder.Entries()[0]
+	if !entry.Response.Content.Truncated || entry.Response.Content.Text != strings.Repeat("a", 10) || entry.Response.BodySize != -1 {
+		t.Errorf("unexpected truncated content: %+v", entry.Response.Content)
+	}
+	if entry.TLS == nil || entry.TLS.Version == "" || entry.TLS.CipherSuite == "" || len(entry.TLS.Certificates) == 0 {
+		t.Fatalf("expected TLS details, got %+v", entry.TLS)
+	}
+	if len(entry.TLS.Certificates[0].SHA256) != 64 {
+		t.Errorf("unexpected certificate fingerprint: %s", entry.TLS.Certificates[0].SHA256)
+	}
+	if entry.Timings.SSL < 0 || entry.Timings.Connect < entry.Timings.SSL {
+		t.Errorf("expected connect to include ssl time, got %+v", entry.Timings)
+	}
+}
+
+func TestTimings_Total(t *testing.T) {
+	timings := Timings{Blocked: -1, DNS: 2, Connect: 10, Send: 1, Wait: 20, Receive: 3, SSL: 6}
+	if total := timings.Total(); total != 36 {
+		t.Errorf("Expected 36ms (ssl included in connect), got %v", total)
+	}
+}
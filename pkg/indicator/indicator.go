@@ -0,0 +1,160 @@
+// Package indicator normaliza IOCs de rede (URLs, domínios e IPs) usando a
+// Public Suffix List embutida em golang.org/x/net/publicsuffix.
+package indicator
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/cti-team/takedown/pkg/defang"
+	"golang.org/x/net/idna"
+	"golang.org/x/net/publicsuffix"
+)
+
+// hostProfile converte hosts IDN para ASCII sem as regras STD3 (hosts de phishing usam "_")
+var hostProfile = idna.New(idna.MapForLookup(), idna.StrictDomainName(false), idna.BidiRule())
+
+// defaultPorts são omitidas na URL canônica
+var defaultPorts = map[string]string{"http": "80", "https": "443", "ftp": "21"}
+
+// Normalized é a forma canônica de um IOC de rede
+type Normalized struct {
+	Input             string `json:"input"`
+	Host              string `json:"host"`                         // hostname em ASCII (punycode) e minúsculas
+	UnicodeHost       string `json:"unicode_host,omitempty"`       // forma Unicode de hosts IDN
+	RegistrableDomain string `json:"registrable_domain,omitempty"` // eTLD+1 pela seção ICANN da PSL (o que o registrar vende)
+	Site              string `json:"site,omitempty"`               // eTLD+1 incluindo sufixos privados (ex: user.github.io)
+	PublicSuffix      string `json:"public_suffix,omitempty"`      // com.br, co.uk, github.io
+	PrivateSuffix     bool   `json:"private_suffix,omitempty"`     // sufixo da seção privada da PSL (plataformas)
+	TLD               string `json:"tld,omitempty"`
+	IP                bool   `json:"ip,omitempty"`
+	URL               string `json:"url"` // URL canônica
+}
+
+// Normalize refanga o indicador e extrai host, eTLD+1, TLD e a URL canônica
+func Normalize(value string) (*Normalized, error) {
+	raw := defang.Refang(value)
+	if raw == "" {
+		return nil, errors.New("empty indicator")
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid indicator %q: %w", value, err)
+	}
+	host := strings.TrimSuffix(strings.ToLower(parsed.Hostname()), ".")
+	if host == "" {
+		return nil, fmt.Errorf("invalid indicator %q: no host", value)
+	}
+
+	result := &Normalized{Input: value}
+	if ip := net.ParseIP(host); ip != nil {
+		result.IP = true
+		result.Host = ip.String()
+	} else {
+		ascii, err := hostProfile.ToASCII(host)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hostname %q: %w", host, err)
+		}
+		result.Host = ascii
+		if unicode, err := hostProfile.ToUnicode(ascii); err == nil && unicode != ascii {
+			result.UnicodeHost = unicode
+		}
+		result.fillDomain()
+	}
+
+	result.URL = canonicalURL(parsed, result)
+	return result, nil
+}
+
+// RegistrableDomain retorna o domínio registrável (eTLD+1 ICANN) do indicador, ou "" se não houver
+func RegistrableDomain(value string) string {
+	normalized, err := Normalize(value)
+	if err != nil {
+		return ""
+	}
+	return normalized.RegistrableDomain
+}
+
+// TLD retorna o TLD do indicador em ASCII, ou "" se não houver
+func TLD(value string) string {
+	normalized, err := Normalize(value)
+	if err != nil {
+		return ""
+	}
+	return normalized.TLD
+}
+
+// fillDomain preenche sufixo público, TLD, site e domínio registrável a partir do host
+func (n *Normalized) fillDomain() {
+	n.TLD = n.Host[strings.LastIndex(n.Host, ".")+1:]
+
+	suffix, icann := publicsuffix.PublicSuffix(n.Host)
+	n.PublicSuffix = suffix
+	// TLDs fora da PSL também retornam icann=false, mas com um único rótulo
+	n.PrivateSuffix = !icann && strings.Contains(suffix, ".")
+
+	n.Site = etldPlusOne(n.Host, suffix)
+	if !n.PrivateSuffix {
+		n.RegistrableDomain = n.Site
+		return
+	}
+
+	// Em sufixos privados (github.io, blogspot.com.br) o registrar vende o domínio da plataforma
+	icannSuffix := suffix
+	for {
+		idx := strings.Index(icannSuffix, ".")
+		if idx < 0 {
+			break
+		}
+		icannSuffix = icannSuffix[idx+1:]
+		if found, ok := publicsuffix.PublicSuffix(icannSuffix); ok && found == icannSuffix {
+			break
+		}
+	}
+	n.RegistrableDomain = etldPlusOne(n.Host, icannSuffix)
+}
+
+// etldPlusOne retorna o rótulo imediatamente à esquerda do sufixo mais o sufixo
+func etldPlusOne(host, suffix string) string {
+	if !strings.HasSuffix(host, "."+suffix) {
+		return ""
+	}
+	rest := strings.TrimSuffix(host, "."+suffix)
+	if idx := strings.LastIndex(rest, "."); idx >= 0 {
+		rest = rest[idx+1:]
+	}
+	return rest + "." + suffix
+}
+
+// canonicalURL monta a URL com esquema e host em minúsculas, sem porta padrão e sem fragmento
+func canonicalURL(parsed *url.URL, normalized *Normalized) string {
+	scheme := strings.ToLower(parsed.Scheme)
+
+	host := normalized.Host
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if port := parsed.Port(); port != "" && defaultPorts[scheme] != port {
+		host += ":" + port
+	}
+
+	canonical := url.URL{
+		Scheme:   scheme,
+		User:     parsed.User,
+		Host:     host,
+		Path:     parsed.Path,
+		RawPath:  parsed.RawPath,
+		RawQuery: parsed.RawQuery,
+	}
+	if canonical.Path == "" {
+		canonical.Path = "/"
+	}
+	return canonical.String()
+}
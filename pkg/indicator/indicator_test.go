@@ -0,0 +1,96 @@
+package indicator
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		input       string
+		host        string
+		registrable string
+		site        string
+		suffix      string
+		tld         string
+		url         string
+	}{
+		{"https://login.bank.example.com.br/acesso", "login.bank.example.com.br", "example.com.br", "example.com.br", "com.br", "br", "https://login.bank.example.com.br/acesso"},
+		{"secure.example.co.uk", "secure.example.co.uk", "example.co.uk", "example.co.uk", "co.uk", "uk", "http://secure.example.co.uk/"},
+		{"hxxps://Paypal-Verify[.]Example[.]COM:443/a?b=1#frag", "paypal-verify.example.com", "example.com", "example.com", "com", "com", "https://paypal-verify.example.com/a?b=1"},
+		{"http://example.com:8080", "example.com", "example.com", "example.com", "com", "com", "http://example.com:8080/"},
+		{"acme-login.github.io/index.html", "acme-login.github.io", "github.io", "acme-login.github.io", "github.io", "io", "http://acme-login.github.io/index.html"},
+		{"banco.blogspot.com.br", "banco.blogspot.com.br", "blogspot.com.br", "banco.blogspot.com.br", "blogspot.com.br", "br", "http://banco.blogspot.com.br/"},
+		{"www.bücher.de", "www.xn--bcher-kva.de", "xn--bcher-kva.de", "xn--bcher-kva.de", "de", "de", "http://www.xn--bcher-kva.de/"},
+		{"login.example.unknowntld", "login.example.unknowntld", "example.unknowntld", "example.unknowntld", "unknowntld", "unknowntld", "http://login.example.unknowntld/"},
+		{"my_site.example.com.", "my_site.example.com", "example.com", "example.com", "com", "com", "http://my_site.example.com/"},
+		{"com.br", "com.br", "", "", "com.br", "br", "http://com.br/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			n, err := Normalize(tt.input)
+			if err != nil {
+				t.Fatalf("Normalize(%q) returned error: %v", tt.input, err)
+			}
+			if n.Host != tt.host || n.RegistrableDomain != tt.registrable || n.Site != tt.site ||
+				n.PublicSuffix != tt.suffix || n.TLD != tt.tld || n.URL != tt.url {
+				t.Errorf("Normalize(%q) = %+v", tt.input, n)
+			}
+			if n.Input != tt.input {
+				t.Errorf("Expected input to be kept, got %q", n.Input)
+			}
+		})
+	}
+}
+
+func TestNormalize_Details(t *testing.T) {
+	n, err := Normalize("acme-login.github.io")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !n.PrivateSuffix {
+		t.Error("Expected github.io to be a private suffix")
+	}
+
+	n, err = Normalize("www.bücher.de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.UnicodeHost != "www.bücher.de" || n.PrivateSuffix {
+		t.Errorf("Unexpected IDN normalization: %+v", n)
+	}
+
+	n, err = Normalize("hxxp://[2001[:]DB8[:][:]1]:8080/x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !n.IP || n.Host != "2001:db8::1" || n.URL != "http://[2001:db8::1]:8080/x" || n.RegistrableDomain != "" {
+		t.Errorf("Unexpected IPv6 normalization: %+v", n)
+	}
+
+	n, err = Normalize("203.0.113[.]10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !n.IP || n.Host != "203.0.113.10" || n.TLD != "" {
+		t.Errorf("Unexpected IPv4 normalization: %+v", n)
+	}
+}
+
+func TestNormalize_Invalid(t *testing.T) {
+	for _, input := range []string{"", "   ", "http://", "http://exa mple.com/"} {
+		if _, err := Normalize(input); err == nil {
+			t.Errorf("Expected error for %q", input)
+		}
+	}
+}
+
+func TestRegistrableDomainAndTLD(t *testing.T) {
+	if got := RegistrableDomain("hxxps://login[.]bank[.]example[.]com[.]br/"); got != "example.com.br" {
+		t.Errorf("RegistrableDomain = %q, want example.com.br", got)
+	}
+	if got := RegistrableDomain("not a host"); got != "" {
+		t.Errorf("Expected empty registrable domain for invalid input, got %q", got)
+	}
+	if got := TLD("shop.example.co.uk"); got != "uk" {
+		t.Errorf("TLD = %q, want uk", got)
+	}
+}
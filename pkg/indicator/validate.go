@@ -0,0 +1,368 @@
+package indicator
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"syscall"
+
+	"github.com/cti-team/takedown/pkg/defang"
+	"github.com/cti-team/takedown/pkg/models"
+	"golang.org/x/net/publicsuffix"
+)
+
+// Famílias de hash reconhecidas pelo tamanho em hexadecimal
+const (
+	HashMD5    = "md5"
+	HashSHA1   = "sha1"
+	HashSHA256 = "sha256"
+	HashSHA512 = "sha512"
+)
+
+// hashFamilies mapeia o tamanho do hash em hexadecimal para a família
+var hashFamilies = map[int]string{32: HashMD5, 40: HashSHA1, 64: HashSHA256, 128: HashSHA512}
+
+// Limites de prefixo: blocos maiores que isso não são um IOC de takedown
+const (
+	minIPv4Prefix = 16
+	minIPv6Prefix = 32
+)
+
+// urlSchemes são os esquemas aceitos em IOCs de URL
+var urlSchemes = map[string]bool{"http": true, "https": true, "ftp": true, "ftps": true}
+
+// reservedTLDs são nomes de uso especial (RFC 6761 e afins) que não apontam para infraestrutura na internet
+var reservedTLDs = map[string]bool{"localhost": true, "local": true, "invalid": true, "internal": true, "arpa": true, "onion": true, "lan": true, "home": true, "corp": true}
+
+// documentationTLDs são reservados para documentação e testes (RFC 2606) e ficam fora da PSL
+var documentationTLDs = map[string]bool{"example": true, "test": true}
+
+// documentationNetworks são as faixas de documentação (RFC 5737 e RFC 3849)
+var documentationNetworks = mustParseCIDRs(
+	"192.0.2.0/24",    // TEST-NET-1
+	"198.51.100.0/24", // TEST-NET-2
+	"203.0.113.0/24",  // TEST-NET-3
+	"2001:db8::/32",   // documentação IPv6
+)
+
+// reservedNetworks são as faixas que não são endereços públicos na internet
+var reservedNetworks = mustParseCIDRs(
+	"0.0.0.0/8",      // "this network"
+	"10.0.0.0/8",     // privado
+	"100.64.0.0/10",  // CGNAT (RFC 6598)
+	"127.0.0.0/8",    // loopback
+	"169.254.0.0/16", // link-local
+	"172.16.0.0/12",  // privado
+	"192.0.0.0/24",   // IETF protocol assignments
+	"192.168.0.0/16", // privado
+	"198.18.0.0/15",  // benchmarking (RFC 2544)
+	"224.0.0.0/4",    // multicast
+	"240.0.0.0/4",    // reservado, inclui broadcast
+	"::/128",         // não especificado
+	"::1/128",        // loopback
+	"100::/64",       // discard (RFC 6666)
+	"2001:2::/48",    // benchmarking (RFC 5180)
+	"2001:10::/28",   // ORCHID
+	"fc00::/7",       // unique local
+	"fe80::/10",      // link-local
+	"fec0::/10",      // site-local (obsoleto)
+	"ff00::/8",       // multicast
+)
+
+// Validated é um IOC validado, com o tipo detectado e o valor canônico
+type Validated struct {
+	Type       models.IOCType `json:"type"`
+	Value      string         `json:"value"`                 // URL canônica, host em punycode, IP/CIDR normalizado, hash e e-mail em minúsculas
+	HashFamily string         `json:"hash_family,omitempty"` // md5, sha1, sha256 ou sha512
+	Normalized *Normalized    `json:"-"`                     // URL, domínio, IP e o domínio do e-mail
+}
+
+// DetectType classifica o valor (refangado) sem validá-lo: e-mail, hash, CIDR, URL, IP ou domínio
+func DetectType(value string) models.IOCType {
+	raw := defang.Refang(value)
+	switch {
+	case strings.Contains(raw, "@") && !strings.ContainsAny(raw, "/:?"):
+		return models.IOCTypeEmail
+	case hashFamilies[len(raw)] != "" && isHex(raw):
+		return models.IOCTypeHash
+	case !strings.Contains(raw, "://") && isCIDR(raw):
+		return models.IOCTypeCIDR
+	case strings.Contains(raw, "://") || strings.ContainsAny(raw, "/?"):
+		return models.IOCTypeURL
+	case net.ParseIP(strings.Trim(raw, "[]")) != nil:
+		return models.IOCTypeIP
+	default:
+		return models.IOCTypeDomain
+	}
+}
+
+// Validate detecta o tipo, confere o tipo declarado (vazio = detectar) e canoniza o valor. Rejeita
+// valores malformados, endereços privados ou reservados, nomes de uso especial e TLDs inexistentes.
+// Um domínio ou IP declarado como url vira a URL http:// do host.
+func Validate(value string, declared models.IOCType) (*Validated, error) {
+	raw := defang.Refang(value)
+	if raw == "" {
+		return nil, fmt.Errorf("empty value")
+	}
+
+	detected := DetectType(raw)
+	iocType := detected
+	if declared != "" {
+		switch declared {
+		case models.IOCTypeURL, models.IOCTypeDomain, models.IOCTypeIP, models.IOCTypeCIDR, models.IOCTypeHash, models.IOCTypeEmail:
+		default:
+			return nil, fmt.Errorf("unsupported type %q", declared)
+		}
+		// Domínio é o tipo residual da detecção: nesse caso o valor é validado como o tipo declarado
+		promoted := declared == models.IOCTypeURL && detected == models.IOCTypeIP
+		if declared != detected && detected != models.IOCTypeDomain && !promoted {
+			return nil, fmt.Errorf("%q looks like %s, not %s", value, detected, declared)
+		}
+		iocType = declared
+	}
+
+	switch iocType {
+	case models.IOCTypeURL:
+		return validateURL(raw)
+	case models.IOCTypeDomain:
+		return validateDomain(raw)
+	case models.IOCTypeIP:
+		return validateIP(raw)
+	case models.IOCTypeCIDR:
+		return validateCIDR(raw)
+	case models.IOCTypeHash:
+		return validateHash(raw)
+	default:
+		return validateEmail(raw)
+	}
+}
+
+// validateURL exige esquema web e valida o host como domínio ou IP
+func validateURL(raw string) (*Validated, error) {
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url %q: %w", raw, err)
+	}
+	if scheme := strings.ToLower(parsed.Scheme); !urlSchemes[scheme] {
+		return nil, fmt.Errorf("invalid url %q: unsupported scheme %q", raw, parsed.Scheme)
+	}
+
+	normalized, err := Normalize(raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkHost(normalized); err != nil {
+		return nil, fmt.Errorf("invalid url %q: %w", raw, err)
+	}
+	return &Validated{Type: models.IOCTypeURL, Value: normalized.URL, Normalized: normalized}, nil
+}
+
+// validateDomain valida o hostname (sem porta, caminho ou credenciais)
+func validateDomain(raw string) (*Validated, error) {
+	if strings.ContainsAny(raw, ":@ ") {
+		return nil, fmt.Errorf("invalid domain %q", raw)
+	}
+	normalized, err := Normalize(raw)
+	if err != nil {
+		return nil, err
+	}
+	if normalized.IP {
+		return nil, fmt.Errorf("%q is an IP address, not a domain", raw)
+	}
+	if err := checkHost(normalized); err != nil {
+		return nil, fmt.Errorf("invalid domain %q: %w", raw, err)
+	}
+	return &Validated{Type: models.IOCTypeDomain, Value: normalized.Host, Normalized: normalized}, nil
+}
+
+// validateIP valida um endereço IPv4 ou IPv6 público
+func validateIP(raw string) (*Validated, error) {
+	ip := net.ParseIP(strings.Trim(raw, "[]"))
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address %q", raw)
+	}
+	if err := checkPublicIP(ip); err != nil {
+		return nil, err
+	}
+	normalized, err := Normalize(ip.String())
+	if err != nil {
+		return nil, err
+	}
+	return &Validated{Type: models.IOCTypeIP, Value: ip.String(), Normalized: normalized}, nil
+}
+
+// validateCIDR valida um bloco público e não largo demais, canonizado para o endereço de rede
+func validateCIDR(raw string) (*Validated, error) {
+	_, network, err := net.ParseCIDR(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CIDR %q: %w", raw, err)
+	}
+	ones, bits := network.Mask.Size()
+	if (bits == 32 && ones < minIPv4Prefix) || (bits == 128 && ones < minIPv6Prefix) {
+		return nil, fmt.Errorf("CIDR %s is too broad (minimum /%d for IPv4, /%d for IPv6)", network, minIPv4Prefix, minIPv6Prefix)
+	}
+	for _, reserved := range reservedNetworks {
+		if reserved.Contains(network.IP) || network.Contains(reserved.IP) {
+			return nil, fmt.Errorf("CIDR %s overlaps reserved range %s", network, reserved)
+		}
+	}
+	for _, documentation := range documentationNetworks {
+		if documentation.Contains(network.IP) || network.Contains(documentation.IP) {
+			return nil, fmt.Errorf("CIDR %s overlaps documentation range %s", network, documentation)
+		}
+	}
+	return &Validated{Type: models.IOCTypeCIDR, Value: network.String()}, nil
+}
+
+// validateHash identifica a família pelo tamanho e canoniza em minúsculas
+func validateHash(raw string) (*Validated, error) {
+	family := hashFamilies[len(raw)]
+	if family == "" || !isHex(raw) {
+		return nil, fmt.Errorf("invalid hash %q: expected MD5, SHA-1, SHA-256 or SHA-512 in hex", raw)
+	}
+	return &Validated{Type: models.IOCTypeHash, Value: strings.ToLower(raw), HashFamily: family}, nil
+}
+
+// validateEmail valida a parte local (RFC 5322, sem aspas) e o domínio
+func validateEmail(raw string) (*Validated, error) {
+	at := strings.LastIndex(raw, "@")
+	if at <= 0 || at == len(raw)-1 {
+		return nil, fmt.Errorf("invalid email %q", raw)
+	}
+	local, domain := raw[:at], raw[at+1:]
+	if len(local) > 64 || strings.HasPrefix(local, ".") || strings.HasSuffix(local, ".") || strings.Contains(local, "..") {
+		return nil, fmt.Errorf("invalid email %q: malformed local part", raw)
+	}
+	for _, r := range local {
+		if !isLocalPartRune(r) {
+			return nil, fmt.Errorf("invalid email %q: character %q not allowed in local part", raw, r)
+		}
+	}
+
+	host, err := validateDomain(domain)
+	if err != nil {
+		return nil, fmt.Errorf("invalid email %q: %w", raw, err)
+	}
+	return &Validated{Type: models.IOCTypeEmail, Value: strings.ToLower(local) + "@" + host.Value, Normalized: host.Normalized}, nil
+}
+
+// checkHost rejeita IPs não públicos, hosts sem ponto, nomes de uso especial e TLDs fora da PSL
+func checkHost(normalized *Normalized) error {
+	if normalized.IP {
+		return checkPublicIP(net.ParseIP(normalized.Host))
+	}
+
+	host := normalized.Host
+	if len(host) > 253 {
+		return fmt.Errorf("hostname longer than 253 characters")
+	}
+	if !strings.Contains(host, ".") {
+		return fmt.Errorf("%q is not a fully qualified domain name", host)
+	}
+	for _, label := range strings.Split(host, ".") {
+		if label == "" || len(label) > 63 {
+			return fmt.Errorf("invalid label %q in %q", label, host)
+		}
+		if strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return fmt.Errorf("label %q in %q starts or ends with a hyphen", label, host)
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9') && r != '-' && r != '_' {
+				return fmt.Errorf("character %q not allowed in hostname %q", r, host)
+			}
+		}
+	}
+
+	tld := normalized.TLD
+	switch {
+	case reservedTLDs[tld]:
+		return fmt.Errorf("%q uses the special-use name .%s", host, tld)
+	case documentationTLDs[tld]:
+		return fmt.Errorf("%q uses the documentation name .%s", host, tld)
+	}
+	if _, icann := publicsuffix.PublicSuffix(tld); !icann {
+		return fmt.Errorf("unknown TLD .%s", tld)
+	}
+	if normalized.Site == "" {
+		return fmt.Errorf("%q is a public suffix, not a domain", host)
+	}
+	return nil
+}
+
+// checkPublicIP rejeita endereços privados, loopback, link-local, multicast e outras faixas reservadas
+func checkPublicIP(ip net.IP) error {
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	for _, reserved := range reservedNetworks {
+		if reserved.Contains(ip) {
+			return fmt.Errorf("%s is in reserved range %s", ip, reserved)
+		}
+	}
+	for _, documentation := range documentationNetworks {
+		if documentation.Contains(ip) {
+			return fmt.Errorf("%s is in documentation range %s", ip, documentation)
+		}
+	}
+	return nil
+}
+
+// DialControl é um net.Dialer.Control que recusa conexões a endereços não públicos. Roda depois da
+// resolução DNS, então cobre tanto IPs literais quanto nomes que resolvem para a rede interna.
+func DialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("unexpected dial address %q", address)
+	}
+	if err := checkPublicIP(ip); err != nil {
+		return fmt.Errorf("refusing to connect to %s: %w", address, err)
+	}
+	return nil
+}
+
+// isCIDR indica se o valor é endereço/prefixo
+func isCIDR(value string) bool {
+	_, _, err := net.ParseCIDR(value)
+	return err == nil
+}
+
+// isHex indica se o valor só tem dígitos hexadecimais
+func isHex(value string) bool {
+	for _, r := range value {
+		if !(r >= '0' && r <= '9') && !(r >= 'a' && r <= 'f') && !(r >= 'A' && r <= 'F') {
+			return false
+		}
+	}
+	return value != ""
+}
+
+// isLocalPartRune indica se o caractere é permitido na parte local de um e-mail sem aspas
+func isLocalPartRune(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return true
+	default:
+		return strings.ContainsRune(".!#$%&'*+-/=?^_`{|}~", r)
+	}
+}
+
+// mustParseCIDRs interpreta as faixas fixas do pacote
+func mustParseCIDRs(values ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(values))
+	for _, value := range values {
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
@@ -0,0 +1,146 @@
+package indicator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		declared models.IOCType
+		wantType models.IOCType
+		want     string
+		wantErr  string
+	}{
+		{name: "defanged url", value: "hxxps://Acme-Login[.]example[.]com:443/a#x", wantType: models.IOCTypeURL, want: "https://acme-login.example.com/a"},
+		{name: "url with ip host", value: "http://45.67.89.7:8080/panel", wantType: models.IOCTypeURL, want: "http://45.67.89.7:8080/panel"},
+		{name: "domain", value: "Login.AcmeBank-Secure.com.br.", wantType: models.IOCTypeDomain, want: "login.acmebank-secure.com.br"},
+		{name: "idn domain", value: "www.bücher.de", wantType: models.IOCTypeDomain, want: "www.xn--bcher-kva.de"},
+		{name: "domain declared as url", value: "acme-login.com", declared: models.IOCTypeURL, wantType: models.IOCTypeURL, want: "http://acme-login.com/"},
+		{name: "ipv4", value: "45.67.89[.]7", wantType: models.IOCTypeIP, want: "45.67.89.7"},
+		{name: "ipv6", value: "2A01:4F8::0:1", wantType: models.IOCTypeIP, want: "2a01:4f8::1"},
+		{name: "cidr", value: "45.67.90.77/24", wantType: models.IOCTypeCIDR, want: "45.67.90.0/24"},
+		{name: "ipv6 cidr", value: "2a01:4f8:10::/48", wantType: models.IOCTypeCIDR, want: "2a01:4f8:10::/48"},
+		{name: "md5", value: "D41D8CD98F00B204E9800998ECF8427E", wantType: models.IOCTypeHash, want: "d41d8cd98f00b204e9800998ecf8427e"},
+		{name: "sha256", value: strings.Repeat("ab", 32), declared: models.IOCTypeHash, wantType: models.IOCTypeHash, want: strings.Repeat("ab", 32)},
+		{name: "email", value: "Suporte.Acme+1[@]Mail-Acme[.]com", wantType: models.IOCTypeEmail, want: "suporte.acme+1@mail-acme.com"},
+
+		{name: "empty", value: "  ", wantErr: "empty value"},
+		{name: "unknown declared type", value: "a.example", declared: "asn", wantErr: "unsupported type"},
+		{name: "url declared as domain", value: "https://a.example/login", declared: models.IOCTypeDomain, wantErr: "looks like url, not domain"},
+		{name: "url declared as hash", value: "http://a.example/x", declared: models.IOCTypeHash, wantErr: "looks like url, not hash"},
+		{name: "domain declared as ip", value: "a.example", declared: models.IOCTypeIP, wantErr: "invalid IP address"},
+		{name: "unknown tld", value: "malware-distribution.evil", wantErr: "unknown TLD .evil"},
+		{name: "single label", value: "intranet", wantErr: "not a fully qualified domain name"},
+		{name: "special-use name", value: "printer.local", wantErr: "special-use name .local"},
+		{name: "localhost url", value: "http://localhost:8080/", wantErr: "not a fully qualified domain name"},
+		{name: "public suffix", value: "com.br", wantErr: "public suffix"},
+		{name: "bad characters", value: "exa!mple.com", wantErr: "not allowed in hostname"},
+		{name: "hyphen label", value: "-acme.example.com", wantErr: "invalid label"},
+		{name: "javascript url", value: "javascript://alert(1)", wantErr: "unsupported scheme"},
+		{name: "private ip", value: "192.168.1.10", wantErr: "reserved range 192.168.0.0/16"},
+		{name: "loopback url", value: "http://127.0.0.1/admin", wantErr: "reserved range 127.0.0.0/8"},
+		{name: "ipv6 link-local", value: "fe80::1", wantErr: "reserved range fe80::/10"},
+		{name: "mapped private ipv4", value: "::ffff:10.0.0.1", wantErr: "reserved range 10.0.0.0/8"},
+		{name: "cgnat", value: "100.64.1.1", wantErr: "reserved range"},
+		{name: "broad cidr", value: "203.0.0.0/8", wantErr: "too broad"},
+		{name: "private cidr", value: "10.1.0.0/16", wantErr: "overlaps reserved range"},
+		{name: "cidr covering private", value: "172.0.0.0/10", declared: models.IOCTypeCIDR, wantErr: "too broad"},
+		{name: "bad hash", value: strings.Repeat("z", 64), declared: models.IOCTypeHash, wantErr: "invalid hash"},
+		{name: "email with space", value: "a b@acme.example", declared: models.IOCTypeEmail, wantErr: "not allowed in local part"},
+		{name: "email on private domain", value: "admin@corp.internal", wantErr: "special-use name .internal"},
+		{name: "email without domain", value: "admin@", declared: models.IOCTypeEmail, wantErr: "invalid email"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validated, err := Validate(tt.value, tt.declared)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Validate(%q) error = %v, want %q", tt.value, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate(%q) returned error: %v", tt.value, err)
+			}
+			if validated.Type != tt.wantType || validated.Value != tt.want {
+				t.Errorf("Validate(%q) = %s %q, want %s %q", tt.value, validated.Type, validated.Value, tt.wantType, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate_DocumentationRejected(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantErr string
+	}{
+		{"192.0.2.10", "documentation range 192.0.2.0/24"},
+		{"hxxp://198.51.100[.]7/panel", "documentation range 198.51.100.0/24"},
+		{"203.0.113.0/28", "overlaps documentation range 203.0.113.0/24"},
+		{"2001:db8::1", "documentation range 2001:db8::/32"},
+		{"192.0.0.0/16", "overlaps reserved range"},
+		{"acme-login.example", "documentation name .example"},
+		{"https://panel.acme.test/login", "documentation name .test"},
+		{"drop@mail.example", "documentation name .example"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			_, err := Validate(tt.value, "")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate(%q) error = %v, want %q", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := Validate("acme-login.com", ""); err != nil {
+		t.Errorf("public domain rejected: %v", err)
+	}
+}
+
+func TestValidate_HashFamily(t *testing.T) {
+	for length, family := range map[int]string{32: HashMD5, 40: HashSHA1, 64: HashSHA256, 128: HashSHA512} {
+		validated, err := Validate(strings.Repeat("0f", length/2), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if validated.HashFamily != family {
+			t.Errorf("length %d: expected %s, got %s", length, family, validated.HashFamily)
+		}
+	}
+}
+
+func TestDialControl(t *testing.T) {
+	tests := []struct {
+		address string
+		wantErr string
+	}{
+		{"45.67.89.7:443", ""},
+		{"[2a01:4f8::1]:80", ""},
+		{"127.0.0.1:8080", "reserved range 127.0.0.0/8"},
+		{"169.254.169.254:80", "reserved range 169.254.0.0/16"},
+		{"10.0.0.5:443", "reserved range 10.0.0.0/8"},
+		{"[::ffff:192.168.0.1]:80", "reserved range 192.168.0.0/16"},
+		{"[fd00::1]:443", "reserved range fc00::/7"},
+		{"example.com:80", "unexpected dial address"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			err := DialControl("tcp", tt.address, nil)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("DialControl(%q) returned error: %v", tt.address, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("DialControl(%q) error = %v, want %q", tt.address, err, tt.wantErr)
+			}
+		})
+	}
+}
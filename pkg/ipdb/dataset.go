@@ -0,0 +1,129 @@
+package ipdb
+
+import (
+	"fmt"
+	"net/netip"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultCheckInterval limita a frequência de stat() do arquivo durante lookups
+const defaultCheckInterval = 30 * time.Second
+
+// Dataset é uma base offline carregada de arquivo que recarrega quando o arquivo muda
+type Dataset struct {
+	path          string
+	load          func(path string) (Reader, error)
+	reader        Reader
+	modTime       time.Time
+	size          int64
+	lastCheck     time.Time
+	checkInterval time.Duration
+	mutex         sync.RWMutex
+}
+
+// Open carrega uma base, escolhendo o formato pela extensão (.mmdb ou TSV iptoasn)
+func Open(path string) (*Dataset, error) {
+	loader := loadTSVReader
+	if strings.HasSuffix(strings.ToLower(path), ".mmdb") {
+		loader = loadMMDBReader
+	}
+
+	dataset := &Dataset{
+		path:          path,
+		load:          loader,
+		checkInterval: defaultCheckInterval,
+	}
+
+	if err := dataset.Reload(); err != nil {
+		return nil, err
+	}
+
+	return dataset, nil
+}
+
+// OpenAll abre várias bases e as combina (ex: iptoasn para ASN e MMDB para país)
+func OpenAll(paths []string) (Multi, error) {
+	var readers Multi
+	for _, path := range paths {
+		dataset, err := Open(path)
+		if err != nil {
+			return nil, err
+		}
+		readers = append(readers, dataset)
+	}
+	return readers, nil
+}
+
+// Path retorna o caminho do arquivo da base
+func (d *Dataset) Path() string {
+	return d.path
+}
+
+// Lookup consulta a base, recarregando-a antes se o arquivo tiver mudado
+func (d *Dataset) Lookup(addr netip.Addr) (Record, bool) {
+	d.reloadIfChanged()
+
+	d.mutex.RLock()
+	reader := d.reader
+	d.mutex.RUnlock()
+
+	if reader == nil {
+		return Record{}, false
+	}
+	return reader.Lookup(addr)
+}
+
+// Reload força a releitura do arquivo; em caso de erro a versão anterior é mantida
+func (d *Dataset) Reload() error {
+	info, err := os.Stat(d.path)
+	if err != nil {
+		return fmt.Errorf("failed to stat dataset: %w", err)
+	}
+
+	reader, err := d.load(d.path)
+	if err != nil {
+		return fmt.Errorf("failed to load dataset %s: %w", d.path, err)
+	}
+
+	d.mutex.Lock()
+	d.reader = reader
+	d.modTime = info.ModTime()
+	d.size = info.Size()
+	d.lastCheck = time.Now()
+	d.mutex.Unlock()
+
+	return nil
+}
+
+// reloadIfChanged verifica mtime/tamanho do arquivo respeitando o intervalo de checagem
+func (d *Dataset) reloadIfChanged() {
+	d.mutex.Lock()
+	if time.Since(d.lastCheck) < d.checkInterval {
+		d.mutex.Unlock()
+		return
+	}
+	d.lastCheck = time.Now()
+	modTime, size := d.modTime, d.size
+	d.mutex.Unlock()
+
+	info, err := os.Stat(d.path)
+	if err != nil || (info.ModTime().Equal(modTime) && info.Size() == size) {
+		return
+	}
+
+	// Erros de parse (ex: arquivo sendo reescrito) mantêm a versão anterior
+	_ = d.Reload()
+}
+
+// loadTSVReader adapta LoadTSVFile para a interface Reader
+func loadTSVReader(path string) (Reader, error) {
+	return LoadTSVFile(path)
+}
+
+// loadMMDBReader adapta LoadMMDBFile para a interface Reader
+func loadMMDBReader(path string) (Reader, error) {
+	return LoadMMDBFile(path)
+}
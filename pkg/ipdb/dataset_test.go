@@ -0,0 +1,84 @@
+package ipdb
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDataset_ReloadsOnChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ip2asn.tsv")
+	if err := os.WriteFile(path, []byte("203.0.113.0\t203.0.113.255\t64500\tBR\tOLD-NAME\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	dataset, err := Open(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	dataset.checkInterval = 0
+
+	addr := netip.MustParseAddr("203.0.113.1")
+	if record, _ := dataset.Lookup(addr); record.ASName != "OLD-NAME" {
+		t.Fatalf("Expected OLD-NAME, got %+v", record)
+	}
+
+	if err := os.WriteFile(path, []byte("203.0.113.0\t203.0.113.255\t64501\tPT\tNEW-NAME-LONGER\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatal(err)
+	}
+
+	record, found := dataset.Lookup(addr)
+	if !found || record.ASN != 64501 || record.Country != "PT" {
+		t.Errorf("Expected reloaded record, got %+v", record)
+	}
+}
+
+func TestDataset_KeepsPreviousOnBadReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ip2asn.tsv")
+	if err := os.WriteFile(path, []byte("203.0.113.0\t203.0.113.255\t64500\tBR\tGOOD\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	dataset, err := Open(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	dataset.checkInterval = 0
+
+	if err := os.WriteFile(path, []byte("garbage line\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := dataset.Reload(); err == nil {
+		t.Error("Expected reload error for corrupt dataset")
+	}
+
+	if record, found := dataset.Lookup(netip.MustParseAddr("203.0.113.1")); !found || record.ASName != "GOOD" {
+		t.Errorf("Expected previous dataset to remain active, got %+v", record)
+	}
+}
+
+func TestOpen_MMDBByExtension(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "GeoLite2-Country.mmdb")
+	data := map[string]interface{}{"country": map[string]interface{}{"iso_code": "BR"}}
+	if err := os.WriteFile(path, buildMMDB(t, 6, 28, netip.MustParsePrefix("203.0.113.0/24"), data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	dataset, err := Open(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if record, found := LookupString(dataset, "203.0.113.9"); !found || record.Country != "BR" {
+		t.Errorf("Unexpected record: %+v", record)
+	}
+
+	if _, err := Open(filepath.Join(t.TempDir(), "missing.tsv")); err == nil {
+		t.Error("Expected error for missing dataset file")
+	}
+}
@@ -0,0 +1,54 @@
+package ipdb
+
+import (
+	"net/netip"
+	"strings"
+)
+
+// Record representa as informações de rede associadas a um IP
+type Record struct {
+	ASN     int    `json:"asn,omitempty"`
+	ASName  string `json:"as_name,omitempty"`
+	Country string `json:"country,omitempty"` // ISO 3166-1 alpha-2 em maiúsculas
+}
+
+// Reader é a interface comum das bases offline de IP
+type Reader interface {
+	Lookup(addr netip.Addr) (Record, bool)
+}
+
+// Multi combina várias bases (ex: ASN em TSV e país em MMDB)
+type Multi []Reader
+
+// Lookup consulta as bases em ordem, preenchendo os campos ainda vazios
+func (m Multi) Lookup(addr netip.Addr) (Record, bool) {
+	var result Record
+	found := false
+
+	for _, reader := range m {
+		record, ok := reader.Lookup(addr)
+		if !ok {
+			continue
+		}
+		found = true
+
+		if result.ASN == 0 && record.ASN != 0 {
+			result.ASN = record.ASN
+			result.ASName = record.ASName
+		}
+		if result.Country == "" {
+			result.Country = record.Country
+		}
+	}
+
+	return result, found
+}
+
+// LookupString faz o parse do IP e consulta a base
+func LookupString(reader Reader, ip string) (Record, bool) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return Record{}, false
+	}
+	return reader.Lookup(addr.Unmap())
+}
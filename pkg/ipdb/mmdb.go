@@ -0,0 +1,407 @@
+package ipdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// metadataMarker precede o bloco de metadados no final de arquivos MaxMind DB
+var metadataMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// dataSectionSeparator é o bloco de zeros entre a árvore de busca e os dados
+const dataSectionSeparator = 16
+
+// Tipos de dados do formato MaxMind DB
+const (
+	mmdbExtended = iota
+	mmdbPointer
+	mmdbString
+	mmdbDouble
+	mmdbBytes
+	mmdbUint16
+	mmdbUint32
+	mmdbMap
+	mmdbInt32
+	mmdbUint64
+	mmdbUint128
+	mmdbArray
+	mmdbContainer
+	mmdbEndMarker
+	mmdbBool
+	mmdbFloat
+)
+
+// MMDB é um leitor mínimo de arquivos MaxMind DB (GeoLite2 ASN/Country, ipinfo, DB-IP)
+type MMDB struct {
+	buffer       []byte
+	nodeCount    uint
+	recordSize   uint
+	ipVersion    uint
+	databaseType string
+	treeSize     uint
+	ipv4Start    uint
+}
+
+// LoadMMDBFile lê um arquivo .mmdb para a memória
+func LoadMMDBFile(path string) (*MMDB, error) {
+	buffer, err := os.ReadFile(path) // #nosec G304 -- caminho vem da configuração do operador
+	if err != nil {
+		return nil, err
+	}
+	return NewMMDB(buffer)
+}
+
+// NewMMDB interpreta os metadados e valida a estrutura do arquivo
+func NewMMDB(buffer []byte) (*MMDB, error) {
+	idx := bytes.LastIndex(buffer, metadataMarker)
+	if idx < 0 {
+		return nil, fmt.Errorf("invalid MaxMind DB: metadata marker not found")
+	}
+
+	metaStart := uint(idx + len(metadataMarker))
+	d := decoder{buffer: buffer[metaStart:]}
+	value, _, err := d.decode(0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MaxMind DB metadata: %w", err)
+	}
+	meta, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid MaxMind DB metadata: expected map")
+	}
+
+	db := &MMDB{
+		buffer:       buffer,
+		nodeCount:    uint(toUint64(meta["node_count"])),
+		recordSize:   uint(toUint64(meta["record_size"])),
+		ipVersion:    uint(toUint64(meta["ip_version"])),
+		databaseType: toString(meta["database_type"]),
+	}
+
+	switch db.recordSize {
+	case 24, 28, 32:
+	default:
+		return nil, fmt.Errorf("unsupported MaxMind DB record size %d", db.recordSize)
+	}
+
+	db.treeSize = db.nodeCount * db.recordSize / 4
+	if db.treeSize+dataSectionSeparator > uint(idx) {
+		return nil, fmt.Errorf("invalid MaxMind DB: search tree exceeds file size")
+	}
+
+	// Em bases IPv6, IPv4 fica sob ::/96
+	if db.ipVersion == 6 {
+		node := uint(0)
+		for i := 0; i < 96 && node < db.nodeCount; i++ {
+			node = db.readRecord(node, 0)
+		}
+		db.ipv4Start = node
+	}
+
+	return db, nil
+}
+
+// DatabaseType retorna o tipo declarado nos metadados (ex: GeoLite2-ASN)
+func (m *MMDB) DatabaseType() string {
+	return m.databaseType
+}
+
+// Lookup percorre a árvore de busca e decodifica o registro encontrado
+func (m *MMDB) Lookup(addr netip.Addr) (Record, bool) {
+	addr = addr.Unmap()
+	if addr.Is6() && m.ipVersion == 4 {
+		return Record{}, false
+	}
+
+	node := uint(0)
+	if addr.Is4() && m.ipVersion == 6 {
+		node = m.ipv4Start
+	}
+
+	raw := addrBytes(addr)
+	for i := 0; i < addr.BitLen() && node < m.nodeCount; i++ {
+		node = m.readRecord(node, addrBit(&raw, i))
+	}
+
+	if node < m.nodeCount+dataSectionSeparator {
+		return Record{}, false
+	}
+
+	offset := node - m.nodeCount - dataSectionSeparator
+	d := decoder{buffer: m.buffer[m.treeSize+dataSectionSeparator:]}
+	value, _, err := d.decode(offset)
+	if err != nil {
+		return Record{}, false
+	}
+
+	data, ok := value.(map[string]interface{})
+	if !ok {
+		return Record{}, false
+	}
+
+	return recordFromMMDB(data), true
+}
+
+// readRecord lê o ponteiro esquerdo (bit 0) ou direito (bit 1) de um nó
+func (m *MMDB) readRecord(node uint, bit int) uint {
+	b := m.buffer
+	switch m.recordSize {
+	case 24:
+		offset := node*6 + uint(bit)*3
+		return uint(b[offset])<<16 | uint(b[offset+1])<<8 | uint(b[offset+2])
+	case 28:
+		offset := node * 7
+		if bit == 0 {
+			return (uint(b[offset+3])&0xF0)<<20 | uint(b[offset])<<16 | uint(b[offset+1])<<8 | uint(b[offset+2])
+		}
+		return (uint(b[offset+3])&0x0F)<<24 | uint(b[offset+4])<<16 | uint(b[offset+5])<<8 | uint(b[offset+6])
+	default:
+		offset := node*8 + uint(bit)*4
+		return uint(binary.BigEndian.Uint32(b[offset : offset+4]))
+	}
+}
+
+// recordFromMMDB normaliza os esquemas mais comuns de bases MMDB
+func recordFromMMDB(data map[string]interface{}) Record {
+	var record Record
+
+	// GeoLite2-ASN / DB-IP ASN
+	if asn := toUint64(data["autonomous_system_number"]); asn != 0 {
+		record.ASN = int(asn)
+		record.ASName = toString(data["autonomous_system_organization"])
+	}
+
+	// ipinfo: {"asn": "AS13335", "as_name": "...", "country": "US"}
+	if record.ASN == 0 {
+		if asn, err := strconv.Atoi(strings.TrimPrefix(toString(data["asn"]), "AS")); err == nil {
+			record.ASN = asn
+			record.ASName = toString(data["as_name"])
+		}
+	}
+
+	// GeoLite2-Country/City: {"country": {"iso_code": "BR"}}
+	for _, key := range []string{"country", "registered_country"} {
+		if record.Country != "" {
+			break
+		}
+		switch v := data[key].(type) {
+		case map[string]interface{}:
+			record.Country = normalizeCountry(toString(v["iso_code"]))
+		case string:
+			record.Country = normalizeCountry(v)
+		}
+	}
+
+	return record
+}
+
+// decoder decodifica a seção de dados do formato MaxMind DB
+type decoder struct {
+	buffer []byte
+}
+
+// decode retorna o valor no offset e o offset seguinte
+func (d *decoder) decode(offset uint) (interface{}, uint, error) {
+	typeNum, size, offset, err := d.decodeControl(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if typeNum == mmdbPointer {
+		pointer, next, err := d.decodePointer(size, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		value, _, err := d.decode(pointer)
+		return value, next, err
+	}
+
+	return d.decodeValue(typeNum, size, offset)
+}
+
+// decodeControl lê o byte de controle, retornando tipo, tamanho e novo offset
+func (d *decoder) decodeControl(offset uint) (int, uint, uint, error) {
+	if offset >= uint(len(d.buffer)) {
+		return 0, 0, 0, fmt.Errorf("unexpected end of data at offset %d", offset)
+	}
+
+	ctrl := d.buffer[offset]
+	offset++
+
+	typeNum := int(ctrl >> 5)
+	if typeNum == mmdbExtended {
+		if offset >= uint(len(d.buffer)) {
+			return 0, 0, 0, fmt.Errorf("unexpected end of data in extended type")
+		}
+		typeNum = 7 + int(d.buffer[offset])
+		offset++
+	}
+
+	// Ponteiros usam os bits de tamanho de forma própria
+	if typeNum == mmdbPointer {
+		return typeNum, uint(ctrl & 0x1F), offset, nil
+	}
+
+	size := uint(ctrl & 0x1F)
+	if size >= 29 {
+		extra := size - 28
+		if offset+extra > uint(len(d.buffer)) {
+			return 0, 0, 0, fmt.Errorf("unexpected end of data in size")
+		}
+		n := uint(0)
+		for _, b := range d.buffer[offset : offset+extra] {
+			n = n<<8 | uint(b)
+		}
+		switch size {
+		case 29:
+			size = 29 + n
+		case 30:
+			size = 285 + n
+		default:
+			size = 65821 + n
+		}
+		offset += extra
+	}
+
+	return typeNum, size, offset, nil
+}
+
+// decodePointer resolve um ponteiro relativo ao início da seção de dados
+func (d *decoder) decodePointer(ctrlSize uint, offset uint) (uint, uint, error) {
+	pointerSize := ((ctrlSize >> 3) & 0x3) + 1
+	if offset+pointerSize > uint(len(d.buffer)) {
+		return 0, 0, fmt.Errorf("unexpected end of data in pointer")
+	}
+
+	b := d.buffer[offset : offset+pointerSize]
+	var prefix uint
+	if pointerSize != 4 {
+		prefix = ctrlSize & 0x7
+	}
+
+	n := prefix
+	for _, v := range b {
+		n = n<<8 | uint(v)
+	}
+
+	switch pointerSize {
+	case 2:
+		n += 2048
+	case 3:
+		n += 526336
+	}
+
+	return n, offset + pointerSize, nil
+}
+
+// decodeValue decodifica um valor de tipo conhecido
+func (d *decoder) decodeValue(typeNum int, size, offset uint) (interface{}, uint, error) {
+	switch typeNum {
+	case mmdbMap:
+		return d.decodeMap(size, offset)
+	case mmdbArray:
+		return d.decodeArray(size, offset)
+	case mmdbBool:
+		return size != 0, offset, nil
+	}
+
+	end := offset + size
+	if end > uint(len(d.buffer)) {
+		return nil, 0, fmt.Errorf("unexpected end of data for type %d", typeNum)
+	}
+	raw := d.buffer[offset:end]
+
+	switch typeNum {
+	case mmdbString:
+		return string(raw), end, nil
+	case mmdbBytes:
+		return append([]byte(nil), raw...), end, nil
+	case mmdbDouble:
+		if size != 8 {
+			return nil, 0, fmt.Errorf("invalid double size %d", size)
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(raw)), end, nil
+	case mmdbFloat:
+		if size != 4 {
+			return nil, 0, fmt.Errorf("invalid float size %d", size)
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(raw))), end, nil
+	case mmdbUint16, mmdbUint32, mmdbUint64, mmdbInt32:
+		var n uint64
+		for _, b := range raw {
+			n = n<<8 | uint64(b)
+		}
+		if typeNum == mmdbInt32 {
+			return int64(int32(uint32(n))), end, nil // #nosec G115 -- int32 do formato
+		}
+		return n, end, nil
+	case mmdbUint128:
+		// Valores de 128 bits não são usados pelos esquemas suportados
+		return append([]byte(nil), raw...), end, nil
+	default:
+		return nil, 0, fmt.Errorf("unsupported data type %d", typeNum)
+	}
+}
+
+// decodeMap decodifica um mapa com chaves string
+func (d *decoder) decodeMap(size, offset uint) (interface{}, uint, error) {
+	result := make(map[string]interface{})
+	for i := uint(0); i < size; i++ {
+		key, next, err := d.decode(offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		keyString, ok := key.(string)
+		if !ok {
+			return nil, 0, fmt.Errorf("map key is not a string")
+		}
+
+		value, next, err := d.decode(next)
+		if err != nil {
+			return nil, 0, err
+		}
+		result[keyString] = value
+		offset = next
+	}
+	return result, offset, nil
+}
+
+// decodeArray decodifica um array
+func (d *decoder) decodeArray(size, offset uint) (interface{}, uint, error) {
+	var result []interface{}
+	for i := uint(0); i < size; i++ {
+		value, next, err := d.decode(offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		result = append(result, value)
+		offset = next
+	}
+	return result, offset, nil
+}
+
+// toUint64 converte valores numéricos decodificados
+func toUint64(value interface{}) uint64 {
+	switch v := value.(type) {
+	case uint64:
+		return v
+	case int64:
+		if v < 0 {
+			return 0
+		}
+		return uint64(v)
+	default:
+		return 0
+	}
+}
+
+// toString converte valores string decodificados
+func toString(value interface{}) string {
+	s, _ := value.(string)
+	return s
+}
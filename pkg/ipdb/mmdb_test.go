@@ -0,0 +1,197 @@
+package ipdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net/netip"
+	"sort"
+	"testing"
+)
+
+// mmdbValue codifica valores no formato de dados MaxMind DB (apenas para testes)
+func mmdbValue(t *testing.T, value interface{}) []byte {
+	t.Helper()
+
+	switch v := value.(type) {
+	case string:
+		return append(mmdbControl(mmdbString, len(v)), v...)
+	case uint32:
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], v)
+		trimmed := bytes.TrimLeft(b[:], "\x00")
+		return append(mmdbControl(mmdbUint32, len(trimmed)), trimmed...)
+	case uint16:
+		var b [2]byte
+		binary.BigEndian.PutUint16(b[:], v)
+		trimmed := bytes.TrimLeft(b[:], "\x00")
+		return append(mmdbControl(mmdbUint16, len(trimmed)), trimmed...)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		out := mmdbControl(mmdbMap, len(v))
+		for _, key := range keys {
+			out = append(out, mmdbValue(t, key)...)
+			out = append(out, mmdbValue(t, v[key])...)
+		}
+		return out
+	default:
+		t.Fatalf("unsupported test value %T", value)
+		return nil
+	}
+}
+
+// mmdbControl monta o byte de controle (com tipo estendido quando necessário)
+func mmdbControl(typeNum, size int) []byte {
+	if size >= 285 {
+		panic("test encoder supports only sizes below 285")
+	}
+
+	var extra []byte
+	if size >= 29 {
+		extra = []byte{byte(size - 29)}
+		size = 29
+	}
+
+	if typeNum <= 7 {
+		return append([]byte{byte(typeNum<<5 | size)}, extra...)
+	}
+	return append([]byte{byte(size), byte(typeNum - 7)}, extra...)
+}
+
+// buildMMDB cria uma base com um único prefixo apontando para data
+func buildMMDB(t *testing.T, ipVersion, recordSize int, prefix netip.Prefix, data map[string]interface{}) []byte {
+	t.Helper()
+
+	// Caminho de bits até o prefixo (IPv4 em base IPv6 fica sob ::/96)
+	var bits []int
+	addr := prefix.Addr()
+	if ipVersion == 6 && addr.Is4() {
+		bits = make([]int, 96)
+	}
+	raw := addrBytes(addr)
+	for i := 0; i < prefix.Bits(); i++ {
+		bits = append(bits, addrBit(&raw, i))
+	}
+
+	nodeCount := len(bits)
+	dataPointer := nodeCount + dataSectionSeparator // dados no offset 0
+
+	var tree []byte
+	for i, bit := range bits {
+		next := i + 1
+		if i == len(bits)-1 {
+			next = dataPointer
+		}
+		records := [2]int{nodeCount, nodeCount}
+		records[bit] = next
+		tree = append(tree, encodeNode(recordSize, records[0], records[1])...)
+	}
+
+	var buffer bytes.Buffer
+	buffer.Write(tree)
+	buffer.Write(make([]byte, dataSectionSeparator))
+	buffer.Write(mmdbValue(t, data))
+	buffer.Write(metadataMarker)
+	buffer.Write(mmdbValue(t, map[string]interface{}{
+		"node_count":                  uint32(nodeCount),
+		"record_size":                 uint16(recordSize),
+		"ip_version":                  uint16(ipVersion),
+		"database_type":               "Test-DB",
+		"binary_format_major_version": uint16(2),
+	}))
+
+	return buffer.Bytes()
+}
+
+// encodeNode serializa um nó da árvore de busca
+func encodeNode(recordSize, left, right int) []byte {
+	switch recordSize {
+	case 24:
+		return []byte{byte(left >> 16), byte(left >> 8), byte(left), byte(right >> 16), byte(right >> 8), byte(right)}
+	case 28:
+		return []byte{byte(left >> 16), byte(left >> 8), byte(left),
+			byte((left>>24)<<4 | (right>>24)&0x0F),
+			byte(right >> 16), byte(right >> 8), byte(right)}
+	default:
+		b := make([]byte, 8)
+		binary.BigEndian.PutUint32(b[:4], uint32(left))
+		binary.BigEndian.PutUint32(b[4:], uint32(right))
+		return b
+	}
+}
+
+func TestMMDB_Lookup_GeoLite2Schemas(t *testing.T) {
+	prefix := netip.MustParsePrefix("203.0.113.0/24")
+	data := map[string]interface{}{
+		"autonomous_system_number":       uint32(64500),
+		"autonomous_system_organization": "Example Hosting",
+		"country": map[string]interface{}{
+			"iso_code": "br",
+		},
+	}
+
+	for _, tc := range []struct {
+		ipVersion  int
+		recordSize int
+	}{
+		{4, 24}, {4, 28}, {4, 32}, {6, 24}, {6, 28},
+	} {
+		db, err := NewMMDB(buildMMDB(t, tc.ipVersion, tc.recordSize, prefix, data))
+		if err != nil {
+			t.Fatalf("v%d/%d: unexpected error: %v", tc.ipVersion, tc.recordSize, err)
+		}
+
+		record, found := db.Lookup(netip.MustParseAddr("203.0.113.55"))
+		if !found {
+			t.Fatalf("v%d/%d: expected IP to be found", tc.ipVersion, tc.recordSize)
+		}
+		if record.ASN != 64500 || record.ASName != "Example Hosting" || record.Country != "BR" {
+			t.Errorf("v%d/%d: unexpected record %+v", tc.ipVersion, tc.recordSize, record)
+		}
+
+		if _, found := db.Lookup(netip.MustParseAddr("198.51.100.1")); found {
+			t.Errorf("v%d/%d: IP outside prefix should not be found", tc.ipVersion, tc.recordSize)
+		}
+		if db.DatabaseType() != "Test-DB" {
+			t.Errorf("Unexpected database type %s", db.DatabaseType())
+		}
+	}
+}
+
+func TestMMDB_Lookup_IPInfoSchema(t *testing.T) {
+	data := map[string]interface{}{
+		"asn":     "AS13335",
+		"as_name": "Cloudflare, Inc.",
+		"country": "US",
+	}
+	db, err := NewMMDB(buildMMDB(t, 4, 24, netip.MustParsePrefix("1.1.1.0/24"), data))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	record, found := db.Lookup(netip.MustParseAddr("1.1.1.1"))
+	if !found || record.ASN != 13335 || record.ASName != "Cloudflare, Inc." || record.Country != "US" {
+		t.Errorf("Unexpected record: %+v (found=%v)", record, found)
+	}
+}
+
+func TestNewMMDB_Invalid(t *testing.T) {
+	if _, err := NewMMDB([]byte("not a database")); err == nil {
+		t.Error("Expected error for missing metadata")
+	}
+
+	var buffer bytes.Buffer
+	buffer.Write(metadataMarker)
+	buffer.Write(mmdbValue(t, map[string]interface{}{
+		"node_count":  uint32(10),
+		"record_size": uint16(20),
+		"ip_version":  uint16(4),
+	}))
+	if _, err := NewMMDB(buffer.Bytes()); err == nil {
+		t.Error("Expected error for unsupported record size")
+	}
+}
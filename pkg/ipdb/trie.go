@@ -0,0 +1,156 @@
+package ipdb
+
+import (
+	"fmt"
+	"net/netip"
+)
+
+// trieNode é um nó da trie binária de prefixos
+type trieNode struct {
+	children [2]*trieNode
+	record   *Record
+}
+
+// Trie implementa longest-prefix match para IPv4 e IPv6
+type Trie struct {
+	v4   *trieNode
+	v6   *trieNode
+	size int
+}
+
+// NewTrie cria uma trie vazia
+func NewTrie() *Trie {
+	return &Trie{
+		v4: &trieNode{},
+		v6: &trieNode{},
+	}
+}
+
+// Insert associa um registro a um prefixo
+func (t *Trie) Insert(prefix netip.Prefix, record Record) {
+	prefix = prefix.Masked()
+	addr := prefix.Addr()
+	raw := addrBytes(addr)
+
+	node := t.root(addr)
+	for i := 0; i < prefix.Bits(); i++ {
+		bit := addrBit(&raw, i)
+		if node.children[bit] == nil {
+			node.children[bit] = &trieNode{}
+		}
+		node = node.children[bit]
+	}
+
+	if node.record == nil {
+		t.size++
+	}
+	r := record
+	node.record = &r
+}
+
+// InsertRange insere um intervalo arbitrário de IPs, decompondo-o em prefixos CIDR
+func (t *Trie) InsertRange(start, end netip.Addr, record Record) error {
+	prefixes, err := rangeToPrefixes(start, end)
+	if err != nil {
+		return err
+	}
+	for _, prefix := range prefixes {
+		t.Insert(prefix, record)
+	}
+	return nil
+}
+
+// Lookup retorna o registro do prefixo mais específico que contém o IP
+func (t *Trie) Lookup(addr netip.Addr) (Record, bool) {
+	addr = addr.Unmap()
+	raw := addrBytes(addr)
+	node := t.root(addr)
+
+	var match *Record
+	for i := 0; node != nil; i++ {
+		if node.record != nil {
+			match = node.record
+		}
+		if i >= addr.BitLen() {
+			break
+		}
+		node = node.children[addrBit(&raw, i)]
+	}
+
+	if match == nil {
+		return Record{}, false
+	}
+	return *match, true
+}
+
+// Len retorna o número de prefixos armazenados
+func (t *Trie) Len() int {
+	return t.size
+}
+
+// root retorna a raiz correspondente à família do endereço
+func (t *Trie) root(addr netip.Addr) *trieNode {
+	if addr.Is4() {
+		return t.v4
+	}
+	return t.v6
+}
+
+// addrBytes retorna os bytes do endereço uma vez por lookup; IPv4 ocupa os 4 primeiros
+func addrBytes(addr netip.Addr) [16]byte {
+	var b [16]byte
+	if addr.Is4() {
+		v4 := addr.As4()
+		copy(b[:], v4[:])
+		return b
+	}
+	return addr.As16()
+}
+
+// addrBit retorna o i-ésimo bit (mais significativo primeiro) dos bytes do endereço
+func addrBit(b *[16]byte, i int) int {
+	return int(b[i/8]>>(7-uint(i%8))) & 1
+}
+
+// rangeToPrefixes converte um intervalo [start, end] no menor conjunto de prefixos
+func rangeToPrefixes(start, end netip.Addr) ([]netip.Prefix, error) {
+	start, end = start.Unmap(), end.Unmap()
+	if !start.IsValid() || !end.IsValid() || start.Is4() != end.Is4() {
+		return nil, fmt.Errorf("invalid range %s - %s", start, end)
+	}
+	if end.Less(start) {
+		return nil, fmt.Errorf("range end %s is before start %s", end, start)
+	}
+
+	var prefixes []netip.Prefix
+	for {
+		// Maior bloco alinhado em start que não ultrapassa end
+		bits := start.BitLen()
+		for bits > 0 {
+			candidate := netip.PrefixFrom(start, bits-1)
+			if candidate.Masked().Addr() != start || end.Less(lastAddr(candidate)) {
+				break
+			}
+			bits--
+		}
+
+		prefix := netip.PrefixFrom(start, bits)
+		prefixes = append(prefixes, prefix)
+
+		last := lastAddr(prefix)
+		if last == end {
+			return prefixes, nil
+		}
+		start = last.Next()
+	}
+}
+
+// lastAddr retorna o último endereço de um prefixo
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	b := prefix.Masked().Addr().AsSlice()
+	for i := prefix.Bits(); i < len(b)*8; i++ {
+		b[i/8] |= 1 << (7 - uint(i%8))
+	}
+	addr, _ := netip.AddrFromSlice(b)
+	return addr
+}
@@ -0,0 +1,101 @@
+package ipdb
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestTrie_LongestPrefixMatch(t *testing.T) {
+	trie := NewTrie()
+	trie.Insert(netip.MustParsePrefix("203.0.0.0/8"), Record{ASN: 1, ASName: "Broad"})
+	trie.Insert(netip.MustParsePrefix("203.0.113.0/24"), Record{ASN: 64500, ASName: "Specific", Country: "BR"})
+	trie.Insert(netip.MustParsePrefix("2001:db8::/32"), Record{ASN: 64501, Country: "US"})
+
+	tests := []struct {
+		ip       string
+		expected int
+		found    bool
+	}{
+		{"203.0.113.10", 64500, true},
+		{"203.1.2.3", 1, true},
+		{"198.51.100.1", 0, false},
+		{"2001:db8::1", 64501, true},
+		{"::ffff:203.0.113.10", 64500, true}, // IPv4-mapped
+		{"2001:db9::1", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			record, found := trie.Lookup(netip.MustParseAddr(tt.ip))
+			if found != tt.found {
+				t.Fatalf("Lookup(%s) found = %v, want %v", tt.ip, found, tt.found)
+			}
+			if record.ASN != tt.expected {
+				t.Errorf("Lookup(%s) ASN = %d, want %d", tt.ip, record.ASN, tt.expected)
+			}
+		})
+	}
+
+	if trie.Len() != 3 {
+		t.Errorf("Expected 3 prefixes, got %d", trie.Len())
+	}
+}
+
+func TestRangeToPrefixes(t *testing.T) {
+	tests := []struct {
+		start    string
+		end      string
+		expected []string
+	}{
+		{"10.0.0.0", "10.0.0.255", []string{"10.0.0.0/24"}},
+		{"10.0.0.1", "10.0.0.6", []string{"10.0.0.1/32", "10.0.0.2/31", "10.0.0.4/31", "10.0.0.6/32"}},
+		{"0.0.0.0", "255.255.255.255", []string{"0.0.0.0/0"}},
+		{"2001:db8::", "2001:db8::ffff", []string{"2001:db8::/112"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.start+"-"+tt.end, func(t *testing.T) {
+			prefixes, err := rangeToPrefixes(netip.MustParseAddr(tt.start), netip.MustParseAddr(tt.end))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(prefixes) != len(tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, prefixes)
+			}
+			for i, prefix := range prefixes {
+				if prefix.String() != tt.expected[i] {
+					t.Errorf("Prefix %d = %s, want %s", i, prefix, tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRangeToPrefixes_Invalid(t *testing.T) {
+	if _, err := rangeToPrefixes(netip.MustParseAddr("10.0.0.5"), netip.MustParseAddr("10.0.0.1")); err == nil {
+		t.Error("Expected error for reversed range")
+	}
+	if _, err := rangeToPrefixes(netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("2001:db8::1")); err == nil {
+		t.Error("Expected error for mixed address families")
+	}
+}
+
+func TestMulti_Lookup(t *testing.T) {
+	asnOnly := NewTrie()
+	asnOnly.Insert(netip.MustParsePrefix("203.0.113.0/24"), Record{ASN: 64500, ASName: "Example Hosting"})
+
+	countryOnly := NewTrie()
+	countryOnly.Insert(netip.MustParsePrefix("203.0.0.0/16"), Record{Country: "BR"})
+
+	record, found := LookupString(Multi{asnOnly, countryOnly}, "203.0.113.7")
+	if !found {
+		t.Fatal("Expected combined lookup to succeed")
+	}
+	if record.ASN != 64500 || record.ASName != "Example Hosting" || record.Country != "BR" {
+		t.Errorf("Unexpected combined record: %+v", record)
+	}
+
+	if _, found := LookupString(Multi{asnOnly}, "not-an-ip"); found {
+		t.Error("Invalid IP should not be found")
+	}
+}
@@ -0,0 +1,121 @@
+package ipdb
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// LoadTSVFile carrega uma base no formato iptoasn (opcionalmente .gz)
+func LoadTSVFile(path string) (*Trie, error) {
+	file, err := os.Open(path) // #nosec G304 -- caminho vem da configuração do operador
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open gzip dataset: %w", err)
+		}
+		defer func() {
+			_ = gz.Close()
+		}()
+		reader = gz
+	}
+
+	return LoadTSV(reader)
+}
+
+// LoadTSV lê linhas "range_start<TAB>range_end<TAB>AS_number<TAB>country_code<TAB>AS_description"
+func LoadTSV(r io.Reader) (*Trie, error) {
+	trie := NewTrie()
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		columns := strings.Split(line, "\t")
+		if len(columns) < 3 {
+			return nil, fmt.Errorf("line %d: expected at least 3 columns, got %d", lineNumber, len(columns))
+		}
+
+		start, err := parseRangeAddr(columns[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		end, err := parseRangeAddr(columns[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		asn, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(columns[2]), "AS"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid AS number %q", lineNumber, columns[2])
+		}
+
+		// iptoasn usa AS 0 para intervalos não roteados
+		if asn == 0 {
+			continue
+		}
+
+		record := Record{ASN: asn}
+		if len(columns) > 3 {
+			record.Country = normalizeCountry(columns[3])
+		}
+		if len(columns) > 4 {
+			record.ASName = strings.TrimSpace(columns[4])
+		}
+
+		if err := trie.InsertRange(start, end, record); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return trie, nil
+}
+
+// parseRangeAddr aceita endereços textuais ou inteiros (variante ip2asn-v4-u32)
+func parseRangeAddr(value string) (netip.Addr, error) {
+	value = strings.TrimSpace(value)
+	if n, err := strconv.ParseUint(value, 10, 32); err == nil {
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], uint32(n))
+		return netip.AddrFrom4(b), nil
+	}
+
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("invalid address %q", value)
+	}
+	return addr.Unmap(), nil
+}
+
+// normalizeCountry padroniza o código do país, descartando marcadores vazios
+func normalizeCountry(value string) string {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if value == "NONE" || value == "-" || value == "ZZ" {
+		return ""
+	}
+	return value
+}
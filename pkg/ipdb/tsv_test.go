@@ -0,0 +1,102 @@
+package ipdb
+
+import (
+	"compress/gzip"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sampleTSV = `1.0.0.0	1.0.0.255	13335	US	CLOUDFLARENET
+1.0.1.0	1.0.3.255	0	None	Not routed
+203.0.113.0	203.0.113.127	64500	BR	EXAMPLE-HOSTING-BR
+2001:db8::	2001:db8:ffff:ffff:ffff:ffff:ffff:ffff	64501	DE	EXAMPLE-V6
+`
+
+func TestLoadTSV(t *testing.T) {
+	trie, err := LoadTSV(strings.NewReader(sampleTSV))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		ip      string
+		found   bool
+		asn     int
+		name    string
+		country string
+	}{
+		{"1.0.0.1", true, 13335, "CLOUDFLARENET", "US"},
+		{"1.0.2.1", false, 0, "", ""},
+		{"203.0.113.100", true, 64500, "EXAMPLE-HOSTING-BR", "BR"},
+		{"203.0.113.200", false, 0, "", ""},
+		{"2001:db8::10", true, 64501, "EXAMPLE-V6", "DE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			record, found := trie.Lookup(netip.MustParseAddr(tt.ip))
+			if found != tt.found {
+				t.Fatalf("found = %v, want %v", found, tt.found)
+			}
+			if record.ASN != tt.asn || record.ASName != tt.name || record.Country != tt.country {
+				t.Errorf("Unexpected record: %+v", record)
+			}
+		})
+	}
+}
+
+func TestLoadTSV_Errors(t *testing.T) {
+	tests := map[string]string{
+		"missing columns": "1.0.0.0\t1.0.0.255\n",
+		"invalid address": "1.0.0.x\t1.0.0.255\t13335\tUS\tX\n",
+		"invalid ASN":     "1.0.0.0\t1.0.0.255\tASX\tUS\tX\n",
+		"reversed range":  "1.0.0.255\t1.0.0.0\t13335\tUS\tX\n",
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := LoadTSV(strings.NewReader(input)); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestLoadTSVFile_Gzip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ip2asn-combined.tsv.gz")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(file)
+	if _, err := gz.Write([]byte(sampleTSV)); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	trie, err := LoadTSVFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if record, found := LookupString(trie, "1.0.0.1"); !found || record.ASN != 13335 {
+		t.Errorf("Unexpected lookup result: %+v %v", record, found)
+	}
+}
+
+func TestParseRangeAddr_Integer(t *testing.T) {
+	addr, err := parseRangeAddr("16777216")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if addr.String() != "1.0.0.0" {
+		t.Errorf("Expected 1.0.0.0, got %s", addr)
+	}
+}
@@ -1,23 +1,49 @@
 package models
 
+import (
+	"net"
+	"time"
+)
+
 // RegistrarInfo representa informações do registrar
 type RegistrarInfo struct {
 	Name   string `json:"name"`
 	IANAID int    `json:"iana_id"`
 }
 
+// ContactSource indica de onde um contato de abuse foi obtido
+type ContactSource string
+
+const (
+	ContactSourceRDAP      ContactSource = "rdap"
+	ContactSourceWHOIS     ContactSource = "whois"
+	ContactSourceDirectory ContactSource = "directory"
+	ContactSourceGuess     ContactSource = "guess" // derivado do nome do AS/provedor, não verificado
+	ContactSourceManual    ContactSource = "manual"
+)
+
 // ContactInfo representa informações de contato para abuse
 type ContactInfo struct {
-	Email   string `json:"email,omitempty"`
-	Phone   string `json:"phone,omitempty"`
-	Webform string `json:"webform,omitempty"`
+	Email      string        `json:"email,omitempty"`
+	Phone      string        `json:"phone,omitempty"`
+	Webform    string        `json:"webform,omitempty"`
+	Source     ContactSource `json:"source,omitempty"`
+	Confidence int           `json:"confidence,omitempty"` // 0-100, calculado na validação
+	Reason     string        `json:"reason,omitempty"`     // por que o contato foi escolhido
+	Validated  bool          `json:"validated,omitempty"`  // já pontuado pela validação de MX
+}
+
+// IsGuessed indica se o contato foi deduzido em vez de obtido de uma fonte
+func (ci ContactInfo) IsGuessed() bool {
+	return ci.Source == ContactSourceGuess
 }
 
 // HostingInfo representa informações do provedor de hosting
 type HostingInfo struct {
-	ASN   int         `json:"asn"`
-	Name  string      `json:"name"`
-	Abuse ContactInfo `json:"abuse"`
+	ASN     int         `json:"asn"`
+	Name    string      `json:"name"`
+	Country string      `json:"country,omitempty"` // ISO 3166-1 alpha-2 do IP
+	Abuse   ContactInfo `json:"abuse"`
 }
 
 // CDNInfo representa informações de CDN
@@ -27,79 +53,163 @@ type CDNInfo struct {
 	Webform string      `json:"webform,omitempty"`
 }
 
+// NetworkInfo representa a alocação de IP publicada pelo RIR (RDAP /ip)
+type NetworkInfo struct {
+	Handle  string      `json:"handle"`
+	Name    string      `json:"name,omitempty"` // nome da rede (ex: NET-203-0-113-0-1)
+	Org     string      `json:"org,omitempty"`  // titular da alocação
+	CIDR    string      `json:"cidr,omitempty"`
+	Country string      `json:"country,omitempty"`
+	Abuse   ContactInfo `json:"abuse"`
+}
+
+// DisplayName retorna o titular da rede ou, na falta dele, o nome/handle
+func (n *NetworkInfo) DisplayName() string {
+	if n.Org != "" {
+		return n.Org
+	}
+	if n.Name != "" {
+		return n.Name
+	}
+	return n.Handle
+}
+
+// CERTInfo representa o CERT nacional do país onde o IP está alocado
+type CERTInfo struct {
+	Name    string      `json:"name"`
+	Country string      `json:"country"`
+	Abuse   ContactInfo `json:"abuse"`
+}
+
+// MailboxInfo representa o provedor de email de uma conta usada na campanha (drop ou remetente)
+type MailboxInfo struct {
+	Name    string      `json:"name"`
+	Account string      `json:"account"`      // endereço a desativar
+	MX      []string    `json:"mx,omitempty"` // MX do domínio do endereço
+	Abuse   ContactInfo `json:"abuse"`
+}
+
+// PlatformInfo representa a plataforma onde o site do IOC está publicado (github.io, blogspot, Wix...);
+// o domínio registrável é da plataforma, então quem remove o site é o time de abuse dela
+type PlatformInfo struct {
+	Name   string      `json:"name"`
+	Suffix string      `json:"suffix"`        // sufixo da plataforma (ex: github.io)
+	Site   string      `json:"site"`          // site do cliente na plataforma (ex: acme-login.github.io)
+	Abuse  ContactInfo `json:"abuse"`         // vazio em plataformas fora do catálogo
+	API    string      `json:"api,omitempty"` // endpoint de denúncia, quando a plataforma oferece
+}
+
+// HasContact indica se a plataforma tem canal de denúncia conhecido (está no catálogo)
+func (p *PlatformInfo) HasContact() bool {
+	return p != nil && (p.Abuse.Email != "" || p.Abuse.Webform != "" || p.API != "")
+}
+
 // AbuseContact representa contatos normalizados por RDAP conforme spec 8.3
 type AbuseContact struct {
-	Domain    string         `json:"domain"`
+	Domain    string         `json:"domain"`         // domínio registrável (eTLD+1)
+	Host      string         `json:"host,omitempty"` // host do IOC (ex: login.bank.example.com.br)
 	Registrar *RegistrarInfo `json:"registrar,omitempty"`
 	Abuse     ContactInfo    `json:"abuse"`
 	Hosting   *HostingInfo   `json:"hosting,omitempty"`
 	CDN       *CDNInfo       `json:"cdn,omitempty"`
-	Privacy   bool           `json:"privacy"` // indica se usa privacy/proxy service
+	Network   *NetworkInfo   `json:"network,omitempty"`  // rede do IP no RIR (IOCs de IP)
+	Upstream  *NetworkInfo   `json:"upstream,omitempty"` // alocação pai, quando tem outro contato de abuse
+	CERT      *CERTInfo      `json:"cert,omitempty"`
+	Mailbox   *MailboxInfo   `json:"mailbox,omitempty"`    // provedor da conta (IOCs de email)
+	Platform  *PlatformInfo  `json:"platform,omitempty"`   // plataforma do site (o registrar é da plataforma)
+	Privacy   bool           `json:"privacy"`              // indica se usa privacy/proxy service
+	CreatedAt *time.Time     `json:"created_at,omitempty"` // data de registro do domínio
+	ExpiresAt *time.Time     `json:"expires_at,omitempty"` // data de expiração do domínio
 }
 
-// GetPrimaryAbuseEmail retorna o email principal para contato
-func (ac *AbuseContact) GetPrimaryAbuseEmail() string {
-	if ac.Abuse.Email != "" {
-		return ac.Abuse.Email
-	}
-	if ac.Registrar != nil && ac.Registrar.Name != "" {
-		// Fallback para emails padrão de registrars conhecidos
-		return getRegistrarAbuseEmail(ac.Registrar.Name)
+// TargetHost retorna o host do IOC (ou o domínio, se o host não for conhecido)
+func (ac *AbuseContact) TargetHost() string {
+	if ac.Host != "" {
+		return ac.Host
 	}
-	return ""
+	return ac.Domain
+}
+
+// IsIP indica se o IOC é um IP sem domínio (sem registrar para acionar)
+func (ac *AbuseContact) IsIP() bool {
+	return ac.Domain == "" && net.ParseIP(ac.Host) != nil
+}
+
+// IsEmail indica se o IOC é um endereço de email (a conta é o alvo, não o domínio)
+func (ac *AbuseContact) IsEmail() bool {
+	return ac.Mailbox != nil && ac.Mailbox.Account != ""
+}
+
+// OnPlatform indica se o site está numa plataforma catalogada, que substitui registrar, hosting e CDN
+func (ac *AbuseContact) OnPlatform() bool {
+	return ac.Platform.HasContact()
+}
+
+// GetPrimaryAbuseEmail retorna o email principal para contato. Registrars conhecidos sem email no
+// RDAP/WHOIS já chegam com o contato do diretório, preenchido pelo enrichment
+func (ac *AbuseContact) GetPrimaryAbuseEmail() string {
+	return ac.Abuse.Email
+}
+
+// GetPrimaryAbuseContact retorna o contato principal com sua origem
+func (ac *AbuseContact) GetPrimaryAbuseContact() ContactInfo {
+	return ac.Abuse
 }
 
 // GetTargets retorna lista de targets para takedown baseado no tipo
 func (ac *AbuseContact) GetTargets(category string) []TakedownTarget {
 	var targets []TakedownTarget
 
-	// Sempre incluir registrar para DNS abuse
-	if ac.Registrar != nil {
-		targets = append(targets, TakedownTarget{
-			Type:   "registrar",
-			Entity: ac.Registrar.Name,
-			Email:  ac.GetPrimaryAbuseEmail(),
-		})
+	// Registrar para DNS abuse, exceto em plataformas: o domínio registrável é da plataforma
+	if ac.Registrar != nil && ac.Platform == nil {
+		target := TakedownTarget{Type: "registrar", Entity: ac.Registrar.Name}
+		target.ApplyContact(ac.GetPrimaryAbuseContact())
+		targets = append(targets, target)
 	}
 
 	// Incluir hosting para conteúdo malicioso
-	if ac.Hosting != nil && (category == "phishing" || category == "malware" || category == "c2") {
-		targets = append(targets, TakedownTarget{
-			Type:   "hosting",
-			Entity: ac.Hosting.Name,
-			Email:  ac.Hosting.Abuse.Email,
-		})
+	if ac.Hosting != nil && !ac.OnPlatform() && (category == "phishing" || category == "malware" || category == "c2") {
+		target := TakedownTarget{Type: "hosting", Entity: ac.Hosting.Name}
+		target.ApplyContact(ac.Hosting.Abuse)
+		targets = append(targets, target)
 	}
 
 	// Incluir CDN se presente
-	if ac.CDN != nil {
-		targets = append(targets, TakedownTarget{
-			Type:    "cdn",
-			Entity:  ac.CDN.Name,
-			Email:   ac.CDN.Abuse.Email,
-			Webform: ac.CDN.Webform,
-		})
+	if ac.CDN != nil && !ac.OnPlatform() {
+		target := TakedownTarget{Type: "cdn", Entity: ac.CDN.Name}
+		target.ApplyContact(ac.CDN.Abuse)
+		if ac.CDN.Webform != "" {
+			target.Webform = ac.CDN.Webform
+		}
+		targets = append(targets, target)
 	}
 
-	return targets
-}
+	// IOCs de IP: a alocação pai e o CERT nacional entram como escalonamento
+	if ac.Upstream != nil {
+		target := TakedownTarget{Type: "upstream", Entity: ac.Upstream.DisplayName()}
+		target.ApplyContact(ac.Upstream.Abuse)
+		targets = append(targets, target)
+	}
+	if ac.CERT != nil {
+		target := TakedownTarget{Type: "cert", Entity: ac.CERT.Name}
+		target.ApplyContact(ac.CERT.Abuse)
+		targets = append(targets, target)
+	}
 
-func getRegistrarAbuseEmail(registrarName string) string {
-	// Mapeamento de registrars conhecidos para emails de abuse
-	knownRegistrars := map[string]string{
-		"GoDaddy.com, LLC":       "abuse@godaddy.com",
-		"NameCheap, Inc.":        "abuse@namecheap.com",
-		"Registro.br":            "abuse@registro.br",
-		"Amazon Registrar, Inc.": "legal@amazon.com",
-		"Google LLC":             "domain-abuse@google.com",
-		"Cloudflare, Inc.":       "abuse@cloudflare.com",
-		"Network Solutions, LLC": "abuse@networksolutions.com",
-		"eNom, LLC":              "abuse@enom.com",
+	// Sites em plataformas: a plataforma remove o site do cliente
+	if ac.OnPlatform() {
+		target := TakedownTarget{Type: "platform", Entity: ac.Platform.Name, Domain: ac.TargetHost()}
+		target.ApplyContact(ac.Platform.Abuse)
+		target.API = ac.Platform.API
+		targets = append(targets, target)
 	}
 
-	if email, exists := knownRegistrars[registrarName]; exists {
-		return email
+	// IOCs de email: o provedor desativa a conta
+	if ac.Mailbox != nil {
+		target := TakedownTarget{Type: "mailbox", Entity: ac.Mailbox.Name, Domain: ac.Mailbox.Account}
+		target.ApplyContact(ac.Mailbox.Abuse)
+		targets = append(targets, target)
 	}
 
-	return ""
+	return targets
 }
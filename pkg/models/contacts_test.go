@@ -20,9 +20,9 @@ func TestAbuseContact_GetPrimaryAbuseEmail(t *testing.T) {
 			expected: "abuse@example.com",
 		},
 		{
-			name: "No direct abuse email, use registrar fallback",
+			name: "Registrar contact filled from the directory",
 			contact: &AbuseContact{
-				Abuse: ContactInfo{},
+				Abuse: ContactInfo{Email: "abuse@godaddy.com", Source: ContactSourceDirectory},
 				Registrar: &RegistrarInfo{
 					Name: "GoDaddy.com, LLC",
 				},
@@ -30,7 +30,18 @@ func TestAbuseContact_GetPrimaryAbuseEmail(t *testing.T) {
 			expected: "abuse@godaddy.com",
 		},
 		{
-			name: "Unknown registrar fallback",
+			// Sem diretório consultado pelo enrichment, models não adivinha o email do registrar
+			name: "Known registrar without abuse email",
+			contact: &AbuseContact{
+				Abuse: ContactInfo{},
+				Registrar: &RegistrarInfo{
+					Name: "GoDaddy.com, LLC",
+				},
+			},
+			expected: "",
+		},
+		{
+			name: "Unknown registrar",
 			contact: &AbuseContact{
 				Abuse: ContactInfo{},
 				Registrar: &RegistrarInfo{
@@ -139,6 +150,37 @@ func TestAbuseContact_GetTargets(t *testing.T) {
 	}
 }
 
+func TestAbuseContact_GetTargets_Platform(t *testing.T) {
+	contact := &AbuseContact{
+		Domain:    "github.io",
+		Host:      "acme-login.github.io",
+		Registrar: &RegistrarInfo{Name: "MarkMonitor Inc."},
+		Abuse:     ContactInfo{Email: "abusecomplaints@markmonitor.com"},
+		Hosting:   &HostingInfo{Name: "Fastly", Abuse: ContactInfo{Email: "abuse@fastly.com"}},
+		Platform: &PlatformInfo{
+			Name:   "GitHub, Inc.",
+			Suffix: "github.io",
+			Site:   "acme-login.github.io",
+			Abuse:  ContactInfo{Webform: "https://support.github.com/contact/report-abuse"},
+		},
+	}
+
+	targets := contact.GetTargets("phishing")
+	if len(targets) != 1 || targets[0].Type != "platform" {
+		t.Fatalf("Expected only the platform target, got %+v", targets)
+	}
+	if targets[0].Domain != "acme-login.github.io" || targets[0].Webform == "" {
+		t.Errorf("Unexpected platform target: %+v", targets[0])
+	}
+
+	// Plataforma fora do catálogo: sem registrar, mas o hosting continua
+	contact.Platform = &PlatformInfo{Name: "glitch.me", Suffix: "glitch.me", Site: "acme-login.glitch.me"}
+	targets = contact.GetTargets("phishing")
+	if len(targets) != 1 || targets[0].Type != "hosting" {
+		t.Errorf("Expected only the hosting target, got %+v", targets)
+	}
+}
+
 func TestRegistrarInfo_Structure(t *testing.T) {
 	registrar := RegistrarInfo{
 		Name:   "GoDaddy.com, LLC",
@@ -211,31 +253,6 @@ func TestCDNInfo_Structure(t *testing.T) {
 	}
 }
 
-func TestGetRegistrarAbuseEmail(t *testing.T) {
-	tests := []struct {
-		registrarName string
-		expected      string
-	}{
-		{"GoDaddy.com, LLC", "abuse@godaddy.com"},
-		{"NameCheap, Inc.", "abuse@namecheap.com"},
-		{"Registro.br", "abuse@registro.br"},
-		{"Amazon Registrar, Inc.", "legal@amazon.com"},
-		{"Google LLC", "domain-abuse@google.com"},
-		{"Cloudflare, Inc.", "abuse@cloudflare.com"},
-		{"Unknown Registrar", ""},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.registrarName, func(t *testing.T) {
-			result := getRegistrarAbuseEmail(tt.registrarName)
-			if result != tt.expected {
-				t.Errorf("getRegistrarAbuseEmail(%s) = %v, want %v",
-					tt.registrarName, result, tt.expected)
-			}
-		})
-	}
-}
-
 func TestAbuseContact_CompleteStructure(t *testing.T) {
 	contact := &AbuseContact{
 		Domain: "suspicious.com",
@@ -293,3 +310,59 @@ func TestAbuseContact_CompleteStructure(t *testing.T) {
 		t.Errorf("Should have targets for phishing")
 	}
 }
+
+func TestAbuseContact_GetPrimaryAbuseContact(t *testing.T) {
+	rdapContact := &AbuseContact{
+		Registrar: &RegistrarInfo{Name: "GoDaddy.com, LLC"},
+		Abuse:     ContactInfo{Email: "abuse@registrar.test", Source: ContactSourceRDAP, Confidence: 90},
+	}
+	if contact := rdapContact.GetPrimaryAbuseContact(); contact.Source != ContactSourceRDAP || contact.Confidence != 90 {
+		t.Errorf("Expected RDAP contact to be kept, got %+v", contact)
+	}
+
+	fallback := &AbuseContact{
+		Registrar: &RegistrarInfo{Name: "GoDaddy.com, LLC"},
+		Abuse:     ContactInfo{Email: "abuse@godaddy.com", Source: ContactSourceDirectory, Reason: "contact directory: GoDaddy"},
+	}
+	contact := fallback.GetPrimaryAbuseContact()
+	if contact.Email != "abuse@godaddy.com" || contact.Source != ContactSourceDirectory {
+		t.Errorf("Expected directory contact, got %+v", contact)
+	}
+
+	targets := fallback.GetTargets("phishing")
+	if len(targets) != 1 || targets[0].Source != ContactSourceDirectory || targets[0].Reason == "" {
+		t.Errorf("Expected target to carry contact provenance, got %+v", targets)
+	}
+}
+
+func TestTakedownTarget_ApplyContact(t *testing.T) {
+	target := TakedownTarget{Type: "hosting", Entity: "Example Hosting"}
+	target.ApplyContact(ContactInfo{
+		Email:      "abuse@examplehosting.com",
+		Source:     ContactSourceGuess,
+		Confidence: 25,
+		Reason:     "guessed",
+	})
+
+	if target.Email != "abuse@examplehosting.com" || target.Source != ContactSourceGuess ||
+		target.Confidence != 25 || target.Reason != "guessed" || target.Entity != "Example Hosting" {
+		t.Errorf("Unexpected target: %+v", target)
+	}
+}
+
+func TestAbuseContact_IsIP(t *testing.T) {
+	tests := []struct {
+		contact AbuseContact
+		want    bool
+	}{
+		{AbuseContact{Host: "203.0.113.10"}, true},
+		{AbuseContact{Host: "2001:db8::1"}, true},
+		{AbuseContact{Domain: "example.com", Host: "login.example.com"}, false},
+		{AbuseContact{}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.contact.IsIP(); got != tt.want {
+			t.Errorf("IsIP(%+v) = %v, want %v", tt.contact, got, tt.want)
+		}
+	}
+}
@@ -1,6 +1,12 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cti-team/takedown/pkg/defang"
+)
 
 // DNSRecord representa registros DNS coletados
 type DNSRecord struct {
@@ -42,47 +48,230 @@ type RiskAssessment struct {
 	Category  string `json:"category"`  // phishing, malware, c2, etc
 }
 
-// EvidencePack representa o pacote de evidências conforme spec 8.2
-type EvidencePack struct {
-	EvidenceID  string         `json:"evidence_id"`
-	IOC         string         `json:"ioc"` // IOC ID relacionado
-	CollectedAt time.Time      `json:"collected_at"`
-	Screenshots []string       `json:"screenshots"`   // paths to files
-	HAR         string         `json:"har,omitempty"` // path to HAR file
-	DNS         DNSRecord      `json:"dns"`
-	HTTP        HTTPInfo       `json:"http"`
-	TLS         *TLSInfo       `json:"tls,omitempty"`
-	IntelRefs   []string       `json:"intel_refs,omitempty"` // external references
-	Risk        RiskAssessment `json:"risk"`
-	Defanged    string         `json:"defanged"` // defanged version of IOC
-}
-
-// GetDefangedURL retorna uma versão defanged da URL para comunicação
-func (e *EvidencePack) GetDefangedURL(original string) string {
-	if e.Defanged != "" {
-		return e.Defanged
+// ContentAnalysis reúne os achados da análise do HTML completo da página
+type ContentAnalysis struct {
+	PageURL         string        `json:"page_url,omitempty"`
+	HasLoginForm    bool          `json:"has_login_form"`
+	HasPasswordForm bool          `json:"has_password_form"`
+	Forms           []FormFinding `json:"forms,omitempty"`
+	ExternalPosts   []string      `json:"external_posts,omitempty"` // destinos de envio em outros domínios (forms e scripts)
+	TelegramBots    []string      `json:"telegram_bots,omitempty"`  // bots usados para exfiltração (token mascarado)
+	DropMailboxes   []string      `json:"drop_mailboxes,omitempty"` // contas que recebem os dados (mailto, relays, destinatário oculto)
+	Brands          []string      `json:"brands,omitempty"`
+	Logos           []string      `json:"logos,omitempty"`
+	Favicons        []string      `json:"favicons,omitempty"`
+	KitMarkers      []KitMarker   `json:"kit_markers,omitempty"`
+}
+
+// FormFinding descreve um formulário encontrado na página
+type FormFinding struct {
+	Action         string   `json:"action"`
+	Method         string   `json:"method"`
+	Fields         []string `json:"fields,omitempty"` // name (ou type) dos campos
+	PasswordFields int      `json:"password_fields"`
+	Login          bool     `json:"login"`
+	CrossDomain    bool     `json:"cross_domain"` // envia para outro domínio
+	Telegram       bool     `json:"telegram"`     // envia para a API de bots do Telegram
+}
+
+// KitMarker representa um marcador conhecido de phishing kit
+type KitMarker struct {
+	Name     string `json:"name"`
+	Evidence string `json:"evidence"` // trecho que casou com o marcador
+}
+
+// Summary resume os achados em uma linha para os templates de notificação
+func (c *ContentAnalysis) Summary() string {
+	if c == nil {
+		return "no page content findings"
+	}
+
+	var findings []string
+	for _, form := range c.Forms {
+		if !form.Login && form.PasswordFields == 0 {
+			continue
+		}
+		finding := "login form"
+		if form.PasswordFields > 0 {
+			finding = fmt.Sprintf("credential form with %d password field(s)", form.PasswordFields)
+		}
+		if form.Action != "" {
+			finding += " posting to " + form.Action
+		}
+		switch {
+		case form.Telegram:
+			finding += " (Telegram bot)"
+		case form.CrossDomain:
+			finding += " (other domain)"
+		}
+		findings = append(findings, finding)
+	}
+	if c.HasPasswordForm && len(findings) == 0 {
+		findings = append(findings, "password input outside of a form")
+	}
+	if len(c.TelegramBots) > 0 {
+		findings = append(findings, "exfiltration to Telegram bot "+strings.Join(c.TelegramBots, ", "))
+	}
+	if len(c.DropMailboxes) > 0 {
+		findings = append(findings, "exfiltration to mailbox "+strings.Join(c.DropMailboxes, ", "))
+	}
+	if len(c.Brands) > 0 {
+		findings = append(findings, "impersonated brand: "+strings.Join(c.Brands, ", "))
+	}
+	if len(c.Logos) > 0 {
+		findings = append(findings, fmt.Sprintf("%d logo reference(s)", len(c.Logos)))
 	}
+	if len(c.KitMarkers) > 0 {
+		names := make([]string, 0, len(c.KitMarkers))
+		for _, marker := range c.KitMarkers {
+			names = append(names, marker.Name)
+		}
+		findings = append(findings, "phishing kit markers: "+strings.Join(names, ", "))
+	}
+
+	if len(findings) == 0 {
+		return "no page content findings"
+	}
+	return strings.Join(findings, "; ")
+}
+
+// FetchVariant é o resultado da coleta sob um perfil de navegação
+type FetchVariant struct {
+	Profile    string           `json:"profile"`
+	PageRef    string           `json:"page_ref,omitempty"` // página correspondente no HAR
+	HTTP       HTTPInfo         `json:"http"`
+	TLS        *TLSInfo         `json:"tls,omitempty"`
+	Content    *ContentAnalysis `json:"content,omitempty"`
+	BodySHA256 string           `json:"body_sha256,omitempty"`
+	BodySize   int              `json:"body_size"`
+	Error      string           `json:"error,omitempty"`
+}
 
-	// Implementação básica de defang
-	defanged := original
-	defanged = replaceInString(defanged, "http://", "hxxp://")
-	defanged = replaceInString(defanged, "https://", "hxxps://")
-	defanged = replaceInString(defanged, ".", "[.]")
+// ServiceInfo é o resultado da sondagem de uma porta aberta em IOCs de IP
+type ServiceInfo struct {
+	Port     int      `json:"port"`
+	Protocol string   `json:"protocol,omitempty"` // ssh, ftp, smtp, http, https, tls
+	Banner   string   `json:"banner,omitempty"`   // primeira linha do serviço (ou status HTTP e Server)
+	TLS      *TLSInfo `json:"tls,omitempty"`
+}
+
+// ServicesSummary descreve as portas abertas em uma linha para os templates de notificação
+func (e *EvidencePack) ServicesSummary() string {
+	if len(e.Services) == 0 {
+		return "no open services found"
+	}
+	services := make([]string, 0, len(e.Services))
+	for _, service := range e.Services {
+		description := fmt.Sprintf("%d/%s", service.Port, service.Protocol)
+		if service.Protocol == "" {
+			description = fmt.Sprintf("%d/tcp", service.Port)
+		}
+		if service.Banner != "" {
+			description += " " + service.Banner
+		}
+		if service.TLS != nil && service.TLS.CN != "" {
+			description += " (TLS CN=" + service.TLS.CN + ")"
+		}
+		services = append(services, description)
+	}
+	return strings.Join(services, "; ")
+}
 
-	return defanged
+// FileInfo descreve o arquivo servido pela URL: o que o provedor precisa para localizar a amostra
+type FileInfo struct {
+	URL         string            `json:"url"`                    // URL final, após redirects
+	Size        int               `json:"size"`                   // bytes lidos
+	Truncated   bool              `json:"truncated,omitempty"`    // arquivo maior que o limite: hashes parciais
+	ContentType string            `json:"content_type,omitempty"` // detectado pelo conteúdo, não pelo header
+	FileType    string            `json:"file_type,omitempty"`    // PE, ELF, ZIP... pelos magic bytes
+	FirstBytes  string            `json:"first_bytes,omitempty"`  // hex dos primeiros 16 bytes
+	Hashes      map[string]string `json:"hashes"`                 // md5, sha1, sha256, sha512
+	Expected    []string          `json:"expected,omitempty"`     // hashes informados no IOC
+	Matched     bool              `json:"matched"`                // algum hash esperado confere
 }
 
-func replaceInString(s, old, new string) string {
-	// Simple string replacement
-	result := ""
-	for i := 0; i < len(s); {
-		if i+len(old) <= len(s) && s[i:i+len(old)] == old {
-			result += new
-			i += len(old)
-		} else {
-			result += string(s[i])
-			i++
+// Summary descreve o arquivo em uma linha para os templates de notificação
+func (f *FileInfo) Summary() string {
+	if f == nil {
+		return "no file captured"
+	}
+	if f.Size == 0 {
+		if len(f.Expected) == 0 {
+			return "no file captured"
 		}
+		return "reported sample " + strings.Join(f.Expected, ", ") + " (file not retrieved)"
+	}
+	kind := f.FileType
+	if kind == "" {
+		kind = f.ContentType
+	}
+	summary := fmt.Sprintf("sha256 %s (%s, %d bytes", f.Hashes["sha256"], kind, f.Size)
+	if f.Truncated {
+		summary += ", truncated"
+	}
+	summary += ")"
+	switch {
+	case f.Matched:
+		summary += ", matches the reported sample"
+	case len(f.Expected) > 0:
+		summary += "; reported sample " + strings.Join(f.Expected, ", ") + " not matched"
+	}
+	return summary
+}
+
+// CloakingResult registra a comparação entre as variantes coletadas
+type CloakingResult struct {
+	Detected      bool     `json:"detected"`
+	Baseline      string   `json:"baseline"`                 // perfil usado como referência
+	VictimProfile string   `json:"victim_profile,omitempty"` // perfil que viu o conteúdo malicioso
+	Reasons       []string `json:"reasons,omitempty"`
+}
+
+// Summary descreve o cloaking em uma linha para os templates de notificação
+func (c *CloakingResult) Summary() string {
+	if c == nil || !c.Detected {
+		return "not detected"
+	}
+	return fmt.Sprintf("content differs by client profile (victims see the %q view): %s",
+		c.VictimProfile, strings.Join(c.Reasons, "; "))
+}
+
+// EvidencePack representa o pacote de evidências conforme spec 8.2
+type EvidencePack struct {
+	EvidenceID   string            `json:"evidence_id"`
+	IOC          string            `json:"ioc"` // IOC ID relacionado
+	CollectedAt  time.Time         `json:"collected_at"`
+	Screenshots  []string          `json:"screenshots"`        // paths to files
+	HAR          string            `json:"har,omitempty"`      // path to HAR file
+	HARPage      string            `json:"har_page,omitempty"` // página do HAR usada em HTTP/TLS/Content
+	Hashes       map[string]string `json:"hashes,omitempty"`   // SHA-256 por arquivo (path -> hex)
+	DNS          DNSRecord         `json:"dns"`
+	HTTP         HTTPInfo          `json:"http"`
+	TLS          *TLSInfo          `json:"tls,omitempty"`
+	Content      *ContentAnalysis  `json:"content,omitempty"`  // análise do HTML completo
+	Variants     []FetchVariant    `json:"variants,omitempty"` // coleta por perfil (desktop, mobile, ...)
+	Cloaking     *CloakingResult   `json:"cloaking,omitempty"`
+	Services     []ServiceInfo     `json:"services,omitempty"`      // portas abertas (IOCs de IP)
+	File         *FileInfo         `json:"file,omitempty"`          // arquivo servido (amostras de malware)
+	PhishingURLs []string          `json:"phishing_urls,omitempty"` // IOCs de email: páginas ligadas à conta (defanged)
+	IntelRefs    []string          `json:"intel_refs,omitempty"`    // external references
+	Risk         RiskAssessment    `json:"risk"`
+	Defanged     string            `json:"defanged"` // defanged version of IOC
+	Body         []byte            `json:"-"`        // corpo da página da vítima lido na coleta (só em memória, para o scoring)
+}
+
+// AddHash registra o SHA-256 de um arquivo de evidência
+func (e *EvidencePack) AddHash(path, sha256 string) {
+	if e.Hashes == nil {
+		e.Hashes = make(map[string]string)
+	}
+	e.Hashes[path] = sha256
+}
+
+// GetDefangedURL retorna uma versão defanged do IOC (URL, domínio, IP ou email) para comunicação
+func (e *EvidencePack) GetDefangedURL(original string) string {
+	if e.Defanged != "" {
+		return e.Defanged
 	}
-	return result
+	return defang.Defang(original)
 }
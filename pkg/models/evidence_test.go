@@ -9,6 +9,9 @@ func TestEvidencePack_GetDefangedURL(t *testing.T) {
 	if got := e.GetDefangedURL(original); got != expected {
 		t.Fatalf("expected %s, got %s", expected, got)
 	}
+	if got := e.GetDefangedURL("abuse@example.com"); got != "abuse[@]example[.]com" {
+		t.Fatalf("expected defanged email, got %s", got)
+	}
 	// When Defanged preset
 	e.Defanged = "custom"
 	if got := e.GetDefangedURL("http://example.com"); got != "custom" {
@@ -16,9 +19,19 @@ func TestEvidencePack_GetDefangedURL(t *testing.T) {
 	}
 }
 
-func TestReplaceInString(t *testing.T) {
-	s := replaceInString("foo bar foo", "foo", "baz")
-	if s != "baz bar baz" {
-		t.Fatalf("unexpected result: %s", s)
+func TestEvidencePack_ServicesSummary(t *testing.T) {
+	e := &EvidencePack{}
+	if got := e.ServicesSummary(); got != "no open services found" {
+		t.Fatalf("unexpected summary without services: %s", got)
+	}
+
+	e.Services = []ServiceInfo{
+		{Port: 22, Protocol: "ssh", Banner: "SSH-2.0-OpenSSH_8.9"},
+		{Port: 443, Protocol: "https", Banner: "HTTP/1.1 404 Not Found", TLS: &TLSInfo{CN: "panel"}},
+		{Port: 4444},
+	}
+	expected := "22/ssh SSH-2.0-OpenSSH_8.9; 443/https HTTP/1.1 404 Not Found (TLS CN=panel); 4444/tcp"
+	if got := e.ServicesSummary(); got != expected {
+		t.Fatalf("expected %q, got %q", expected, got)
 	}
 }
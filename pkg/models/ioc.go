@@ -10,6 +10,8 @@ const (
 	IOCTypeDomain IOCType = "domain"
 	IOCTypeIP     IOCType = "ip"
 	IOCTypeHash   IOCType = "hash"
+	IOCTypeCIDR   IOCType = "cidr"
+	IOCTypeEmail  IOCType = "email"
 )
 
 // IOC representa um indicador de comprometimento conforme spec 8.1
@@ -20,6 +22,24 @@ type IOC struct {
 	FirstSeen   time.Time `json:"first_seen"`
 	Source      string    `json:"source"`
 	Tags        []string  `json:"tags"`
+	IntelRefs   []string  `json:"intel_refs,omitempty"` // IDs na fonte de inteligência (STIX, MISP) para rastreabilidade
+	Confidence  int       `json:"confidence,omitempty"` // confiança da fonte (0-100); 0 = não informada
+
+	DistributionURLs []string `json:"distribution_urls,omitempty"` // IOCs de hash: URLs onde a amostra foi vista
+	Hashes           []string `json:"hashes,omitempty"`            // IOCs de URL: hashes das amostras servidas
+	Emails           []string `json:"emails,omitempty"`            // IOCs de URL: contas drop e remetentes da campanha
+	PhishingURLs     []string `json:"phishing_urls,omitempty"`     // IOCs de email: páginas ligadas à conta
+
+	BrandSimilarity *BrandSimilarity `json:"brand_similarity,omitempty"`
+}
+
+// BrandSimilarity registra a semelhança do indicador com um domínio protegido da marca
+type BrandSimilarity struct {
+	Brand    string   `json:"brand"`
+	Domain   string   `json:"domain"` // domínio protegido mais parecido
+	Score    float64  `json:"score"`  // 0-1
+	Skeleton string   `json:"skeleton,omitempty"`
+	Reasons  []string `json:"reasons"`
 }
 
 // GetBrand extrai a tag de marca do IOC (ex: "brand:AcmeBank" -> "AcmeBank")
@@ -12,6 +12,7 @@ const (
 	StatusRoute        TakedownStatus = "route"
 	StatusSubmit       TakedownStatus = "submit"
 	StatusSubmitted    TakedownStatus = "submitted"
+	StatusManual       TakedownStatus = "needs_manual" // contato recusado pela política, aguarda o operador
 	StatusAcked        TakedownStatus = "acked"
 	StatusFollowUp     TakedownStatus = "follow_up"
 	StatusOutcome      TakedownStatus = "outcome"
@@ -22,20 +23,38 @@ const (
 type TakedownAction string
 
 const (
-	ActionSuspendDomain TakedownAction = "suspend_domain"
-	ActionRemoveContent TakedownAction = "remove_content"
-	ActionBlockNS       TakedownAction = "block_ns"
-	ActionWarningList   TakedownAction = "warning_list"
-	ActionBlocklist     TakedownAction = "blocklist"
+	ActionSuspendDomain  TakedownAction = "suspend_domain"
+	ActionRemoveContent  TakedownAction = "remove_content"
+	ActionBlockNS        TakedownAction = "block_ns"
+	ActionWarningList    TakedownAction = "warning_list"
+	ActionBlocklist      TakedownAction = "blocklist"
+	ActionNullRoute      TakedownAction = "null_route"      // upstream deixa de anunciar/rotear o IP
+	ActionCoordinate     TakedownAction = "coordinate"      // CERT nacional coordena com a rede
+	ActionDisableAccount TakedownAction = "disable_account" // provedor de email desativa a conta
 )
 
 // TakedownTarget representa um alvo para o takedown
 type TakedownTarget struct {
-	Type    string `json:"type"`   // registrar, hosting, cdn, search, blocklist
-	Entity  string `json:"entity"` // nome da entidade
-	Email   string `json:"email,omitempty"`
-	Phone   string `json:"phone,omitempty"`
-	Webform string `json:"webform,omitempty"`
+	Type       string        `json:"type"`             // registrar, hosting, cdn, upstream, cert, mailbox, platform, search, blocklist
+	Entity     string        `json:"entity"`           // nome da entidade
+	Domain     string        `json:"domain,omitempty"` // domínio ou host alvo da ação (a conta, em mailbox)
+	Email      string        `json:"email,omitempty"`
+	Phone      string        `json:"phone,omitempty"`
+	Webform    string        `json:"webform,omitempty"`
+	API        string        `json:"api,omitempty"`        // endpoint de denúncia (plataformas)
+	Source     ContactSource `json:"source,omitempty"`     // origem do contato
+	Confidence int           `json:"confidence,omitempty"` // 0-100
+	Reason     string        `json:"reason,omitempty"`     // por que o contato foi escolhido
+}
+
+// ApplyContact copia o contato de abuse (e sua origem/confiança) para o target
+func (tt *TakedownTarget) ApplyContact(contact ContactInfo) {
+	tt.Email = contact.Email
+	tt.Phone = contact.Phone
+	tt.Webform = contact.Webform
+	tt.Source = contact.Source
+	tt.Confidence = contact.Confidence
+	tt.Reason = contact.Reason
 }
 
 // SLA representa configurações de SLA
@@ -70,6 +89,7 @@ type TakedownRequest struct {
 	Priority        string          `json:"priority"` // low, medium, high, critical
 	Assignee        string          `json:"assignee,omitempty"`
 	Tags            []string        `json:"tags,omitempty"`
+	IntelRefs       []string        `json:"intel_refs,omitempty"` // IDs do IOC de origem na fonte de inteligência
 }
 
 // AddEvent adiciona um evento ao histórico
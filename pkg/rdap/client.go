@@ -8,13 +8,17 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cti-team/takedown/pkg/defang"
+	"github.com/cti-team/takedown/pkg/indicator"
 	"github.com/cti-team/takedown/pkg/models"
 )
 
 // Client representa um cliente RDAP
 type Client struct {
 	httpClient *http.Client
+	ipClient   *http.Client // consultas de rede no RIR, que seguem links e redirects de servidores remotos
 	userAgent  string
+	ipBaseURL  string
 }
 
 // NewClient cria um novo cliente RDAP
@@ -23,7 +27,13 @@ func NewClient() *Client {
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		ipClient: &http.Client{
+			Timeout:       10 * time.Second,
+			Transport:     newNetworkTransport(),
+			CheckRedirect: httpsRedirect,
+		},
 		userAgent: "CTI-Takedown/1.0",
+		ipBaseURL: defaultIPBaseURL,
 	}
 }
 
@@ -53,7 +63,12 @@ type Event struct {
 
 // LookupDomain realiza lookup RDAP para um domínio
 func (c *Client) LookupDomain(domain string) (*models.AbuseContact, error) {
-	domain = strings.ToLower(strings.TrimSpace(domain))
+	// O registro responde pelo domínio registrável (example.com.br), não por subdomínios
+	if registrable := indicator.RegistrableDomain(domain); registrable != "" {
+		domain = registrable
+	} else {
+		domain = defang.Host(domain)
+	}
 
 	// Determinar servidor RDAP baseado no TLD
 	rdapURL, err := c.getRDAPURL(domain)
@@ -88,6 +103,11 @@ func (c *Client) LookupDomain(domain string) (*models.AbuseContact, error) {
 
 // makeRequest faz uma requisição HTTP
 func (c *Client) makeRequest(url string) ([]byte, error) {
+	return c.get(c.httpClient, url)
+}
+
+// get executa o GET RDAP com o cliente HTTP dado
+func (c *Client) get(client *http.Client, url string) ([]byte, error) {
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, err
@@ -96,7 +116,7 @@ func (c *Client) makeRequest(url string) ([]byte, error) {
 	req.Header.Set("User-Agent", c.userAgent)
 	req.Header.Set("Accept", "application/rdap+json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -123,6 +143,7 @@ func (c *Client) processEntity(entity Entity, contact *models.AbuseContact) {
 		abuseEmail := c.extractAbuseEmail(entity)
 		if abuseEmail != "" {
 			contact.Abuse.Email = abuseEmail
+			contact.Abuse.Source = models.ContactSourceRDAP
 		}
 	}
 
@@ -253,12 +274,12 @@ func (c *Client) isAbuseEmail(email string) bool {
 
 // getRDAPURL determina a URL do servidor RDAP baseado no TLD
 func (c *Client) getRDAPURL(domain string) (string, error) {
-	parts := strings.Split(domain, ".")
-	if len(parts) < 2 {
+	normalized, err := indicator.Normalize(domain)
+	if err != nil || normalized.IP || normalized.RegistrableDomain == "" {
 		return "", fmt.Errorf("invalid domain format")
 	}
 
-	tld := parts[len(parts)-1]
+	tld := normalized.TLD
 
 	// Mapeamento de TLDs conhecidos para servidores RDAP
 	rdapServers := map[string]string{
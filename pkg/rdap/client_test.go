@@ -53,6 +53,11 @@ func TestClient_GetRDAPURL(t *testing.T) {
 			expectedURL: "https://rdap.registro.br",
 			shouldError: false,
 		},
+		{
+			domain:      "login.bank.example.com.br",
+			expectedURL: "https://rdap.registro.br",
+			shouldError: false,
+		},
 		{
 			domain:      "unknown.xyz",
 			expectedURL: "https://rdap-bootstrap.arin.net/bootstrap/domain/unknown.xyz",
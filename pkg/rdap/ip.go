@@ -0,0 +1,210 @@
+package rdap
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cti-team/takedown/pkg/defang"
+	"github.com/cti-team/takedown/pkg/indicator"
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// defaultIPBaseURL é o RDAP da ARIN, que redireciona consultas de /ip para o RIR responsável
+const defaultIPBaseURL = "https://rdap.arin.net/registry"
+
+// IPNetworkResponse representa a resposta RDAP de /ip (RFC 9083, seção 5.4)
+type IPNetworkResponse struct {
+	ObjectClassName string   `json:"objectClassName"`
+	Handle          string   `json:"handle"`
+	StartAddress    string   `json:"startAddress"`
+	EndAddress      string   `json:"endAddress"`
+	Name            string   `json:"name"`
+	Country         string   `json:"country"`
+	ParentHandle    string   `json:"parentHandle"`
+	Cidrs           []CIDR   `json:"cidr0_cidrs"`
+	Entities        []Entity `json:"entities"`
+	Links           []Link   `json:"links"`
+}
+
+// CIDR é um prefixo da extensão cidr0 (ARIN, RIPE, APNIC)
+type CIDR struct {
+	V4Prefix string `json:"v4prefix"`
+	V6Prefix string `json:"v6prefix"`
+	Length   int    `json:"length"`
+}
+
+// Link representa um link RDAP; rel "up" aponta para a alocação pai
+type Link struct {
+	Rel  string `json:"rel"`
+	Href string `json:"href"`
+}
+
+// newNetworkTransport só conecta em endereços públicos: os links "up" e os redirects entre RIRs vêm
+// de servidores remotos e não podem levar o cliente a serviços internos
+func newNetworkTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   indicator.DialControl,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// Com proxy a verificação veria só o endereço do proxy, que busca o destino sem ela
+	transport.Proxy = nil
+	return transport
+}
+
+// httpsRedirect recusa redirects entre RIRs para fora de HTTPS
+func httpsRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return fmt.Errorf("stopped after %d redirects", len(via))
+	}
+	if req.URL.Scheme != "https" {
+		return fmt.Errorf("refusing redirect to %s URL", req.URL.Scheme)
+	}
+	return nil
+}
+
+// SetIPTransport substitui o transport das consultas de IP (ex: servidor RDAP local em testes)
+func (c *Client) SetIPTransport(transport http.RoundTripper) {
+	c.ipClient.Transport = transport
+}
+
+// SetIPBaseURL configura o servidor RDAP consultado para IPs (padrão: ARIN, com redirect entre RIRs)
+func (c *Client) SetIPBaseURL(baseURL string) {
+	c.ipBaseURL = strings.TrimSuffix(baseURL, "/")
+}
+
+// LookupIP consulta a rede do IP no RIR e, pelo link "up", a alocação pai (upstream).
+// Falhas na consulta da alocação pai não invalidam o resultado: upstream volta nil.
+func (c *Client) LookupIP(ip string) (network, upstream *models.NetworkInfo, err error) {
+	ip = defang.Refang(strings.TrimSpace(ip))
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return nil, nil, fmt.Errorf("invalid IP address %q", ip)
+	}
+
+	response, err := c.lookupNetwork(c.ipBaseURL + "/ip/" + parsed.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	network = c.networkInfo(response)
+
+	if up := upLink(response); up != "" {
+		if parent, err := c.lookupNetwork(up); err == nil && parent.Handle != response.Handle {
+			upstream = c.networkInfo(parent)
+		}
+	}
+	return network, upstream, nil
+}
+
+// lookupNetwork busca e interpreta um objeto ip network
+func (c *Client) lookupNetwork(url string) (*IPNetworkResponse, error) {
+	body, err := c.get(c.ipClient, url)
+	if err != nil {
+		return nil, fmt.Errorf("RDAP request failed: %w", err)
+	}
+
+	var response IPNetworkResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse RDAP response: %w", err)
+	}
+	if response.Handle == "" && response.StartAddress == "" {
+		return nil, fmt.Errorf("RDAP response is not an ip network")
+	}
+	return &response, nil
+}
+
+// networkInfo extrai titular, prefixo e contato de abuse da rede
+func (c *Client) networkInfo(response *IPNetworkResponse) *models.NetworkInfo {
+	network := &models.NetworkInfo{
+		Handle:  response.Handle,
+		Name:    response.Name,
+		CIDR:    networkCIDR(response),
+		Country: strings.ToUpper(response.Country),
+	}
+	if registrant, ok := c.findRole(response.Entities, "registrant"); ok {
+		network.Org = c.extractEntityName(registrant)
+	}
+
+	// O RIR publica o contato no papel "abuse" (RIPE no topo, ARIN aninhado na organização)
+	email := ""
+	if abuse, ok := c.findRole(response.Entities, "abuse"); ok {
+		email = c.firstEmail(abuse)
+	}
+	if email == "" {
+		for _, entity := range response.Entities {
+			if email = c.extractAbuseEmail(entity); email != "" {
+				break
+			}
+		}
+	}
+	if email != "" {
+		network.Abuse = models.ContactInfo{
+			Email:  email,
+			Source: models.ContactSourceRDAP,
+			Reason: fmt.Sprintf("RIR RDAP abuse contact of network %s", response.Handle),
+		}
+	}
+	return network
+}
+
+// findRole busca (em profundidade) a primeira entidade com o papel informado
+func (c *Client) findRole(entities []Entity, role string) (Entity, bool) {
+	for _, entity := range entities {
+		if c.hasRole(entity.Roles, role) {
+			return entity, true
+		}
+	}
+	for _, entity := range entities {
+		if found, ok := c.findRole(entity.Entities, role); ok {
+			return found, true
+		}
+	}
+	return Entity{}, false
+}
+
+// firstEmail retorna o primeiro email do vCard, sem exigir "abuse" no endereço
+func (c *Client) firstEmail(entity Entity) string {
+	for _, item := range c.getVCardArray(entity) {
+		itemArray := c.validateVCardItem(item)
+		if itemArray != nil && c.isEmailProperty(itemArray) {
+			if email := c.getEmailValue(itemArray); email != "" {
+				return email
+			}
+		}
+	}
+	return ""
+}
+
+// networkCIDR retorna o prefixo da rede (cidr0) ou o intervalo início-fim
+func networkCIDR(response *IPNetworkResponse) string {
+	for _, cidr := range response.Cidrs {
+		prefix := cidr.V4Prefix
+		if prefix == "" {
+			prefix = cidr.V6Prefix
+		}
+		if prefix != "" {
+			return prefix + "/" + strconv.Itoa(cidr.Length)
+		}
+	}
+	if response.StartAddress != "" && response.EndAddress != "" {
+		return response.StartAddress + " - " + response.EndAddress
+	}
+	return ""
+}
+
+// upLink retorna o link HTTPS para a alocação pai; o href vem do servidor remoto
+func upLink(response *IPNetworkResponse) string {
+	for _, link := range response.Links {
+		if strings.EqualFold(link.Rel, "up") && strings.HasPrefix(strings.ToLower(link.Href), "https://") {
+			return link.Href
+		}
+	}
+	return ""
+}
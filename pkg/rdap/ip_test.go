@@ -0,0 +1,134 @@
+package rdap
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// vcard monta o JSON de um vCard com nome e email
+func vcard(name, email string) string {
+	data, _ := json.Marshal([]interface{}{
+		"vcard",
+		[]interface{}{
+			[]interface{}{"fn", map[string]interface{}{}, "text", name},
+			[]interface{}{"email", map[string]interface{}{}, "text", email},
+		},
+	})
+	return string(data)
+}
+
+func TestClient_LookupIP(t *testing.T) {
+	var serverURL string
+	responses := map[string]func() string{
+		// Reatribuição de cliente (ARIN): abuse aninhado na organização, sem "abuse" no email
+		"/ip/203.0.113.10": func() string {
+			return `{"objectClassName": "ip network", "handle": "NET-203-0-113-0-2", "name": "C2-CUSTOMER",
+				"startAddress": "203.0.113.0", "endAddress": "203.0.113.255", "country": "br",
+				"parentHandle": "NET-203-0-0-0-1",
+				"cidr0_cidrs": [{"v4prefix": "203.0.113.0", "length": 24}],
+				"links": [{"rel": "self", "href": "` + serverURL + `/ip/203.0.113.10"},
+				          {"rel": "up", "href": "` + serverURL + `/ip/203.0.0.0/16"}],
+				"entities": [{"objectClassName": "entity", "handle": "CUST-1", "roles": ["registrant"],
+					"vcardArray": ` + vcard("Bulletproof Customer Ltda", "noc@customer.example") + `,
+					"entities": [{"handle": "ABUSE-1", "roles": ["abuse"],
+						"vcardArray": ` + vcard("Abuse", "security@customer.example") + `}]}]}`
+		},
+		"/ip/203.0.0.0/16": func() string {
+			return `{"objectClassName": "ip network", "handle": "NET-203-0-0-0-1", "name": "TRANSIT-BLOCK",
+				"startAddress": "203.0.0.0", "endAddress": "203.0.255.255", "country": "BR",
+				"entities": [{"handle": "TRANSIT", "roles": ["registrant"], "vcardArray": ` + vcard("Transit Telecom S.A.", "noc@transit.example") + `},
+				             {"handle": "TRANSIT-ABUSE", "roles": ["abuse"], "vcardArray": ` + vcard("Abuse", "abuse@transit.example") + `}]}`
+		},
+	}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rdap+json")
+		_, _ = w.Write([]byte(response()))
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	// O servidor de teste é local: o transport padrão recusaria a conexão
+	client := NewClient()
+	client.SetIPTransport(server.Client().Transport)
+	client.SetIPBaseURL(server.URL + "/")
+
+	network, upstream, err := client.LookupIP("203.0.113[.]10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if network.Handle != "NET-203-0-113-0-2" || network.CIDR != "203.0.113.0/24" || network.Country != "BR" {
+		t.Errorf("Unexpected network: %+v", network)
+	}
+	if network.Org != "Bulletproof Customer Ltda" || network.DisplayName() != "Bulletproof Customer Ltda" {
+		t.Errorf("Expected registrant as org, got %q", network.Org)
+	}
+	if network.Abuse.Email != "security@customer.example" || network.Abuse.Source != "rdap" {
+		t.Errorf("Expected nested abuse role contact, got %+v", network.Abuse)
+	}
+	if upstream == nil || upstream.Org != "Transit Telecom S.A." || upstream.Abuse.Email != "abuse@transit.example" {
+		t.Fatalf("Expected parent allocation as upstream, got %+v", upstream)
+	}
+	if upstream.CIDR != "203.0.0.0 - 203.0.255.255" {
+		t.Errorf("Expected address range without cidr0, got %q", upstream.CIDR)
+	}
+
+	network, upstream, err = client.LookupIP("198.51.100.1")
+	if err == nil || network != nil || upstream != nil {
+		t.Errorf("Expected error for unknown network, got %+v %+v %v", network, upstream, err)
+	}
+
+	if _, _, err := client.LookupIP("not-an-ip"); err == nil {
+		t.Error("Expected error for invalid IP")
+	}
+}
+
+func TestClient_LookupIP_UnsafeLinks(t *testing.T) {
+	var plainURL string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ip/203.0.113.10":
+			_, _ = w.Write([]byte(`{"handle": "NET-1", "links": [{"rel": "up", "href": "` + plainURL + `/ip/203.0.0.0/16"}]}`))
+		case "/ip/203.0.113.20":
+			http.Redirect(w, r, plainURL+"/ip/203.0.113.20", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected plain HTTP request to %s", r.URL.Path)
+		_, _ = w.Write([]byte(`{"handle": "NET-PARENT"}`))
+	}))
+	defer plain.Close()
+	plainURL = plain.URL
+
+	client := NewClient()
+	client.SetIPTransport(server.Client().Transport)
+	client.SetIPBaseURL(server.URL)
+
+	// Link "up" sem HTTPS é ignorado: a rede vem sem upstream
+	network, upstream, err := client.LookupIP("203.0.113.10")
+	if err != nil || network.Handle != "NET-1" || upstream != nil {
+		t.Errorf("Expected network without upstream, got %+v %+v %v", network, upstream, err)
+	}
+
+	// Redirect para fora de HTTPS é recusado
+	if _, _, err := client.LookupIP("203.0.113.20"); err == nil || !strings.Contains(err.Error(), "refusing redirect") {
+		t.Errorf("Expected refused redirect, got %v", err)
+	}
+
+	// Com o transport padrão, endereços não públicos são recusados antes da conexão
+	client = NewClient()
+	client.SetIPBaseURL(server.URL)
+	if _, _, err := client.LookupIP("203.0.113.10"); err == nil || !strings.Contains(err.Error(), "refusing to connect") {
+		t.Errorf("Expected loopback RDAP server to be refused, got %v", err)
+	}
+}
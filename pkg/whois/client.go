@@ -0,0 +1,254 @@
+package whois
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cti-team/takedown/pkg/defang"
+	"github.com/cti-team/takedown/pkg/indicator"
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+const (
+	defaultPort      = "43"
+	ianaWhoisServer  = "whois.iana.org"
+	maxResponseBytes = 1 << 20 // respostas WHOIS raramente passam de alguns KB
+	defaultReferrals = 2
+	defaultTimeout   = 10 * time.Second
+)
+
+// Client representa um cliente WHOIS (porta 43) usado como fallback do RDAP
+type Client struct {
+	dial         func(ctx context.Context, network, address string) (net.Conn, error)
+	timeout      time.Duration
+	ianaServer   string
+	maxReferrals int
+	servers      map[string]string // cache TLD -> servidor WHOIS
+	mutex        sync.RWMutex
+}
+
+// NewClient cria um novo cliente WHOIS
+func NewClient() *Client {
+	dialer := &net.Dialer{
+		Timeout: defaultTimeout,
+		Control: indicator.DialControl,
+	}
+	return &Client{
+		dial:         dialer.DialContext,
+		timeout:      defaultTimeout,
+		ianaServer:   ianaWhoisServer,
+		maxReferrals: defaultReferrals,
+		servers:      make(map[string]string),
+	}
+}
+
+// LookupDomain realiza lookup WHOIS e retorna o contato no mesmo formato do cliente RDAP
+func (c *Client) LookupDomain(ctx context.Context, domain string) (*models.AbuseContact, error) {
+	record, err := c.Lookup(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+	return record.ToAbuseContact(), nil
+}
+
+// Lookup realiza lookup WHOIS seguindo referrals a partir do whois.iana.org
+func (c *Client) Lookup(ctx context.Context, domain string) (*Record, error) {
+	// O registro responde pelo domínio registrável (example.com.br), não por subdomínios
+	if registrable := indicator.RegistrableDomain(domain); registrable != "" {
+		domain = registrable
+	} else {
+		domain = defang.Host(domain)
+	}
+
+	tld, err := extractTLD(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	server, err := c.serverForTLD(ctx, tld)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine WHOIS server: %w", err)
+	}
+
+	raw, err := c.query(ctx, server, domain)
+	if err != nil {
+		return nil, fmt.Errorf("WHOIS query to %s failed: %w", server, err)
+	}
+
+	record := parseResponse(tld, raw)
+	record.Domain = domain
+	record.Server = server
+
+	// Registries "thin" apontam para o WHOIS do registrar, que tem o contato de abuse
+	visited := map[string]bool{normalizeServer(server): true}
+	for i := 0; i < c.maxReferrals && record.WhoisServer != ""; i++ {
+		referral, err := referralServer(record.WhoisServer)
+		if err != nil || visited[referral] {
+			break
+		}
+		visited[referral] = true
+
+		raw, err := c.query(ctx, referral, domain)
+		if err != nil {
+			// Mantemos os dados do registry mesmo sem resposta do registrar
+			break
+		}
+
+		referred := parseResponse(tld, raw)
+		referred.Server = referral
+		record.merge(referred)
+	}
+
+	return record, nil
+}
+
+// serverForTLD descobre o servidor WHOIS de um TLD via IANA, com cache
+func (c *Client) serverForTLD(ctx context.Context, tld string) (string, error) {
+	c.mutex.RLock()
+	server, exists := c.servers[tld]
+	c.mutex.RUnlock()
+	if exists {
+		return server, nil
+	}
+
+	raw, err := c.query(ctx, c.ianaServer, tld)
+	if err != nil {
+		return "", fmt.Errorf("IANA query failed: %w", err)
+	}
+
+	referral := parseIANAReferral(raw)
+	if referral == "" {
+		return "", fmt.Errorf("no WHOIS server published for TLD %q", tld)
+	}
+	server, err = referralServer(referral)
+	if err != nil {
+		return "", err
+	}
+
+	c.mutex.Lock()
+	c.servers[tld] = server
+	c.mutex.Unlock()
+
+	return server, nil
+}
+
+// query envia uma consulta WHOIS e lê a resposta completa
+func (c *Client) query(ctx context.Context, server, query string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	conn, err := c.dial(ctx, "tcp", normalizeServer(server))
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	// Cancelamento e timeout fecham a conexão, interrompendo a escrita ou a leitura em andamento
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.Close()
+	})
+	defer stop()
+
+	if _, err := io.WriteString(conn, formatQuery(server, query)+"\r\n"); err != nil {
+		return "", contextError(ctx, err)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(conn, maxResponseBytes))
+	if err != nil {
+		return "", contextError(ctx, err)
+	}
+
+	return string(data), nil
+}
+
+// contextError prefere o erro do contexto quando a conexão foi fechada por cancelamento ou timeout
+func contextError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
+// formatQuery aplica a sintaxe de consulta exigida por alguns servidores
+func formatQuery(server, query string) string {
+	host := strings.ToLower(server)
+	if h, _, err := net.SplitHostPort(server); err == nil {
+		host = strings.ToLower(h)
+	}
+
+	switch host {
+	case "whois.denic.de":
+		return "-T dn,ace " + query
+	case "whois.verisign-grs.com", "whois.crsnic.net":
+		return "domain " + query
+	default:
+		return query
+	}
+}
+
+// normalizeServer garante que o endereço do servidor inclua a porta
+func normalizeServer(server string) string {
+	server = strings.TrimSpace(server)
+	server = strings.TrimPrefix(server, "whois://")
+	server = strings.TrimSuffix(server, "/")
+
+	if _, _, err := net.SplitHostPort(server); err == nil {
+		return strings.ToLower(server)
+	}
+	return net.JoinHostPort(strings.ToLower(server), defaultPort)
+}
+
+// referralServer valida o servidor WHOIS indicado numa resposta remota (refer da IANA ou Registrar
+// WHOIS Server). Só aceitamos hostnames públicos na porta 43: um registry ou registrar malicioso não
+// pode apontar nossas conexões para um IP, uma porta arbitrária ou um nome da rede interna.
+func referralServer(server string) (string, error) {
+	host := strings.TrimSpace(server)
+	host = strings.TrimPrefix(host, "whois://")
+	host = strings.TrimSuffix(host, "/")
+
+	if h, port, err := net.SplitHostPort(host); err == nil {
+		if port != defaultPort {
+			return "", fmt.Errorf("WHOIS referral %q uses port %s, only %s is allowed", server, port, defaultPort)
+		}
+		host = h
+	}
+	if net.ParseIP(strings.Trim(host, "[]")) != nil {
+		return "", fmt.Errorf("WHOIS referral %q is an IP address, expected a hostname", server)
+	}
+
+	validated, err := indicator.Validate(host, models.IOCTypeDomain)
+	if err != nil {
+		return "", fmt.Errorf("WHOIS referral %q rejected: %w", server, err)
+	}
+	return net.JoinHostPort(validated.Value, defaultPort), nil
+}
+
+// extractTLD retorna o último label do domínio
+func extractTLD(domain string) (string, error) {
+	parts := strings.Split(domain, ".")
+	if len(parts) < 2 || parts[len(parts)-1] == "" {
+		return "", fmt.Errorf("invalid domain format")
+	}
+	return parts[len(parts)-1], nil
+}
+
+// parseIANAReferral extrai o servidor WHOIS da resposta da IANA
+func parseIANAReferral(raw string) string {
+	for _, line := range strings.Split(raw, "\n") {
+		key, value, ok := splitKeyValue(line)
+		if !ok {
+			continue
+		}
+		if (key == "refer" || key == "whois") && value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,358 @@
+package whois
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// standInServer é um servidor WHOIS local que responde conforme a consulta recebida
+type standInServer struct {
+	listener net.Listener
+	mutex    sync.Mutex
+	queries  []string
+}
+
+func startStandInServer(t *testing.T, respond func(query string) string) *standInServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start stand-in server: %v", err)
+	}
+
+	server := &standInServer{listener: listener}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer func() {
+					_ = conn.Close()
+				}()
+				line, err := bufio.NewReader(conn).ReadString('\n')
+				if err != nil {
+					return
+				}
+				query := strings.TrimSpace(line)
+				server.mutex.Lock()
+				server.queries = append(server.queries, query)
+				server.mutex.Unlock()
+				_, _ = conn.Write([]byte(respond(query)))
+			}(conn)
+		}
+	}()
+
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	return server
+}
+
+func (s *standInServer) addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *standInServer) received() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string(nil), s.queries...)
+}
+
+// newTestClient direciona os nomes dos servidores WHOIS (host:43) para os stand-ins locais
+func newTestClient(routes map[string]*standInServer) *Client {
+	client := NewClient()
+	client.dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+		server, ok := routes[address]
+		if !ok {
+			return nil, fmt.Errorf("unexpected dial to %s", address)
+		}
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, network, server.addr())
+	}
+	return client
+}
+
+func TestNewClient(t *testing.T) {
+	client := NewClient()
+
+	if client == nil {
+		t.Fatal("NewClient should not return nil")
+	}
+	if client.ianaServer != "whois.iana.org" {
+		t.Errorf("Expected IANA server whois.iana.org, got %s", client.ianaServer)
+	}
+	if client.maxReferrals <= 0 {
+		t.Error("Referral following should be enabled by default")
+	}
+}
+
+func TestClient_LookupDomain_FollowsReferrals(t *testing.T) {
+	registrar := startStandInServer(t, func(query string) string {
+		return `Domain Name: PHISHING-SITE.COM
+Registrar: Example Registrar, LLC
+Registrar IANA ID: 9999
+Registrar Abuse Contact Email: abuse@example-registrar.com
+Registrar Abuse Contact Phone: +1.5555551234
+Creation Date: 2024-03-01T10:00:00Z
+Registrant Organization: Privacy Protect, LLC
+`
+	})
+
+	registry := startStandInServer(t, func(query string) string {
+		return `   Domain Name: PHISHING-SITE.COM
+   Registrar WHOIS Server: whois.example-registrar.com
+   Registrar: Example Registrar, LLC
+   Creation Date: 2024-03-01T10:00:00Z
+   Registry Expiry Date: 2025-03-01T10:00:00Z
+`
+	})
+
+	iana := startStandInServer(t, func(query string) string {
+		if query != "com" {
+			return "% no match\n"
+		}
+		return "domain:       COM\n\nrefer:        whois.verisign-grs.com\n"
+	})
+
+	client := newTestClient(map[string]*standInServer{
+		"whois.iana.org:43":              iana,
+		"whois.verisign-grs.com:43":      registry,
+		"whois.example-registrar.com:43": registrar,
+	})
+	contact, err := client.LookupDomain(context.Background(), "Phishing-Site.com")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if contact.Domain != "phishing-site.com" {
+		t.Errorf("Expected normalized domain, got %s", contact.Domain)
+	}
+	if contact.Registrar == nil || contact.Registrar.Name != "Example Registrar, LLC" {
+		t.Fatalf("Expected registrar from referral, got %+v", contact.Registrar)
+	}
+	if contact.Registrar.IANAID != 9999 {
+		t.Errorf("Expected IANA ID 9999, got %d", contact.Registrar.IANAID)
+	}
+	if contact.Abuse.Email != "abuse@example-registrar.com" {
+		t.Errorf("Expected abuse email from registrar WHOIS, got %s", contact.Abuse.Email)
+	}
+	if contact.ExpiresAt == nil || contact.ExpiresAt.Year() != 2025 {
+		t.Errorf("Expected expiry date from registry, got %v", contact.ExpiresAt)
+	}
+	if !contact.Privacy {
+		t.Error("Expected privacy service to be detected")
+	}
+
+	if got := registry.received(); len(got) != 1 || got[0] != "domain phishing-site.com" {
+		t.Errorf("Unexpected registry queries: %v", got)
+	}
+}
+
+func TestClient_Lookup_QueriesRegistrableDomain(t *testing.T) {
+	registry := startStandInServer(t, func(query string) string {
+		return "Domain name:\n    example.co.uk\n"
+	})
+	iana := startStandInServer(t, func(query string) string {
+		return "refer:        whois.nic.uk\n"
+	})
+
+	client := newTestClient(map[string]*standInServer{"whois.iana.org:43": iana, "whois.nic.uk:43": registry})
+	if _, err := client.Lookup(context.Background(), "hxxps://login.secure.example[.]co[.]uk/path"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := registry.received(); len(got) != 1 || got[0] != "example.co.uk" {
+		t.Errorf("Expected registry query for the registrable domain, got %v", got)
+	}
+}
+
+func TestClient_Lookup_CachesTLDServer(t *testing.T) {
+	registry := startStandInServer(t, func(query string) string {
+		return "domain: " + query + "\nowner: Example\ncreated: 20200115 #123\nexpires: 20260115\n"
+	})
+	iana := startStandInServer(t, func(query string) string {
+		return "refer: whois.registro.br\n"
+	})
+
+	client := newTestClient(map[string]*standInServer{"whois.iana.org:43": iana, "whois.registro.br:43": registry})
+	for _, domain := range []string{"banco-falso.com.br", "outro.com.br"} {
+		record, err := client.Lookup(context.Background(), domain)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", domain, err)
+		}
+		if record.Registrar != "Registro.br" {
+			t.Errorf("Expected Registro.br registrar, got %s", record.Registrar)
+		}
+	}
+
+	if got := iana.received(); len(got) != 1 {
+		t.Errorf("Expected a single IANA query thanks to cache, got %v", got)
+	}
+}
+
+func TestClient_Lookup_ReferralLoop(t *testing.T) {
+	registry := startStandInServer(t, func(query string) string {
+		return "Registrar: Loop Registrar\nRegistrar WHOIS Server: whois.nic.xyz\n"
+	})
+	iana := startStandInServer(t, func(query string) string {
+		return "refer: whois.nic.xyz\n"
+	})
+
+	client := newTestClient(map[string]*standInServer{"whois.iana.org:43": iana, "whois.nic.xyz:43": registry})
+	record, err := client.Lookup(context.Background(), "loop.xyz")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if record.Registrar != "Loop Registrar" {
+		t.Errorf("Expected registrar, got %s", record.Registrar)
+	}
+	if got := registry.received(); len(got) != 1 {
+		t.Errorf("Referral loop should not re-query the same server, got %v", got)
+	}
+}
+
+func TestClient_Lookup_Errors(t *testing.T) {
+	iana := startStandInServer(t, func(query string) string {
+		return "% This query returned 0 objects.\n"
+	})
+	client := newTestClient(map[string]*standInServer{"whois.iana.org:43": iana})
+
+	if _, err := client.Lookup(context.Background(), "invalid"); err == nil {
+		t.Error("Expected error for domain without TLD")
+	}
+	if _, err := client.Lookup(context.Background(), "example.unknowntld"); err == nil {
+		t.Error("Expected error when IANA has no referral")
+	}
+
+	unreachable := newTestClient(nil)
+	if _, err := unreachable.LookupDomain(context.Background(), "example.com"); err == nil {
+		t.Error("Expected error for unreachable IANA server")
+	}
+}
+
+func TestClient_Lookup_RejectsUnsafeReferrals(t *testing.T) {
+	for _, referral := range []string{"127.0.0.1:4343", "10.0.0.5", "[fd00::1]:43", "whois.example-registrar.com:8080", "localhost", "whois.corp.internal"} {
+		t.Run(referral, func(t *testing.T) {
+			registry := startStandInServer(t, func(query string) string {
+				return "Registrar: Example Registrar, LLC\nRegistrar WHOIS Server: " + referral + "\n"
+			})
+			iana := startStandInServer(t, func(query string) string {
+				return "refer: whois.nic.xyz\n"
+			})
+
+			// Qualquer discagem fora das rotas falha no dial de teste
+			client := newTestClient(map[string]*standInServer{"whois.iana.org:43": iana, "whois.nic.xyz:43": registry})
+			record, err := client.Lookup(context.Background(), "acme-login.xyz")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if record.Registrar != "Example Registrar, LLC" || record.Server != "whois.nic.xyz:43" {
+				t.Errorf("Expected registry data only, got %+v", record)
+			}
+		})
+	}
+
+	iana := startStandInServer(t, func(query string) string {
+		return "refer: 169.254.169.254\n"
+	})
+	client := newTestClient(map[string]*standInServer{"whois.iana.org:43": iana})
+	if _, err := client.Lookup(context.Background(), "acme-login.xyz"); err == nil || !strings.Contains(err.Error(), "IP address") {
+		t.Errorf("Expected IANA referral to an IP to be rejected, got %v", err)
+	}
+}
+
+func TestClient_Lookup_Cancel(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	iana := startStandInServer(t, func(query string) string {
+		<-release
+		return ""
+	})
+	client := newTestClient(map[string]*standInServer{"whois.iana.org:43": iana})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.Lookup(ctx, "acme-login.com")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Lookup ignored cancellation, took %s", elapsed)
+	}
+}
+
+func TestReferralServer(t *testing.T) {
+	tests := []struct {
+		referral string
+		want     string
+		wantErr  string
+	}{
+		{referral: "whois.nic.uk", want: "whois.nic.uk:43"},
+		{referral: "WHOIS.Example-Registrar.com:43", want: "whois.example-registrar.com:43"},
+		{referral: "whois://whois.nic.it/", want: "whois.nic.it:43"},
+		{referral: "whois.example-registrar.com:4343", wantErr: "only 43 is allowed"},
+		{referral: "203.0.0.1", wantErr: "IP address"},
+		{referral: "[2a01:4f8::1]:43", wantErr: "IP address"},
+		{referral: "localhost", wantErr: "rejected"},
+		{referral: "metadata.google.internal", wantErr: "special-use name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.referral, func(t *testing.T) {
+			got, err := referralServer(tt.referral)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("referralServer(%q) error = %v, want %q", tt.referral, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("referralServer(%q) = %q, %v, want %q", tt.referral, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatQuery(t *testing.T) {
+	tests := []struct {
+		server   string
+		query    string
+		expected string
+	}{
+		{"whois.denic.de", "example.de", "-T dn,ace example.de"},
+		{"whois.verisign-grs.com:43", "example.com", "domain example.com"},
+		{"whois.registro.br", "example.com.br", "example.com.br"},
+	}
+
+	for _, tt := range tests {
+		if got := formatQuery(tt.server, tt.query); got != tt.expected {
+			t.Errorf("formatQuery(%s, %s) = %s, want %s", tt.server, tt.query, got, tt.expected)
+		}
+	}
+}
+
+func TestNormalizeServer(t *testing.T) {
+	tests := map[string]string{
+		"whois.nic.uk":          "whois.nic.uk:43",
+		"WHOIS.NIC.UK":          "whois.nic.uk:43",
+		"whois://whois.nic.it/": "whois.nic.it:43",
+		"127.0.0.1:4343":        "127.0.0.1:4343",
+	}
+
+	for input, expected := range tests {
+		if got := normalizeServer(input); got != expected {
+			t.Errorf("normalizeServer(%s) = %s, want %s", input, got, expected)
+		}
+	}
+}
@@ -0,0 +1,348 @@
+package whois
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cti-team/takedown/pkg/models"
+)
+
+// Record representa os campos relevantes extraídos de uma resposta WHOIS
+type Record struct {
+	Domain      string
+	Registrar   string
+	IANAID      int
+	AbuseEmail  string
+	AbusePhone  string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	ExpiresAt   time.Time
+	WhoisServer string // referral para o WHOIS do registrar
+	Server      string // servidor que respondeu a consulta
+	Privacy     bool
+}
+
+// ToAbuseContact converte o registro para o formato normalizado de contato
+func (r *Record) ToAbuseContact() *models.AbuseContact {
+	contact := &models.AbuseContact{
+		Domain: r.Domain,
+		Abuse: models.ContactInfo{
+			Email:  r.AbuseEmail,
+			Phone:  r.AbusePhone,
+			Source: models.ContactSourceWHOIS,
+		},
+		Privacy: r.Privacy,
+	}
+
+	if r.Registrar != "" {
+		contact.Registrar = &models.RegistrarInfo{
+			Name:   r.Registrar,
+			IANAID: r.IANAID,
+		}
+	}
+
+	if !r.CreatedAt.IsZero() {
+		created := r.CreatedAt
+		contact.CreatedAt = &created
+	}
+	if !r.ExpiresAt.IsZero() {
+		expires := r.ExpiresAt
+		contact.ExpiresAt = &expires
+	}
+
+	return contact
+}
+
+// merge sobrescreve os campos com os dados (mais completos) do WHOIS do registrar
+func (r *Record) merge(other *Record) {
+	if other.Registrar != "" {
+		r.Registrar = other.Registrar
+	}
+	if other.IANAID != 0 {
+		r.IANAID = other.IANAID
+	}
+	if other.AbuseEmail != "" {
+		r.AbuseEmail = other.AbuseEmail
+	}
+	if other.AbusePhone != "" {
+		r.AbusePhone = other.AbusePhone
+	}
+	if !other.CreatedAt.IsZero() {
+		r.CreatedAt = other.CreatedAt
+	}
+	if !other.UpdatedAt.IsZero() {
+		r.UpdatedAt = other.UpdatedAt
+	}
+	if !other.ExpiresAt.IsZero() {
+		r.ExpiresAt = other.ExpiresAt
+	}
+	r.Privacy = r.Privacy || other.Privacy
+	r.WhoisServer = other.WhoisServer
+	r.Server = other.Server
+}
+
+// profile descreve as chaves usadas por um registry para cada campo
+type profile struct {
+	registrar      []string
+	ianaID         []string
+	abuseEmail     []string
+	abusePhone     []string
+	created        []string
+	updated        []string
+	expires        []string
+	referral       []string
+	fixedRegistrar string // registries que também atuam como registrar
+}
+
+// defaultProfile cobre o formato ICANN (RAA 2013) e os formatos em seções de ccTLDs europeus
+var defaultProfile = profile{
+	registrar:  []string{"registrar", "sponsoring registrar", "registrar name"},
+	ianaID:     []string{"registrar iana id", "sponsoring registrar iana id"},
+	abuseEmail: []string{"registrar abuse contact email", "abuse contact email", "abuse-mailbox", "abuse email", "abuse-email"},
+	abusePhone: []string{"registrar abuse contact phone", "abuse contact phone", "abuse phone"},
+	created:    []string{"creation date", "created", "created on", "registered on", "registration time", "registration date", "domain record activated"},
+	updated:    []string{"updated date", "last updated", "last-update", "last modified", "changed", "modified"},
+	expires: []string{"registry expiry date", "registrar registration expiration date", "expiry date",
+		"expiration date", "expire date", "expires", "expires on", "renewal date", "paid-till"},
+	referral: []string{"registrar whois server", "whois server", "referralserver"},
+}
+
+// tldProfiles contém os parsers específicos por TLD
+var tldProfiles = map[string]profile{
+	// Registro.br é registry e registrar; datas no formato AAAAMMDD
+	"br": {
+		created:        []string{"created"},
+		updated:        []string{"changed"},
+		expires:        []string{"expires"},
+		abuseEmail:     []string{"abuse-mailbox"},
+		fixedRegistrar: "Registro.br",
+	},
+	// Coordination Center for TLD RU
+	"ru": {
+		registrar: []string{"registrar"},
+		created:   []string{"created"},
+		expires:   []string{"paid-till", "free-date"},
+	},
+	"su": {
+		registrar: []string{"registrar"},
+		created:   []string{"created"},
+		expires:   []string{"paid-till", "free-date"},
+	},
+}
+
+// registrarSections são os cabeçalhos de seção que descrevem o registrar
+var registrarSections = map[string]bool{
+	"registrar":            true,
+	"sponsoring registrar": true,
+}
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	tagPattern   = regexp.MustCompile(`\s*\[Tag = [^\]]*\]`)
+)
+
+var privacyMarkers = []string{"redacted for privacy", "privacy", "whoisguard", "proxy", "protected"}
+
+// dateLayouts são os formatos de data observados nos servidores WHOIS
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02",
+	"2006.01.02",
+	"2006/01/02",
+	"20060102",
+	"02-Jan-2006",
+	"02.01.2006",
+	"02/01/2006",
+}
+
+// field representa uma linha chave/valor dentro de uma seção
+type field struct {
+	section string
+	key     string
+	value   string
+}
+
+// parseResponse aplica o parser do TLD a uma resposta WHOIS bruta
+func parseResponse(tld, raw string) *Record {
+	p, exists := tldProfiles[tld]
+	if !exists {
+		p = defaultProfile
+	}
+	return parseWithProfile(p, raw)
+}
+
+// parseWithProfile extrai os campos da resposta segundo um perfil de chaves
+func parseWithProfile(p profile, raw string) *Record {
+	record := &Record{Registrar: p.fixedRegistrar}
+	var sectionEmail string
+
+	for _, f := range scanFields(raw) {
+		inRegistrarSection := registrarSections[f.section]
+
+		switch {
+		case inRegistrarSection && record.Registrar == "" &&
+			(f.key == "" || f.key == "name" || f.key == "organization" || f.key == "organisation"):
+			record.Registrar = tagPattern.ReplaceAllString(f.value, "")
+
+		case inRegistrarSection && (f.key == "email" || f.key == "e-mail") && sectionEmail == "":
+			sectionEmail = f.value
+
+		case f.section == "" && matchesKey(f.key, p.registrar) && record.Registrar == "":
+			record.Registrar = tagPattern.ReplaceAllString(f.value, "")
+
+		case matchesKey(f.key, p.ianaID) && record.IANAID == 0:
+			record.IANAID, _ = strconv.Atoi(f.value)
+
+		case matchesKey(f.key, p.abuseEmail) && record.AbuseEmail == "":
+			record.AbuseEmail = emailPattern.FindString(f.value)
+
+		case matchesKey(f.key, p.abusePhone) && record.AbusePhone == "":
+			record.AbusePhone = f.value
+
+		case isDateSection(f.section) && matchesKey(f.key, p.created) && record.CreatedAt.IsZero():
+			record.CreatedAt = parseDate(f.value)
+
+		case isDateSection(f.section) && matchesKey(f.key, p.updated) && record.UpdatedAt.IsZero():
+			record.UpdatedAt = parseDate(f.value)
+
+		case isDateSection(f.section) && matchesKey(f.key, p.expires) && record.ExpiresAt.IsZero():
+			record.ExpiresAt = parseDate(f.value)
+
+		case matchesKey(f.key, p.referral) && record.WhoisServer == "":
+			record.WhoisServer = f.value
+
+		case strings.HasPrefix(f.key, "registrant") && containsPrivacyMarker(f.value):
+			record.Privacy = true
+		}
+	}
+
+	if record.AbuseEmail == "" {
+		record.AbuseEmail = findAbuseEmail(raw)
+	}
+	if record.AbuseEmail == "" {
+		record.AbuseEmail = sectionEmail
+	}
+
+	return record
+}
+
+// scanFields quebra a resposta em campos, preservando a seção de cada linha
+func scanFields(raw string) []field {
+	var fields []field
+	section := ""
+	sectionIndent := 0
+
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimRight(line, "\r \t")
+		text := strings.TrimSpace(line)
+		if text == "" || isComment(text) {
+			continue
+		}
+
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if section != "" && indent <= sectionIndent {
+			section = ""
+		}
+
+		key, value, ok := splitKeyValue(text)
+
+		// Cabeçalho de seção, ex: "Registrar:" (.uk/.eu/.nl) ou "Registrar" (.it)
+		if (ok && value == "") || (!ok && section == "") {
+			section = strings.ToLower(strings.TrimSuffix(text, ":"))
+			sectionIndent = indent
+			continue
+		}
+
+		if ok {
+			fields = append(fields, field{section: section, key: key, value: value})
+		} else {
+			fields = append(fields, field{section: section, value: text})
+		}
+	}
+
+	return fields
+}
+
+// splitKeyValue separa uma linha "Chave: valor", normalizando a chave
+func splitKeyValue(line string) (string, string, bool) {
+	idx := strings.Index(line, ":")
+	if idx <= 0 {
+		return "", "", false
+	}
+
+	key := strings.ToLower(strings.TrimSpace(line[:idx]))
+	// URLs soltas ("http://...") não são pares chave/valor
+	if strings.ContainsAny(key, "/[") {
+		return "", "", false
+	}
+
+	return key, strings.TrimSpace(line[idx+1:]), true
+}
+
+// isComment identifica linhas de comentário e avisos legais
+func isComment(text string) bool {
+	return strings.HasPrefix(text, "%") || strings.HasPrefix(text, "#") || strings.HasPrefix(text, ">>>")
+}
+
+// isDateSection indica se datas desta seção se referem ao domínio (e não a contatos)
+func isDateSection(section string) bool {
+	return section == "" || strings.Contains(section, "date")
+}
+
+// matchesKey verifica se a chave está entre os aliases do campo
+func matchesKey(key string, aliases []string) bool {
+	for _, alias := range aliases {
+		if key == alias {
+			return true
+		}
+	}
+	return false
+}
+
+// containsPrivacyMarker detecta serviços de privacy/proxy nos dados do registrante
+func containsPrivacyMarker(value string) bool {
+	lower := strings.ToLower(value)
+	for _, marker := range privacyMarkers {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// findAbuseEmail procura qualquer email de abuse no corpo da resposta
+func findAbuseEmail(raw string) string {
+	for _, email := range emailPattern.FindAllString(raw, -1) {
+		if strings.Contains(strings.ToLower(email), "abuse") {
+			return email
+		}
+	}
+	return ""
+}
+
+// parseDate tenta os formatos conhecidos; comentários após "#" são ignorados (.br)
+func parseDate(value string) time.Time {
+	if idx := strings.Index(value, "#"); idx >= 0 {
+		value = value[:idx]
+	}
+	value = strings.TrimSpace(value)
+
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC()
+		}
+	}
+
+	// Alguns servidores acrescentam texto após a data (ex: "2025-01-01 (YYYY-MM-DD)")
+	if fields := strings.Fields(value); len(fields) > 1 {
+		return parseDate(fields[0])
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,216 @@
+package whois
+
+import (
+	"testing"
+	"time"
+)
+
+const nominetResponse = `
+    Domain name:
+        banco-seguro.co.uk
+
+    Registrar:
+        Key-Systems GmbH [Tag = KEY-SYSTEMS-DE]
+        URL: http://www.key-systems.net
+
+    Relevant dates:
+        Registered on: 08-Nov-2023
+        Expiry date:  08-Nov-2025
+        Last updated:  10-Oct-2024
+
+    Registration status:
+        Registered until expiry date.
+`
+
+const eurIDResponse = `% The WHOIS service offered by EURid
+Domain: phish-login.eu
+Script: LATIN
+
+Registrant:
+        NOT DISCLOSED!
+
+Technical:
+        Organisation: Tech Contact Ltd
+        Email: tech@techcontact.example
+
+Registrar:
+        Name: Example EU Registrar
+        Website: www.registrar.example
+        Email: support@registrar.example
+`
+
+const nicITResponse = `Domain:             falso-banco.it
+Status:             ok
+Created:            2024-01-15 10:12:44
+Last Update:        2024-02-01 00:51:12
+Expire Date:        2025-01-15
+
+Registrant
+  Organization:     Someone Else
+  Created:          2010-01-01 00:00:00
+
+Registrar
+  Organization:     Aruba s.p.a.
+  Name:             ARUBA-REG
+  Web:              http://www.aruba.it
+`
+
+const registroBRResponse = `% Copyright (c) Nic.br
+domain:      banco-falso.com.br
+owner:       Fulano de Tal
+owner-c:     FUTAL
+created:     20240210 #27654321
+changed:     20240211
+expires:     20250210
+status:      published
+
+nic-hdl-br:  FUTAL
+person:      Fulano de Tal
+e-mail:      fulano@example.com
+created:     20100101
+`
+
+func TestParseResponse_ICANN(t *testing.T) {
+	raw := `Domain Name: EXAMPLE-PHISH.COM
+Registry Domain ID: 123_DOMAIN_COM-VRSN
+Registrar WHOIS Server: whois.godaddy.com
+Updated Date: 2024-05-01T12:00:00Z
+Creation Date: 2024-04-30T08:15:00Z
+Registrar Registration Expiration Date: 2025-04-30T08:15:00Z
+Registrar: GoDaddy.com, LLC
+Registrar IANA ID: 146
+Registrar Abuse Contact Email: abuse@godaddy.com
+Registrar Abuse Contact Phone: +1.4806242505
+Registrant Organization: Domains By Proxy, LLC
+`
+
+	record := parseResponse("com", raw)
+
+	if record.Registrar != "GoDaddy.com, LLC" {
+		t.Errorf("Expected GoDaddy.com, LLC, got %s", record.Registrar)
+	}
+	if record.IANAID != 146 {
+		t.Errorf("Expected IANA ID 146, got %d", record.IANAID)
+	}
+	if record.AbuseEmail != "abuse@godaddy.com" {
+		t.Errorf("Expected abuse email, got %s", record.AbuseEmail)
+	}
+	if record.AbusePhone != "+1.4806242505" {
+		t.Errorf("Expected abuse phone, got %s", record.AbusePhone)
+	}
+	if record.WhoisServer != "whois.godaddy.com" {
+		t.Errorf("Expected referral whois.godaddy.com, got %s", record.WhoisServer)
+	}
+	expectedCreated := time.Date(2024, 4, 30, 8, 15, 0, 0, time.UTC)
+	if !record.CreatedAt.Equal(expectedCreated) {
+		t.Errorf("Expected created %v, got %v", expectedCreated, record.CreatedAt)
+	}
+	if record.ExpiresAt.Year() != 2025 {
+		t.Errorf("Expected expiry in 2025, got %v", record.ExpiresAt)
+	}
+	if !record.Privacy {
+		t.Error("Expected Domains By Proxy to be flagged as privacy service")
+	}
+}
+
+func TestParseResponse_Sectioned(t *testing.T) {
+	tests := []struct {
+		name              string
+		tld               string
+		raw               string
+		expectedRegistrar string
+		expectedEmail     string
+		expectedCreated   time.Time
+	}{
+		{
+			name:              "Nominet .uk",
+			tld:               "uk",
+			raw:               nominetResponse,
+			expectedRegistrar: "Key-Systems GmbH",
+			expectedCreated:   time.Date(2023, 11, 8, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:              "EURid .eu",
+			tld:               "eu",
+			raw:               eurIDResponse,
+			expectedRegistrar: "Example EU Registrar",
+			expectedEmail:     "support@registrar.example",
+		},
+		{
+			name:              "NIC .it ignores registrant dates",
+			tld:               "it",
+			raw:               nicITResponse,
+			expectedRegistrar: "Aruba s.p.a.",
+			expectedCreated:   time.Date(2024, 1, 15, 10, 12, 44, 0, time.UTC),
+		},
+		{
+			name:              "Registro.br",
+			tld:               "br",
+			raw:               registroBRResponse,
+			expectedRegistrar: "Registro.br",
+			expectedCreated:   time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := parseResponse(tt.tld, tt.raw)
+
+			if record.Registrar != tt.expectedRegistrar {
+				t.Errorf("Expected registrar %q, got %q", tt.expectedRegistrar, record.Registrar)
+			}
+			if record.AbuseEmail != tt.expectedEmail {
+				t.Errorf("Expected email %q, got %q", tt.expectedEmail, record.AbuseEmail)
+			}
+			if !record.CreatedAt.Equal(tt.expectedCreated) {
+				t.Errorf("Expected created %v, got %v", tt.expectedCreated, record.CreatedAt)
+			}
+		})
+	}
+}
+
+func TestParseIANAReferral(t *testing.T) {
+	raw := `% IANA WHOIS server
+domain:       UK
+
+organisation: Nominet UK
+whois:        whois.nic.uk
+`
+	if got := parseIANAReferral(raw); got != "whois.nic.uk" {
+		t.Errorf("Expected whois.nic.uk, got %s", got)
+	}
+	if got := parseIANAReferral("% no referral\n"); got != "" {
+		t.Errorf("Expected empty referral, got %s", got)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Time
+	}{
+		{"2024-04-30T08:15:00Z", time.Date(2024, 4, 30, 8, 15, 0, 0, time.UTC)},
+		{"20240210 #27654321", time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC)},
+		{"08-Nov-2023", time.Date(2023, 11, 8, 0, 0, 0, 0, time.UTC)},
+		{"2025-01-01 (YYYY-MM-DD)", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"not a date", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		if got := parseDate(tt.value); !got.Equal(tt.expected) {
+			t.Errorf("parseDate(%q) = %v, want %v", tt.value, got, tt.expected)
+		}
+	}
+}
+
+func TestRecord_ToAbuseContact(t *testing.T) {
+	record := &Record{Domain: "example.com"}
+	contact := record.ToAbuseContact()
+
+	if contact.Registrar != nil {
+		t.Error("Registrar should be nil when unknown")
+	}
+	if contact.CreatedAt != nil || contact.ExpiresAt != nil {
+		t.Error("Dates should be nil when unknown")
+	}
+}